@@ -0,0 +1,6 @@
+// Package scdv1 holds the protobuf messages and gRPC services of the API. The
+// .pb.go files are generated from the .proto files next to them; run
+// go generate after changing those.
+package scdv1
+
+//go:generate protoc -I ../../.. --go_out=../../.. --go_opt=paths=source_relative --go-grpc_out=../../.. --go-grpc_opt=paths=source_relative api/scd/v1/jobs.proto api/scd/v1/timelogs.proto api/scd/v1/payment_line_items.proto api/scd/v1/events.proto
@@ -0,0 +1,866 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        v5.27.1
+// source: api/scd/v1/payment_line_items.proto
+
+package scdv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// PaymentLineItem is one version of a payment line item.
+type PaymentLineItem struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id           string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Uid          string                 `protobuf:"bytes,2,opt,name=uid,proto3" json:"uid,omitempty"`
+	Version      int32                  `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	ContractorId string                 `protobuf:"bytes,4,opt,name=contractor_id,json=contractorId,proto3" json:"contractor_id,omitempty"`
+	Amount       float64                `protobuf:"fixed64,5,opt,name=amount,proto3" json:"amount,omitempty"`
+	IssuedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=issued_at,json=issuedAt,proto3" json:"issued_at,omitempty"`
+	CreatedAt    *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt    *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+}
+
+func (x *PaymentLineItem) Reset() {
+	*x = PaymentLineItem{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_scd_v1_payment_line_items_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PaymentLineItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PaymentLineItem) ProtoMessage() {}
+
+func (x *PaymentLineItem) ProtoReflect() protoreflect.Message {
+	mi := &file_api_scd_v1_payment_line_items_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PaymentLineItem.ProtoReflect.Descriptor instead.
+func (*PaymentLineItem) Descriptor() ([]byte, []int) {
+	return file_api_scd_v1_payment_line_items_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *PaymentLineItem) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *PaymentLineItem) GetUid() string {
+	if x != nil {
+		return x.Uid
+	}
+	return ""
+}
+
+func (x *PaymentLineItem) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *PaymentLineItem) GetContractorId() string {
+	if x != nil {
+		return x.ContractorId
+	}
+	return ""
+}
+
+func (x *PaymentLineItem) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *PaymentLineItem) GetIssuedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.IssuedAt
+	}
+	return nil
+}
+
+func (x *PaymentLineItem) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *PaymentLineItem) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+// PaymentLineItemInput is the complete writable representation of a payment
+// line item.
+type PaymentLineItemInput struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ContractorId string                 `protobuf:"bytes,1,opt,name=contractor_id,json=contractorId,proto3" json:"contractor_id,omitempty"`
+	Amount       float64                `protobuf:"fixed64,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	IssuedAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=issued_at,json=issuedAt,proto3" json:"issued_at,omitempty"`
+}
+
+func (x *PaymentLineItemInput) Reset() {
+	*x = PaymentLineItemInput{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_scd_v1_payment_line_items_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PaymentLineItemInput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PaymentLineItemInput) ProtoMessage() {}
+
+func (x *PaymentLineItemInput) ProtoReflect() protoreflect.Message {
+	mi := &file_api_scd_v1_payment_line_items_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PaymentLineItemInput.ProtoReflect.Descriptor instead.
+func (*PaymentLineItemInput) Descriptor() ([]byte, []int) {
+	return file_api_scd_v1_payment_line_items_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *PaymentLineItemInput) GetContractorId() string {
+	if x != nil {
+		return x.ContractorId
+	}
+	return ""
+}
+
+func (x *PaymentLineItemInput) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *PaymentLineItemInput) GetIssuedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.IssuedAt
+	}
+	return nil
+}
+
+type CreatePaymentLineItemRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PaymentLineItem *PaymentLineItemInput `protobuf:"bytes,1,opt,name=payment_line_item,json=paymentLineItem,proto3" json:"payment_line_item,omitempty"`
+}
+
+func (x *CreatePaymentLineItemRequest) Reset() {
+	*x = CreatePaymentLineItemRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_scd_v1_payment_line_items_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreatePaymentLineItemRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePaymentLineItemRequest) ProtoMessage() {}
+
+func (x *CreatePaymentLineItemRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_scd_v1_payment_line_items_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePaymentLineItemRequest.ProtoReflect.Descriptor instead.
+func (*CreatePaymentLineItemRequest) Descriptor() ([]byte, []int) {
+	return file_api_scd_v1_payment_line_items_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreatePaymentLineItemRequest) GetPaymentLineItem() *PaymentLineItemInput {
+	if x != nil {
+		return x.PaymentLineItem
+	}
+	return nil
+}
+
+type GetPaymentLineItemRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Uid  string                 `protobuf:"bytes,1,opt,name=uid,proto3" json:"uid,omitempty"`
+	AsOf *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=as_of,json=asOf,proto3" json:"as_of,omitempty"`
+}
+
+func (x *GetPaymentLineItemRequest) Reset() {
+	*x = GetPaymentLineItemRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_scd_v1_payment_line_items_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetPaymentLineItemRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPaymentLineItemRequest) ProtoMessage() {}
+
+func (x *GetPaymentLineItemRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_scd_v1_payment_line_items_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPaymentLineItemRequest.ProtoReflect.Descriptor instead.
+func (*GetPaymentLineItemRequest) Descriptor() ([]byte, []int) {
+	return file_api_scd_v1_payment_line_items_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetPaymentLineItemRequest) GetUid() string {
+	if x != nil {
+		return x.Uid
+	}
+	return ""
+}
+
+func (x *GetPaymentLineItemRequest) GetAsOf() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AsOf
+	}
+	return nil
+}
+
+type UpdatePaymentLineItemRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Uid             string                `protobuf:"bytes,1,opt,name=uid,proto3" json:"uid,omitempty"`
+	PaymentLineItem *PaymentLineItemInput `protobuf:"bytes,2,opt,name=payment_line_item,json=paymentLineItem,proto3" json:"payment_line_item,omitempty"`
+	ExpectedHeadUid string                `protobuf:"bytes,3,opt,name=expected_head_uid,json=expectedHeadUid,proto3" json:"expected_head_uid,omitempty"`
+}
+
+func (x *UpdatePaymentLineItemRequest) Reset() {
+	*x = UpdatePaymentLineItemRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_scd_v1_payment_line_items_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdatePaymentLineItemRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdatePaymentLineItemRequest) ProtoMessage() {}
+
+func (x *UpdatePaymentLineItemRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_scd_v1_payment_line_items_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdatePaymentLineItemRequest.ProtoReflect.Descriptor instead.
+func (*UpdatePaymentLineItemRequest) Descriptor() ([]byte, []int) {
+	return file_api_scd_v1_payment_line_items_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *UpdatePaymentLineItemRequest) GetUid() string {
+	if x != nil {
+		return x.Uid
+	}
+	return ""
+}
+
+func (x *UpdatePaymentLineItemRequest) GetPaymentLineItem() *PaymentLineItemInput {
+	if x != nil {
+		return x.PaymentLineItem
+	}
+	return nil
+}
+
+func (x *UpdatePaymentLineItemRequest) GetExpectedHeadUid() string {
+	if x != nil {
+		return x.ExpectedHeadUid
+	}
+	return ""
+}
+
+type DeletePaymentLineItemRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Uid             string `protobuf:"bytes,1,opt,name=uid,proto3" json:"uid,omitempty"`
+	ExpectedHeadUid string `protobuf:"bytes,2,opt,name=expected_head_uid,json=expectedHeadUid,proto3" json:"expected_head_uid,omitempty"`
+}
+
+func (x *DeletePaymentLineItemRequest) Reset() {
+	*x = DeletePaymentLineItemRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_scd_v1_payment_line_items_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeletePaymentLineItemRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeletePaymentLineItemRequest) ProtoMessage() {}
+
+func (x *DeletePaymentLineItemRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_scd_v1_payment_line_items_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeletePaymentLineItemRequest.ProtoReflect.Descriptor instead.
+func (*DeletePaymentLineItemRequest) Descriptor() ([]byte, []int) {
+	return file_api_scd_v1_payment_line_items_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *DeletePaymentLineItemRequest) GetUid() string {
+	if x != nil {
+		return x.Uid
+	}
+	return ""
+}
+
+func (x *DeletePaymentLineItemRequest) GetExpectedHeadUid() string {
+	if x != nil {
+		return x.ExpectedHeadUid
+	}
+	return ""
+}
+
+type ListPaymentLineItemHistoryRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Uid string `protobuf:"bytes,1,opt,name=uid,proto3" json:"uid,omitempty"`
+}
+
+func (x *ListPaymentLineItemHistoryRequest) Reset() {
+	*x = ListPaymentLineItemHistoryRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_scd_v1_payment_line_items_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListPaymentLineItemHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPaymentLineItemHistoryRequest) ProtoMessage() {}
+
+func (x *ListPaymentLineItemHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_scd_v1_payment_line_items_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPaymentLineItemHistoryRequest.ProtoReflect.Descriptor instead.
+func (*ListPaymentLineItemHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_api_scd_v1_payment_line_items_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListPaymentLineItemHistoryRequest) GetUid() string {
+	if x != nil {
+		return x.Uid
+	}
+	return ""
+}
+
+type ListContractorPaymentLineItemsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ContractorId string `protobuf:"bytes,1,opt,name=contractor_id,json=contractorId,proto3" json:"contractor_id,omitempty"`
+}
+
+func (x *ListContractorPaymentLineItemsRequest) Reset() {
+	*x = ListContractorPaymentLineItemsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_scd_v1_payment_line_items_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListContractorPaymentLineItemsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListContractorPaymentLineItemsRequest) ProtoMessage() {}
+
+func (x *ListContractorPaymentLineItemsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_scd_v1_payment_line_items_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListContractorPaymentLineItemsRequest.ProtoReflect.Descriptor instead.
+func (*ListContractorPaymentLineItemsRequest) Descriptor() ([]byte, []int) {
+	return file_api_scd_v1_payment_line_items_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListContractorPaymentLineItemsRequest) GetContractorId() string {
+	if x != nil {
+		return x.ContractorId
+	}
+	return ""
+}
+
+type ListPaymentLineItemsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PaymentLineItems []*PaymentLineItem `protobuf:"bytes,1,rep,name=payment_line_items,json=paymentLineItems,proto3" json:"payment_line_items,omitempty"`
+}
+
+func (x *ListPaymentLineItemsResponse) Reset() {
+	*x = ListPaymentLineItemsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_scd_v1_payment_line_items_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListPaymentLineItemsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPaymentLineItemsResponse) ProtoMessage() {}
+
+func (x *ListPaymentLineItemsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_scd_v1_payment_line_items_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPaymentLineItemsResponse.ProtoReflect.Descriptor instead.
+func (*ListPaymentLineItemsResponse) Descriptor() ([]byte, []int) {
+	return file_api_scd_v1_payment_line_items_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListPaymentLineItemsResponse) GetPaymentLineItems() []*PaymentLineItem {
+	if x != nil {
+		return x.PaymentLineItems
+	}
+	return nil
+}
+
+var File_api_scd_v1_payment_line_items_proto protoreflect.FileDescriptor
+
+var file_api_scd_v1_payment_line_items_proto_rawDesc = []byte{
+	0x0a, 0x23, 0x61, 0x70, 0x69, 0x2f, 0x73, 0x63, 0x64, 0x2f, 0x76, 0x31, 0x2f, 0x70, 0x61, 0x79,
+	0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x6c, 0x69, 0x6e, 0x65, 0x5f, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x06, 0x73, 0x63, 0x64, 0x2e, 0x76, 0x31, 0x1a, 0x1b, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x65,
+	0x6d, 0x70, 0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xb9, 0x02, 0x0a, 0x0f,
+	0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x4c, 0x69, 0x6e, 0x65, 0x49, 0x74, 0x65, 0x6d, 0x12,
+	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12,
+	0x10, 0x0a, 0x03, 0x75, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x69,
+	0x64, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x23, 0x0a, 0x0d, 0x63,
+	0x6f, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0c, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x49, 0x64,
+	0x12, 0x16, 0x0a, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x37, 0x0a, 0x09, 0x69, 0x73, 0x73, 0x75,
+	0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x08, 0x69, 0x73, 0x73, 0x75, 0x65, 0x64, 0x41,
+	0x74, 0x12, 0x39, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x39, 0x0a, 0x0a,
+	0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x75, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x22, 0x8c, 0x01, 0x0a, 0x14, 0x50, 0x61, 0x79, 0x6d,
+	0x65, 0x6e, 0x74, 0x4c, 0x69, 0x6e, 0x65, 0x49, 0x74, 0x65, 0x6d, 0x49, 0x6e, 0x70, 0x75, 0x74,
+	0x12, 0x23, 0x0a, 0x0d, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x5f, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x61, 0x63,
+	0x74, 0x6f, 0x72, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x37, 0x0a,
+	0x09, 0x69, 0x73, 0x73, 0x75, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x08, 0x69, 0x73,
+	0x73, 0x75, 0x65, 0x64, 0x41, 0x74, 0x22, 0x68, 0x0a, 0x1c, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65,
+	0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x4c, 0x69, 0x6e, 0x65, 0x49, 0x74, 0x65, 0x6d, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x48, 0x0a, 0x11, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e,
+	0x74, 0x5f, 0x6c, 0x69, 0x6e, 0x65, 0x5f, 0x69, 0x74, 0x65, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1c, 0x2e, 0x73, 0x63, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x79, 0x6d, 0x65,
+	0x6e, 0x74, 0x4c, 0x69, 0x6e, 0x65, 0x49, 0x74, 0x65, 0x6d, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x52,
+	0x0f, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x4c, 0x69, 0x6e, 0x65, 0x49, 0x74, 0x65, 0x6d,
+	0x22, 0x5e, 0x0a, 0x19, 0x47, 0x65, 0x74, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x4c, 0x69,
+	0x6e, 0x65, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x10, 0x0a,
+	0x03, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x69, 0x64, 0x12,
+	0x2f, 0x0a, 0x05, 0x61, 0x73, 0x5f, 0x6f, 0x66, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x61, 0x73, 0x4f, 0x66,
+	0x22, 0xa6, 0x01, 0x0a, 0x1c, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x61, 0x79, 0x6d, 0x65,
+	0x6e, 0x74, 0x4c, 0x69, 0x6e, 0x65, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
+	0x75, 0x69, 0x64, 0x12, 0x48, 0x0a, 0x11, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x6c,
+	0x69, 0x6e, 0x65, 0x5f, 0x69, 0x74, 0x65, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c,
+	0x2e, 0x73, 0x63, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x4c,
+	0x69, 0x6e, 0x65, 0x49, 0x74, 0x65, 0x6d, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x52, 0x0f, 0x70, 0x61,
+	0x79, 0x6d, 0x65, 0x6e, 0x74, 0x4c, 0x69, 0x6e, 0x65, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x2a, 0x0a,
+	0x11, 0x65, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x5f, 0x68, 0x65, 0x61, 0x64, 0x5f, 0x75,
+	0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x65, 0x78, 0x70, 0x65, 0x63, 0x74,
+	0x65, 0x64, 0x48, 0x65, 0x61, 0x64, 0x55, 0x69, 0x64, 0x22, 0x5c, 0x0a, 0x1c, 0x44, 0x65, 0x6c,
+	0x65, 0x74, 0x65, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x4c, 0x69, 0x6e, 0x65, 0x49, 0x74,
+	0x65, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x69, 0x64, 0x12, 0x2a, 0x0a, 0x11, 0x65,
+	0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x5f, 0x68, 0x65, 0x61, 0x64, 0x5f, 0x75, 0x69, 0x64,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x65, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64,
+	0x48, 0x65, 0x61, 0x64, 0x55, 0x69, 0x64, 0x22, 0x35, 0x0a, 0x21, 0x4c, 0x69, 0x73, 0x74, 0x50,
+	0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x4c, 0x69, 0x6e, 0x65, 0x49, 0x74, 0x65, 0x6d, 0x48, 0x69,
+	0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x10, 0x0a, 0x03,
+	0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x69, 0x64, 0x22, 0x4c,
+	0x0a, 0x25, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x74, 0x6f, 0x72,
+	0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x4c, 0x69, 0x6e, 0x65, 0x49, 0x74, 0x65, 0x6d, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x23, 0x0a, 0x0d, 0x63, 0x6f, 0x6e, 0x74, 0x72,
+	0x61, 0x63, 0x74, 0x6f, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c,
+	0x63, 0x6f, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x49, 0x64, 0x22, 0x65, 0x0a, 0x1c,
+	0x4c, 0x69, 0x73, 0x74, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x4c, 0x69, 0x6e, 0x65, 0x49,
+	0x74, 0x65, 0x6d, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x45, 0x0a, 0x12,
+	0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x6c, 0x69, 0x6e, 0x65, 0x5f, 0x69, 0x74, 0x65,
+	0x6d, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x73, 0x63, 0x64, 0x2e, 0x76,
+	0x31, 0x2e, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x4c, 0x69, 0x6e, 0x65, 0x49, 0x74, 0x65,
+	0x6d, 0x52, 0x10, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x4c, 0x69, 0x6e, 0x65, 0x49, 0x74,
+	0x65, 0x6d, 0x73, 0x32, 0xd7, 0x04, 0x0a, 0x16, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x4c,
+	0x69, 0x6e, 0x65, 0x49, 0x74, 0x65, 0x6d, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x56,
+	0x0a, 0x15, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x4c,
+	0x69, 0x6e, 0x65, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x24, 0x2e, 0x73, 0x63, 0x64, 0x2e, 0x76, 0x31,
+	0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x4c, 0x69,
+	0x6e, 0x65, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e,
+	0x73, 0x63, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x4c, 0x69,
+	0x6e, 0x65, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x50, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x50, 0x61, 0x79,
+	0x6d, 0x65, 0x6e, 0x74, 0x4c, 0x69, 0x6e, 0x65, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x21, 0x2e, 0x73,
+	0x63, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74,
+	0x4c, 0x69, 0x6e, 0x65, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x17, 0x2e, 0x73, 0x63, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74,
+	0x4c, 0x69, 0x6e, 0x65, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x56, 0x0a, 0x15, 0x55, 0x70, 0x64, 0x61,
+	0x74, 0x65, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x4c, 0x69, 0x6e, 0x65, 0x49, 0x74, 0x65,
+	0x6d, 0x12, 0x24, 0x2e, 0x73, 0x63, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x4c, 0x69, 0x6e, 0x65, 0x49, 0x74, 0x65, 0x6d,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x73, 0x63, 0x64, 0x2e, 0x76, 0x31,
+	0x2e, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x4c, 0x69, 0x6e, 0x65, 0x49, 0x74, 0x65, 0x6d,
+	0x12, 0x55, 0x0a, 0x15, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e,
+	0x74, 0x4c, 0x69, 0x6e, 0x65, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x24, 0x2e, 0x73, 0x63, 0x64, 0x2e,
+	0x76, 0x31, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74,
+	0x4c, 0x69, 0x6e, 0x65, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x6d, 0x0a, 0x1a, 0x4c, 0x69, 0x73, 0x74, 0x50,
+	0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x4c, 0x69, 0x6e, 0x65, 0x49, 0x74, 0x65, 0x6d, 0x48, 0x69,
+	0x73, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x29, 0x2e, 0x73, 0x63, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x4c,
+	0x69, 0x73, 0x74, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x4c, 0x69, 0x6e, 0x65, 0x49, 0x74,
+	0x65, 0x6d, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x24, 0x2e, 0x73, 0x63, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x61,
+	0x79, 0x6d, 0x65, 0x6e, 0x74, 0x4c, 0x69, 0x6e, 0x65, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x75, 0x0a, 0x1e, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x6f,
+	0x6e, 0x74, 0x72, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x4c,
+	0x69, 0x6e, 0x65, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x12, 0x2d, 0x2e, 0x73, 0x63, 0x64, 0x2e, 0x76,
+	0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x74, 0x6f, 0x72,
+	0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x4c, 0x69, 0x6e, 0x65, 0x49, 0x74, 0x65, 0x6d, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x24, 0x2e, 0x73, 0x63, 0x64, 0x2e, 0x76, 0x31,
+	0x2e, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x4c, 0x69, 0x6e, 0x65,
+	0x49, 0x74, 0x65, 0x6d, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x19, 0x5a,
+	0x17, 0x6d, 0x65, 0x72, 0x63, 0x6f, 0x72, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x73, 0x63, 0x64, 0x2f,
+	0x76, 0x31, 0x3b, 0x73, 0x63, 0x64, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_api_scd_v1_payment_line_items_proto_rawDescOnce sync.Once
+	file_api_scd_v1_payment_line_items_proto_rawDescData = file_api_scd_v1_payment_line_items_proto_rawDesc
+)
+
+func file_api_scd_v1_payment_line_items_proto_rawDescGZIP() []byte {
+	file_api_scd_v1_payment_line_items_proto_rawDescOnce.Do(func() {
+		file_api_scd_v1_payment_line_items_proto_rawDescData = protoimpl.X.CompressGZIP(file_api_scd_v1_payment_line_items_proto_rawDescData)
+	})
+	return file_api_scd_v1_payment_line_items_proto_rawDescData
+}
+
+var file_api_scd_v1_payment_line_items_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_api_scd_v1_payment_line_items_proto_goTypes = []any{
+	(*PaymentLineItem)(nil),                       // 0: scd.v1.PaymentLineItem
+	(*PaymentLineItemInput)(nil),                  // 1: scd.v1.PaymentLineItemInput
+	(*CreatePaymentLineItemRequest)(nil),          // 2: scd.v1.CreatePaymentLineItemRequest
+	(*GetPaymentLineItemRequest)(nil),             // 3: scd.v1.GetPaymentLineItemRequest
+	(*UpdatePaymentLineItemRequest)(nil),          // 4: scd.v1.UpdatePaymentLineItemRequest
+	(*DeletePaymentLineItemRequest)(nil),          // 5: scd.v1.DeletePaymentLineItemRequest
+	(*ListPaymentLineItemHistoryRequest)(nil),     // 6: scd.v1.ListPaymentLineItemHistoryRequest
+	(*ListContractorPaymentLineItemsRequest)(nil), // 7: scd.v1.ListContractorPaymentLineItemsRequest
+	(*ListPaymentLineItemsResponse)(nil),          // 8: scd.v1.ListPaymentLineItemsResponse
+	(*timestamppb.Timestamp)(nil),                 // 9: google.protobuf.Timestamp
+	(*emptypb.Empty)(nil),                         // 10: google.protobuf.Empty
+}
+var file_api_scd_v1_payment_line_items_proto_depIdxs = []int32{
+	9,  // 0: scd.v1.PaymentLineItem.issued_at:type_name -> google.protobuf.Timestamp
+	9,  // 1: scd.v1.PaymentLineItem.created_at:type_name -> google.protobuf.Timestamp
+	9,  // 2: scd.v1.PaymentLineItem.updated_at:type_name -> google.protobuf.Timestamp
+	9,  // 3: scd.v1.PaymentLineItemInput.issued_at:type_name -> google.protobuf.Timestamp
+	1,  // 4: scd.v1.CreatePaymentLineItemRequest.payment_line_item:type_name -> scd.v1.PaymentLineItemInput
+	9,  // 5: scd.v1.GetPaymentLineItemRequest.as_of:type_name -> google.protobuf.Timestamp
+	1,  // 6: scd.v1.UpdatePaymentLineItemRequest.payment_line_item:type_name -> scd.v1.PaymentLineItemInput
+	0,  // 7: scd.v1.ListPaymentLineItemsResponse.payment_line_items:type_name -> scd.v1.PaymentLineItem
+	2,  // 8: scd.v1.PaymentLineItemService.CreatePaymentLineItem:input_type -> scd.v1.CreatePaymentLineItemRequest
+	3,  // 9: scd.v1.PaymentLineItemService.GetPaymentLineItem:input_type -> scd.v1.GetPaymentLineItemRequest
+	4,  // 10: scd.v1.PaymentLineItemService.UpdatePaymentLineItem:input_type -> scd.v1.UpdatePaymentLineItemRequest
+	5,  // 11: scd.v1.PaymentLineItemService.DeletePaymentLineItem:input_type -> scd.v1.DeletePaymentLineItemRequest
+	6,  // 12: scd.v1.PaymentLineItemService.ListPaymentLineItemHistory:input_type -> scd.v1.ListPaymentLineItemHistoryRequest
+	7,  // 13: scd.v1.PaymentLineItemService.ListContractorPaymentLineItems:input_type -> scd.v1.ListContractorPaymentLineItemsRequest
+	0,  // 14: scd.v1.PaymentLineItemService.CreatePaymentLineItem:output_type -> scd.v1.PaymentLineItem
+	0,  // 15: scd.v1.PaymentLineItemService.GetPaymentLineItem:output_type -> scd.v1.PaymentLineItem
+	0,  // 16: scd.v1.PaymentLineItemService.UpdatePaymentLineItem:output_type -> scd.v1.PaymentLineItem
+	10, // 17: scd.v1.PaymentLineItemService.DeletePaymentLineItem:output_type -> google.protobuf.Empty
+	8,  // 18: scd.v1.PaymentLineItemService.ListPaymentLineItemHistory:output_type -> scd.v1.ListPaymentLineItemsResponse
+	8,  // 19: scd.v1.PaymentLineItemService.ListContractorPaymentLineItems:output_type -> scd.v1.ListPaymentLineItemsResponse
+	14, // [14:20] is the sub-list for method output_type
+	8,  // [8:14] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_api_scd_v1_payment_line_items_proto_init() }
+func file_api_scd_v1_payment_line_items_proto_init() {
+	if File_api_scd_v1_payment_line_items_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_api_scd_v1_payment_line_items_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*PaymentLineItem); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_scd_v1_payment_line_items_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*PaymentLineItemInput); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_scd_v1_payment_line_items_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*CreatePaymentLineItemRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_scd_v1_payment_line_items_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*GetPaymentLineItemRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_scd_v1_payment_line_items_proto_msgTypes[4].Exporter = func(v any, i int) any {
+			switch v := v.(*UpdatePaymentLineItemRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_scd_v1_payment_line_items_proto_msgTypes[5].Exporter = func(v any, i int) any {
+			switch v := v.(*DeletePaymentLineItemRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_scd_v1_payment_line_items_proto_msgTypes[6].Exporter = func(v any, i int) any {
+			switch v := v.(*ListPaymentLineItemHistoryRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_scd_v1_payment_line_items_proto_msgTypes[7].Exporter = func(v any, i int) any {
+			switch v := v.(*ListContractorPaymentLineItemsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_scd_v1_payment_line_items_proto_msgTypes[8].Exporter = func(v any, i int) any {
+			switch v := v.(*ListPaymentLineItemsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_api_scd_v1_payment_line_items_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   9,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_api_scd_v1_payment_line_items_proto_goTypes,
+		DependencyIndexes: file_api_scd_v1_payment_line_items_proto_depIdxs,
+		MessageInfos:      file_api_scd_v1_payment_line_items_proto_msgTypes,
+	}.Build()
+	File_api_scd_v1_payment_line_items_proto = out.File
+	file_api_scd_v1_payment_line_items_proto_rawDesc = nil
+	file_api_scd_v1_payment_line_items_proto_goTypes = nil
+	file_api_scd_v1_payment_line_items_proto_depIdxs = nil
+}
@@ -0,0 +1,310 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.4.0
+// - protoc             v5.27.1
+// source: api/scd/v1/payment_line_items.proto
+
+package scdv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.62.0 or later.
+const _ = grpc.SupportPackageIsVersion8
+
+const (
+	PaymentLineItemService_CreatePaymentLineItem_FullMethodName          = "/scd.v1.PaymentLineItemService/CreatePaymentLineItem"
+	PaymentLineItemService_GetPaymentLineItem_FullMethodName             = "/scd.v1.PaymentLineItemService/GetPaymentLineItem"
+	PaymentLineItemService_UpdatePaymentLineItem_FullMethodName          = "/scd.v1.PaymentLineItemService/UpdatePaymentLineItem"
+	PaymentLineItemService_DeletePaymentLineItem_FullMethodName          = "/scd.v1.PaymentLineItemService/DeletePaymentLineItem"
+	PaymentLineItemService_ListPaymentLineItemHistory_FullMethodName     = "/scd.v1.PaymentLineItemService/ListPaymentLineItemHistory"
+	PaymentLineItemService_ListContractorPaymentLineItems_FullMethodName = "/scd.v1.PaymentLineItemService/ListContractorPaymentLineItems"
+)
+
+// PaymentLineItemServiceClient is the client API for PaymentLineItemService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// PaymentLineItemService mirrors the /payment-line-items REST endpoints.
+type PaymentLineItemServiceClient interface {
+	CreatePaymentLineItem(ctx context.Context, in *CreatePaymentLineItemRequest, opts ...grpc.CallOption) (*PaymentLineItem, error)
+	GetPaymentLineItem(ctx context.Context, in *GetPaymentLineItemRequest, opts ...grpc.CallOption) (*PaymentLineItem, error)
+	UpdatePaymentLineItem(ctx context.Context, in *UpdatePaymentLineItemRequest, opts ...grpc.CallOption) (*PaymentLineItem, error)
+	// DeletePaymentLineItem voids the line item by storing a version with a
+	// zero amount.
+	DeletePaymentLineItem(ctx context.Context, in *DeletePaymentLineItemRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ListPaymentLineItemHistory(ctx context.Context, in *ListPaymentLineItemHistoryRequest, opts ...grpc.CallOption) (*ListPaymentLineItemsResponse, error)
+	ListContractorPaymentLineItems(ctx context.Context, in *ListContractorPaymentLineItemsRequest, opts ...grpc.CallOption) (*ListPaymentLineItemsResponse, error)
+}
+
+type paymentLineItemServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPaymentLineItemServiceClient(cc grpc.ClientConnInterface) PaymentLineItemServiceClient {
+	return &paymentLineItemServiceClient{cc}
+}
+
+func (c *paymentLineItemServiceClient) CreatePaymentLineItem(ctx context.Context, in *CreatePaymentLineItemRequest, opts ...grpc.CallOption) (*PaymentLineItem, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PaymentLineItem)
+	err := c.cc.Invoke(ctx, PaymentLineItemService_CreatePaymentLineItem_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentLineItemServiceClient) GetPaymentLineItem(ctx context.Context, in *GetPaymentLineItemRequest, opts ...grpc.CallOption) (*PaymentLineItem, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PaymentLineItem)
+	err := c.cc.Invoke(ctx, PaymentLineItemService_GetPaymentLineItem_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentLineItemServiceClient) UpdatePaymentLineItem(ctx context.Context, in *UpdatePaymentLineItemRequest, opts ...grpc.CallOption) (*PaymentLineItem, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PaymentLineItem)
+	err := c.cc.Invoke(ctx, PaymentLineItemService_UpdatePaymentLineItem_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentLineItemServiceClient) DeletePaymentLineItem(ctx context.Context, in *DeletePaymentLineItemRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, PaymentLineItemService_DeletePaymentLineItem_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentLineItemServiceClient) ListPaymentLineItemHistory(ctx context.Context, in *ListPaymentLineItemHistoryRequest, opts ...grpc.CallOption) (*ListPaymentLineItemsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListPaymentLineItemsResponse)
+	err := c.cc.Invoke(ctx, PaymentLineItemService_ListPaymentLineItemHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentLineItemServiceClient) ListContractorPaymentLineItems(ctx context.Context, in *ListContractorPaymentLineItemsRequest, opts ...grpc.CallOption) (*ListPaymentLineItemsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListPaymentLineItemsResponse)
+	err := c.cc.Invoke(ctx, PaymentLineItemService_ListContractorPaymentLineItems_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PaymentLineItemServiceServer is the server API for PaymentLineItemService service.
+// All implementations must embed UnimplementedPaymentLineItemServiceServer
+// for forward compatibility
+//
+// PaymentLineItemService mirrors the /payment-line-items REST endpoints.
+type PaymentLineItemServiceServer interface {
+	CreatePaymentLineItem(context.Context, *CreatePaymentLineItemRequest) (*PaymentLineItem, error)
+	GetPaymentLineItem(context.Context, *GetPaymentLineItemRequest) (*PaymentLineItem, error)
+	UpdatePaymentLineItem(context.Context, *UpdatePaymentLineItemRequest) (*PaymentLineItem, error)
+	// DeletePaymentLineItem voids the line item by storing a version with a
+	// zero amount.
+	DeletePaymentLineItem(context.Context, *DeletePaymentLineItemRequest) (*emptypb.Empty, error)
+	ListPaymentLineItemHistory(context.Context, *ListPaymentLineItemHistoryRequest) (*ListPaymentLineItemsResponse, error)
+	ListContractorPaymentLineItems(context.Context, *ListContractorPaymentLineItemsRequest) (*ListPaymentLineItemsResponse, error)
+	mustEmbedUnimplementedPaymentLineItemServiceServer()
+}
+
+// UnimplementedPaymentLineItemServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedPaymentLineItemServiceServer struct {
+}
+
+func (UnimplementedPaymentLineItemServiceServer) CreatePaymentLineItem(context.Context, *CreatePaymentLineItemRequest) (*PaymentLineItem, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreatePaymentLineItem not implemented")
+}
+func (UnimplementedPaymentLineItemServiceServer) GetPaymentLineItem(context.Context, *GetPaymentLineItemRequest) (*PaymentLineItem, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPaymentLineItem not implemented")
+}
+func (UnimplementedPaymentLineItemServiceServer) UpdatePaymentLineItem(context.Context, *UpdatePaymentLineItemRequest) (*PaymentLineItem, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdatePaymentLineItem not implemented")
+}
+func (UnimplementedPaymentLineItemServiceServer) DeletePaymentLineItem(context.Context, *DeletePaymentLineItemRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeletePaymentLineItem not implemented")
+}
+func (UnimplementedPaymentLineItemServiceServer) ListPaymentLineItemHistory(context.Context, *ListPaymentLineItemHistoryRequest) (*ListPaymentLineItemsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPaymentLineItemHistory not implemented")
+}
+func (UnimplementedPaymentLineItemServiceServer) ListContractorPaymentLineItems(context.Context, *ListContractorPaymentLineItemsRequest) (*ListPaymentLineItemsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListContractorPaymentLineItems not implemented")
+}
+func (UnimplementedPaymentLineItemServiceServer) mustEmbedUnimplementedPaymentLineItemServiceServer() {
+}
+
+// UnsafePaymentLineItemServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PaymentLineItemServiceServer will
+// result in compilation errors.
+type UnsafePaymentLineItemServiceServer interface {
+	mustEmbedUnimplementedPaymentLineItemServiceServer()
+}
+
+func RegisterPaymentLineItemServiceServer(s grpc.ServiceRegistrar, srv PaymentLineItemServiceServer) {
+	s.RegisterService(&PaymentLineItemService_ServiceDesc, srv)
+}
+
+func _PaymentLineItemService_CreatePaymentLineItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreatePaymentLineItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentLineItemServiceServer).CreatePaymentLineItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentLineItemService_CreatePaymentLineItem_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentLineItemServiceServer).CreatePaymentLineItem(ctx, req.(*CreatePaymentLineItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentLineItemService_GetPaymentLineItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPaymentLineItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentLineItemServiceServer).GetPaymentLineItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentLineItemService_GetPaymentLineItem_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentLineItemServiceServer).GetPaymentLineItem(ctx, req.(*GetPaymentLineItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentLineItemService_UpdatePaymentLineItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdatePaymentLineItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentLineItemServiceServer).UpdatePaymentLineItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentLineItemService_UpdatePaymentLineItem_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentLineItemServiceServer).UpdatePaymentLineItem(ctx, req.(*UpdatePaymentLineItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentLineItemService_DeletePaymentLineItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeletePaymentLineItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentLineItemServiceServer).DeletePaymentLineItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentLineItemService_DeletePaymentLineItem_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentLineItemServiceServer).DeletePaymentLineItem(ctx, req.(*DeletePaymentLineItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentLineItemService_ListPaymentLineItemHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPaymentLineItemHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentLineItemServiceServer).ListPaymentLineItemHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentLineItemService_ListPaymentLineItemHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentLineItemServiceServer).ListPaymentLineItemHistory(ctx, req.(*ListPaymentLineItemHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentLineItemService_ListContractorPaymentLineItems_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListContractorPaymentLineItemsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentLineItemServiceServer).ListContractorPaymentLineItems(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentLineItemService_ListContractorPaymentLineItems_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentLineItemServiceServer).ListContractorPaymentLineItems(ctx, req.(*ListContractorPaymentLineItemsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// PaymentLineItemService_ServiceDesc is the grpc.ServiceDesc for PaymentLineItemService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PaymentLineItemService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "scd.v1.PaymentLineItemService",
+	HandlerType: (*PaymentLineItemServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreatePaymentLineItem",
+			Handler:    _PaymentLineItemService_CreatePaymentLineItem_Handler,
+		},
+		{
+			MethodName: "GetPaymentLineItem",
+			Handler:    _PaymentLineItemService_GetPaymentLineItem_Handler,
+		},
+		{
+			MethodName: "UpdatePaymentLineItem",
+			Handler:    _PaymentLineItemService_UpdatePaymentLineItem_Handler,
+		},
+		{
+			MethodName: "DeletePaymentLineItem",
+			Handler:    _PaymentLineItemService_DeletePaymentLineItem_Handler,
+		},
+		{
+			MethodName: "ListPaymentLineItemHistory",
+			Handler:    _PaymentLineItemService_ListPaymentLineItemHistory_Handler,
+		},
+		{
+			MethodName: "ListContractorPaymentLineItems",
+			Handler:    _PaymentLineItemService_ListContractorPaymentLineItems_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "api/scd/v1/payment_line_items.proto",
+}
@@ -0,0 +1,874 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        v5.27.1
+// source: api/scd/v1/timelogs.proto
+
+package scdv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Timelog is one version of a timelog.
+type Timelog struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id           string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Uid          string                 `protobuf:"bytes,2,opt,name=uid,proto3" json:"uid,omitempty"`
+	Version      int32                  `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	ContractorId string                 `protobuf:"bytes,4,opt,name=contractor_id,json=contractorId,proto3" json:"contractor_id,omitempty"`
+	StartTime    *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime      *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	ExternalRef  string                 `protobuf:"bytes,7,opt,name=external_ref,json=externalRef,proto3" json:"external_ref,omitempty"`
+	CreatedAt    *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt    *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+}
+
+func (x *Timelog) Reset() {
+	*x = Timelog{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_scd_v1_timelogs_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Timelog) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Timelog) ProtoMessage() {}
+
+func (x *Timelog) ProtoReflect() protoreflect.Message {
+	mi := &file_api_scd_v1_timelogs_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Timelog.ProtoReflect.Descriptor instead.
+func (*Timelog) Descriptor() ([]byte, []int) {
+	return file_api_scd_v1_timelogs_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Timelog) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Timelog) GetUid() string {
+	if x != nil {
+		return x.Uid
+	}
+	return ""
+}
+
+func (x *Timelog) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Timelog) GetContractorId() string {
+	if x != nil {
+		return x.ContractorId
+	}
+	return ""
+}
+
+func (x *Timelog) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *Timelog) GetEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+func (x *Timelog) GetExternalRef() string {
+	if x != nil {
+		return x.ExternalRef
+	}
+	return ""
+}
+
+func (x *Timelog) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Timelog) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+// TimelogInput is the complete writable representation of a timelog. An
+// empty external_ref keeps the stored one on update.
+type TimelogInput struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ContractorId string                 `protobuf:"bytes,1,opt,name=contractor_id,json=contractorId,proto3" json:"contractor_id,omitempty"`
+	StartTime    *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime      *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	ExternalRef  string                 `protobuf:"bytes,4,opt,name=external_ref,json=externalRef,proto3" json:"external_ref,omitempty"`
+}
+
+func (x *TimelogInput) Reset() {
+	*x = TimelogInput{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_scd_v1_timelogs_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TimelogInput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TimelogInput) ProtoMessage() {}
+
+func (x *TimelogInput) ProtoReflect() protoreflect.Message {
+	mi := &file_api_scd_v1_timelogs_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TimelogInput.ProtoReflect.Descriptor instead.
+func (*TimelogInput) Descriptor() ([]byte, []int) {
+	return file_api_scd_v1_timelogs_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *TimelogInput) GetContractorId() string {
+	if x != nil {
+		return x.ContractorId
+	}
+	return ""
+}
+
+func (x *TimelogInput) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *TimelogInput) GetEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+func (x *TimelogInput) GetExternalRef() string {
+	if x != nil {
+		return x.ExternalRef
+	}
+	return ""
+}
+
+type CreateTimelogRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Timelog *TimelogInput `protobuf:"bytes,1,opt,name=timelog,proto3" json:"timelog,omitempty"`
+}
+
+func (x *CreateTimelogRequest) Reset() {
+	*x = CreateTimelogRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_scd_v1_timelogs_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateTimelogRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTimelogRequest) ProtoMessage() {}
+
+func (x *CreateTimelogRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_scd_v1_timelogs_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTimelogRequest.ProtoReflect.Descriptor instead.
+func (*CreateTimelogRequest) Descriptor() ([]byte, []int) {
+	return file_api_scd_v1_timelogs_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreateTimelogRequest) GetTimelog() *TimelogInput {
+	if x != nil {
+		return x.Timelog
+	}
+	return nil
+}
+
+type GetTimelogRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Uid  string                 `protobuf:"bytes,1,opt,name=uid,proto3" json:"uid,omitempty"`
+	AsOf *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=as_of,json=asOf,proto3" json:"as_of,omitempty"`
+}
+
+func (x *GetTimelogRequest) Reset() {
+	*x = GetTimelogRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_scd_v1_timelogs_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetTimelogRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTimelogRequest) ProtoMessage() {}
+
+func (x *GetTimelogRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_scd_v1_timelogs_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTimelogRequest.ProtoReflect.Descriptor instead.
+func (*GetTimelogRequest) Descriptor() ([]byte, []int) {
+	return file_api_scd_v1_timelogs_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetTimelogRequest) GetUid() string {
+	if x != nil {
+		return x.Uid
+	}
+	return ""
+}
+
+func (x *GetTimelogRequest) GetAsOf() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AsOf
+	}
+	return nil
+}
+
+type UpdateTimelogRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Uid             string        `protobuf:"bytes,1,opt,name=uid,proto3" json:"uid,omitempty"`
+	Timelog         *TimelogInput `protobuf:"bytes,2,opt,name=timelog,proto3" json:"timelog,omitempty"`
+	ExpectedHeadUid string        `protobuf:"bytes,3,opt,name=expected_head_uid,json=expectedHeadUid,proto3" json:"expected_head_uid,omitempty"`
+}
+
+func (x *UpdateTimelogRequest) Reset() {
+	*x = UpdateTimelogRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_scd_v1_timelogs_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateTimelogRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateTimelogRequest) ProtoMessage() {}
+
+func (x *UpdateTimelogRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_scd_v1_timelogs_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateTimelogRequest.ProtoReflect.Descriptor instead.
+func (*UpdateTimelogRequest) Descriptor() ([]byte, []int) {
+	return file_api_scd_v1_timelogs_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *UpdateTimelogRequest) GetUid() string {
+	if x != nil {
+		return x.Uid
+	}
+	return ""
+}
+
+func (x *UpdateTimelogRequest) GetTimelog() *TimelogInput {
+	if x != nil {
+		return x.Timelog
+	}
+	return nil
+}
+
+func (x *UpdateTimelogRequest) GetExpectedHeadUid() string {
+	if x != nil {
+		return x.ExpectedHeadUid
+	}
+	return ""
+}
+
+type DeleteTimelogRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Uid             string `protobuf:"bytes,1,opt,name=uid,proto3" json:"uid,omitempty"`
+	ExpectedHeadUid string `protobuf:"bytes,2,opt,name=expected_head_uid,json=expectedHeadUid,proto3" json:"expected_head_uid,omitempty"`
+}
+
+func (x *DeleteTimelogRequest) Reset() {
+	*x = DeleteTimelogRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_scd_v1_timelogs_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteTimelogRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteTimelogRequest) ProtoMessage() {}
+
+func (x *DeleteTimelogRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_scd_v1_timelogs_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteTimelogRequest.ProtoReflect.Descriptor instead.
+func (*DeleteTimelogRequest) Descriptor() ([]byte, []int) {
+	return file_api_scd_v1_timelogs_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *DeleteTimelogRequest) GetUid() string {
+	if x != nil {
+		return x.Uid
+	}
+	return ""
+}
+
+func (x *DeleteTimelogRequest) GetExpectedHeadUid() string {
+	if x != nil {
+		return x.ExpectedHeadUid
+	}
+	return ""
+}
+
+type ListTimelogHistoryRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Uid string `protobuf:"bytes,1,opt,name=uid,proto3" json:"uid,omitempty"`
+}
+
+func (x *ListTimelogHistoryRequest) Reset() {
+	*x = ListTimelogHistoryRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_scd_v1_timelogs_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListTimelogHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTimelogHistoryRequest) ProtoMessage() {}
+
+func (x *ListTimelogHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_scd_v1_timelogs_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTimelogHistoryRequest.ProtoReflect.Descriptor instead.
+func (*ListTimelogHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_api_scd_v1_timelogs_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListTimelogHistoryRequest) GetUid() string {
+	if x != nil {
+		return x.Uid
+	}
+	return ""
+}
+
+type ListContractorTimelogsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ContractorId string `protobuf:"bytes,1,opt,name=contractor_id,json=contractorId,proto3" json:"contractor_id,omitempty"`
+}
+
+func (x *ListContractorTimelogsRequest) Reset() {
+	*x = ListContractorTimelogsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_scd_v1_timelogs_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListContractorTimelogsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListContractorTimelogsRequest) ProtoMessage() {}
+
+func (x *ListContractorTimelogsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_scd_v1_timelogs_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListContractorTimelogsRequest.ProtoReflect.Descriptor instead.
+func (*ListContractorTimelogsRequest) Descriptor() ([]byte, []int) {
+	return file_api_scd_v1_timelogs_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListContractorTimelogsRequest) GetContractorId() string {
+	if x != nil {
+		return x.ContractorId
+	}
+	return ""
+}
+
+type ListTimelogsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Timelogs []*Timelog `protobuf:"bytes,1,rep,name=timelogs,proto3" json:"timelogs,omitempty"`
+}
+
+func (x *ListTimelogsResponse) Reset() {
+	*x = ListTimelogsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_scd_v1_timelogs_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListTimelogsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTimelogsResponse) ProtoMessage() {}
+
+func (x *ListTimelogsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_scd_v1_timelogs_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTimelogsResponse.ProtoReflect.Descriptor instead.
+func (*ListTimelogsResponse) Descriptor() ([]byte, []int) {
+	return file_api_scd_v1_timelogs_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListTimelogsResponse) GetTimelogs() []*Timelog {
+	if x != nil {
+		return x.Timelogs
+	}
+	return nil
+}
+
+var File_api_scd_v1_timelogs_proto protoreflect.FileDescriptor
+
+var file_api_scd_v1_timelogs_proto_rawDesc = []byte{
+	0x0a, 0x19, 0x61, 0x70, 0x69, 0x2f, 0x73, 0x63, 0x64, 0x2f, 0x76, 0x31, 0x2f, 0x74, 0x69, 0x6d,
+	0x65, 0x6c, 0x6f, 0x67, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x06, 0x73, 0x63, 0x64,
+	0x2e, 0x76, 0x31, 0x1a, 0x1b, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2f, 0x65, 0x6d, 0x70, 0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x22, 0xf5, 0x02, 0x0a, 0x07, 0x54, 0x69, 0x6d, 0x65, 0x6c, 0x6f, 0x67, 0x12, 0x0e, 0x0a,
+	0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x10, 0x0a,
+	0x03, 0x75, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x69, 0x64, 0x12,
+	0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x23, 0x0a, 0x0d, 0x63, 0x6f, 0x6e,
+	0x74, 0x72, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0c, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x49, 0x64, 0x12, 0x39,
+	0x0a, 0x0a, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09,
+	0x73, 0x74, 0x61, 0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x35, 0x0a, 0x08, 0x65, 0x6e, 0x64,
+	0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x07, 0x65, 0x6e, 0x64, 0x54, 0x69, 0x6d, 0x65,
+	0x12, 0x21, 0x0a, 0x0c, 0x65, 0x78, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x5f, 0x72, 0x65, 0x66,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x65, 0x78, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c,
+	0x52, 0x65, 0x66, 0x12, 0x39, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61,
+	0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x39,
+	0x0a, 0x0a, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x09, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09,
+	0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x22, 0xc8, 0x01, 0x0a, 0x0c, 0x54, 0x69,
+	0x6d, 0x65, 0x6c, 0x6f, 0x67, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x12, 0x23, 0x0a, 0x0d, 0x63, 0x6f,
+	0x6e, 0x74, 0x72, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0c, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x49, 0x64, 0x12,
+	0x39, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52,
+	0x09, 0x73, 0x74, 0x61, 0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x35, 0x0a, 0x08, 0x65, 0x6e,
+	0x64, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x07, 0x65, 0x6e, 0x64, 0x54, 0x69, 0x6d,
+	0x65, 0x12, 0x21, 0x0a, 0x0c, 0x65, 0x78, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x5f, 0x72, 0x65,
+	0x66, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x65, 0x78, 0x74, 0x65, 0x72, 0x6e, 0x61,
+	0x6c, 0x52, 0x65, 0x66, 0x22, 0x46, 0x0a, 0x14, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x54, 0x69,
+	0x6d, 0x65, 0x6c, 0x6f, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2e, 0x0a, 0x07,
+	0x74, 0x69, 0x6d, 0x65, 0x6c, 0x6f, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e,
+	0x73, 0x63, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x6c, 0x6f, 0x67, 0x49, 0x6e,
+	0x70, 0x75, 0x74, 0x52, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x6c, 0x6f, 0x67, 0x22, 0x56, 0x0a, 0x11,
+	0x47, 0x65, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x6c, 0x6f, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
+	0x75, 0x69, 0x64, 0x12, 0x2f, 0x0a, 0x05, 0x61, 0x73, 0x5f, 0x6f, 0x66, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04,
+	0x61, 0x73, 0x4f, 0x66, 0x22, 0x84, 0x01, 0x0a, 0x14, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x54,
+	0x69, 0x6d, 0x65, 0x6c, 0x6f, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x10, 0x0a,
+	0x03, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x69, 0x64, 0x12,
+	0x2e, 0x0a, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x6c, 0x6f, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x14, 0x2e, 0x73, 0x63, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x6c, 0x6f,
+	0x67, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x52, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x6c, 0x6f, 0x67, 0x12,
+	0x2a, 0x0a, 0x11, 0x65, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x5f, 0x68, 0x65, 0x61, 0x64,
+	0x5f, 0x75, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x65, 0x78, 0x70, 0x65,
+	0x63, 0x74, 0x65, 0x64, 0x48, 0x65, 0x61, 0x64, 0x55, 0x69, 0x64, 0x22, 0x54, 0x0a, 0x14, 0x44,
+	0x65, 0x6c, 0x65, 0x74, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x6c, 0x6f, 0x67, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x03, 0x75, 0x69, 0x64, 0x12, 0x2a, 0x0a, 0x11, 0x65, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65,
+	0x64, 0x5f, 0x68, 0x65, 0x61, 0x64, 0x5f, 0x75, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0f, 0x65, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x48, 0x65, 0x61, 0x64, 0x55, 0x69,
+	0x64, 0x22, 0x2d, 0x0a, 0x19, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x6c, 0x6f, 0x67,
+	0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x10,
+	0x0a, 0x03, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x69, 0x64,
+	0x22, 0x44, 0x0a, 0x1d, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x74,
+	0x6f, 0x72, 0x54, 0x69, 0x6d, 0x65, 0x6c, 0x6f, 0x67, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x23, 0x0a, 0x0d, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x5f,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x61,
+	0x63, 0x74, 0x6f, 0x72, 0x49, 0x64, 0x22, 0x43, 0x0a, 0x14, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x69,
+	0x6d, 0x65, 0x6c, 0x6f, 0x67, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2b,
+	0x0a, 0x08, 0x74, 0x69, 0x6d, 0x65, 0x6c, 0x6f, 0x67, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x0f, 0x2e, 0x73, 0x63, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x6c, 0x6f,
+	0x67, 0x52, 0x08, 0x74, 0x69, 0x6d, 0x65, 0x6c, 0x6f, 0x67, 0x73, 0x32, 0xc7, 0x03, 0x0a, 0x0e,
+	0x54, 0x69, 0x6d, 0x65, 0x6c, 0x6f, 0x67, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x3e,
+	0x0a, 0x0d, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x6c, 0x6f, 0x67, 0x12,
+	0x1c, 0x2e, 0x73, 0x63, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x54,
+	0x69, 0x6d, 0x65, 0x6c, 0x6f, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0f, 0x2e,
+	0x73, 0x63, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x6c, 0x6f, 0x67, 0x12, 0x38,
+	0x0a, 0x0a, 0x47, 0x65, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x6c, 0x6f, 0x67, 0x12, 0x19, 0x2e, 0x73,
+	0x63, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x6c, 0x6f, 0x67,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0f, 0x2e, 0x73, 0x63, 0x64, 0x2e, 0x76, 0x31,
+	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x6c, 0x6f, 0x67, 0x12, 0x3e, 0x0a, 0x0d, 0x55, 0x70, 0x64, 0x61,
+	0x74, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x6c, 0x6f, 0x67, 0x12, 0x1c, 0x2e, 0x73, 0x63, 0x64, 0x2e,
+	0x76, 0x31, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x6c, 0x6f, 0x67,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0f, 0x2e, 0x73, 0x63, 0x64, 0x2e, 0x76, 0x31,
+	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x6c, 0x6f, 0x67, 0x12, 0x45, 0x0a, 0x0d, 0x44, 0x65, 0x6c, 0x65,
+	0x74, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x6c, 0x6f, 0x67, 0x12, 0x1c, 0x2e, 0x73, 0x63, 0x64, 0x2e,
+	0x76, 0x31, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x6c, 0x6f, 0x67,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12,
+	0x55, 0x0a, 0x12, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x6c, 0x6f, 0x67, 0x48, 0x69,
+	0x73, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x21, 0x2e, 0x73, 0x63, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x4c,
+	0x69, 0x73, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x6c, 0x6f, 0x67, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72,
+	0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x73, 0x63, 0x64, 0x2e, 0x76,
+	0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x6c, 0x6f, 0x67, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x5d, 0x0a, 0x16, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x6f,
+	0x6e, 0x74, 0x72, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x54, 0x69, 0x6d, 0x65, 0x6c, 0x6f, 0x67, 0x73,
+	0x12, 0x25, 0x2e, 0x73, 0x63, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x6f,
+	0x6e, 0x74, 0x72, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x54, 0x69, 0x6d, 0x65, 0x6c, 0x6f, 0x67, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x73, 0x63, 0x64, 0x2e, 0x76, 0x31,
+	0x2e, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x6c, 0x6f, 0x67, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x19, 0x5a, 0x17, 0x6d, 0x65, 0x72, 0x63, 0x6f, 0x72, 0x2f,
+	0x61, 0x70, 0x69, 0x2f, 0x73, 0x63, 0x64, 0x2f, 0x76, 0x31, 0x3b, 0x73, 0x63, 0x64, 0x76, 0x31,
+	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_api_scd_v1_timelogs_proto_rawDescOnce sync.Once
+	file_api_scd_v1_timelogs_proto_rawDescData = file_api_scd_v1_timelogs_proto_rawDesc
+)
+
+func file_api_scd_v1_timelogs_proto_rawDescGZIP() []byte {
+	file_api_scd_v1_timelogs_proto_rawDescOnce.Do(func() {
+		file_api_scd_v1_timelogs_proto_rawDescData = protoimpl.X.CompressGZIP(file_api_scd_v1_timelogs_proto_rawDescData)
+	})
+	return file_api_scd_v1_timelogs_proto_rawDescData
+}
+
+var file_api_scd_v1_timelogs_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_api_scd_v1_timelogs_proto_goTypes = []any{
+	(*Timelog)(nil),                       // 0: scd.v1.Timelog
+	(*TimelogInput)(nil),                  // 1: scd.v1.TimelogInput
+	(*CreateTimelogRequest)(nil),          // 2: scd.v1.CreateTimelogRequest
+	(*GetTimelogRequest)(nil),             // 3: scd.v1.GetTimelogRequest
+	(*UpdateTimelogRequest)(nil),          // 4: scd.v1.UpdateTimelogRequest
+	(*DeleteTimelogRequest)(nil),          // 5: scd.v1.DeleteTimelogRequest
+	(*ListTimelogHistoryRequest)(nil),     // 6: scd.v1.ListTimelogHistoryRequest
+	(*ListContractorTimelogsRequest)(nil), // 7: scd.v1.ListContractorTimelogsRequest
+	(*ListTimelogsResponse)(nil),          // 8: scd.v1.ListTimelogsResponse
+	(*timestamppb.Timestamp)(nil),         // 9: google.protobuf.Timestamp
+	(*emptypb.Empty)(nil),                 // 10: google.protobuf.Empty
+}
+var file_api_scd_v1_timelogs_proto_depIdxs = []int32{
+	9,  // 0: scd.v1.Timelog.start_time:type_name -> google.protobuf.Timestamp
+	9,  // 1: scd.v1.Timelog.end_time:type_name -> google.protobuf.Timestamp
+	9,  // 2: scd.v1.Timelog.created_at:type_name -> google.protobuf.Timestamp
+	9,  // 3: scd.v1.Timelog.updated_at:type_name -> google.protobuf.Timestamp
+	9,  // 4: scd.v1.TimelogInput.start_time:type_name -> google.protobuf.Timestamp
+	9,  // 5: scd.v1.TimelogInput.end_time:type_name -> google.protobuf.Timestamp
+	1,  // 6: scd.v1.CreateTimelogRequest.timelog:type_name -> scd.v1.TimelogInput
+	9,  // 7: scd.v1.GetTimelogRequest.as_of:type_name -> google.protobuf.Timestamp
+	1,  // 8: scd.v1.UpdateTimelogRequest.timelog:type_name -> scd.v1.TimelogInput
+	0,  // 9: scd.v1.ListTimelogsResponse.timelogs:type_name -> scd.v1.Timelog
+	2,  // 10: scd.v1.TimelogService.CreateTimelog:input_type -> scd.v1.CreateTimelogRequest
+	3,  // 11: scd.v1.TimelogService.GetTimelog:input_type -> scd.v1.GetTimelogRequest
+	4,  // 12: scd.v1.TimelogService.UpdateTimelog:input_type -> scd.v1.UpdateTimelogRequest
+	5,  // 13: scd.v1.TimelogService.DeleteTimelog:input_type -> scd.v1.DeleteTimelogRequest
+	6,  // 14: scd.v1.TimelogService.ListTimelogHistory:input_type -> scd.v1.ListTimelogHistoryRequest
+	7,  // 15: scd.v1.TimelogService.ListContractorTimelogs:input_type -> scd.v1.ListContractorTimelogsRequest
+	0,  // 16: scd.v1.TimelogService.CreateTimelog:output_type -> scd.v1.Timelog
+	0,  // 17: scd.v1.TimelogService.GetTimelog:output_type -> scd.v1.Timelog
+	0,  // 18: scd.v1.TimelogService.UpdateTimelog:output_type -> scd.v1.Timelog
+	10, // 19: scd.v1.TimelogService.DeleteTimelog:output_type -> google.protobuf.Empty
+	8,  // 20: scd.v1.TimelogService.ListTimelogHistory:output_type -> scd.v1.ListTimelogsResponse
+	8,  // 21: scd.v1.TimelogService.ListContractorTimelogs:output_type -> scd.v1.ListTimelogsResponse
+	16, // [16:22] is the sub-list for method output_type
+	10, // [10:16] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
+}
+
+func init() { file_api_scd_v1_timelogs_proto_init() }
+func file_api_scd_v1_timelogs_proto_init() {
+	if File_api_scd_v1_timelogs_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_api_scd_v1_timelogs_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*Timelog); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_scd_v1_timelogs_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*TimelogInput); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_scd_v1_timelogs_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*CreateTimelogRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_scd_v1_timelogs_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*GetTimelogRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_scd_v1_timelogs_proto_msgTypes[4].Exporter = func(v any, i int) any {
+			switch v := v.(*UpdateTimelogRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_scd_v1_timelogs_proto_msgTypes[5].Exporter = func(v any, i int) any {
+			switch v := v.(*DeleteTimelogRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_scd_v1_timelogs_proto_msgTypes[6].Exporter = func(v any, i int) any {
+			switch v := v.(*ListTimelogHistoryRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_scd_v1_timelogs_proto_msgTypes[7].Exporter = func(v any, i int) any {
+			switch v := v.(*ListContractorTimelogsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_scd_v1_timelogs_proto_msgTypes[8].Exporter = func(v any, i int) any {
+			switch v := v.(*ListTimelogsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_api_scd_v1_timelogs_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   9,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_api_scd_v1_timelogs_proto_goTypes,
+		DependencyIndexes: file_api_scd_v1_timelogs_proto_depIdxs,
+		MessageInfos:      file_api_scd_v1_timelogs_proto_msgTypes,
+	}.Build()
+	File_api_scd_v1_timelogs_proto = out.File
+	file_api_scd_v1_timelogs_proto_rawDesc = nil
+	file_api_scd_v1_timelogs_proto_goTypes = nil
+	file_api_scd_v1_timelogs_proto_depIdxs = nil
+}
@@ -0,0 +1,309 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.4.0
+// - protoc             v5.27.1
+// source: api/scd/v1/timelogs.proto
+
+package scdv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.62.0 or later.
+const _ = grpc.SupportPackageIsVersion8
+
+const (
+	TimelogService_CreateTimelog_FullMethodName          = "/scd.v1.TimelogService/CreateTimelog"
+	TimelogService_GetTimelog_FullMethodName             = "/scd.v1.TimelogService/GetTimelog"
+	TimelogService_UpdateTimelog_FullMethodName          = "/scd.v1.TimelogService/UpdateTimelog"
+	TimelogService_DeleteTimelog_FullMethodName          = "/scd.v1.TimelogService/DeleteTimelog"
+	TimelogService_ListTimelogHistory_FullMethodName     = "/scd.v1.TimelogService/ListTimelogHistory"
+	TimelogService_ListContractorTimelogs_FullMethodName = "/scd.v1.TimelogService/ListContractorTimelogs"
+)
+
+// TimelogServiceClient is the client API for TimelogService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// TimelogService mirrors the /timelogs REST endpoints.
+type TimelogServiceClient interface {
+	CreateTimelog(ctx context.Context, in *CreateTimelogRequest, opts ...grpc.CallOption) (*Timelog, error)
+	GetTimelog(ctx context.Context, in *GetTimelogRequest, opts ...grpc.CallOption) (*Timelog, error)
+	UpdateTimelog(ctx context.Context, in *UpdateTimelogRequest, opts ...grpc.CallOption) (*Timelog, error)
+	// DeleteTimelog voids the timelog by storing a version with an empty
+	// interval.
+	DeleteTimelog(ctx context.Context, in *DeleteTimelogRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ListTimelogHistory(ctx context.Context, in *ListTimelogHistoryRequest, opts ...grpc.CallOption) (*ListTimelogsResponse, error)
+	ListContractorTimelogs(ctx context.Context, in *ListContractorTimelogsRequest, opts ...grpc.CallOption) (*ListTimelogsResponse, error)
+}
+
+type timelogServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTimelogServiceClient(cc grpc.ClientConnInterface) TimelogServiceClient {
+	return &timelogServiceClient{cc}
+}
+
+func (c *timelogServiceClient) CreateTimelog(ctx context.Context, in *CreateTimelogRequest, opts ...grpc.CallOption) (*Timelog, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Timelog)
+	err := c.cc.Invoke(ctx, TimelogService_CreateTimelog_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *timelogServiceClient) GetTimelog(ctx context.Context, in *GetTimelogRequest, opts ...grpc.CallOption) (*Timelog, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Timelog)
+	err := c.cc.Invoke(ctx, TimelogService_GetTimelog_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *timelogServiceClient) UpdateTimelog(ctx context.Context, in *UpdateTimelogRequest, opts ...grpc.CallOption) (*Timelog, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Timelog)
+	err := c.cc.Invoke(ctx, TimelogService_UpdateTimelog_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *timelogServiceClient) DeleteTimelog(ctx context.Context, in *DeleteTimelogRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, TimelogService_DeleteTimelog_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *timelogServiceClient) ListTimelogHistory(ctx context.Context, in *ListTimelogHistoryRequest, opts ...grpc.CallOption) (*ListTimelogsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTimelogsResponse)
+	err := c.cc.Invoke(ctx, TimelogService_ListTimelogHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *timelogServiceClient) ListContractorTimelogs(ctx context.Context, in *ListContractorTimelogsRequest, opts ...grpc.CallOption) (*ListTimelogsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTimelogsResponse)
+	err := c.cc.Invoke(ctx, TimelogService_ListContractorTimelogs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TimelogServiceServer is the server API for TimelogService service.
+// All implementations must embed UnimplementedTimelogServiceServer
+// for forward compatibility
+//
+// TimelogService mirrors the /timelogs REST endpoints.
+type TimelogServiceServer interface {
+	CreateTimelog(context.Context, *CreateTimelogRequest) (*Timelog, error)
+	GetTimelog(context.Context, *GetTimelogRequest) (*Timelog, error)
+	UpdateTimelog(context.Context, *UpdateTimelogRequest) (*Timelog, error)
+	// DeleteTimelog voids the timelog by storing a version with an empty
+	// interval.
+	DeleteTimelog(context.Context, *DeleteTimelogRequest) (*emptypb.Empty, error)
+	ListTimelogHistory(context.Context, *ListTimelogHistoryRequest) (*ListTimelogsResponse, error)
+	ListContractorTimelogs(context.Context, *ListContractorTimelogsRequest) (*ListTimelogsResponse, error)
+	mustEmbedUnimplementedTimelogServiceServer()
+}
+
+// UnimplementedTimelogServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedTimelogServiceServer struct {
+}
+
+func (UnimplementedTimelogServiceServer) CreateTimelog(context.Context, *CreateTimelogRequest) (*Timelog, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateTimelog not implemented")
+}
+func (UnimplementedTimelogServiceServer) GetTimelog(context.Context, *GetTimelogRequest) (*Timelog, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTimelog not implemented")
+}
+func (UnimplementedTimelogServiceServer) UpdateTimelog(context.Context, *UpdateTimelogRequest) (*Timelog, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateTimelog not implemented")
+}
+func (UnimplementedTimelogServiceServer) DeleteTimelog(context.Context, *DeleteTimelogRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteTimelog not implemented")
+}
+func (UnimplementedTimelogServiceServer) ListTimelogHistory(context.Context, *ListTimelogHistoryRequest) (*ListTimelogsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTimelogHistory not implemented")
+}
+func (UnimplementedTimelogServiceServer) ListContractorTimelogs(context.Context, *ListContractorTimelogsRequest) (*ListTimelogsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListContractorTimelogs not implemented")
+}
+func (UnimplementedTimelogServiceServer) mustEmbedUnimplementedTimelogServiceServer() {}
+
+// UnsafeTimelogServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TimelogServiceServer will
+// result in compilation errors.
+type UnsafeTimelogServiceServer interface {
+	mustEmbedUnimplementedTimelogServiceServer()
+}
+
+func RegisterTimelogServiceServer(s grpc.ServiceRegistrar, srv TimelogServiceServer) {
+	s.RegisterService(&TimelogService_ServiceDesc, srv)
+}
+
+func _TimelogService_CreateTimelog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateTimelogRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TimelogServiceServer).CreateTimelog(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TimelogService_CreateTimelog_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TimelogServiceServer).CreateTimelog(ctx, req.(*CreateTimelogRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TimelogService_GetTimelog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTimelogRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TimelogServiceServer).GetTimelog(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TimelogService_GetTimelog_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TimelogServiceServer).GetTimelog(ctx, req.(*GetTimelogRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TimelogService_UpdateTimelog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateTimelogRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TimelogServiceServer).UpdateTimelog(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TimelogService_UpdateTimelog_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TimelogServiceServer).UpdateTimelog(ctx, req.(*UpdateTimelogRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TimelogService_DeleteTimelog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteTimelogRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TimelogServiceServer).DeleteTimelog(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TimelogService_DeleteTimelog_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TimelogServiceServer).DeleteTimelog(ctx, req.(*DeleteTimelogRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TimelogService_ListTimelogHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTimelogHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TimelogServiceServer).ListTimelogHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TimelogService_ListTimelogHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TimelogServiceServer).ListTimelogHistory(ctx, req.(*ListTimelogHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TimelogService_ListContractorTimelogs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListContractorTimelogsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TimelogServiceServer).ListContractorTimelogs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TimelogService_ListContractorTimelogs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TimelogServiceServer).ListContractorTimelogs(ctx, req.(*ListContractorTimelogsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// TimelogService_ServiceDesc is the grpc.ServiceDesc for TimelogService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TimelogService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "scd.v1.TimelogService",
+	HandlerType: (*TimelogServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateTimelog",
+			Handler:    _TimelogService_CreateTimelog_Handler,
+		},
+		{
+			MethodName: "GetTimelog",
+			Handler:    _TimelogService_GetTimelog_Handler,
+		},
+		{
+			MethodName: "UpdateTimelog",
+			Handler:    _TimelogService_UpdateTimelog_Handler,
+		},
+		{
+			MethodName: "DeleteTimelog",
+			Handler:    _TimelogService_DeleteTimelog_Handler,
+		},
+		{
+			MethodName: "ListTimelogHistory",
+			Handler:    _TimelogService_ListTimelogHistory_Handler,
+		},
+		{
+			MethodName: "ListContractorTimelogs",
+			Handler:    _TimelogService_ListContractorTimelogs_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "api/scd/v1/timelogs.proto",
+}
@@ -0,0 +1,310 @@
+// Package client is a typed Go SDK for the SCD backend API.
+//
+// Every write creates a new version of an entity. Versions are addressed by
+// their UID, and a write through any version of an entity applies on top of
+// its head version. To make sure a write is based on the version you read,
+// pass IfMatch(v.ETag()); the call then fails with ErrPreconditionFailed when
+// someone else wrote a newer version first. Modify wraps that read, change
+// and write cycle and retries it on conflicts.
+//
+// Requests are retried on network errors, 429 and 502-504 responses with
+// exponential backoff. Mutating requests carry an Idempotency-Key, so a retry
+// never applies a write twice.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultRetries = 3
+	defaultBackoff = 200 * time.Millisecond
+	// maxBackoff caps the wait between two attempts.
+	maxBackoff = 10 * time.Second
+)
+
+var (
+	ErrNotFound           = errors.New("not found")
+	ErrConflict           = errors.New("conflict")
+	ErrPreconditionFailed = errors.New("precondition failed")
+)
+
+// APIError is returned for every response with a 4xx or 5xx status. It
+// matches ErrNotFound, ErrConflict and ErrPreconditionFailed with errors.Is.
+type APIError struct {
+	StatusCode int
+	Message    string
+	// Fields holds the per-field messages of a failed validation.
+	Fields map[string]string
+	// Body is the raw response body.
+	Body []byte
+}
+
+func (e *APIError) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = http.StatusText(e.StatusCode)
+	}
+	if len(e.Fields) > 0 {
+		parts := make([]string, 0, len(e.Fields))
+		for name, m := range e.Fields {
+			parts = append(parts, name+" "+m)
+		}
+		sort.Strings(parts)
+		msg += ": " + strings.Join(parts, "; ")
+	}
+	return fmt.Sprintf("api error %d: %s", e.StatusCode, msg)
+}
+
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrPreconditionFailed:
+		return e.StatusCode == http.StatusPreconditionFailed
+	}
+	return false
+}
+
+// Client talks to one deployment of the service. It is safe for concurrent
+// use.
+type Client struct {
+	baseURL string
+	http    *http.Client
+	retries int
+	backoff time.Duration
+
+	Companies        *CompaniesService
+	Contractors      *ContractorsService
+	Jobs             *JobsService
+	Timelogs         *TimelogsService
+	PaymentLineItems *PaymentLineItemsService
+	Invoices         *InvoicesService
+	Reconciliation   *ReconciliationService
+}
+
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client used for requests, e.g. to configure
+// timeouts, transports or instrumentation. The default is http.DefaultClient.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.http = hc }
+}
+
+// WithRetries sets how often a failed request is retried and the wait before
+// the first retry, which doubles for every further attempt. Zero retries
+// disables retrying.
+func WithRetries(retries int, backoff time.Duration) Option {
+	return func(c *Client) {
+		c.retries = retries
+		c.backoff = backoff
+	}
+}
+
+// New returns a client for the service at baseURL, e.g.
+// "https://scd.example.com".
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		http:    http.DefaultClient,
+		retries: defaultRetries,
+		backoff: defaultBackoff,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.Companies = &CompaniesService{resource[Company, CompanyInput]{c: c, path: "/companies"}}
+	c.Contractors = &ContractorsService{resource[Contractor, ContractorInput]{c: c, path: "/contractors"}}
+	c.Jobs = &JobsService{resource[Job, JobInput]{c: c, path: "/jobs"}}
+	c.Timelogs = &TimelogsService{resource[Timelog, TimelogInput]{c: c, path: "/timelogs"}}
+	c.PaymentLineItems = &PaymentLineItemsService{resource[PaymentLineItem, PaymentLineItemInput]{c: c, path: "/payment-line-items"}}
+	c.Invoices = &InvoicesService{c: c}
+	c.Reconciliation = &ReconciliationService{c: c}
+	return c
+}
+
+// RequestOption adjusts a single request.
+type RequestOption func(*http.Request)
+
+// IfMatch makes a write conditional on etag being the ETag of the head
+// version, as returned by the ETag method of the entity types.
+func IfMatch(etag string) RequestOption {
+	return func(r *http.Request) { r.Header.Set("If-Match", etag) }
+}
+
+// WithIdempotencyKey sets the Idempotency-Key of a mutating request instead
+// of a generated one, so that it can be retried across processes.
+func WithIdempotencyKey(key string) RequestOption {
+	return func(r *http.Request) { r.Header.Set("Idempotency-Key", key) }
+}
+
+// WithHeader sets an arbitrary request header.
+func WithHeader(name, value string) RequestOption {
+	return func(r *http.Request) { r.Header.Set(name, value) }
+}
+
+type request struct {
+	method      string
+	path        string
+	query       url.Values
+	body        []byte
+	contentType string
+	opts        []RequestOption
+}
+
+func jsonRequest(method, path string, v any, opts []RequestOption) (request, error) {
+	req := request{method: method, path: path, opts: opts}
+	if v != nil {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return req, err
+		}
+		req.body = b
+		req.contentType = "application/json"
+	}
+	return req, nil
+}
+
+// do sends req, retrying it as configured, and decodes a successful response
+// body into out unless out is nil. A *[]byte out receives the raw body.
+func (c *Client) do(ctx context.Context, req request, out any) error {
+	u := c.baseURL + req.path
+	if len(req.query) > 0 {
+		u += "?" + req.query.Encode()
+	}
+	idempotencyKey := ""
+	if mutating(req.method) {
+		idempotencyKey = uuid.NewString()
+	}
+
+	for attempt := 0; ; attempt++ {
+		var body io.Reader
+		if req.body != nil {
+			body = bytes.NewReader(req.body)
+		}
+		hr, err := http.NewRequestWithContext(ctx, req.method, u, body)
+		if err != nil {
+			return err
+		}
+		hr.Header.Set("Accept", "application/json")
+		if req.contentType != "" {
+			hr.Header.Set("Content-Type", req.contentType)
+		}
+		if idempotencyKey != "" {
+			hr.Header.Set("Idempotency-Key", idempotencyKey)
+		}
+		for _, opt := range req.opts {
+			opt(hr)
+		}
+
+		resp, err := c.http.Do(hr)
+		if err != nil {
+			if ctx.Err() != nil || attempt >= c.retries {
+				return err
+			}
+			if err := c.wait(ctx, attempt, 0); err != nil {
+				return err
+			}
+			continue
+		}
+		if retryable(resp.StatusCode) && attempt < c.retries {
+			retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"))
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+			if err := c.wait(ctx, attempt, retryAfter); err != nil {
+				return err
+			}
+			continue
+		}
+		return decode(resp, out)
+	}
+}
+
+func decode(resp *http.Response, out any) error {
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 400 {
+		apiErr := &APIError{StatusCode: resp.StatusCode, Body: b}
+		var body struct {
+			Error  string            `json:"error"`
+			Fields map[string]string `json:"fields"`
+		}
+		if json.Unmarshal(b, &body) == nil {
+			apiErr.Message = body.Error
+			apiErr.Fields = body.Fields
+		}
+		return apiErr
+	}
+	if out == nil || len(b) == 0 {
+		return nil
+	}
+	if raw, ok := out.(*[]byte); ok {
+		*raw = b
+		return nil
+	}
+	if err := json.Unmarshal(b, out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
+
+// wait sleeps before the retry following attempt, for at least retryAfter.
+func (c *Client) wait(ctx context.Context, attempt int, retryAfter time.Duration) error {
+	d := time.Duration(float64(c.backoff) * math.Pow(2, float64(attempt)))
+	if d > maxBackoff {
+		d = maxBackoff
+	}
+	if retryAfter > d {
+		d = retryAfter
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+func retryable(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+func parseRetryAfter(v string) time.Duration {
+	if secs, err := strconv.Atoi(v); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	return 0
+}
+
+func mutating(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
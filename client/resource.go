@@ -0,0 +1,266 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// maxModifyAttempts bounds how often Modify re-reads the head version after
+// losing a race against another writer.
+const maxModifyAttempts = 5
+
+// entity is a version as returned by the API. Input returns the writable
+// fields, so a version can be changed and written back.
+type entity[I any] interface {
+	ETag() string
+	Input() I
+	effectiveAt(t time.Time) bool
+}
+
+// resource implements the operations every versioned entity supports.
+type resource[T entity[I], I any] struct {
+	c    *Client
+	path string
+}
+
+func (r resource[T, I]) uidPath(uid uuid.UUID) string {
+	return r.path + "/" + uid.String()
+}
+
+func (r resource[T, I]) send(ctx context.Context, method, path string, body any, opts []RequestOption, out any) error {
+	req, err := jsonRequest(method, path, body, opts)
+	if err != nil {
+		return err
+	}
+	return r.c.do(ctx, req, out)
+}
+
+// Create stores the first version of a new entity.
+func (r resource[T, I]) Create(ctx context.Context, in I, opts ...RequestOption) (T, error) {
+	var out T
+	err := r.send(ctx, http.MethodPost, r.path, in, opts, &out)
+	return out, err
+}
+
+// Get returns the version with the given UID.
+func (r resource[T, I]) Get(ctx context.Context, uid uuid.UUID, opts ...RequestOption) (T, error) {
+	var out T
+	err := r.send(ctx, http.MethodGet, r.uidPath(uid), nil, opts, &out)
+	return out, err
+}
+
+// GetAsOf returns the version of the entity uid belongs to that was effective
+// at the given time.
+func (r resource[T, I]) GetAsOf(ctx context.Context, uid uuid.UUID, at time.Time, opts ...RequestOption) (T, error) {
+	var out T
+	err := r.c.do(ctx, request{
+		method: http.MethodGet,
+		path:   r.uidPath(uid),
+		query:  url.Values{"as_of": {at.Format(time.RFC3339Nano)}},
+		opts:   opts,
+	}, &out)
+	return out, err
+}
+
+// GetAsKnown returns the version of the entity uid belongs to that was
+// effective at effectiveAt according to what was recorded at knownAt, i.e.
+// before any later back-dated correction.
+func (r resource[T, I]) GetAsKnown(ctx context.Context, uid uuid.UUID, knownAt, effectiveAt time.Time, opts ...RequestOption) (T, error) {
+	var out T
+	err := r.c.do(ctx, request{
+		method: http.MethodGet,
+		path:   r.uidPath(uid),
+		query: url.Values{
+			"as_of":    {effectiveAt.Format(time.RFC3339Nano)},
+			"known_at": {knownAt.Format(time.RFC3339Nano)},
+		},
+		opts: opts,
+	}, &out)
+	return out, err
+}
+
+// History returns every version of the entity uid belongs to, oldest first,
+// including the ones corrected later and the scheduled ones.
+func (r resource[T, I]) History(ctx context.Context, uid uuid.UUID, opts ...RequestOption) ([]T, error) {
+	var out []T
+	err := r.send(ctx, http.MethodGet, r.uidPath(uid)+"/history", nil, opts, &out)
+	return out, err
+}
+
+// Update stores in as the version following the head version of the entity
+// uid belongs to.
+func (r resource[T, I]) Update(ctx context.Context, uid uuid.UUID, in I, opts ...RequestOption) (T, error) {
+	var out T
+	err := r.send(ctx, http.MethodPut, r.uidPath(uid), in, opts, &out)
+	return out, err
+}
+
+// MergePatch applies an RFC 7386 merge patch to the head version, e.g.
+// map[string]any{"rate": 50}.
+func (r resource[T, I]) MergePatch(ctx context.Context, uid uuid.UUID, patch any, opts ...RequestOption) (T, error) {
+	return r.patch(ctx, uid, "application/merge-patch+json", patch, opts)
+}
+
+// JSONPatch applies an RFC 6902 JSON Patch to the head version.
+func (r resource[T, I]) JSONPatch(ctx context.Context, uid uuid.UUID, ops []PatchOperation, opts ...RequestOption) (T, error) {
+	return r.patch(ctx, uid, "application/json-patch+json", ops, opts)
+}
+
+func (r resource[T, I]) patch(ctx context.Context, uid uuid.UUID, contentType string, p any, opts []RequestOption) (T, error) {
+	var out T
+	body, err := json.Marshal(p)
+	if err != nil {
+		return out, err
+	}
+	err = r.c.do(ctx, request{
+		method:      http.MethodPatch,
+		path:        r.uidPath(uid),
+		body:        body,
+		contentType: contentType,
+		opts:        opts,
+	}, &out)
+	return out, err
+}
+
+// Modify reads the head version of the entity uid belongs to, lets change
+// edit its fields and writes the result conditionally on the head being
+// unchanged. If another writer got there first, the cycle starts over with
+// the new head. An error from change aborts Modify.
+func (r resource[T, I]) Modify(ctx context.Context, uid uuid.UUID, change func(in *I) error) (T, error) {
+	var out T
+	var err error
+	for attempt := 0; attempt < maxModifyAttempts; attempt++ {
+		var versions []T
+		versions, err = r.History(ctx, uid)
+		if err != nil {
+			return out, err
+		}
+		head, ok := current(versions)
+		if !ok {
+			return out, &APIError{StatusCode: http.StatusNotFound}
+		}
+		in := head.Input()
+		if err := change(&in); err != nil {
+			return out, err
+		}
+		out, err = r.Update(ctx, uid, in, IfMatch(head.ETag()))
+		if !errors.Is(err, ErrPreconditionFailed) {
+			return out, err
+		}
+	}
+	return out, err
+}
+
+// Revert stores a new version of the entity uid belongs to with the values of
+// its version to, recording the reason. The history is kept as it is.
+func (r resource[T, I]) Revert(ctx context.Context, uid, to uuid.UUID, reason string, opts ...RequestOption) (T, error) {
+	var out T
+	req, err := jsonRequest(http.MethodPost, r.uidPath(uid)+"/revert", map[string]string{"reason": reason}, opts)
+	if err != nil {
+		return out, err
+	}
+	req.query = url.Values{"to": {to.String()}}
+	return out, r.c.do(ctx, req, &out)
+}
+
+// Scheduled lists the versions written to take effect later, soonest first.
+func (r resource[T, I]) Scheduled(ctx context.Context, opts ...RequestOption) ([]T, error) {
+	var out []T
+	err := r.send(ctx, http.MethodGet, r.path+"/scheduled", nil, opts, &out)
+	return out, err
+}
+
+// CancelScheduled withdraws the scheduled version with the given UID before it
+// takes effect. It fails with a 409 *APIError once the version is effective.
+func (r resource[T, I]) CancelScheduled(ctx context.Context, uid uuid.UUID, opts ...RequestOption) (T, error) {
+	var out T
+	err := r.send(ctx, http.MethodDelete, r.path+"/scheduled/"+uid.String(), nil, opts, &out)
+	return out, err
+}
+
+// Batch applies up to 1000 creates and updates in one transaction. In
+// BatchAtomic mode a failed item rejects the whole batch; the returned error
+// is then an *APIError and the results say which items failed. It posts to
+// the collection's :batch path, e.g. /timelogs:batch.
+func (r resource[T, I]) Batch(ctx context.Context, mode BatchMode, ops []BatchOp[I], opts ...RequestOption) ([]BatchResult[T], error) {
+	var out BatchResponse[T]
+	err := r.send(ctx, http.MethodPost, r.path+":batch", BatchRequest[I]{Mode: mode, Items: ops}, opts, &out)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnprocessableEntity {
+		json.Unmarshal(apiErr.Body, &out)
+	}
+	return out.Results, err
+}
+
+// current returns the head version from a history: the last one on record
+// that has taken effect.
+func current[T entity[I], I any](versions []T) (T, bool) {
+	now := time.Now()
+	for i := len(versions) - 1; i >= 0; i-- {
+		if versions[i].effectiveAt(now) {
+			return versions[i], true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+func etag(uid uuid.UUID) string {
+	return `"` + uid.String() + `"`
+}
+
+// PatchOperation is one operation of an RFC 6902 JSON Patch.
+type PatchOperation struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	From  string `json:"from,omitempty"`
+	Value any    `json:"value,omitempty"`
+}
+
+type BatchMode string
+
+const (
+	BatchAtomic   BatchMode = "atomic"
+	BatchContinue BatchMode = "continue"
+)
+
+// BatchOp creates an entity from Data, or with a UID stores Data as the next
+// version of the entity that UID belongs to.
+type BatchOp[I any] struct {
+	Op   string    `json:"op"`
+	UID  uuid.UUID `json:"uid,omitzero"`
+	Data I         `json:"data"`
+}
+
+// CreateOp and UpdateOp build batch operations.
+func CreateOp[I any](in I) BatchOp[I] {
+	return BatchOp[I]{Op: "create", Data: in}
+}
+
+func UpdateOp[I any](uid uuid.UUID, in I) BatchOp[I] {
+	return BatchOp[I]{Op: "update", UID: uid, Data: in}
+}
+
+type BatchRequest[I any] struct {
+	Mode  BatchMode    `json:"mode,omitempty"`
+	Items []BatchOp[I] `json:"items"`
+}
+
+type BatchResult[T any] struct {
+	Index int    `json:"index"`
+	Op    string `json:"op"`
+	OK    bool   `json:"ok"`
+	Item  *T     `json:"item,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+type BatchResponse[T any] struct {
+	Error   string           `json:"error,omitempty"`
+	Results []BatchResult[T] `json:"results"`
+}
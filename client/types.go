@@ -0,0 +1,584 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Periods are the two time axes of a version: when its values applied, and
+// when it was the recorded state. Open ends are nil.
+type Periods struct {
+	EffectiveFrom time.Time  `json:"effectiveFrom"`
+	EffectiveTo   *time.Time `json:"effectiveTo"`
+	RecordedFrom  time.Time  `json:"recordedFrom"`
+	RecordedTo    *time.Time `json:"recordedTo"`
+}
+
+// effectiveAt reports whether the version is on record and has taken effect
+// by t; the last such version is the one writes build on.
+func (p Periods) effectiveAt(t time.Time) bool {
+	return p.RecordedTo == nil && !p.EffectiveFrom.After(t)
+}
+
+// Reversion is set on a version written by Revert: the version whose values
+// it restored and why.
+type Reversion struct {
+	RevertedFrom *uuid.UUID `json:"revertedFrom,omitempty"`
+	RevertReason string     `json:"revertReason,omitempty"`
+}
+
+// Company is one version of a company's settings. Jobs refer to it by its
+// ID.
+type Company struct {
+	ID               uuid.UUID `json:"id"`
+	UID              uuid.UUID `json:"uid"`
+	Version          int       `json:"version"`
+	Name             string    `json:"name"`
+	BillingAddress   string    `json:"billingAddress"`
+	DefaultCurrency  string    `json:"defaultCurrency"`
+	PaymentTermsDays int       `json:"paymentTermsDays"`
+	CreatedAt        time.Time `json:"createdAt"`
+	UpdatedAt        time.Time `json:"updatedAt"`
+	Periods
+	Reversion
+}
+
+// CompanyInput is the complete writable representation of a company.
+// DefaultCurrency is an ISO 4217 code, PaymentTermsDays the days after which
+// an invoice is due. EffectiveFrom back-dates or schedules a change.
+type CompanyInput struct {
+	Name             string     `json:"name"`
+	BillingAddress   string     `json:"billingAddress"`
+	DefaultCurrency  string     `json:"defaultCurrency"`
+	PaymentTermsDays int        `json:"paymentTermsDays"`
+	EffectiveFrom    *time.Time `json:"effectiveFrom,omitempty"`
+}
+
+func (c Company) ETag() string { return etag(c.UID) }
+
+func (c Company) Input() CompanyInput {
+	return CompanyInput{
+		Name:             c.Name,
+		BillingAddress:   c.BillingAddress,
+		DefaultCurrency:  c.DefaultCurrency,
+		PaymentTermsDays: c.PaymentTermsDays,
+	}
+}
+
+// Contractor is one version of a contractor's profile. Jobs, timelogs and
+// payment line items refer to it by its ID.
+type Contractor struct {
+	ID            uuid.UUID `json:"id"`
+	UID           uuid.UUID `json:"uid"`
+	Version       int       `json:"version"`
+	Name          string    `json:"name"`
+	Email         string    `json:"email"`
+	TaxRegion     string    `json:"taxRegion"`
+	PayoutMethod  string    `json:"payoutMethod"`
+	PayoutAccount string    `json:"payoutAccount"`
+	CreatedAt     time.Time `json:"createdAt"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+	Periods
+	Reversion
+}
+
+// ContractorInput is the complete writable representation of a contractor.
+// PayoutMethod is one of bank_transfer, paypal or wise. EffectiveFrom
+// back-dates or schedules a change.
+type ContractorInput struct {
+	Name          string     `json:"name"`
+	Email         string     `json:"email"`
+	TaxRegion     string     `json:"taxRegion"`
+	PayoutMethod  string     `json:"payoutMethod"`
+	PayoutAccount string     `json:"payoutAccount"`
+	EffectiveFrom *time.Time `json:"effectiveFrom,omitempty"`
+}
+
+func (c Contractor) ETag() string { return etag(c.UID) }
+
+func (c Contractor) Input() ContractorInput {
+	return ContractorInput{
+		Name:          c.Name,
+		Email:         c.Email,
+		TaxRegion:     c.TaxRegion,
+		PayoutMethod:  c.PayoutMethod,
+		PayoutAccount: c.PayoutAccount,
+	}
+}
+
+// Job is one version of a job.
+type Job struct {
+	ID           uuid.UUID `json:"id"`
+	UID          uuid.UUID `json:"uid"`
+	Version      int       `json:"version"`
+	Title        string    `json:"title"`
+	Status       string    `json:"status"`
+	Rate         float64   `json:"rate"`
+	Currency     string    `json:"currency"`
+	CompanyID    uuid.UUID `json:"companyId"`
+	ContractorID uuid.UUID `json:"contractorId"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+	Periods
+	Reversion
+}
+
+// JobInput is the complete writable representation of a job. Set
+// EffectiveFrom to back-date a change or, on updates, to schedule it for a
+// later time; by default it applies from now on. An empty Currency takes the
+// default currency of the company, on create and on update alike.
+type JobInput struct {
+	Title         string     `json:"title"`
+	Status        string     `json:"status"`
+	Rate          float64    `json:"rate"`
+	Currency      string     `json:"currency,omitempty"`
+	CompanyID     uuid.UUID  `json:"companyId"`
+	ContractorID  uuid.UUID  `json:"contractorId"`
+	EffectiveFrom *time.Time `json:"effectiveFrom,omitempty"`
+}
+
+func (j Job) ETag() string { return etag(j.UID) }
+
+func (j Job) Input() JobInput {
+	return JobInput{
+		Title:        j.Title,
+		Status:       j.Status,
+		Rate:         j.Rate,
+		Currency:     j.Currency,
+		CompanyID:    j.CompanyID,
+		ContractorID: j.ContractorID,
+	}
+}
+
+// Timelog is one version of a timelog.
+type Timelog struct {
+	ID           uuid.UUID  `json:"id"`
+	UID          uuid.UUID  `json:"uid"`
+	Version      int        `json:"version"`
+	ContractorID uuid.UUID  `json:"contractorId"`
+	JobUID       *uuid.UUID `json:"jobUid,omitempty"`
+	StartTime    time.Time  `json:"startTime"`
+	EndTime      time.Time  `json:"endTime"`
+	ExternalRef  string     `json:"externalRef,omitempty"`
+	// PreviousExternalRef is the reference ExternalRef replaced in place.
+	PreviousExternalRef string    `json:"previousExternalRef,omitempty"`
+	CreatedAt           time.Time `json:"createdAt"`
+	UpdatedAt           time.Time `json:"updatedAt"`
+	Periods
+	Reversion
+}
+
+// TimelogInput is the complete writable representation of a timelog. An
+// update replaces every field, so an empty ExternalRef or a nil JobUID clears
+// the stored one. EffectiveFrom back-dates or schedules a change.
+type TimelogInput struct {
+	ContractorID  uuid.UUID  `json:"contractorId"`
+	JobUID        *uuid.UUID `json:"jobUid,omitempty"`
+	StartTime     time.Time  `json:"startTime"`
+	EndTime       time.Time  `json:"endTime"`
+	ExternalRef   string     `json:"externalRef,omitempty"`
+	EffectiveFrom *time.Time `json:"effectiveFrom,omitempty"`
+}
+
+func (t Timelog) ETag() string { return etag(t.UID) }
+
+func (t Timelog) Input() TimelogInput {
+	return TimelogInput{
+		ContractorID: t.ContractorID,
+		JobUID:       t.JobUID,
+		StartTime:    t.StartTime,
+		EndTime:      t.EndTime,
+		ExternalRef:  t.ExternalRef,
+	}
+}
+
+// PaymentLineItem is one version of a payment line item.
+type PaymentLineItem struct {
+	ID           uuid.UUID  `json:"id"`
+	UID          uuid.UUID  `json:"uid"`
+	Version      int        `json:"version"`
+	ContractorID uuid.UUID  `json:"contractorId"`
+	TimelogUID   *uuid.UUID `json:"timelogUid,omitempty"`
+	Amount       float64    `json:"amount"`
+	IssuedAt     time.Time  `json:"issuedAt"`
+	Status       string     `json:"status"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	UpdatedAt    time.Time  `json:"updatedAt"`
+	Periods
+	Reversion
+}
+
+// PaymentLineItemInput is the complete writable representation of a payment
+// line item. An update replaces every field, so a nil TimelogUID clears the
+// link; line items are pending unless Status is approved. EffectiveFrom
+// back-dates or schedules a change.
+type PaymentLineItemInput struct {
+	ContractorID  uuid.UUID  `json:"contractorId"`
+	TimelogUID    *uuid.UUID `json:"timelogUid,omitempty"`
+	Amount        float64    `json:"amount"`
+	IssuedAt      time.Time  `json:"issuedAt"`
+	Status        string     `json:"status,omitempty"`
+	EffectiveFrom *time.Time `json:"effectiveFrom,omitempty"`
+}
+
+func (p PaymentLineItem) ETag() string { return etag(p.UID) }
+
+func (p PaymentLineItem) Input() PaymentLineItemInput {
+	return PaymentLineItemInput{
+		ContractorID: p.ContractorID,
+		TimelogUID:   p.TimelogUID,
+		Amount:       p.Amount,
+		IssuedAt:     p.IssuedAt,
+		Status:       p.Status,
+	}
+}
+
+// Invoice is one version of an invoice. Lines and Totals are only set on
+// single versions, not in lists.
+type Invoice struct {
+	ID          uuid.UUID      `json:"id"`
+	UID         uuid.UUID      `json:"uid"`
+	Version     int            `json:"version"`
+	Number      string         `json:"number"`
+	CompanyID   uuid.UUID      `json:"companyId"`
+	CompanyUID  uuid.UUID      `json:"companyUid"`
+	PeriodStart time.Time      `json:"periodStart"`
+	PeriodEnd   time.Time      `json:"periodEnd"`
+	Status      string         `json:"status"`
+	IssuedAt    *time.Time     `json:"issuedAt"`
+	DueAt       *time.Time     `json:"dueAt"`
+	PaidAt      *time.Time     `json:"paidAt"`
+	Lines       []InvoiceLine  `json:"lines,omitempty"`
+	Totals      []InvoiceTotal `json:"totals,omitempty"`
+	CreatedAt   time.Time      `json:"createdAt"`
+	UpdatedAt   time.Time      `json:"updatedAt"`
+	Periods
+}
+
+func (i Invoice) ETag() string { return etag(i.UID) }
+
+// InvoiceLine is a payment line item version billed on an invoice.
+type InvoiceLine struct {
+	LineItemUID    uuid.UUID `json:"lineItemUid"`
+	LineItemID     uuid.UUID `json:"lineItemId"`
+	JobUID         uuid.UUID `json:"jobUid"`
+	Description    string    `json:"description"`
+	ContractorID   uuid.UUID `json:"contractorId"`
+	ContractorName string    `json:"contractorName"`
+	IssuedAt       time.Time `json:"issuedAt"`
+	Amount         float64   `json:"amount"`
+	Currency       string    `json:"currency"`
+}
+
+// InvoiceTotal is the sum of the lines of an invoice in one currency.
+type InvoiceTotal struct {
+	Currency string  `json:"currency"`
+	Amount   float64 `json:"amount"`
+}
+
+// Statement lists what a contractor earned and was paid in a period, with
+// running balances per currency.
+type Statement struct {
+	ContractorID   uuid.UUID          `json:"contractorId"`
+	ContractorName string             `json:"contractorName"`
+	From           time.Time          `json:"from"`
+	To             time.Time          `json:"to"`
+	AsOf           *time.Time         `json:"asOf,omitempty"`
+	KnownAt        *time.Time         `json:"knownAt,omitempty"`
+	Entries        []StatementEntry   `json:"entries"`
+	Summary        []StatementSummary `json:"summary"`
+}
+
+// StatementEntry is a timelog (Kind "work") or a payment line item (Kind
+// "payment", Status "issued", "paid" or "voided").
+type StatementEntry struct {
+	Date        time.Time `json:"date"`
+	Kind        string    `json:"kind"`
+	UID         uuid.UUID `json:"uid"`
+	Description string    `json:"description"`
+	Hours       float64   `json:"hours,omitempty"`
+	Rate        float64   `json:"rate,omitempty"`
+	Status      string    `json:"status,omitempty"`
+	Amount      float64   `json:"amount"`
+	Currency    string    `json:"currency"`
+	Balance     float64   `json:"balance"`
+}
+
+// StatementSummary is the movement of the balance in one currency.
+type StatementSummary struct {
+	Currency string  `json:"currency"`
+	Opening  float64 `json:"opening"`
+	Earned   float64 `json:"earned"`
+	Paid     float64 `json:"paid"`
+	Issued   float64 `json:"issued"`
+	Closing  float64 `json:"closing"`
+}
+
+// Reconciliation lists the discrepancies between the hours worked and the
+// amounts paid, with totals per kind and currency.
+type Reconciliation struct {
+	GeneratedAt time.Time             `json:"generatedAt"`
+	Checked     int                   `json:"checked"`
+	Issues      []ReconciliationIssue `json:"issues"`
+	Totals      []ReconciliationTotal `json:"totals"`
+}
+
+// ReconciliationIssue is time paid less or more than it is worth (Kind
+// "underpaid" or "overpaid"), time with no line item ("unpaid_time") or a
+// line item not linked to a current timelog ("orphaned_payment").
+type ReconciliationIssue struct {
+	Kind         string      `json:"kind"`
+	ContractorID uuid.UUID   `json:"contractorId"`
+	JobID        *uuid.UUID  `json:"jobId,omitempty"`
+	JobTitle     string      `json:"jobTitle,omitempty"`
+	TimelogUID   *uuid.UUID  `json:"timelogUid,omitempty"`
+	LineItemUIDs []uuid.UUID `json:"lineItemUids,omitempty"`
+	Date         time.Time   `json:"date"`
+	Hours        float64     `json:"hours,omitempty"`
+	Rate         float64     `json:"rate,omitempty"`
+	Expected     float64     `json:"expected"`
+	Paid         float64     `json:"paid"`
+	Difference   float64     `json:"difference"`
+	Currency     string      `json:"currency"`
+}
+
+// ReconciliationTotal sums the issues of one kind in one currency.
+type ReconciliationTotal struct {
+	Kind     string  `json:"kind"`
+	Currency string  `json:"currency"`
+	Count    int     `json:"count"`
+	Amount   float64 `json:"amount"`
+}
+
+// InvoiceInput selects what an invoice bills: the approved line items of the
+// company issued from PeriodStart up to, but not including, PeriodEnd.
+type InvoiceInput struct {
+	CompanyID   uuid.UUID `json:"companyId"`
+	PeriodStart time.Time `json:"periodStart"`
+	PeriodEnd   time.Time `json:"periodEnd"`
+}
+
+type CompaniesService struct {
+	resource[Company, CompanyInput]
+}
+
+type ContractorsService struct {
+	resource[Contractor, ContractorInput]
+}
+
+// StatementQuery selects a statement: the period [From, To) and, optionally,
+// the time it is produced as of. Set KnownAt to the issue date of a
+// statement to reproduce it as it was issued.
+type StatementQuery struct {
+	From    time.Time
+	To      time.Time
+	AsOf    *time.Time
+	KnownAt *time.Time
+}
+
+func (q StatementQuery) values(format string) url.Values {
+	v := url.Values{
+		"from":   {q.From.Format(time.RFC3339Nano)},
+		"to":     {q.To.Format(time.RFC3339Nano)},
+		"format": {format},
+	}
+	if q.AsOf != nil {
+		v.Set("as_of", q.AsOf.Format(time.RFC3339Nano))
+	}
+	if q.KnownAt != nil {
+		v.Set("known_at", q.KnownAt.Format(time.RFC3339Nano))
+	}
+	return v
+}
+
+// Statement returns the payout statement of a contractor.
+func (s *ContractorsService) Statement(ctx context.Context, contractorID uuid.UUID, q StatementQuery, opts ...RequestOption) (Statement, error) {
+	var out Statement
+	err := s.c.do(ctx, request{
+		method: http.MethodGet,
+		path:   "/contractors/" + contractorID.String() + "/statements",
+		query:  q.values("json"),
+		opts:   opts,
+	}, &out)
+	return out, err
+}
+
+// StatementFile returns the payout statement of a contractor rendered as
+// "csv" or "pdf".
+func (s *ContractorsService) StatementFile(ctx context.Context, contractorID uuid.UUID, q StatementQuery, format string, opts ...RequestOption) ([]byte, error) {
+	var out []byte
+	err := s.c.do(ctx, request{
+		method: http.MethodGet,
+		path:   "/contractors/" + contractorID.String() + "/statements",
+		query:  q.values(format),
+		opts:   opts,
+	}, &out)
+	return out, err
+}
+
+type JobsService struct {
+	resource[Job, JobInput]
+}
+
+// UpdateStatus stores a version of the job with only the status changed.
+func (s *JobsService) UpdateStatus(ctx context.Context, uid uuid.UUID, status string, opts ...RequestOption) (Job, error) {
+	var out Job
+	err := s.c.do(ctx, request{
+		method: http.MethodPut,
+		path:   s.uidPath(uid) + "/status",
+		query:  url.Values{"status": {status}},
+		opts:   opts,
+	}, &out)
+	return out, err
+}
+
+// ListActiveByCompany returns the head versions of the active jobs of a
+// company.
+func (s *JobsService) ListActiveByCompany(ctx context.Context, companyID uuid.UUID, opts ...RequestOption) ([]Job, error) {
+	var out []Job
+	err := s.send(ctx, http.MethodGet, "/companies/"+companyID.String()+"/jobs", nil, opts, &out)
+	return out, err
+}
+
+type TimelogsService struct {
+	resource[Timelog, TimelogInput]
+}
+
+// Delete voids the timelog by storing a version with an empty interval.
+func (s *TimelogsService) Delete(ctx context.Context, uid uuid.UUID, opts ...RequestOption) error {
+	return s.send(ctx, http.MethodDelete, s.uidPath(uid), nil, opts, nil)
+}
+
+// ListByContractor returns the head versions of a contractor's timelogs.
+func (s *TimelogsService) ListByContractor(ctx context.Context, contractorID uuid.UUID, opts ...RequestOption) ([]Timelog, error) {
+	var out []Timelog
+	err := s.send(ctx, http.MethodGet, "/contractors/"+contractorID.String()+"/timelogs", nil, opts, &out)
+	return out, err
+}
+
+// ListByJob returns the head versions of the timelogs linked to any version
+// of the job jobUID belongs to.
+func (s *TimelogsService) ListByJob(ctx context.Context, jobUID uuid.UUID, opts ...RequestOption) ([]Timelog, error) {
+	var out []Timelog
+	err := s.send(ctx, http.MethodGet, "/jobs/"+jobUID.String()+"/timelogs", nil, opts, &out)
+	return out, err
+}
+
+type PaymentLineItemsService struct {
+	resource[PaymentLineItem, PaymentLineItemInput]
+}
+
+// Delete voids the line item by storing a version with a zero amount.
+func (s *PaymentLineItemsService) Delete(ctx context.Context, uid uuid.UUID, opts ...RequestOption) error {
+	return s.send(ctx, http.MethodDelete, s.uidPath(uid), nil, opts, nil)
+}
+
+// ListByContractor returns the head versions of a contractor's line items.
+func (s *PaymentLineItemsService) ListByContractor(ctx context.Context, contractorID uuid.UUID, opts ...RequestOption) ([]PaymentLineItem, error) {
+	var out []PaymentLineItem
+	err := s.send(ctx, http.MethodGet, "/contractors/"+contractorID.String()+"/payment-line-items", nil, opts, &out)
+	return out, err
+}
+
+// ListByTimelog returns the head versions of the line items linked to any
+// version of the timelog timelogUID belongs to.
+func (s *PaymentLineItemsService) ListByTimelog(ctx context.Context, timelogUID uuid.UUID, opts ...RequestOption) ([]PaymentLineItem, error) {
+	var out []PaymentLineItem
+	err := s.send(ctx, http.MethodGet, "/timelogs/"+timelogUID.String()+"/payment-line-items", nil, opts, &out)
+	return out, err
+}
+
+// InvoicesService generates invoices and moves them through their statuses.
+// Invoices are not written directly, so it offers fewer operations than the
+// other services.
+type InvoicesService struct {
+	c *Client
+}
+
+// Generate stores a draft invoice for the approved line items in the period
+// that are not on another invoice.
+func (s *InvoicesService) Generate(ctx context.Context, in InvoiceInput, opts ...RequestOption) (Invoice, error) {
+	var out Invoice
+	req, err := jsonRequest(http.MethodPost, "/invoices", in, opts)
+	if err != nil {
+		return out, err
+	}
+	err = s.c.do(ctx, req, &out)
+	return out, err
+}
+
+// Get returns the invoice version with the given UID, with its lines.
+func (s *InvoicesService) Get(ctx context.Context, uid uuid.UUID, opts ...RequestOption) (Invoice, error) {
+	var out Invoice
+	err := s.c.do(ctx, request{method: http.MethodGet, path: "/invoices/" + uid.String(), opts: opts}, &out)
+	return out, err
+}
+
+// History returns every version of the invoice uid belongs to, oldest first.
+func (s *InvoicesService) History(ctx context.Context, uid uuid.UUID, opts ...RequestOption) ([]Invoice, error) {
+	var out []Invoice
+	err := s.c.do(ctx, request{method: http.MethodGet, path: "/invoices/" + uid.String() + "/history", opts: opts}, &out)
+	return out, err
+}
+
+// UpdateStatus stores a version of the invoice in the given status: issued,
+// paid or void.
+func (s *InvoicesService) UpdateStatus(ctx context.Context, uid uuid.UUID, status string, opts ...RequestOption) (Invoice, error) {
+	var out Invoice
+	err := s.c.do(ctx, request{
+		method: http.MethodPut,
+		path:   "/invoices/" + uid.String() + "/status",
+		query:  url.Values{"status": {status}},
+		opts:   opts,
+	}, &out)
+	return out, err
+}
+
+// PDF returns the invoice version rendered as a PDF document.
+func (s *InvoicesService) PDF(ctx context.Context, uid uuid.UUID, opts ...RequestOption) ([]byte, error) {
+	var out []byte
+	err := s.c.do(ctx, request{method: http.MethodGet, path: "/invoices/" + uid.String() + "/pdf", opts: opts}, &out)
+	return out, err
+}
+
+// ListByCompany returns the head versions of the invoices of a company,
+// newest first.
+func (s *InvoicesService) ListByCompany(ctx context.Context, companyID uuid.UUID, opts ...RequestOption) ([]Invoice, error) {
+	var out []Invoice
+	err := s.c.do(ctx, request{method: http.MethodGet, path: "/companies/" + companyID.String() + "/invoices", opts: opts}, &out)
+	return out, err
+}
+
+// ReconciliationService reconciles the hours worked with the amounts paid.
+type ReconciliationService struct {
+	c *Client
+}
+
+// ReconciliationQuery narrows a reconciliation to one contractor, one
+// company's jobs or both. The zero value reconciles everything.
+type ReconciliationQuery struct {
+	ContractorID *uuid.UUID
+	CompanyID    *uuid.UUID
+}
+
+func (q ReconciliationQuery) values() url.Values {
+	v := url.Values{}
+	if q.ContractorID != nil {
+		v.Set("contractor_id", q.ContractorID.String())
+	}
+	if q.CompanyID != nil {
+		v.Set("company_id", q.CompanyID.String())
+	}
+	return v
+}
+
+// Report compares the current timelogs with the current payment line items.
+func (s *ReconciliationService) Report(ctx context.Context, q ReconciliationQuery, opts ...RequestOption) (Reconciliation, error) {
+	var out Reconciliation
+	err := s.c.do(ctx, request{method: http.MethodGet, path: "/reconciliation", query: q.values(), opts: opts}, &out)
+	return out, err
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"mercor/internal/db"
+)
+
+// runBackfill implements `backfill`, the one-off migration that records the
+// companies and contractors rows written before they had records refer to:
+//
+//	go run ./cmd backfill -dry-run
+//
+// The records get empty fields, to be filled in with an update. The IDs
+// recorded are written to stdout as JSON.
+func runBackfill(args []string) int {
+	fs := flag.NewFlagSet("backfill", flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "report the IDs without recording them")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	done, err := db.BackfillReferences(db.Connect(), *dryRun)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "backfill: %v\n", err)
+		return 1
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(done); err != nil {
+		fmt.Fprintf(os.Stderr, "backfill: %v\n", err)
+		return 1
+	}
+	return 0
+}
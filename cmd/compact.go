@@ -0,0 +1,72 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"mercor/internal/db"
+	"mercor/internal/domain/retention"
+	"mercor/internal/scd"
+)
+
+// runCompact implements `compact`, e.g. to preview a retention policy:
+//
+//	go run ./cmd compact -retain jobs=2y -dry-run
+//
+// Removed versions go to the archive table or, with -archive, to an NDJSON
+// file. The report is written to stdout as JSON.
+func runCompact(args []string) int {
+	fs := flag.NewFlagSet("compact", flag.ContinueOnError)
+	entity := fs.String("entity", "", "comma separated "+strings.Join(retention.Names(), ", ")+" (default all)")
+	retain := fs.String("retain", retention.DefaultPolicies.String(), "retention per entity, e.g. jobs=3y,timelogs=keep")
+	dryRun := fs.Bool("dry-run", false, "only report what would be removed")
+	archive := fs.String("archive", "", "NDJSON file to archive removed versions to (default the archive table)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	policies, err := retention.ParsePolicies(*retain)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "compact: %v\n", err)
+		return 2
+	}
+	var entities []string
+	if *entity != "" {
+		entities = strings.Split(*entity, ",")
+		for _, name := range entities {
+			if _, ok := retention.Entities[name]; !ok {
+				fmt.Fprintf(os.Stderr, "compact: -entity must be among %v\n", retention.Names())
+				return 2
+			}
+		}
+	}
+
+	opts := scd.CompactOptions{DryRun: *dryRun}
+	if *archive != "" && !*dryRun {
+		f, err := os.OpenFile(*archive, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "compact: %v\n", err)
+			return 1
+		}
+		// Unbuffered, so every version is in the file before its data is
+		// cleared from the archive table.
+		defer f.Close()
+		opts.File, opts.Location = f, *archive
+	}
+
+	rep, err := retention.Run(db.Connect(), policies, entities, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "compact: %v\n", err)
+		return 1
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(rep); err != nil {
+		fmt.Fprintf(os.Stderr, "compact: %v\n", err)
+		return 1
+	}
+	return 0
+}
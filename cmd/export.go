@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"mercor/internal/db"
+	"mercor/internal/domain/exports"
+	"mercor/internal/export"
+)
+
+// runExport implements `export`, e.g. for nightly dumps:
+//
+//	go run ./cmd export -entity jobs -format parquet -scope history -out jobs.parquet
+func runExport(args []string) int {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	entity := fs.String("entity", "", "jobs, timelogs or payment-line-items")
+	format := fs.String("format", string(export.FormatCSV), "csv, ndjson or parquet")
+	scope := fs.String("scope", string(export.ScopeCurrent), "current, history or as_of")
+	asOf := fs.String("as-of", "", "RFC 3339 time for -scope as_of")
+	out := fs.String("out", "", "output file (default stdout)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	write, ok := exports.Entities[*entity]
+	if !ok {
+		names := make([]string, 0, len(exports.Entities))
+		for name := range exports.Entities {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		fmt.Fprintf(os.Stderr, "export: -entity must be one of %v\n", names)
+		return 2
+	}
+	opts := export.Options{Format: export.Format(*format), Scope: export.Scope(*scope)}
+	if *asOf != "" {
+		at, err := time.Parse(time.RFC3339, *asOf)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "export: invalid -as-of: %v\n", err)
+			return 2
+		}
+		opts.AsOf = at
+	}
+	if err := opts.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "export: %v\n", err)
+		return 2
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "export: %v\n", err)
+			return 1
+		}
+		defer f.Close()
+		w = f
+	}
+	bw := bufio.NewWriter(w)
+	if err := write(db.Connect(), bw, opts); err != nil {
+		fmt.Fprintf(os.Stderr, "export: %v\n", err)
+		return 1
+	}
+	if err := bw.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "export: %v\n", err)
+		return 1
+	}
+	return 0
+}
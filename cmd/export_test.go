@@ -0,0 +1,38 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportCommand(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "jobs.ndjson")
+	if !assert.Equal(t, 0, runExport([]string{"-entity", "jobs", "-format", "ndjson", "-scope", "history", "-out", out})) {
+		return
+	}
+	f, err := os.Open(out)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		var row map[string]any
+		if !assert.NoError(t, json.Unmarshal(scanner.Bytes(), &row)) {
+			return
+		}
+		assert.Contains(t, row, "uid")
+		assert.Contains(t, row, "valid_from")
+	}
+	assert.NoError(t, scanner.Err())
+
+	// Bad flags are reported before connecting.
+	assert.Equal(t, 2, runExport([]string{"-entity", "unknown"}))
+	assert.Equal(t, 2, runExport([]string{"-entity", "jobs", "-scope", "as_of"}))
+}
@@ -1,12 +1,98 @@
 package main
 
 import (
+	"context"
+	"flag"
+	"log"
+	"net"
+	"os"
+	"time"
+
+	"mercor/internal/domain/retention"
 	router "mercor/internal/domain/router"
+	"mercor/internal/rpc"
+	"mercor/internal/scd"
 	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "export" {
+		os.Exit(runExport(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "reconcile" {
+		os.Exit(runReconcile(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "verify" {
+		os.Exit(runVerify(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "compact" {
+		os.Exit(runCompact(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "partition" {
+		os.Exit(runPartition(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "backfill" {
+		os.Exit(runBackfill(os.Args[2:]))
+	}
+
+	httpAddr := flag.String("http", ":8080", "address of the REST API")
+	grpcAddr := flag.String("grpc", ":9090", "address of the gRPC API")
+	retain := flag.String("retain", retention.DefaultPolicies.String(), "retention per entity, e.g. jobs=3y,timelogs=keep")
+	compactEvery := flag.Duration("compact-every", 0, "interval of the background compaction (0 disables it)")
+	flag.Parse()
+
+	policies, err := retention.ParsePolicies(*retain)
+	if err != nil {
+		log.Fatalf("invalid -retain: %v", err)
+	}
+	services := router.NewServices()
+	services.Retention = policies
+	if n, err := services.Imports.Recover(); err != nil {
+		log.Fatalf("settling interrupted imports failed: %v", err)
+	} else if n > 0 {
+		log.Printf("imports: settled %d interrupted by the last stop", n)
+	}
+	// Split tables need a history partition for every month to come.
+	addPartitions := func() error {
+		made, err := retention.Partition(services.DB, nil, false)
+		for _, p := range made {
+			if len(p.Partitions) > 0 {
+				log.Printf("partitions: added %v to %s", p.Partitions, p.Table)
+			}
+		}
+		return err
+	}
+	if err := addPartitions(); err != nil {
+		log.Fatalf("adding history partitions failed: %v", err)
+	}
+	go scd.RunEvery(context.Background(), "partitions", 24*time.Hour, addPartitions)
+	if *compactEvery > 0 {
+		go scd.RunEvery(context.Background(), "compaction", *compactEvery, func() error {
+			rep, err := retention.Run(services.DB, policies, nil, scd.CompactOptions{})
+			for _, c := range rep.Compactions {
+				log.Printf("compaction: removed %d versions of %d %s, %d held", c.Removed, c.Entities, c.Table, c.Held)
+			}
+			return err
+		})
+	}
+
+	gs := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(rpc.RecoverUnary),
+		grpc.ChainStreamInterceptor(rpc.RecoverStream),
+	)
+	router.RegisterGRPC(gs, services)
+	lis, err := net.Listen("tcp", *grpcAddr)
+	if err != nil {
+		log.Fatalf("failed to listen on %s: %v", *grpcAddr, err)
+	}
+	go func() {
+		if err := gs.Serve(lis); err != nil {
+			log.Fatalf("gRPC server stopped: %v", err)
+		}
+	}()
+
 	r := gin.Default()
-	router.InitRoutes(r)
-	r.Run(":8080")
+	router.RegisterRoutes(r, services)
+	r.Run(*httpAddr)
 }
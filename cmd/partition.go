@@ -0,0 +1,51 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"mercor/internal/db"
+	"mercor/internal/domain/retention"
+)
+
+// runPartition implements `partition`, which splits the versioned tables of
+// the largest entities into current and monthly history partitions:
+//
+//	go run ./cmd partition -entity jobs,timelogs
+//
+// The server adds the partitions of the months to come to split tables. The
+// partitions made are written to stdout as JSON.
+func runPartition(args []string) int {
+	fs := flag.NewFlagSet("partition", flag.ContinueOnError)
+	entity := fs.String("entity", "", "comma separated "+strings.Join(retention.Names(), ", ")+" (default all)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	var entities []string
+	if *entity != "" {
+		entities = strings.Split(*entity, ",")
+		for _, name := range entities {
+			if _, ok := retention.Partitioners[name]; !ok {
+				fmt.Fprintf(os.Stderr, "partition: -entity must be among %v\n", retention.Names())
+				return 2
+			}
+		}
+	}
+
+	made, err := retention.Partition(db.Connect(), entities, true)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "partition: %v\n", err)
+		return 1
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(made); err != nil {
+		fmt.Fprintf(os.Stderr, "partition: %v\n", err)
+		return 1
+	}
+	return 0
+}
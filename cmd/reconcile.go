@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/google/uuid"
+	"mercor/internal/db"
+	"mercor/internal/domain/reconciliation"
+)
+
+// runReconcile implements `reconcile`, e.g. before a payout run:
+//
+//	go run ./cmd reconcile -company <id> -format csv -out reconciliation.csv
+//
+// It exits with 3 when the report has issues, so scripts can act on them.
+func runReconcile(args []string) int {
+	fs := flag.NewFlagSet("reconcile", flag.ContinueOnError)
+	contractor := fs.String("contractor", "", "only this contractor ID")
+	company := fs.String("company", "", "only the jobs of this company ID")
+	format := fs.String("format", reconciliation.FormatText, "text, csv or json")
+	out := fs.String("out", "", "output file (default stdout)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	var f reconciliation.Filter
+	for name, flagged := range map[string]struct {
+		value string
+		dst   **uuid.UUID
+	}{"contractor": {*contractor, &f.ContractorID}, "company": {*company, &f.CompanyID}} {
+		if flagged.value == "" {
+			continue
+		}
+		id, err := uuid.Parse(flagged.value)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "reconcile: invalid -%s: %v\n", name, err)
+			return 2
+		}
+		*flagged.dst = &id
+	}
+	var write func(reconciliation.Report, io.Writer) error
+	switch *format {
+	case reconciliation.FormatText:
+		write = reconciliation.Report.WriteText
+	case reconciliation.FormatCSV:
+		write = reconciliation.Report.WriteCSV
+	case reconciliation.FormatJSON:
+		write = func(r reconciliation.Report, w io.Writer) error {
+			enc := json.NewEncoder(w)
+			enc.SetIndent("", "  ")
+			return enc.Encode(r)
+		}
+	default:
+		fmt.Fprintln(os.Stderr, "reconcile: -format must be text, csv or json")
+		return 2
+	}
+
+	svc := reconciliation.NewService(reconciliation.NewRepository(db.Connect()))
+	rep, err := svc.Reconcile(f)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "reconcile: %v\n", err)
+		return 1
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		file, err := os.Create(*out)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "reconcile: %v\n", err)
+			return 1
+		}
+		defer file.Close()
+		w = file
+	}
+	bw := bufio.NewWriter(w)
+	if err := write(rep, bw); err != nil {
+		fmt.Fprintf(os.Stderr, "reconcile: %v\n", err)
+		return 1
+	}
+	if err := bw.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "reconcile: %v\n", err)
+		return 1
+	}
+	if len(rep.Issues) > 0 {
+		return 3
+	}
+	return 0
+}
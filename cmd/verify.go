@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"mercor/internal/db"
+	"mercor/internal/domain/verify"
+)
+
+// runVerify implements `verify`, e.g. as a nightly health check:
+//
+//	go run ./cmd verify -entity jobs,timelogs -format json -out verify.json
+//
+// It exits with 3 when violations remain after any repair.
+func runVerify(args []string) int {
+	fs := flag.NewFlagSet("verify", flag.ContinueOnError)
+	entity := fs.String("entity", "", "comma separated "+strings.Join(verify.Names(), ", ")+" (default all)")
+	repair := fs.Bool("repair", false, "repair the safe violations")
+	format := fs.String("format", "text", "text or json")
+	out := fs.String("out", "", "output file (default stdout)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	var entities []string
+	if *entity != "" {
+		entities = strings.Split(*entity, ",")
+		for _, name := range entities {
+			if _, ok := verify.Entities[name]; !ok {
+				fmt.Fprintf(os.Stderr, "verify: -entity must be among %v\n", verify.Names())
+				return 2
+			}
+		}
+	}
+	if *format != "text" && *format != "json" {
+		fmt.Fprintln(os.Stderr, "verify: -format must be text or json")
+		return 2
+	}
+
+	rep, err := verify.Run(db.Connect(), entities, *repair)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "verify: %v\n", err)
+		return 1
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "verify: %v\n", err)
+			return 1
+		}
+		defer f.Close()
+		w = f
+	}
+	bw := bufio.NewWriter(w)
+	if *format == "json" {
+		enc := json.NewEncoder(bw)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(rep)
+	} else {
+		err = rep.WriteText(bw)
+	}
+	if err == nil {
+		err = bw.Flush()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "verify: %v\n", err)
+		return 1
+	}
+	if rep.Remaining > 0 {
+		return 3
+	}
+	return 0
+}
@@ -0,0 +1,83 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"mercor/internal/domain/companies"
+	"mercor/internal/domain/contractors"
+	"mercor/internal/scd"
+)
+
+// Backfilled lists the IDs a backfill recorded, or would record, in Table.
+type Backfilled struct {
+	Table string      `json:"table"`
+	IDs   []uuid.UUID `json:"ids"`
+}
+
+// BackfillReferences records a company or contractor for every ID that rows
+// written before companies and contractors had records of their own refer
+// to. It is a one-off migration, run with `go run ./cmd backfill`; with
+// dryRun it only reports what it would record.
+func BackfillReferences(db *gorm.DB, dryRun bool) ([]Backfilled, error) {
+	var done []Backfilled
+	err := db.Transaction(func(tx *gorm.DB) error {
+		companyIDs, err := backfillReferenced(tx, dryRun, "company_id", []string{"jobs"}, func(id uuid.UUID, first time.Time) companies.Company {
+			return companies.Company{ID: id, UID: uuid.New(), Version: 1, Bitemporal: scd.Bitemporal{EffectiveFrom: first}}
+		})
+		if err != nil {
+			return err
+		}
+		tables := []string{"jobs", "timelogs", "payment_line_items"}
+		contractorIDs, err := backfillReferenced(tx, dryRun, "contractor_id", tables, func(id uuid.UUID, first time.Time) contractors.Contractor {
+			return contractors.Contractor{ID: id, UID: uuid.New(), Version: 1, Bitemporal: scd.Bitemporal{EffectiveFrom: first}}
+		})
+		if err != nil {
+			return err
+		}
+		done = []Backfilled{
+			{Table: companies.Company{}.TableName(), IDs: companyIDs},
+			{Table: contractors.Contractor{}.TableName(), IDs: contractorIDs},
+		}
+		return nil
+	})
+	return done, err
+}
+
+// backfillReferenced stores the version newRow builds for every ID in column
+// of tables that is not on record in T's table, effective from its first
+// reference, and returns those IDs. Its other fields are left empty, to be
+// filled in with an update; until then the references stay valid.
+func backfillReferenced[T scd.SCDModel[T]](db *gorm.DB, dryRun bool, column string, tables []string, newRow func(id uuid.UUID, first time.Time) T) ([]uuid.UUID, error) {
+	var target T
+	refs := make([]string, len(tables))
+	for i, table := range tables {
+		refs[i] = fmt.Sprintf("SELECT %s AS ref, created_at FROM %s", column, table)
+	}
+	var missing []struct {
+		Ref   uuid.UUID
+		First time.Time
+	}
+	err := db.Raw(fmt.Sprintf(`SELECT ref, MIN(created_at) AS first FROM (%s) refs
+		WHERE ref NOT IN (SELECT id FROM %s)
+		GROUP BY ref ORDER BY ref`, strings.Join(refs, " UNION ALL "), target.TableName())).Scan(&missing).Error
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uuid.UUID, 0, len(missing))
+	m := scd.NewManager[T](db)
+	for _, r := range missing {
+		if !dryRun {
+			row := newRow(r.Ref, r.First)
+			if err := m.Insert(&row); err != nil {
+				return nil, err
+			}
+		}
+		ids = append(ids, r.Ref)
+	}
+	return ids, nil
+}
@@ -4,7 +4,13 @@ import (
   "log"
   "gorm.io/driver/postgres"
 
+  "mercor/internal/domain/companies"
+  "mercor/internal/domain/contractors"
+  "mercor/internal/domain/imports"
+  "mercor/internal/domain/invoices"
   jobs "mercor/internal/domain/jobs"
+  "mercor/internal/idempotency"
+  "mercor/internal/scd"
   timelog "mercor/internal/domain/timelog"
   paymentLineItem "mercor/internal/domain/paymentLineItem"
   "gorm.io/gorm"
@@ -18,13 +24,69 @@ func Connect() *gorm.DB {
 	}
 
 	err = db.AutoMigrate(
+		&companies.Company{},
+		&contractors.Contractor{},
 		&jobs.Job{},
 		&timelog.Timelog{},
 		&paymentLineItem.PaymentLineItem{},
+		&invoices.Invoice{},
+		&invoices.Line{},
+		&invoices.Counter{},
+		&imports.Import{},
+		&idempotency.Record{},
+		&scd.ArchivedVersion{},
+		&scd.LegalHold{},
 	)
 	if err != nil {
 		log.Fatalf("Auto migration failed: %v", err)
 	}
+	if err := backfillPeriods(db); err != nil {
+		log.Fatalf("Backfilling effective and recorded periods failed: %v", err)
+	}
+	if err := createIndexes(db); err != nil {
+		log.Fatalf("Creating indexes failed: %v", err)
+	}
 
 	return db
 }
+
+// backfillPeriods derives the bitemporal columns of versions written before
+// they existed.
+func backfillPeriods(db *gorm.DB) error {
+	if err := scd.NewManager[companies.Company](db).BackfillPeriods(); err != nil {
+		return err
+	}
+	if err := scd.NewManager[contractors.Contractor](db).BackfillPeriods(); err != nil {
+		return err
+	}
+	if err := scd.NewManager[jobs.Job](db).BackfillPeriods(); err != nil {
+		return err
+	}
+	if err := scd.NewManager[timelog.Timelog](db).BackfillPeriods(); err != nil {
+		return err
+	}
+	if err := scd.NewManager[paymentLineItem.PaymentLineItem](db).BackfillPeriods(); err != nil {
+		return err
+	}
+	return scd.NewManager[invoices.Invoice](db).BackfillPeriods()
+}
+
+// createIndexes creates the indexes of the versioned tables.
+func createIndexes(db *gorm.DB) error {
+	if err := scd.NewManager[companies.Company](db).CreateIndexes(); err != nil {
+		return err
+	}
+	if err := scd.NewManager[contractors.Contractor](db).CreateIndexes(); err != nil {
+		return err
+	}
+	if err := scd.NewManager[jobs.Job](db).CreateIndexes(); err != nil {
+		return err
+	}
+	if err := scd.NewManager[timelog.Timelog](db).CreateIndexes(); err != nil {
+		return err
+	}
+	if err := scd.NewManager[paymentLineItem.PaymentLineItem](db).CreateIndexes(); err != nil {
+		return err
+	}
+	return scd.NewManager[invoices.Invoice](db).CreateIndexes()
+}
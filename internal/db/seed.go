@@ -4,6 +4,8 @@ import (
 	"log"
 	"time"
 
+	"mercor/internal/domain/companies"
+	"mercor/internal/domain/contractors"
 	jobs "mercor/internal/domain/jobs"
 	paymentLineItem "mercor/internal/domain/paymentLineItem"
 	timelog"mercor/internal/domain/timelog"
@@ -13,6 +15,49 @@ import (
 )
 
 func Seed(db *gorm.DB) {
+	// -------- SEED COMPANIES ----------
+	company := companies.Company{
+		ID:               uuid.MustParse("bbbbbbbb-bbbb-bbbb-bbbb-bbbbbbbbbbbb"), // comp_cab5i8o0rvh5arskod
+		Version:          1,
+		UID:              uuid.MustParse("b0000000-0000-0000-0000-000000000001"),
+		Name:             "Acme Corp",
+		BillingAddress:   "1 Market St, San Francisco, CA 94105, US",
+		DefaultCurrency:  "USD",
+		PaymentTermsDays: 30,
+	}
+	if err := db.Create(&company).Error; err != nil {
+		log.Fatalf("❌ Failed to seed company %v: %v", company.UID, err)
+	}
+
+	// -------- SEED CONTRACTORS ----------
+	contractorsToSeed := []contractors.Contractor{
+		{
+			ID:            uuid.MustParse("cccccccc-cccc-cccc-cccc-cccccccccccc"), // cont_e0nhseq682vkoc4d
+			Version:       1,
+			UID:           uuid.MustParse("c0000000-0000-0000-0000-000000000001"),
+			Name:          "Ada Lovelace",
+			Email:         "ada@example.com",
+			TaxRegion:     "GB",
+			PayoutMethod:  "bank_transfer",
+			PayoutAccount: "GB29NWBK60161331926819",
+		},
+		{
+			ID:            uuid.MustParse("eeeeeeee-eeee-eeee-eeee-eeeeeeeeeeee"), // cont_aezrtdqy9kpdvnhuml
+			Version:       1,
+			UID:           uuid.MustParse("c0000000-0000-0000-0000-000000000002"),
+			Name:          "Grace Hopper",
+			Email:         "grace@example.com",
+			TaxRegion:     "US-NY",
+			PayoutMethod:  "paypal",
+			PayoutAccount: "grace@example.com",
+		},
+	}
+	for _, c := range contractorsToSeed {
+		if err := db.Create(&c).Error; err != nil {
+			log.Fatalf("❌ Failed to seed contractor %v: %v", c.UID, err)
+		}
+	}
+
 	// -------- SEED JOBS ----------
 	jobsToSeed := []jobs.Job{
 		{
@@ -21,6 +66,7 @@ func Seed(db *gorm.DB) {
 			UID:          uuid.MustParse("00000000-0000-0000-0000-000000000001"), // job_uid_tm15dj18wal295r3xiea
 			Status:       "extended",
 			Rate:         20.0,
+			Currency:     "USD",
 			Title:        "Software Engineer",
 			CompanyID:    uuid.MustParse("bbbbbbbb-bbbb-bbbb-bbbb-bbbbbbbbbbbb"), // comp_cab5i8o0rvh5arskod
 			ContractorID: uuid.MustParse("cccccccc-cccc-cccc-cccc-cccccccccccc"), // cont_e0nhseq682vkoc4d
@@ -31,6 +77,7 @@ func Seed(db *gorm.DB) {
 			UID:          uuid.MustParse("00000000-0000-0000-0000-000000000002"), // job_uid_ae51ppj9jpt56he2ua3
 			Status:       "active",
 			Rate:         20.0,
+			Currency:     "USD",
 			Title:        "Software Engineer",
 			CompanyID:    uuid.MustParse("bbbbbbbb-bbbb-bbbb-bbbb-bbbbbbbbbbbb"),
 			ContractorID: uuid.MustParse("cccccccc-cccc-cccc-cccc-cccccccccccc"),
@@ -41,6 +88,7 @@ func Seed(db *gorm.DB) {
 			UID:          uuid.MustParse("00000000-0000-0000-0000-000000000003"), // job_uid_ywij5sh1tvfp5nkq7azav
 			Status:       "active",
 			Rate:         15.5,
+			Currency:     "USD",
 			Title:        "Software Engineer",
 			CompanyID:    uuid.MustParse("bbbbbbbb-bbbb-bbbb-bbbb-bbbbbbbbbbbb"),
 			ContractorID: uuid.MustParse("cccccccc-cccc-cccc-cccc-cccccccccccc"),
@@ -51,6 +99,7 @@ func Seed(db *gorm.DB) {
 			UID:          uuid.MustParse("00000000-0000-0000-0000-000000000004"), // job_uid_c7pnhvtsgcqm15z8pvh
 			Status:       "extended",
 			Rate:         30.0,
+			Currency:     "USD",
 			Title:        "ML Engineer",
 			CompanyID:    uuid.MustParse("bbbbbbbb-bbbb-bbbb-bbbb-bbbbbbbbbbbb"),
 			ContractorID: uuid.MustParse("eeeeeeee-eeee-eeee-eeee-eeeeeeeeeeee"), // cont_aezrtdqy9kpdvnhuml
@@ -118,5 +167,9 @@ func Seed(db *gorm.DB) {
 		}
 	}
 
+	if err := backfillPeriods(db); err != nil {
+		log.Fatalf("❌ Failed to backfill periods: %v", err)
+	}
+
 	log.Println("✅ SCD Seed completed for Timelogs and PaymentLineItems.")
 }
\ No newline at end of file
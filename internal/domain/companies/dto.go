@@ -0,0 +1,90 @@
+package companies
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"mercor/internal/scd"
+)
+
+// CompanyRequest is the body of POST /companies and PUT /companies/:id and
+// the data of batch items. PUT takes the complete representation. Identity,
+// version and timestamps are assigned by the server. EffectiveFrom back-dates
+// the change; it defaults to now. DefaultCurrency is an ISO 4217 code and
+// PaymentTermsDays the number of days after which an invoice is due.
+type CompanyRequest struct {
+	Name             string     `json:"name" binding:"required"`
+	BillingAddress   string     `json:"billingAddress" binding:"required"`
+	DefaultCurrency  string     `json:"defaultCurrency" binding:"required,iso4217"`
+	PaymentTermsDays int        `json:"paymentTermsDays" binding:"gte=0,lte=365"`
+	EffectiveFrom    *time.Time `json:"effectiveFrom,omitempty"`
+}
+
+// toCompany converts a validated request.
+func (r CompanyRequest) toCompany() Company {
+	return Company{
+		Name:             r.Name,
+		BillingAddress:   r.BillingAddress,
+		DefaultCurrency:  r.DefaultCurrency,
+		PaymentTermsDays: r.PaymentTermsDays,
+		Bitemporal:       effectiveFrom(r.EffectiveFrom),
+	}
+}
+
+// effectiveFrom starts the effective period of a version at from, or leaves
+// it to the server if from is nil.
+func effectiveFrom(from *time.Time) scd.Bitemporal {
+	if from == nil {
+		return scd.Bitemporal{}
+	}
+	return scd.Bitemporal{EffectiveFrom: *from}
+}
+
+type CompanyResponse struct {
+	ID               uuid.UUID `json:"id"`
+	UID              uuid.UUID `json:"uid"`
+	Version          int       `json:"version"`
+	Name             string    `json:"name"`
+	BillingAddress   string    `json:"billingAddress"`
+	DefaultCurrency  string    `json:"defaultCurrency"`
+	PaymentTermsDays int       `json:"paymentTermsDays"`
+	CreatedAt        time.Time `json:"createdAt"`
+	UpdatedAt        time.Time `json:"updatedAt"`
+	// EffectiveFrom and EffectiveTo bound when the version applied;
+	// RecordedFrom and RecordedTo when it was the recorded state.
+	EffectiveFrom time.Time  `json:"effectiveFrom"`
+	EffectiveTo   *time.Time `json:"effectiveTo"`
+	RecordedFrom  time.Time  `json:"recordedFrom"`
+	RecordedTo    *time.Time `json:"recordedTo"`
+	// RevertedFrom and RevertReason are set on versions written by a revert.
+	RevertedFrom *uuid.UUID `json:"revertedFrom,omitempty"`
+	RevertReason string     `json:"revertReason,omitempty"`
+}
+
+func NewCompanyResponse(c Company) CompanyResponse {
+	return CompanyResponse{
+		ID:               c.ID,
+		UID:              c.UID,
+		Version:          c.Version,
+		Name:             c.Name,
+		BillingAddress:   c.BillingAddress,
+		DefaultCurrency:  c.DefaultCurrency,
+		PaymentTermsDays: c.PaymentTermsDays,
+		CreatedAt:        c.CreatedAt,
+		UpdatedAt:        c.UpdatedAt,
+		EffectiveFrom:    c.EffectiveFrom,
+		EffectiveTo:      c.EffectiveTo,
+		RecordedFrom:     c.RecordedFrom,
+		RecordedTo:       c.RecordedTo,
+		RevertedFrom:     c.RevertedFrom,
+		RevertReason:     c.RevertReason,
+	}
+}
+
+func NewCompanyResponses(list []Company) []CompanyResponse {
+	out := make([]CompanyResponse, len(list))
+	for i, c := range list {
+		out[i] = NewCompanyResponse(c)
+	}
+	return out
+}
@@ -0,0 +1,196 @@
+package companies
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"mercor/internal/idempotency"
+	"mercor/internal/patch"
+	"mercor/internal/scd"
+	"mercor/internal/validation"
+)
+
+type Handler struct {
+	svc Service
+}
+
+func NewHandler(s Service) *Handler {
+	return &Handler{svc: s}
+}
+
+// RegisterRoutes registers the company routes. Gin needs every wildcard in
+// the same position to have the same name, and /companies/:id/jobs and
+// /companies/:id/invoices take the company ID there. The routes below share
+// the name, but like :uid elsewhere :id holds the UID of a version.
+// service returns the service to serve c with: for a request with an
+// Idempotency-Key, one writing in its transaction, so the stored response
+// commits with the writes.
+func (h *Handler) service(c *gin.Context) Service {
+	if tx, ok := idempotency.Tx(c); ok {
+		return NewService(NewRepository(tx))
+	}
+	return h.svc
+}
+
+func (h *Handler) RegisterRoutes(r *gin.Engine) {
+	r.POST("/companies", h.Create)
+	r.POST("/companies/batch", h.Batch)
+	r.GET("/companies/scheduled", h.Scheduled)
+	r.DELETE("/companies/scheduled/:uid", h.CancelScheduled)
+	r.GET("/companies/:id", h.GetByUID)
+	r.GET("/companies/:id/history", h.History)
+	r.POST("/companies/:id/revert", h.Revert)
+	r.PUT("/companies/:id", h.Update)
+	r.PATCH("/companies/:id", h.Patch)
+}
+
+func (h *Handler) Create(c *gin.Context) {
+	var req CompanyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, validation.Response(err))
+		return
+	}
+	company, err := h.service(c).Create(req.toCompany())
+	if err != nil {
+		c.JSON(scd.StatusCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, NewCompanyResponse(company))
+}
+
+// GetByUID returns the given version. With ?as_of=<RFC 3339 time> it returns
+// the version of the same company that was effective at that time, with
+// ?known_at= as it was on record at that time.
+func (h *Handler) GetByUID(c *gin.Context) {
+	tt, err := scd.ParseTimeTravel(c.Query("as_of"), c.Query("known_at"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var company Company
+	switch {
+	case tt.KnownAt != nil:
+		company, err = h.service(c).GetAsKnown(c.Param("id"), *tt.KnownAt, *tt.AsOf)
+	case tt.AsOf != nil:
+		company, err = h.service(c).GetAsOf(c.Param("id"), *tt.AsOf)
+	default:
+		company, err = h.service(c).GetByUID(c.Param("id"))
+	}
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("ETag", scd.ETag(company.UID.String()))
+	c.JSON(http.StatusOK, NewCompanyResponse(company))
+}
+
+func (h *Handler) History(c *gin.Context) {
+	list, err := h.service(c).History(c.Param("id"))
+	if err != nil {
+		c.JSON(scd.StatusCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, NewCompanyResponses(list))
+}
+
+// Scheduled lists the versions that take effect later, soonest first.
+func (h *Handler) Scheduled(c *gin.Context) {
+	list, err := h.service(c).Scheduled()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, NewCompanyResponses(list))
+}
+
+// CancelScheduled withdraws a scheduled version and returns it, no longer
+// recorded. Versions that already took effect cannot be cancelled (409).
+func (h *Handler) CancelScheduled(c *gin.Context) {
+	company, err := h.service(c).CancelScheduled(c.Param("uid"))
+	if err != nil {
+		c.JSON(scd.StatusCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, NewCompanyResponse(company))
+}
+
+// Revert stores a new version with the values of the version named by
+// ?to=<uid>, recording the reason given in the body. It honours If-Match like
+// Update.
+func (h *Handler) Revert(c *gin.Context) {
+	to := c.Query("to")
+	if to == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to is required"})
+		return
+	}
+	var req scd.RevertRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, validation.Response(err))
+		return
+	}
+	company, err := h.service(c).Revert(c.Param("id"), to, req.Reason, scd.IfMatch(c.GetHeader("If-Match")))
+	if err != nil {
+		c.JSON(scd.StatusCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("ETag", scd.ETag(company.UID.String()))
+	c.JSON(http.StatusOK, NewCompanyResponse(company))
+}
+
+// Update honours If-Match: the write fails with 412 unless the ETag of the
+// head version is listed.
+func (h *Handler) Update(c *gin.Context) {
+	var req CompanyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, validation.Response(err))
+		return
+	}
+	company, err := h.service(c).Update(c.Param("id"), req.toCompany(), scd.IfMatch(c.GetHeader("If-Match")))
+	if err != nil {
+		c.JSON(scd.StatusCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("ETag", scd.ETag(company.UID.String()))
+	c.JSON(http.StatusOK, NewCompanyResponse(company))
+}
+
+func (h *Handler) Batch(c *gin.Context) {
+	var req scd.BatchRequest[CompanyRequest]
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	items, err := h.service(c).Batch(req)
+	results := scd.MapBatchResults(items, NewCompanyResponse)
+	if errors.Is(err, scd.ErrBatchRejected) {
+		c.JSON(http.StatusUnprocessableEntity, scd.BatchResponse[CompanyResponse]{Error: err.Error(), Results: results})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, scd.BatchResponse[CompanyResponse]{Results: results})
+}
+
+// Patch accepts application/merge-patch+json (or application/json) and
+// application/json-patch+json bodies.
+func (h *Handler) Patch(c *gin.Context) {
+	body, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	company, err := h.service(c).Patch(c.Param("id"), c.ContentType(), body, scd.IfMatch(c.GetHeader("If-Match")))
+	if err != nil {
+		c.JSON(patch.StatusCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("ETag", scd.ETag(company.UID.String()))
+	c.JSON(http.StatusOK, NewCompanyResponse(company))
+}
@@ -0,0 +1,46 @@
+package companies
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"mercor/internal/scd"
+)
+
+// Company is one version of a company's settings. Jobs reference the company
+// by its ID and take their defaults from it when they are created.
+type Company struct {
+	ID               uuid.UUID `gorm:"type:uuid" json:"id"`
+	UID              uuid.UUID `gorm:"type:uuid;primaryKey" json:"uid"`
+	Version          int       `json:"version"`
+	Name             string    `json:"name"`
+	BillingAddress   string    `json:"billingAddress"`
+	DefaultCurrency  string    `json:"defaultCurrency"`
+	PaymentTermsDays int       `json:"paymentTermsDays"`
+	CreatedAt        time.Time `json:"createdAt"`
+	UpdatedAt        time.Time `json:"updatedAt"`
+	scd.Bitemporal
+	scd.Reversion
+}
+
+func (Company) TableName() string { return "companies" }
+
+func (c Company) GetID() string   { return c.ID.String() }
+func (c Company) GetUID() string  { return c.UID.String() }
+func (c Company) GetVersion() int { return c.Version }
+
+// ChangePolicy versions every change: documents sent to a company have to
+// show the billing address and terms that applied when they were issued.
+func (Company) ChangePolicy() scd.Policy { return nil }
+
+func (c Company) CopyForNewVersion() Company {
+	return Company{
+		ID:               c.ID,
+		Name:             c.Name,
+		BillingAddress:   c.BillingAddress,
+		DefaultCurrency:  c.DefaultCurrency,
+		PaymentTermsDays: c.PaymentTermsDays,
+		Version:          c.Version + 1,
+		UID:              uuid.New(),
+	}
+}
@@ -0,0 +1,134 @@
+package companies
+
+import (
+	"net/http"
+
+	"mercor/internal/openapi"
+	"mercor/internal/scd"
+)
+
+// versionUID documents the :id wildcard of the company routes, which holds
+// a version UID (see RegisterRoutes).
+var versionUID = openapi.Param{
+	Name: "id", In: "path", Required: true,
+	Description: "UID of a company version.",
+	Schema:      openapi.Schema{"type": "string", "format": "uuid"},
+}
+
+// Operations documents the routes registered by Handler.
+func Operations() []openapi.Operation {
+	company := openapi.JSON("The company version", CompanyResponse{})
+	batch := openapi.JSON("Per-item results", scd.BatchResponse[CompanyResponse]{})
+	return []openapi.Operation{
+		{
+			Method: http.MethodPost, Path: "/companies", Tag: "companies",
+			Summary: "Create a company",
+			Body:    openapi.Body(CompanyRequest{}),
+			Responses: openapi.Responses{
+				201: openapi.JSON("The first version of the company", CompanyResponse{}),
+				400: openapi.Invalid,
+				422: openapi.InvalidPeriod,
+				500: openapi.ServerError,
+			},
+		},
+		{
+			Method: http.MethodPost, Path: "/companies/batch", Tag: "companies",
+			Summary:     "Create and version companies in one transaction",
+			Description: "Also served at POST /companies:batch.",
+			Body:        openapi.Body(scd.BatchRequest[CompanyRequest]{}),
+			Responses: openapi.Responses{
+				200: batch,
+				400: openapi.BadRequest,
+				422: openapi.JSON("An atomic batch was rejected", scd.BatchResponse[CompanyResponse]{}),
+				500: openapi.ServerError,
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/companies/scheduled", Tag: "companies",
+			Summary:     "List scheduled company versions",
+			Description: "Versions written with an effectiveFrom in the future, soonest first. Reads of the latest version leave them out until they take effect.",
+			Responses: openapi.Responses{
+				200: openapi.JSON("Pending versions", []CompanyResponse{}),
+				500: openapi.ServerError,
+			},
+		},
+		{
+			Method: http.MethodDelete, Path: "/companies/scheduled/:uid", Tag: "companies",
+			Summary: "Cancel a scheduled company version",
+			Responses: openapi.Responses{
+				200: openapi.JSON("The cancelled version, no longer recorded", CompanyResponse{}),
+				404: openapi.NotFound,
+				409: openapi.JSON("The version is not pending", openapi.Error{}),
+				500: openapi.ServerError,
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/companies/:id", Tag: "companies",
+			Summary: "Get a company version by UID",
+			Params:  []openapi.Param{versionUID, openapi.AsOf, openapi.KnownAt},
+			Responses: openapi.Responses{
+				200: company,
+				400: openapi.BadRequest,
+				404: openapi.NotFound,
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/companies/:id/history", Tag: "companies",
+			Summary: "List every version of a company, oldest first",
+			Params:  []openapi.Param{versionUID},
+			Responses: openapi.Responses{
+				200: openapi.JSON("All versions of the company", []CompanyResponse{}),
+				404: openapi.NotFound,
+			},
+		},
+		{
+			Method: http.MethodPost, Path: "/companies/:id/revert", Tag: "companies",
+			Summary:     "Revert a company to an earlier version",
+			Description: "Stores a new version with the values of the version named by to, recording it and the reason. The history is not rewritten.",
+			Params: []openapi.Param{
+				versionUID,
+				{Name: "to", In: "query", Required: true, Description: "UID of the version to restore."},
+				openapi.IfMatch,
+			},
+			Body: openapi.Body(scd.RevertRequest{}),
+			Responses: openapi.Responses{
+				200: company,
+				400: openapi.Invalid,
+				404: openapi.NotFound,
+				412: openapi.PreconditionFailed,
+				422: openapi.JSON("to is not a version of this company", openapi.Error{}),
+				500: openapi.ServerError,
+			},
+		},
+		{
+			Method: http.MethodPut, Path: "/companies/:id", Tag: "companies",
+			Summary:     "Replace a company",
+			Description: "Stores the complete representation as a new version on top of the head version. An effectiveFrom in the future schedules the version.",
+			Params:      []openapi.Param{versionUID, openapi.IfMatch},
+			Body:        openapi.Body(CompanyRequest{}),
+			Responses: openapi.Responses{
+				200: company,
+				400: openapi.Invalid,
+				404: openapi.NotFound,
+				412: openapi.PreconditionFailed,
+				422: openapi.InvalidPeriod,
+				500: openapi.ServerError,
+			},
+		},
+		{
+			Method: http.MethodPatch, Path: "/companies/:id", Tag: "companies",
+			Summary: "Partially update a company",
+			Params:  []openapi.Param{versionUID, openapi.IfMatch},
+			Body:    openapi.PatchBody(CompanyResponse{}),
+			Responses: openapi.Responses{
+				200: company,
+				400: openapi.BadRequest,
+				404: openapi.NotFound,
+				409: openapi.JSON("A JSON Patch test operation failed", openapi.Error{}),
+				412: openapi.PreconditionFailed,
+				415: openapi.JSON("Unsupported patch media type", openapi.Error{}),
+				422: openapi.JSON("The patched company is invalid or its effectiveFrom cannot be applied", openapi.Error{}),
+			},
+		},
+	}
+}
@@ -0,0 +1,117 @@
+package companies
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"mercor/internal/scd"
+	"mercor/internal/validation"
+)
+
+type Repository interface {
+	Create(c Company) (Company, error)
+	FindByUID(uid string) (Company, error)
+	History(uid string) ([]Company, error)
+	FindAsOf(uid string, at time.Time) (Company, error)
+	FindAsKnown(uid string, knownAt, at time.Time) (Company, error)
+	Scheduled() ([]Company, error)
+	CancelScheduled(uid string) (Company, error)
+	Revert(uid, to, reason string, pre scd.Precondition) (Company, error)
+	Update(uid string, updated Company, pre scd.Precondition) (Company, error)
+	Append(uid string, pre scd.Precondition, next func(head Company) (Company, error)) (Company, error)
+	FindLatest(id uuid.UUID) (Company, error)
+	Batch(ops []scd.BatchOp[CompanyRequest], mode scd.BatchMode) ([]scd.BatchResult[Company], error)
+}
+
+type repo struct {
+	scd *scd.SCDManager[Company]
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repo{scd: scd.NewManager[Company](db)}
+}
+
+func (r *repo) Create(c Company) (Company, error) {
+	err := r.scd.Insert(&c)
+	return c, err
+}
+
+func (r *repo) FindByUID(uid string) (Company, error) {
+	return r.scd.FindByUID(uid)
+}
+
+func (r *repo) History(uid string) ([]Company, error) {
+	return r.scd.HistoryByUID(uid)
+}
+
+func (r *repo) FindAsOf(uid string, at time.Time) (Company, error) {
+	return r.scd.FindAsOfByUID(uid, at)
+}
+
+func (r *repo) FindAsKnown(uid string, knownAt, at time.Time) (Company, error) {
+	return r.scd.FindAsKnownByUID(uid, knownAt, at)
+}
+
+// Scheduled returns the versions that take effect later, soonest first.
+func (r *repo) Scheduled() ([]Company, error) {
+	var list []Company
+	err := r.scd.Scheduled().Find(&list).Error
+	return list, err
+}
+
+func (r *repo) CancelScheduled(uid string) (Company, error) {
+	return r.scd.CancelScheduled(uid)
+}
+
+func (r *repo) Revert(uid, to, reason string, pre scd.Precondition) (Company, error) {
+	return r.scd.Revert(uid, to, reason, pre)
+}
+
+func (r *repo) Update(uid string, updated Company, pre scd.Precondition) (Company, error) {
+	return r.Append(uid, pre, func(old Company) (Company, error) {
+		return nextVersion(old, updated), nil
+	})
+}
+
+// Append stores the version next builds from the locked head version.
+func (r *repo) Append(uid string, pre scd.Precondition, next func(head Company) (Company, error)) (Company, error) {
+	return r.scd.AppendVersion(uid, pre, next)
+}
+
+// nextVersion builds the version following old with the fields taken from in.
+func nextVersion(old, in Company) Company {
+	updated := old.CopyForNewVersion()
+	updated.Name = in.Name
+	updated.BillingAddress = in.BillingAddress
+	updated.DefaultCurrency = in.DefaultCurrency
+	updated.PaymentTermsDays = in.PaymentTermsDays
+	updated.EffectiveFrom = in.EffectiveFrom
+	return updated
+}
+
+// FindLatest returns the head version of the company with the given ID.
+func (r *repo) FindLatest(id uuid.UUID) (Company, error) {
+	return r.scd.FindLatestByID(id.String())
+}
+
+func (r *repo) Batch(ops []scd.BatchOp[CompanyRequest], mode scd.BatchMode) ([]scd.BatchResult[Company], error) {
+	return scd.ApplyBatch(r.scd, ops, mode, scd.BatchHooks[CompanyRequest, Company]{
+		Create: func(in CompanyRequest) (Company, error) {
+			if err := validation.Struct(in); err != nil {
+				return Company{}, err
+			}
+			c := in.toCompany()
+			c.ID = uuid.New()
+			c.UID = uuid.New()
+			c.Version = 1
+			return c, nil
+		},
+		Update: func(old Company, in CompanyRequest) (Company, error) {
+			if err := validation.Struct(in); err != nil {
+				return Company{}, err
+			}
+			return nextVersion(old, in.toCompany()), nil
+		},
+	})
+}
@@ -0,0 +1,106 @@
+package companies
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"mercor/internal/patch"
+	"mercor/internal/scd"
+	"mercor/internal/validation"
+)
+
+type Service interface {
+	Create(c Company) (Company, error)
+	GetByUID(uid string) (Company, error)
+	GetAsOf(uid string, at time.Time) (Company, error)
+	GetAsKnown(uid string, knownAt, at time.Time) (Company, error)
+	Scheduled() ([]Company, error)
+	CancelScheduled(uid string) (Company, error)
+	Revert(uid, to, reason string, pre scd.Precondition) (Company, error)
+	History(uid string) ([]Company, error)
+	Update(uid string, updated Company, pre scd.Precondition) (Company, error)
+	Patch(uid, contentType string, body []byte, pre scd.Precondition) (Company, error)
+	Batch(req scd.BatchRequest[CompanyRequest]) ([]scd.BatchResult[Company], error)
+}
+
+type service struct {
+	repo Repository
+}
+
+func NewService(r Repository) Service {
+	return &service{repo: r}
+}
+
+// Create stores the first version of a new company. Identity and version
+// are always assigned here, never taken from the client.
+func (s *service) Create(c Company) (Company, error) {
+	c.ID = uuid.New()
+	c.UID = uuid.New()
+	c.Version = 1
+	return s.repo.Create(c)
+}
+
+func (s *service) GetByUID(uid string) (Company, error) {
+	return s.repo.FindByUID(uid)
+}
+
+// GetAsOf returns the version of the company that was effective at the
+// given time.
+func (s *service) GetAsOf(uid string, at time.Time) (Company, error) {
+	return s.repo.FindAsOf(uid, at)
+}
+
+// GetAsKnown returns the version of the company that was effective at the
+// given time according to what was recorded at knownAt.
+func (s *service) GetAsKnown(uid string, knownAt, at time.Time) (Company, error) {
+	return s.repo.FindAsKnown(uid, knownAt, at)
+}
+
+// Scheduled returns the pending versions, stored with an effectiveFrom in the
+// future, that reads of the latest version leave out until they take effect.
+func (s *service) Scheduled() ([]Company, error) {
+	return s.repo.Scheduled()
+}
+
+// CancelScheduled withdraws a pending version before it takes effect.
+func (s *service) CancelScheduled(uid string) (Company, error) {
+	return s.repo.CancelScheduled(uid)
+}
+
+// Revert stores a new version of the company with the values of its
+// version `to`, recording that version and the reason on it.
+func (s *service) Revert(uid, to, reason string, pre scd.Precondition) (Company, error) {
+	return s.repo.Revert(uid, to, reason, pre)
+}
+
+// History returns every version of the company, oldest first.
+func (s *service) History(uid string) ([]Company, error) {
+	return s.repo.History(uid)
+}
+
+func (s *service) Update(uid string, updated Company, pre scd.Precondition) (Company, error) {
+	return s.repo.Update(uid, updated, pre)
+}
+
+// Patch applies a merge patch or JSON patch to the response representation of
+// the head version of the entity that uid belongs to. The head is locked while
+// the patch is applied, so the result is based on the version it replaces. The
+// result must be a valid CompanyRequest and is stored as a new version.
+func (s *service) Patch(uid, contentType string, body []byte, pre scd.Precondition) (Company, error) {
+	return s.repo.Append(uid, pre, func(head Company) (Company, error) {
+		patched, err := patch.Entity(NewCompanyResponse(head), contentType, body, validation.Struct[CompanyRequest])
+		if err != nil {
+			return Company{}, err
+		}
+		// The representation carries the effectiveFrom of the head; only a
+		// changed one back-dates the patch.
+		if patched.EffectiveFrom != nil && patched.EffectiveFrom.Equal(head.EffectiveFrom) {
+			patched.EffectiveFrom = nil
+		}
+		return nextVersion(head, patched.toCompany()), nil
+	})
+}
+
+func (s *service) Batch(req scd.BatchRequest[CompanyRequest]) ([]scd.BatchResult[Company], error) {
+	return s.repo.Batch(req.Items, req.Mode)
+}
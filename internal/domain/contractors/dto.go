@@ -0,0 +1,93 @@
+package contractors
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"mercor/internal/scd"
+)
+
+// ContractorRequest is the body of POST /contractors and PUT /contractors/:id
+// and the data of batch items. PUT takes the complete representation.
+// Identity, version and timestamps are assigned by the server. EffectiveFrom
+// back-dates the change; it defaults to now.
+type ContractorRequest struct {
+	Name          string     `json:"name" binding:"required"`
+	Email         string     `json:"email" binding:"required,email"`
+	TaxRegion     string     `json:"taxRegion" binding:"required"`
+	PayoutMethod  string     `json:"payoutMethod" binding:"required,oneof=bank_transfer paypal wise"`
+	PayoutAccount string     `json:"payoutAccount" binding:"required"`
+	EffectiveFrom *time.Time `json:"effectiveFrom,omitempty"`
+}
+
+// toContractor converts a validated request.
+func (r ContractorRequest) toContractor() Contractor {
+	return Contractor{
+		Name:          r.Name,
+		Email:         r.Email,
+		TaxRegion:     r.TaxRegion,
+		PayoutMethod:  r.PayoutMethod,
+		PayoutAccount: r.PayoutAccount,
+		Bitemporal:    effectiveFrom(r.EffectiveFrom),
+	}
+}
+
+// effectiveFrom starts the effective period of a version at from, or leaves
+// it to the server if from is nil.
+func effectiveFrom(from *time.Time) scd.Bitemporal {
+	if from == nil {
+		return scd.Bitemporal{}
+	}
+	return scd.Bitemporal{EffectiveFrom: *from}
+}
+
+type ContractorResponse struct {
+	ID            uuid.UUID `json:"id"`
+	UID           uuid.UUID `json:"uid"`
+	Version       int       `json:"version"`
+	Name          string    `json:"name"`
+	Email         string    `json:"email"`
+	TaxRegion     string    `json:"taxRegion"`
+	PayoutMethod  string    `json:"payoutMethod"`
+	PayoutAccount string    `json:"payoutAccount"`
+	CreatedAt     time.Time `json:"createdAt"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+	// EffectiveFrom and EffectiveTo bound when the version applied;
+	// RecordedFrom and RecordedTo when it was the recorded state.
+	EffectiveFrom time.Time  `json:"effectiveFrom"`
+	EffectiveTo   *time.Time `json:"effectiveTo"`
+	RecordedFrom  time.Time  `json:"recordedFrom"`
+	RecordedTo    *time.Time `json:"recordedTo"`
+	// RevertedFrom and RevertReason are set on versions written by a revert.
+	RevertedFrom *uuid.UUID `json:"revertedFrom,omitempty"`
+	RevertReason string     `json:"revertReason,omitempty"`
+}
+
+func NewContractorResponse(c Contractor) ContractorResponse {
+	return ContractorResponse{
+		ID:            c.ID,
+		UID:           c.UID,
+		Version:       c.Version,
+		Name:          c.Name,
+		Email:         c.Email,
+		TaxRegion:     c.TaxRegion,
+		PayoutMethod:  c.PayoutMethod,
+		PayoutAccount: c.PayoutAccount,
+		CreatedAt:     c.CreatedAt,
+		UpdatedAt:     c.UpdatedAt,
+		EffectiveFrom: c.EffectiveFrom,
+		EffectiveTo:   c.EffectiveTo,
+		RecordedFrom:  c.RecordedFrom,
+		RecordedTo:    c.RecordedTo,
+		RevertedFrom:  c.RevertedFrom,
+		RevertReason:  c.RevertReason,
+	}
+}
+
+func NewContractorResponses(list []Contractor) []ContractorResponse {
+	out := make([]ContractorResponse, len(list))
+	for i, c := range list {
+		out[i] = NewContractorResponse(c)
+	}
+	return out
+}
@@ -0,0 +1,197 @@
+package contractors
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"mercor/internal/idempotency"
+	"mercor/internal/patch"
+	"mercor/internal/scd"
+	"mercor/internal/validation"
+)
+
+type Handler struct {
+	svc Service
+}
+
+func NewHandler(s Service) *Handler {
+	return &Handler{svc: s}
+}
+
+// RegisterRoutes registers the contractor routes. Gin needs every wildcard in
+// the same position to have the same name, and /contractors/:id/timelogs and
+// /contractors/:id/payment-line-items take the contractor ID there. The
+// routes below share the name, but like :uid elsewhere :id holds the UID of
+// a version.
+// service returns the service to serve c with: for a request with an
+// Idempotency-Key, one writing in its transaction, so the stored response
+// commits with the writes.
+func (h *Handler) service(c *gin.Context) Service {
+	if tx, ok := idempotency.Tx(c); ok {
+		return NewService(NewRepository(tx))
+	}
+	return h.svc
+}
+
+func (h *Handler) RegisterRoutes(r *gin.Engine) {
+	r.POST("/contractors", h.Create)
+	r.POST("/contractors/batch", h.Batch)
+	r.GET("/contractors/scheduled", h.Scheduled)
+	r.DELETE("/contractors/scheduled/:uid", h.CancelScheduled)
+	r.GET("/contractors/:id", h.GetByUID)
+	r.GET("/contractors/:id/history", h.History)
+	r.POST("/contractors/:id/revert", h.Revert)
+	r.PUT("/contractors/:id", h.Update)
+	r.PATCH("/contractors/:id", h.Patch)
+}
+
+func (h *Handler) Create(c *gin.Context) {
+	var req ContractorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, validation.Response(err))
+		return
+	}
+	contractor, err := h.service(c).Create(req.toContractor())
+	if err != nil {
+		c.JSON(scd.StatusCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, NewContractorResponse(contractor))
+}
+
+// GetByUID returns the given version. With ?as_of=<RFC 3339 time> it returns
+// the version of the same contractor that was effective at that time, with
+// ?known_at= as it was on record at that time.
+func (h *Handler) GetByUID(c *gin.Context) {
+	tt, err := scd.ParseTimeTravel(c.Query("as_of"), c.Query("known_at"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var contractor Contractor
+	switch {
+	case tt.KnownAt != nil:
+		contractor, err = h.service(c).GetAsKnown(c.Param("id"), *tt.KnownAt, *tt.AsOf)
+	case tt.AsOf != nil:
+		contractor, err = h.service(c).GetAsOf(c.Param("id"), *tt.AsOf)
+	default:
+		contractor, err = h.service(c).GetByUID(c.Param("id"))
+	}
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("ETag", scd.ETag(contractor.UID.String()))
+	c.JSON(http.StatusOK, NewContractorResponse(contractor))
+}
+
+func (h *Handler) History(c *gin.Context) {
+	list, err := h.service(c).History(c.Param("id"))
+	if err != nil {
+		c.JSON(scd.StatusCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, NewContractorResponses(list))
+}
+
+// Scheduled lists the versions that take effect later, soonest first.
+func (h *Handler) Scheduled(c *gin.Context) {
+	list, err := h.service(c).Scheduled()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, NewContractorResponses(list))
+}
+
+// CancelScheduled withdraws a scheduled version and returns it, no longer
+// recorded. Versions that already took effect cannot be cancelled (409).
+func (h *Handler) CancelScheduled(c *gin.Context) {
+	contractor, err := h.service(c).CancelScheduled(c.Param("uid"))
+	if err != nil {
+		c.JSON(scd.StatusCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, NewContractorResponse(contractor))
+}
+
+// Revert stores a new version with the values of the version named by
+// ?to=<uid>, recording the reason given in the body. It honours If-Match like
+// Update.
+func (h *Handler) Revert(c *gin.Context) {
+	to := c.Query("to")
+	if to == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to is required"})
+		return
+	}
+	var req scd.RevertRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, validation.Response(err))
+		return
+	}
+	contractor, err := h.service(c).Revert(c.Param("id"), to, req.Reason, scd.IfMatch(c.GetHeader("If-Match")))
+	if err != nil {
+		c.JSON(scd.StatusCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("ETag", scd.ETag(contractor.UID.String()))
+	c.JSON(http.StatusOK, NewContractorResponse(contractor))
+}
+
+// Update honours If-Match: the write fails with 412 unless the ETag of the
+// head version is listed.
+func (h *Handler) Update(c *gin.Context) {
+	var req ContractorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, validation.Response(err))
+		return
+	}
+	contractor, err := h.service(c).Update(c.Param("id"), req.toContractor(), scd.IfMatch(c.GetHeader("If-Match")))
+	if err != nil {
+		c.JSON(scd.StatusCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("ETag", scd.ETag(contractor.UID.String()))
+	c.JSON(http.StatusOK, NewContractorResponse(contractor))
+}
+
+func (h *Handler) Batch(c *gin.Context) {
+	var req scd.BatchRequest[ContractorRequest]
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	items, err := h.service(c).Batch(req)
+	results := scd.MapBatchResults(items, NewContractorResponse)
+	if errors.Is(err, scd.ErrBatchRejected) {
+		c.JSON(http.StatusUnprocessableEntity, scd.BatchResponse[ContractorResponse]{Error: err.Error(), Results: results})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, scd.BatchResponse[ContractorResponse]{Results: results})
+}
+
+// Patch accepts application/merge-patch+json (or application/json) and
+// application/json-patch+json bodies.
+func (h *Handler) Patch(c *gin.Context) {
+	body, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	contractor, err := h.service(c).Patch(c.Param("id"), c.ContentType(), body, scd.IfMatch(c.GetHeader("If-Match")))
+	if err != nil {
+		c.JSON(patch.StatusCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("ETag", scd.ETag(contractor.UID.String()))
+	c.JSON(http.StatusOK, NewContractorResponse(contractor))
+}
@@ -0,0 +1,48 @@
+package contractors
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"mercor/internal/scd"
+)
+
+// Contractor is one version of a contractor's profile. Jobs, timelogs and
+// payment line items reference the contractor by its ID.
+type Contractor struct {
+	ID            uuid.UUID `gorm:"type:uuid" json:"id"`
+	UID           uuid.UUID `gorm:"type:uuid;primaryKey" json:"uid"`
+	Version       int       `json:"version"`
+	Name          string    `json:"name"`
+	Email         string    `gorm:"index" json:"email"`
+	TaxRegion     string    `json:"taxRegion"`
+	PayoutMethod  string    `json:"payoutMethod"`
+	PayoutAccount string    `json:"payoutAccount"`
+	CreatedAt     time.Time `json:"createdAt"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+	scd.Bitemporal
+	scd.Reversion
+}
+
+func (Contractor) TableName() string { return "contractors" }
+
+func (c Contractor) GetID() string   { return c.ID.String() }
+func (c Contractor) GetUID() string  { return c.UID.String() }
+func (c Contractor) GetVersion() int { return c.Version }
+
+// ChangePolicy versions every change: payouts have to be traced to the tax
+// region and payout details that applied when they were made.
+func (Contractor) ChangePolicy() scd.Policy { return nil }
+
+func (c Contractor) CopyForNewVersion() Contractor {
+	return Contractor{
+		ID:            c.ID,
+		Name:          c.Name,
+		Email:         c.Email,
+		TaxRegion:     c.TaxRegion,
+		PayoutMethod:  c.PayoutMethod,
+		PayoutAccount: c.PayoutAccount,
+		Version:       c.Version + 1,
+		UID:           uuid.New(),
+	}
+}
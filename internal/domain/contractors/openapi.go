@@ -0,0 +1,134 @@
+package contractors
+
+import (
+	"net/http"
+
+	"mercor/internal/openapi"
+	"mercor/internal/scd"
+)
+
+// versionUID documents the :id wildcard of the contractor routes, which holds
+// a version UID (see RegisterRoutes).
+var versionUID = openapi.Param{
+	Name: "id", In: "path", Required: true,
+	Description: "UID of a contractor version.",
+	Schema:      openapi.Schema{"type": "string", "format": "uuid"},
+}
+
+// Operations documents the routes registered by Handler.
+func Operations() []openapi.Operation {
+	contractor := openapi.JSON("The contractor version", ContractorResponse{})
+	batch := openapi.JSON("Per-item results", scd.BatchResponse[ContractorResponse]{})
+	return []openapi.Operation{
+		{
+			Method: http.MethodPost, Path: "/contractors", Tag: "contractors",
+			Summary: "Create a contractor",
+			Body:    openapi.Body(ContractorRequest{}),
+			Responses: openapi.Responses{
+				201: openapi.JSON("The first version of the contractor", ContractorResponse{}),
+				400: openapi.Invalid,
+				422: openapi.InvalidPeriod,
+				500: openapi.ServerError,
+			},
+		},
+		{
+			Method: http.MethodPost, Path: "/contractors/batch", Tag: "contractors",
+			Summary:     "Create and version contractors in one transaction",
+			Description: "Also served at POST /contractors:batch.",
+			Body:        openapi.Body(scd.BatchRequest[ContractorRequest]{}),
+			Responses: openapi.Responses{
+				200: batch,
+				400: openapi.BadRequest,
+				422: openapi.JSON("An atomic batch was rejected", scd.BatchResponse[ContractorResponse]{}),
+				500: openapi.ServerError,
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/contractors/scheduled", Tag: "contractors",
+			Summary:     "List scheduled contractor versions",
+			Description: "Versions written with an effectiveFrom in the future, soonest first. Reads of the latest version leave them out until they take effect.",
+			Responses: openapi.Responses{
+				200: openapi.JSON("Pending versions", []ContractorResponse{}),
+				500: openapi.ServerError,
+			},
+		},
+		{
+			Method: http.MethodDelete, Path: "/contractors/scheduled/:uid", Tag: "contractors",
+			Summary: "Cancel a scheduled contractor version",
+			Responses: openapi.Responses{
+				200: openapi.JSON("The cancelled version, no longer recorded", ContractorResponse{}),
+				404: openapi.NotFound,
+				409: openapi.JSON("The version is not pending", openapi.Error{}),
+				500: openapi.ServerError,
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/contractors/:id", Tag: "contractors",
+			Summary: "Get a contractor version by UID",
+			Params:  []openapi.Param{versionUID, openapi.AsOf, openapi.KnownAt},
+			Responses: openapi.Responses{
+				200: contractor,
+				400: openapi.BadRequest,
+				404: openapi.NotFound,
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/contractors/:id/history", Tag: "contractors",
+			Summary: "List every version of a contractor, oldest first",
+			Params:  []openapi.Param{versionUID},
+			Responses: openapi.Responses{
+				200: openapi.JSON("All versions of the contractor", []ContractorResponse{}),
+				404: openapi.NotFound,
+			},
+		},
+		{
+			Method: http.MethodPost, Path: "/contractors/:id/revert", Tag: "contractors",
+			Summary:     "Revert a contractor to an earlier version",
+			Description: "Stores a new version with the values of the version named by to, recording it and the reason. The history is not rewritten.",
+			Params: []openapi.Param{
+				versionUID,
+				{Name: "to", In: "query", Required: true, Description: "UID of the version to restore."},
+				openapi.IfMatch,
+			},
+			Body: openapi.Body(scd.RevertRequest{}),
+			Responses: openapi.Responses{
+				200: contractor,
+				400: openapi.Invalid,
+				404: openapi.NotFound,
+				412: openapi.PreconditionFailed,
+				422: openapi.JSON("to is not a version of this contractor", openapi.Error{}),
+				500: openapi.ServerError,
+			},
+		},
+		{
+			Method: http.MethodPut, Path: "/contractors/:id", Tag: "contractors",
+			Summary:     "Replace a contractor",
+			Description: "Stores the complete representation as a new version on top of the head version. An effectiveFrom in the future schedules the version.",
+			Params:      []openapi.Param{versionUID, openapi.IfMatch},
+			Body:        openapi.Body(ContractorRequest{}),
+			Responses: openapi.Responses{
+				200: contractor,
+				400: openapi.Invalid,
+				404: openapi.NotFound,
+				412: openapi.PreconditionFailed,
+				422: openapi.InvalidPeriod,
+				500: openapi.ServerError,
+			},
+		},
+		{
+			Method: http.MethodPatch, Path: "/contractors/:id", Tag: "contractors",
+			Summary: "Partially update a contractor",
+			Params:  []openapi.Param{versionUID, openapi.IfMatch},
+			Body:    openapi.PatchBody(ContractorResponse{}),
+			Responses: openapi.Responses{
+				200: contractor,
+				400: openapi.BadRequest,
+				404: openapi.NotFound,
+				409: openapi.JSON("A JSON Patch test operation failed", openapi.Error{}),
+				412: openapi.PreconditionFailed,
+				415: openapi.JSON("Unsupported patch media type", openapi.Error{}),
+				422: openapi.JSON("The patched contractor is invalid or its effectiveFrom cannot be applied", openapi.Error{}),
+			},
+		},
+	}
+}
@@ -0,0 +1,121 @@
+package contractors
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"mercor/internal/scd"
+	"mercor/internal/validation"
+)
+
+type Repository interface {
+	Create(c Contractor) (Contractor, error)
+	FindByUID(uid string) (Contractor, error)
+	History(uid string) ([]Contractor, error)
+	FindAsOf(uid string, at time.Time) (Contractor, error)
+	FindAsKnown(uid string, knownAt, at time.Time) (Contractor, error)
+	Scheduled() ([]Contractor, error)
+	CancelScheduled(uid string) (Contractor, error)
+	Revert(uid, to, reason string, pre scd.Precondition) (Contractor, error)
+	Update(uid string, updated Contractor, pre scd.Precondition) (Contractor, error)
+	Append(uid string, pre scd.Precondition, next func(head Contractor) (Contractor, error)) (Contractor, error)
+	Exists(id uuid.UUID) (bool, error)
+	Batch(ops []scd.BatchOp[ContractorRequest], mode scd.BatchMode) ([]scd.BatchResult[Contractor], error)
+}
+
+type repo struct {
+	scd *scd.SCDManager[Contractor]
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repo{scd: scd.NewManager[Contractor](db)}
+}
+
+func (r *repo) Create(c Contractor) (Contractor, error) {
+	err := r.scd.Insert(&c)
+	return c, err
+}
+
+func (r *repo) FindByUID(uid string) (Contractor, error) {
+	return r.scd.FindByUID(uid)
+}
+
+func (r *repo) History(uid string) ([]Contractor, error) {
+	return r.scd.HistoryByUID(uid)
+}
+
+func (r *repo) FindAsOf(uid string, at time.Time) (Contractor, error) {
+	return r.scd.FindAsOfByUID(uid, at)
+}
+
+func (r *repo) FindAsKnown(uid string, knownAt, at time.Time) (Contractor, error) {
+	return r.scd.FindAsKnownByUID(uid, knownAt, at)
+}
+
+// Scheduled returns the versions that take effect later, soonest first.
+func (r *repo) Scheduled() ([]Contractor, error) {
+	var list []Contractor
+	err := r.scd.Scheduled().Find(&list).Error
+	return list, err
+}
+
+func (r *repo) CancelScheduled(uid string) (Contractor, error) {
+	return r.scd.CancelScheduled(uid)
+}
+
+func (r *repo) Revert(uid, to, reason string, pre scd.Precondition) (Contractor, error) {
+	return r.scd.Revert(uid, to, reason, pre)
+}
+
+func (r *repo) Update(uid string, updated Contractor, pre scd.Precondition) (Contractor, error) {
+	return r.Append(uid, pre, func(old Contractor) (Contractor, error) {
+		return nextVersion(old, updated), nil
+	})
+}
+
+// Append stores the version next builds from the locked head version.
+func (r *repo) Append(uid string, pre scd.Precondition, next func(head Contractor) (Contractor, error)) (Contractor, error) {
+	return r.scd.AppendVersion(uid, pre, next)
+}
+
+// nextVersion builds the version following old with the fields taken from in.
+func nextVersion(old, in Contractor) Contractor {
+	updated := old.CopyForNewVersion()
+	updated.Name = in.Name
+	updated.Email = in.Email
+	updated.TaxRegion = in.TaxRegion
+	updated.PayoutMethod = in.PayoutMethod
+	updated.PayoutAccount = in.PayoutAccount
+	updated.EffectiveFrom = in.EffectiveFrom
+	return updated
+}
+
+// Exists reports whether a contractor with the given ID is on record.
+// Contractors are never deleted, so once created one always exists.
+func (r *repo) Exists(id uuid.UUID) (bool, error) {
+	var count int64
+	err := r.scd.GetLatest().Where("main.id = ?", id).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *repo) Batch(ops []scd.BatchOp[ContractorRequest], mode scd.BatchMode) ([]scd.BatchResult[Contractor], error) {
+	return scd.ApplyBatch(r.scd, ops, mode, scd.BatchHooks[ContractorRequest, Contractor]{
+		Create: func(in ContractorRequest) (Contractor, error) {
+			if err := validation.Struct(in); err != nil {
+				return Contractor{}, err
+			}
+			c := in.toContractor()
+			c.ID = uuid.New()
+			c.UID = uuid.New()
+			c.Version = 1
+			return c, nil
+		},
+		Update: func(old Contractor, in ContractorRequest) (Contractor, error) {
+			if err := validation.Struct(in); err != nil {
+				return Contractor{}, err
+			}
+			return nextVersion(old, in.toContractor()), nil
+		},
+	})
+}
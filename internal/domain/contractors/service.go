@@ -0,0 +1,112 @@
+package contractors
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"mercor/internal/patch"
+	"mercor/internal/scd"
+	"mercor/internal/validation"
+)
+
+type Service interface {
+	Create(c Contractor) (Contractor, error)
+	GetByUID(uid string) (Contractor, error)
+	GetAsOf(uid string, at time.Time) (Contractor, error)
+	GetAsKnown(uid string, knownAt, at time.Time) (Contractor, error)
+	Scheduled() ([]Contractor, error)
+	CancelScheduled(uid string) (Contractor, error)
+	Revert(uid, to, reason string, pre scd.Precondition) (Contractor, error)
+	History(uid string) ([]Contractor, error)
+	Update(uid string, updated Contractor, pre scd.Precondition) (Contractor, error)
+	Exists(id uuid.UUID) (bool, error)
+	Patch(uid, contentType string, body []byte, pre scd.Precondition) (Contractor, error)
+	Batch(req scd.BatchRequest[ContractorRequest]) ([]scd.BatchResult[Contractor], error)
+}
+
+type service struct {
+	repo Repository
+}
+
+func NewService(r Repository) Service {
+	return &service{repo: r}
+}
+
+// Create stores the first version of a new contractor. Identity and version
+// are always assigned here, never taken from the client.
+func (s *service) Create(c Contractor) (Contractor, error) {
+	c.ID = uuid.New()
+	c.UID = uuid.New()
+	c.Version = 1
+	return s.repo.Create(c)
+}
+
+func (s *service) GetByUID(uid string) (Contractor, error) {
+	return s.repo.FindByUID(uid)
+}
+
+// GetAsOf returns the version of the contractor that was effective at the
+// given time.
+func (s *service) GetAsOf(uid string, at time.Time) (Contractor, error) {
+	return s.repo.FindAsOf(uid, at)
+}
+
+// GetAsKnown returns the version of the contractor that was effective at the
+// given time according to what was recorded at knownAt.
+func (s *service) GetAsKnown(uid string, knownAt, at time.Time) (Contractor, error) {
+	return s.repo.FindAsKnown(uid, knownAt, at)
+}
+
+// Scheduled returns the pending versions, stored with an effectiveFrom in the
+// future, that reads of the latest version leave out until they take effect.
+func (s *service) Scheduled() ([]Contractor, error) {
+	return s.repo.Scheduled()
+}
+
+// CancelScheduled withdraws a pending version before it takes effect.
+func (s *service) CancelScheduled(uid string) (Contractor, error) {
+	return s.repo.CancelScheduled(uid)
+}
+
+// Revert stores a new version of the contractor with the values of its
+// version `to`, recording that version and the reason on it.
+func (s *service) Revert(uid, to, reason string, pre scd.Precondition) (Contractor, error) {
+	return s.repo.Revert(uid, to, reason, pre)
+}
+
+// History returns every version of the contractor, oldest first.
+func (s *service) History(uid string) ([]Contractor, error) {
+	return s.repo.History(uid)
+}
+
+func (s *service) Update(uid string, updated Contractor, pre scd.Precondition) (Contractor, error) {
+	return s.repo.Update(uid, updated, pre)
+}
+
+// Exists reports whether a contractor with the given ID exists.
+func (s *service) Exists(id uuid.UUID) (bool, error) {
+	return s.repo.Exists(id)
+}
+
+// Patch applies a merge patch or JSON patch to the response representation of
+// the head version of the entity that uid belongs to. The head is locked while
+// the patch is applied, so the result is based on the version it replaces. The
+// result must be a valid ContractorRequest and is stored as a new version.
+func (s *service) Patch(uid, contentType string, body []byte, pre scd.Precondition) (Contractor, error) {
+	return s.repo.Append(uid, pre, func(head Contractor) (Contractor, error) {
+		patched, err := patch.Entity(NewContractorResponse(head), contentType, body, validation.Struct[ContractorRequest])
+		if err != nil {
+			return Contractor{}, err
+		}
+		// The representation carries the effectiveFrom of the head; only a
+		// changed one back-dates the patch.
+		if patched.EffectiveFrom != nil && patched.EffectiveFrom.Equal(head.EffectiveFrom) {
+			patched.EffectiveFrom = nil
+		}
+		return nextVersion(head, patched.toContractor()), nil
+	})
+}
+
+func (s *service) Batch(req scd.BatchRequest[ContractorRequest]) ([]scd.BatchResult[Contractor], error) {
+	return s.repo.Batch(req.Items, req.Mode)
+}
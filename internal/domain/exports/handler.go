@@ -0,0 +1,78 @@
+package exports
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"mercor/internal/domain/companies"
+	"mercor/internal/domain/contractors"
+	"mercor/internal/domain/invoices"
+	"mercor/internal/domain/jobs"
+	payment "mercor/internal/domain/paymentLineItem"
+	"mercor/internal/domain/timelog"
+	"mercor/internal/export"
+)
+
+// Writer streams one entity's versions in the requested format.
+type Writer func(db *gorm.DB, w io.Writer, opts export.Options) error
+
+// Entities maps the exported entity names to their writers.
+var Entities = map[string]Writer{
+	"companies":          export.Write[companies.Company],
+	"contractors":        export.Write[contractors.Contractor],
+	"jobs":               export.Write[jobs.Job],
+	"timelogs":           export.Write[timelog.Timelog],
+	"payment-line-items": export.Write[payment.PaymentLineItem],
+	"invoices":           export.Write[invoices.Invoice],
+}
+
+type Handler struct {
+	db *gorm.DB
+}
+
+func NewHandler(db *gorm.DB) *Handler {
+	return &Handler{db: db}
+}
+
+func (h *Handler) RegisterRoutes(r *gin.Engine) {
+	r.GET("/exports/:entity", h.Export)
+}
+
+// Export streams ?format=csv|ndjson|parquet for ?scope=current|history|as_of.
+// The as_of scope takes an RFC 3339 ?as_of time.
+func (h *Handler) Export(c *gin.Context) {
+	entity := c.Param("entity")
+	write, ok := Entities[entity]
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "unknown entity " + entity})
+		return
+	}
+	opts := export.Options{
+		Format: export.Format(c.DefaultQuery("format", string(export.FormatCSV))),
+		Scope:  export.Scope(c.DefaultQuery("scope", string(export.ScopeCurrent))),
+	}
+	if raw := c.Query("as_of"); raw != "" {
+		at, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid as_of: " + err.Error()})
+			return
+		}
+		opts.AsOf = at
+	}
+	if err := opts.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Type", opts.Format.ContentType())
+	c.Header("Content-Disposition", `attachment; filename="`+entity+"-"+string(opts.Scope)+"."+string(opts.Format)+`"`)
+	c.Status(http.StatusOK)
+	if err := write(h.db, c.Writer, opts); err != nil {
+		// Headers are already sent; the truncated body is the only signal.
+		c.Error(err)
+		c.Abort()
+	}
+}
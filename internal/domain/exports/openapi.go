@@ -0,0 +1,40 @@
+package exports
+
+import (
+	"net/http"
+	"sort"
+
+	"mercor/internal/export"
+	"mercor/internal/openapi"
+)
+
+// Operations documents the routes registered by Handler.
+func Operations() []openapi.Operation {
+	var entities []string
+	for name := range Entities {
+		entities = append(entities, name)
+	}
+	sort.Strings(entities)
+	file := openapi.Schema{"type": "string"}
+	return []openapi.Operation{
+		{
+			Method: http.MethodGet, Path: "/exports/:entity", Tag: "exports",
+			Summary: "Stream the versions of an entity",
+			Params: []openapi.Param{
+				{Name: "entity", In: "path", Schema: openapi.Schema{"type": "string", "enum": entities}},
+				{Name: "format", In: "query", Schema: openapi.Schema{"type": "string", "enum": []export.Format{export.FormatCSV, export.FormatNDJSON, export.FormatParquet}, "default": export.FormatCSV}},
+				{Name: "scope", In: "query", Schema: openapi.Schema{"type": "string", "enum": []export.Scope{export.ScopeCurrent, export.ScopeHistory, export.ScopeAsOf}, "default": export.ScopeCurrent}},
+				{Name: "as_of", In: "query", Description: "Required with scope=as_of.", Schema: openapi.Schema{"type": "string", "format": "date-time"}},
+			},
+			Responses: openapi.Responses{
+				200: {Description: "The export file", Content: map[string]any{
+					export.FormatCSV.ContentType():     file,
+					export.FormatNDJSON.ContentType():  file,
+					export.FormatParquet.ContentType(): file,
+				}},
+				400: openapi.BadRequest,
+				404: openapi.NotFound,
+			},
+		},
+	}
+}
@@ -0,0 +1,64 @@
+package graph
+
+import (
+	_ "embed"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/graph-gophers/graphql-go"
+	job "mercor/internal/domain/jobs"
+	payment "mercor/internal/domain/paymentLineItem"
+	timelog "mercor/internal/domain/timelog"
+)
+
+//go:embed schema.graphql
+var schemaSDL string
+
+// Request is the body of a GraphQL request.
+type Request struct {
+	Query         string         `json:"query" binding:"required"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+}
+
+type Handler struct {
+	schema   *graphql.Schema
+	jobs     job.Repository
+	timelogs timelog.Repository
+	payments payment.Repository
+}
+
+// NewHandler serves the GraphQL schema over the repositories. It reads
+// through them directly since the schema only has queries.
+func NewHandler(jobs job.Repository, timelogs timelog.Repository, payments payment.Repository) *Handler {
+	r := &resolver{jobs: jobs, timelogs: timelogs, payments: payments}
+	return &Handler{
+		schema:   graphql.MustParseSchema(schemaSDL, r, graphql.UseStringDescriptions()),
+		jobs:     jobs,
+		timelogs: timelogs,
+		payments: payments,
+	}
+}
+
+func (h *Handler) RegisterRoutes(r *gin.Engine) {
+	r.POST("/graphql", h.Query)
+	r.GET("/graphql/schema", h.Schema)
+}
+
+// Query executes a request. As with any GraphQL server, errors while
+// resolving fields are reported in the errors of a 200 response.
+func (h *Handler) Query(c *gin.Context) {
+	var req Request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	// Loaders cache what they load, so they live for one request only.
+	ctx := withLoaders(c.Request.Context(), newLoaders(h.jobs, h.timelogs, h.payments))
+	c.JSON(http.StatusOK, h.schema.Exec(ctx, req.Query, req.OperationName, req.Variables))
+}
+
+// Schema serves the schema in SDL.
+func (h *Handler) Schema(c *gin.Context) {
+	c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(schemaSDL))
+}
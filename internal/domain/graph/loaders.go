@@ -0,0 +1,200 @@
+package graph
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/graph-gophers/dataloader/v7"
+	job "mercor/internal/domain/jobs"
+	payment "mercor/internal/domain/paymentLineItem"
+	timelog "mercor/internal/domain/timelog"
+)
+
+// snapshotKey addresses the entities matching ID at a point in time. The zero
+// At selects head versions.
+type snapshotKey struct {
+	ID uuid.UUID
+	At time.Time
+}
+
+// keyAt builds the key for id at a point in time, nil for head versions.
+func keyAt(id uuid.UUID, at *time.Time) snapshotKey {
+	if at == nil {
+		return snapshotKey{ID: id}
+	}
+	// Equal instants must map to the same key whatever their location.
+	return snapshotKey{ID: id, At: at.UTC().Round(0)}
+}
+
+func (k snapshotKey) at() *time.Time {
+	if k.At.IsZero() {
+		return nil
+	}
+	return &k.At
+}
+
+// loaders batch the lookups of one request, so resolving a field on every
+// element of a list costs one query per field rather than one per element.
+type loaders struct {
+	jobVersions          *dataloader.Loader[uuid.UUID, []job.Job]
+	jobsByUID            *dataloader.Loader[uuid.UUID, []job.Job]
+	jobsByID             *dataloader.Loader[snapshotKey, []job.Job]
+	jobsByContractor     *dataloader.Loader[snapshotKey, []job.Job]
+	timelogVersions      *dataloader.Loader[uuid.UUID, []timelog.Timelog]
+	timelogsByUID        *dataloader.Loader[uuid.UUID, []timelog.Timelog]
+	timelogsByID         *dataloader.Loader[snapshotKey, []timelog.Timelog]
+	timelogsByContractor *dataloader.Loader[snapshotKey, []timelog.Timelog]
+	timelogsByJob        *dataloader.Loader[snapshotKey, []timelog.Timelog]
+	paymentVersions      *dataloader.Loader[uuid.UUID, []payment.PaymentLineItem]
+	paymentsByID         *dataloader.Loader[snapshotKey, []payment.PaymentLineItem]
+	paymentsByContractor *dataloader.Loader[snapshotKey, []payment.PaymentLineItem]
+	paymentsByTimelog    *dataloader.Loader[snapshotKey, []payment.PaymentLineItem]
+}
+
+func newLoaders(jobs job.Repository, timelogs timelog.Repository, payments payment.Repository) *loaders {
+	jobID := func(j job.Job) uuid.UUID { return j.ID }
+	jobUID := func(j job.Job) uuid.UUID { return j.UID }
+	jobContractor := func(j job.Job) uuid.UUID { return j.ContractorID }
+	timelogID := func(t timelog.Timelog) uuid.UUID { return t.ID }
+	timelogUID := func(t timelog.Timelog) uuid.UUID { return t.UID }
+	timelogContractor := func(t timelog.Timelog) uuid.UUID { return t.ContractorID }
+	timelogJob := func(t timelog.Timelog) *uuid.UUID { return t.JobUID }
+	paymentID := func(p payment.PaymentLineItem) uuid.UUID { return p.ID }
+	paymentContractor := func(p payment.PaymentLineItem) uuid.UUID { return p.ContractorID }
+	paymentTimelog := func(p payment.PaymentLineItem) *uuid.UUID { return p.TimelogUID }
+	return &loaders{
+		jobVersions:          versionsLoader(jobs.VersionsByIDs, jobID),
+		jobsByUID:            versionsLoader(jobs.FindByUIDs, jobUID),
+		jobsByID:             snapshotLoader(jobs.FindByIDs, jobID),
+		jobsByContractor:     snapshotLoader(jobs.FindByContractors, jobContractor),
+		timelogVersions:      versionsLoader(timelogs.VersionsByIDs, timelogID),
+		timelogsByUID:        versionsLoader(timelogs.FindByUIDs, timelogUID),
+		timelogsByID:         snapshotLoader(timelogs.FindByIDs, timelogID),
+		timelogsByContractor: snapshotLoader(timelogs.FindByContractors, timelogContractor),
+		timelogsByJob:        linkLoader(jobs.VersionsByIDs, jobID, jobUID, timelogs.FindByJobs, timelogJob),
+		paymentVersions:      versionsLoader(payments.VersionsByIDs, paymentID),
+		paymentsByID:         snapshotLoader(payments.FindByIDs, paymentID),
+		paymentsByContractor: snapshotLoader(payments.FindByContractors, paymentContractor),
+		paymentsByTimelog:    linkLoader(timelogs.VersionsByIDs, timelogID, timelogUID, payments.FindByTimelogs, paymentTimelog),
+	}
+}
+
+type loadersKey struct{}
+
+func withLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
+
+// versionsLoader groups every version returned by load under the ID key
+// selects.
+func versionsLoader[T any](load func([]uuid.UUID) ([]T, error), key func(T) uuid.UUID) *dataloader.Loader[uuid.UUID, []T] {
+	return dataloader.NewBatchedLoader(func(_ context.Context, ids []uuid.UUID) []*dataloader.Result[[]T] {
+		list, err := load(ids)
+		return group(ids, list, err, func(id uuid.UUID) uuid.UUID { return id }, key)
+	})
+}
+
+// snapshotLoader runs load once per distinct point in time among the keys and
+// groups the results by the value key selects.
+func snapshotLoader[T any](load func([]uuid.UUID, *time.Time) ([]T, error), key func(T) uuid.UUID) *dataloader.Loader[snapshotKey, []T] {
+	return dataloader.NewBatchedLoader(func(_ context.Context, keys []snapshotKey) []*dataloader.Result[[]T] {
+		byTime := map[time.Time][]uuid.UUID{}
+		for _, k := range keys {
+			byTime[k.At] = append(byTime[k.At], k.ID)
+		}
+		found := map[snapshotKey][]T{}
+		for at, ids := range byTime {
+			k := snapshotKey{At: at}
+			list, err := load(ids, k.at())
+			if err != nil {
+				return group(keys, nil, err, nil, key)
+			}
+			for _, item := range list {
+				k.ID = key(item)
+				found[k] = append(found[k], item)
+			}
+		}
+		out := make([]*dataloader.Result[[]T], len(keys))
+		for i, k := range keys {
+			out[i] = &dataloader.Result[[]T]{Data: found[k]}
+		}
+		return out
+	})
+}
+
+// linkLoader loads the items linked to any version of the parent entities
+// keyed by ID. versions lists those versions; load selects the items whose
+// link holds one of their uids at a point in time.
+func linkLoader[P any, T any](
+	versions func([]uuid.UUID) ([]P, error), parentID, parentUID func(P) uuid.UUID,
+	load func([]uuid.UUID, *time.Time) ([]T, error), link func(T) *uuid.UUID,
+) *dataloader.Loader[snapshotKey, []T] {
+	return dataloader.NewBatchedLoader(func(_ context.Context, keys []snapshotKey) []*dataloader.Result[[]T] {
+		fail := func(err error) []*dataloader.Result[[]T] {
+			return group[snapshotKey, T](keys, nil, err, nil, nil)
+		}
+		ids := make([]uuid.UUID, len(keys))
+		for i, k := range keys {
+			ids[i] = k.ID
+		}
+		parents, err := versions(ids)
+		if err != nil {
+			return fail(err)
+		}
+		owner := map[uuid.UUID]uuid.UUID{}
+		uids := map[uuid.UUID][]uuid.UUID{}
+		for _, p := range parents {
+			owner[parentUID(p)] = parentID(p)
+			uids[parentID(p)] = append(uids[parentID(p)], parentUID(p))
+		}
+		byTime := map[time.Time][]uuid.UUID{}
+		for _, k := range keys {
+			byTime[k.At] = append(byTime[k.At], uids[k.ID]...)
+		}
+		found := map[snapshotKey][]T{}
+		for at, linked := range byTime {
+			k := snapshotKey{At: at}
+			if len(linked) == 0 {
+				continue
+			}
+			list, err := load(linked, k.at())
+			if err != nil {
+				return fail(err)
+			}
+			for _, item := range list {
+				k.ID = owner[*link(item)]
+				found[k] = append(found[k], item)
+			}
+		}
+		out := make([]*dataloader.Result[[]T], len(keys))
+		for i, k := range keys {
+			out[i] = &dataloader.Result[[]T]{Data: found[k]}
+		}
+		return out
+	})
+}
+
+// group returns a result per key with the items whose itemKey equals the
+// key's ID, or err for every key.
+func group[K any, T any](keys []K, items []T, err error, keyID func(K) uuid.UUID, itemKey func(T) uuid.UUID) []*dataloader.Result[[]T] {
+	out := make([]*dataloader.Result[[]T], len(keys))
+	if err != nil {
+		for i := range out {
+			out[i] = &dataloader.Result[[]T]{Error: err}
+		}
+		return out
+	}
+	byID := map[uuid.UUID][]T{}
+	for _, item := range items {
+		byID[itemKey(item)] = append(byID[itemKey(item)], item)
+	}
+	for i, k := range keys {
+		out[i] = &dataloader.Result[[]T]{Data: byID[keyID(k)]}
+	}
+	return out
+}
@@ -0,0 +1,41 @@
+package graph
+
+import (
+	"net/http"
+
+	"mercor/internal/openapi"
+)
+
+// Response is the result of a GraphQL request.
+type Response struct {
+	Data   map[string]any  `json:"data,omitempty"`
+	Errors []ResponseError `json:"errors,omitempty"`
+}
+
+type ResponseError struct {
+	Message string `json:"message"`
+	Path    []any  `json:"path,omitempty"`
+}
+
+// Operations documents the routes registered by Handler.
+func Operations() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method: http.MethodPost, Path: "/graphql", Tag: "graphql",
+			Summary:     "Run a GraphQL query",
+			Description: "Queries jobs, timelogs and payment line items with their versions and relationships at any point in time. See /graphql/schema for the schema.",
+			Body:        openapi.Body(Request{}),
+			Responses: openapi.Responses{
+				200: openapi.JSON("The result; field errors are listed in errors", Response{}),
+				400: openapi.BadRequest,
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/graphql/schema", Tag: "graphql",
+			Summary: "Get the GraphQL schema",
+			Responses: openapi.Responses{
+				200: {Description: "The schema in SDL", Content: map[string]any{"text/plain": openapi.Schema{"type": "string"}}},
+			},
+		},
+	}
+}
@@ -0,0 +1,417 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/graph-gophers/graphql-go"
+	"gorm.io/gorm"
+	job "mercor/internal/domain/jobs"
+	payment "mercor/internal/domain/paymentLineItem"
+	timelog "mercor/internal/domain/timelog"
+)
+
+type resolver struct {
+	jobs     job.Repository
+	timelogs timelog.Repository
+	payments payment.Repository
+}
+
+type uidArgs struct {
+	UID  graphql.ID
+	AsOf *graphql.Time
+}
+
+type companyArgs struct {
+	CompanyID graphql.ID
+	AsOf      *graphql.Time
+}
+
+type contractorArgs struct {
+	ContractorID graphql.ID
+	AsOf         *graphql.Time
+}
+
+type asOfArgs struct {
+	AsOf *graphql.Time
+}
+
+type timeArgs struct {
+	Time graphql.Time
+}
+
+// timeOf converts an optional time argument.
+func timeOf(t *graphql.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	return &t.Time
+}
+
+// within picks the point in time of a relationship: its own asOf argument,
+// else the one its parent was resolved at.
+func within(arg *graphql.Time, parent *time.Time) *time.Time {
+	if arg != nil {
+		return &arg.Time
+	}
+	return parent
+}
+
+func parseID(id graphql.ID) (uuid.UUID, error) {
+	u, err := uuid.Parse(string(id))
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("invalid id %q", id)
+	}
+	return u, nil
+}
+
+// notFound reports a missing entity, which resolves to null.
+func notFound(err error) bool {
+	return errors.Is(err, gorm.ErrRecordNotFound)
+}
+
+func (r *resolver) Job(args uidArgs) (*jobResolver, error) {
+	if _, err := parseID(args.UID); err != nil {
+		return nil, err
+	}
+	at := timeOf(args.AsOf)
+	var j job.Job
+	var err error
+	if at != nil {
+		j, err = r.jobs.FindAsOf(string(args.UID), *at)
+	} else {
+		j, err = r.jobs.FindByUID(string(args.UID))
+	}
+	if notFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &jobResolver{j: j, at: at}, nil
+}
+
+func (r *resolver) Timelog(args uidArgs) (*timelogResolver, error) {
+	if _, err := parseID(args.UID); err != nil {
+		return nil, err
+	}
+	at := timeOf(args.AsOf)
+	var t timelog.Timelog
+	var err error
+	if at != nil {
+		t, err = r.timelogs.FindAsOf(string(args.UID), *at)
+	} else {
+		t, err = r.timelogs.FindByUID(string(args.UID))
+	}
+	if notFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &timelogResolver{t: t, at: at}, nil
+}
+
+func (r *resolver) PaymentLineItem(args uidArgs) (*paymentResolver, error) {
+	if _, err := parseID(args.UID); err != nil {
+		return nil, err
+	}
+	at := timeOf(args.AsOf)
+	var p payment.PaymentLineItem
+	var err error
+	if at != nil {
+		p, err = r.payments.FindAsOf(string(args.UID), *at)
+	} else {
+		p, err = r.payments.FindByUID(string(args.UID))
+	}
+	if notFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &paymentResolver{p: p, at: at}, nil
+}
+
+func (r *resolver) CompanyJobs(args companyArgs) ([]*jobResolver, error) {
+	id, err := parseID(args.CompanyID)
+	if err != nil {
+		return nil, err
+	}
+	at := timeOf(args.AsOf)
+	list, err := r.jobs.FindByCompanies([]uuid.UUID{id}, at)
+	return jobResolvers(list, at), err
+}
+
+func (r *resolver) ContractorJobs(ctx context.Context, args contractorArgs) ([]*jobResolver, error) {
+	id, err := parseID(args.ContractorID)
+	if err != nil {
+		return nil, err
+	}
+	return contractorJobs(ctx, id, timeOf(args.AsOf))
+}
+
+func (r *resolver) ContractorTimelogs(ctx context.Context, args contractorArgs) ([]*timelogResolver, error) {
+	id, err := parseID(args.ContractorID)
+	if err != nil {
+		return nil, err
+	}
+	return contractorTimelogs(ctx, id, timeOf(args.AsOf))
+}
+
+func (r *resolver) ContractorPaymentLineItems(ctx context.Context, args contractorArgs) ([]*paymentResolver, error) {
+	id, err := parseID(args.ContractorID)
+	if err != nil {
+		return nil, err
+	}
+	return contractorPayments(ctx, id, timeOf(args.AsOf))
+}
+
+// The relationship loaders shared by the entity resolvers.
+
+func contractorJobs(ctx context.Context, contractorID uuid.UUID, at *time.Time) ([]*jobResolver, error) {
+	list, err := loadersFrom(ctx).jobsByContractor.Load(ctx, keyAt(contractorID, at))()
+	return jobResolvers(list, at), err
+}
+
+func contractorTimelogs(ctx context.Context, contractorID uuid.UUID, at *time.Time) ([]*timelogResolver, error) {
+	list, err := loadersFrom(ctx).timelogsByContractor.Load(ctx, keyAt(contractorID, at))()
+	return timelogResolvers(list, at), err
+}
+
+func contractorPayments(ctx context.Context, contractorID uuid.UUID, at *time.Time) ([]*paymentResolver, error) {
+	list, err := loadersFrom(ctx).paymentsByContractor.Load(ctx, keyAt(contractorID, at))()
+	return paymentResolvers(list, at), err
+}
+
+// linkedJob resolves the job the version uid belongs to, or null for no link.
+func linkedJob(ctx context.Context, uid *uuid.UUID, at *time.Time) (*jobResolver, error) {
+	if uid == nil {
+		return nil, nil
+	}
+	l := loadersFrom(ctx)
+	versions, err := l.jobsByUID.Load(ctx, *uid)()
+	if err != nil || len(versions) == 0 {
+		return nil, err
+	}
+	list, err := l.jobsByID.Load(ctx, keyAt(versions[0].ID, at))()
+	if err != nil || len(list) == 0 {
+		return nil, err
+	}
+	return &jobResolver{j: list[0], at: at}, nil
+}
+
+// linkedTimelog resolves the timelog the version uid belongs to, see
+// linkedJob.
+func linkedTimelog(ctx context.Context, uid *uuid.UUID, at *time.Time) (*timelogResolver, error) {
+	if uid == nil {
+		return nil, nil
+	}
+	l := loadersFrom(ctx)
+	versions, err := l.timelogsByUID.Load(ctx, *uid)()
+	if err != nil || len(versions) == 0 {
+		return nil, err
+	}
+	list, err := l.timelogsByID.Load(ctx, keyAt(versions[0].ID, at))()
+	if err != nil || len(list) == 0 {
+		return nil, err
+	}
+	return &timelogResolver{t: list[0], at: at}, nil
+}
+
+func optionalID(id *uuid.UUID) *graphql.ID {
+	if id == nil {
+		return nil
+	}
+	s := graphql.ID(id.String())
+	return &s
+}
+
+// jobResolver resolves one version of a job. at is the point in time it was
+// selected for, which its relationships default to; nil means now.
+type jobResolver struct {
+	j  job.Job
+	at *time.Time
+}
+
+func jobResolvers(list []job.Job, at *time.Time) []*jobResolver {
+	out := make([]*jobResolver, len(list))
+	for i, j := range list {
+		out[i] = &jobResolver{j: j, at: at}
+	}
+	return out
+}
+
+func (r *jobResolver) ID() graphql.ID           { return graphql.ID(r.j.ID.String()) }
+func (r *jobResolver) UID() graphql.ID          { return graphql.ID(r.j.UID.String()) }
+func (r *jobResolver) Version() int32           { return int32(r.j.Version) }
+func (r *jobResolver) Title() string            { return r.j.Title }
+func (r *jobResolver) Status() string           { return r.j.Status }
+func (r *jobResolver) Rate() float64            { return r.j.Rate }
+func (r *jobResolver) Currency() string         { return r.j.Currency }
+func (r *jobResolver) CompanyID() graphql.ID    { return graphql.ID(r.j.CompanyID.String()) }
+func (r *jobResolver) ContractorID() graphql.ID { return graphql.ID(r.j.ContractorID.String()) }
+func (r *jobResolver) CreatedAt() graphql.Time  { return graphql.Time{Time: r.j.CreatedAt} }
+func (r *jobResolver) UpdatedAt() graphql.Time  { return graphql.Time{Time: r.j.UpdatedAt} }
+
+func (r *jobResolver) Versions(ctx context.Context) ([]*jobResolver, error) {
+	list, err := loadersFrom(ctx).jobVersions.Load(ctx, r.j.ID)()
+	return jobResolvers(list, nil), err
+}
+
+func (r *jobResolver) AsOf(ctx context.Context, args timeArgs) (*jobResolver, error) {
+	at := &args.Time.Time
+	list, err := loadersFrom(ctx).jobsByID.Load(ctx, keyAt(r.j.ID, at))()
+	if err != nil || len(list) == 0 {
+		return nil, err
+	}
+	return &jobResolver{j: list[0], at: at}, nil
+}
+
+func (r *jobResolver) Timelogs(ctx context.Context, args asOfArgs) ([]*timelogResolver, error) {
+	at := within(args.AsOf, r.at)
+	list, err := loadersFrom(ctx).timelogsByJob.Load(ctx, keyAt(r.j.ID, at))()
+	return timelogResolvers(list, at), err
+}
+
+func (r *jobResolver) PaymentLineItems(ctx context.Context, args asOfArgs) ([]*paymentResolver, error) {
+	at := within(args.AsOf, r.at)
+	timelogs, err := loadersFrom(ctx).timelogsByJob.Load(ctx, keyAt(r.j.ID, at))()
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]snapshotKey, len(timelogs))
+	for i, t := range timelogs {
+		keys[i] = keyAt(t.ID, at)
+	}
+	var list []payment.PaymentLineItem
+	results, errs := loadersFrom(ctx).paymentsByTimelog.LoadMany(ctx, keys)()
+	for i := range results {
+		if errs != nil && errs[i] != nil {
+			return nil, errs[i]
+		}
+		list = append(list, results[i]...)
+	}
+	return paymentResolvers(list, at), nil
+}
+
+// timelogResolver resolves one version of a timelog, see jobResolver.
+type timelogResolver struct {
+	t  timelog.Timelog
+	at *time.Time
+}
+
+func timelogResolvers(list []timelog.Timelog, at *time.Time) []*timelogResolver {
+	out := make([]*timelogResolver, len(list))
+	for i, t := range list {
+		out[i] = &timelogResolver{t: t, at: at}
+	}
+	return out
+}
+
+func (r *timelogResolver) ID() graphql.ID           { return graphql.ID(r.t.ID.String()) }
+func (r *timelogResolver) UID() graphql.ID          { return graphql.ID(r.t.UID.String()) }
+func (r *timelogResolver) Version() int32           { return int32(r.t.Version) }
+func (r *timelogResolver) ContractorID() graphql.ID { return graphql.ID(r.t.ContractorID.String()) }
+func (r *timelogResolver) StartTime() graphql.Time  { return graphql.Time{Time: r.t.StartTime} }
+func (r *timelogResolver) EndTime() graphql.Time    { return graphql.Time{Time: r.t.EndTime} }
+func (r *timelogResolver) CreatedAt() graphql.Time  { return graphql.Time{Time: r.t.CreatedAt} }
+func (r *timelogResolver) UpdatedAt() graphql.Time  { return graphql.Time{Time: r.t.UpdatedAt} }
+
+func (r *timelogResolver) ExternalRef() *string {
+	return optional(r.t.ExternalRef)
+}
+
+func (r *timelogResolver) PreviousExternalRef() *string {
+	return optional(r.t.PreviousExternalRef)
+}
+
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func (r *timelogResolver) Versions(ctx context.Context) ([]*timelogResolver, error) {
+	list, err := loadersFrom(ctx).timelogVersions.Load(ctx, r.t.ID)()
+	return timelogResolvers(list, nil), err
+}
+
+func (r *timelogResolver) AsOf(ctx context.Context, args timeArgs) (*timelogResolver, error) {
+	at := &args.Time.Time
+	list, err := loadersFrom(ctx).timelogsByID.Load(ctx, keyAt(r.t.ID, at))()
+	if err != nil || len(list) == 0 {
+		return nil, err
+	}
+	return &timelogResolver{t: list[0], at: at}, nil
+}
+
+func (r *timelogResolver) JobUID() *graphql.ID { return optionalID(r.t.JobUID) }
+
+func (r *timelogResolver) Job(ctx context.Context, args asOfArgs) (*jobResolver, error) {
+	return linkedJob(ctx, r.t.JobUID, within(args.AsOf, r.at))
+}
+
+func (r *timelogResolver) PaymentLineItems(ctx context.Context, args asOfArgs) ([]*paymentResolver, error) {
+	at := within(args.AsOf, r.at)
+	list, err := loadersFrom(ctx).paymentsByTimelog.Load(ctx, keyAt(r.t.ID, at))()
+	return paymentResolvers(list, at), err
+}
+
+// paymentResolver resolves one version of a payment line item, see
+// jobResolver.
+type paymentResolver struct {
+	p  payment.PaymentLineItem
+	at *time.Time
+}
+
+func paymentResolvers(list []payment.PaymentLineItem, at *time.Time) []*paymentResolver {
+	out := make([]*paymentResolver, len(list))
+	for i, p := range list {
+		out[i] = &paymentResolver{p: p, at: at}
+	}
+	return out
+}
+
+func (r *paymentResolver) ID() graphql.ID           { return graphql.ID(r.p.ID.String()) }
+func (r *paymentResolver) UID() graphql.ID          { return graphql.ID(r.p.UID.String()) }
+func (r *paymentResolver) Version() int32           { return int32(r.p.Version) }
+func (r *paymentResolver) ContractorID() graphql.ID { return graphql.ID(r.p.ContractorID.String()) }
+func (r *paymentResolver) Amount() float64          { return r.p.Amount }
+func (r *paymentResolver) IssuedAt() graphql.Time   { return graphql.Time{Time: r.p.IssuedAt} }
+func (r *paymentResolver) Status() string           { return r.p.Status }
+func (r *paymentResolver) CreatedAt() graphql.Time  { return graphql.Time{Time: r.p.CreatedAt} }
+func (r *paymentResolver) UpdatedAt() graphql.Time  { return graphql.Time{Time: r.p.UpdatedAt} }
+
+func (r *paymentResolver) Versions(ctx context.Context) ([]*paymentResolver, error) {
+	list, err := loadersFrom(ctx).paymentVersions.Load(ctx, r.p.ID)()
+	return paymentResolvers(list, nil), err
+}
+
+func (r *paymentResolver) AsOf(ctx context.Context, args timeArgs) (*paymentResolver, error) {
+	at := &args.Time.Time
+	list, err := loadersFrom(ctx).paymentsByID.Load(ctx, keyAt(r.p.ID, at))()
+	if err != nil || len(list) == 0 {
+		return nil, err
+	}
+	return &paymentResolver{p: list[0], at: at}, nil
+}
+
+func (r *paymentResolver) TimelogUID() *graphql.ID { return optionalID(r.p.TimelogUID) }
+
+func (r *paymentResolver) Timelog(ctx context.Context, args asOfArgs) (*timelogResolver, error) {
+	return linkedTimelog(ctx, r.p.TimelogUID, within(args.AsOf, r.at))
+}
+
+func (r *paymentResolver) Job(ctx context.Context, args asOfArgs) (*jobResolver, error) {
+	at := within(args.AsOf, r.at)
+	t, err := linkedTimelog(ctx, r.p.TimelogUID, at)
+	if err != nil || t == nil {
+		return nil, err
+	}
+	return linkedJob(ctx, t.t.JobUID, at)
+}
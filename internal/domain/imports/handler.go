@@ -0,0 +1,137 @@
+package imports
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Handler struct {
+	svc Service
+}
+
+func NewHandler(s Service) *Handler {
+	return &Handler{svc: s}
+}
+
+func (h *Handler) RegisterRoutes(r *gin.Engine) {
+	r.POST("/imports/timelogs", h.Upload)
+	r.GET("/imports/:id", h.Get)
+	r.POST("/imports/:id/commit", h.Commit)
+	r.GET("/imports/:id/errors", h.Errors)
+}
+
+// Upload accepts a multipart form with a "file" (.csv or .xlsx), an optional
+// "mapping" JSON object of timelog field to column header, and "dryRun".
+func (h *Handler) Upload(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, MaxUploadBytes+1<<20)
+	fh, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if fh.Size > MaxUploadBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "file too large"})
+		return
+	}
+	var mapping Mapping
+	if raw := c.PostForm("mapping"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &mapping); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid mapping: " + err.Error()})
+			return
+		}
+	}
+	dryRun, _ := strconv.ParseBool(c.DefaultPostForm("dryRun", c.Query("dryRun")))
+
+	f, err := fh.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	imp, err := h.svc.Start(fh.Filename, data, mapping, dryRun)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusAccepted, imp)
+}
+
+func (h *Handler) Get(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+	imp, results, err := h.svc.Get(id)
+	if err != nil {
+		respondLookupError(c, err)
+		return
+	}
+	if len(results) > previewRows {
+		results = results[:previewRows]
+	}
+	c.JSON(http.StatusOK, Details{Import: imp, Rows: results})
+}
+
+func (h *Handler) Commit(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+	imp, err := h.svc.Commit(id)
+	if errors.Is(err, ErrNotCommittable) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		respondLookupError(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, imp)
+}
+
+// Errors downloads a CSV with one line per row error.
+func (h *Handler) Errors(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+	if _, _, err := h.svc.Get(id); err != nil {
+		respondLookupError(c, err)
+		return
+	}
+	c.Header("Content-Type", "text/csv")
+	c.Header("Content-Disposition", `attachment; filename="import-`+id.String()+`-errors.csv"`)
+	if err := h.svc.WriteErrorReport(id, c.Writer); err != nil {
+		c.Error(err)
+	}
+}
+
+func parseID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid import id"})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func respondLookupError(c *gin.Context, err error) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
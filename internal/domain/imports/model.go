@@ -0,0 +1,70 @@
+package imports
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// MaxUploadBytes bounds the size of an uploaded spreadsheet.
+	MaxUploadBytes = 10 << 20
+	// MaxRows bounds the number of data rows in one import.
+	MaxRows = 50000
+	// previewRows is how many row results are returned with an import.
+	previewRows = 100
+)
+
+const (
+	StatusProcessing = "processing"
+	StatusPreviewed  = "previewed"
+	StatusCommitting = "committing"
+	StatusCompleted  = "completed"
+	StatusFailed     = "failed"
+)
+
+// Row actions decided during validation.
+const (
+	ActionCreate    = "create"
+	ActionVersion   = "version"
+	ActionUnchanged = "unchanged"
+	ActionError     = "error"
+)
+
+// Import tracks one uploaded file through validation and commit. Rows and
+// Results hold JSON so a previewed import can be committed later.
+type Import struct {
+	ID          uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	Entity      string    `json:"entity"`
+	FileName    string    `json:"fileName"`
+	Status      string    `json:"status"`
+	DryRun      bool      `json:"dryRun"`
+	TotalRows   int       `json:"totalRows"`
+	ValidRows   int       `json:"validRows"`
+	InvalidRows int       `json:"invalidRows"`
+	Created     int       `json:"created"`
+	Versioned   int       `json:"versioned"`
+	Unchanged   int       `json:"unchanged"`
+	Error       string    `json:"error,omitempty"`
+	Rows        string    `gorm:"type:text" json:"-"`
+	Results     string    `gorm:"type:text" json:"-"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+func (Import) TableName() string { return "imports" }
+
+// RowResult is the outcome of validating, and later committing, one row.
+type RowResult struct {
+	Line        int      `json:"line"`
+	ExternalRef string   `json:"externalRef"`
+	Action      string   `json:"action"`
+	TimelogUID  string   `json:"timelogUid,omitempty"`
+	Errors      []string `json:"errors,omitempty"`
+}
+
+// Details is an import together with the first row results.
+type Details struct {
+	Import Import      `json:"import"`
+	Rows   []RowResult `json:"rows"`
+}
@@ -0,0 +1,65 @@
+package imports
+
+import (
+	"net/http"
+
+	"mercor/internal/openapi"
+)
+
+// Operations documents the routes registered by Handler.
+func Operations() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method: http.MethodPost, Path: "/imports/timelogs", Tag: "imports",
+			Summary:     "Upload a CSV or XLSX file of timelogs",
+			Description: "Rows are validated in the background; poll GET /imports/{id} for the outcome.",
+			Params: []openapi.Param{
+				{Name: "dryRun", In: "query", Schema: openapi.Schema{"type": "boolean"}},
+			},
+			Body: &openapi.RequestBody{Content: map[string]any{
+				"multipart/form-data": openapi.Schema{
+					"type":     "object",
+					"required": []string{"file"},
+					"properties": openapi.Schema{
+						"file":    openapi.Schema{"type": "string", "contentMediaType": "application/octet-stream"},
+						"mapping": openapi.Schema{"type": "string", "description": "JSON object of timelog field (externalRef, contractorId, startTime, endTime, jobUid) to column header."},
+						"dryRun":  openapi.Schema{"type": "boolean"},
+					},
+				},
+			}},
+			Responses: openapi.Responses{
+				202: openapi.JSON("The import was accepted", Import{}),
+				400: openapi.BadRequest,
+				413: openapi.JSON("The file is too large", openapi.Error{}),
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/imports/:id", Tag: "imports",
+			Summary: "Get an import and its first row results",
+			Responses: openapi.Responses{
+				200: openapi.JSON("The import", Details{}),
+				400: openapi.BadRequest,
+				404: openapi.NotFound,
+			},
+		},
+		{
+			Method: http.MethodPost, Path: "/imports/:id/commit", Tag: "imports",
+			Summary: "Commit a previewed import",
+			Responses: openapi.Responses{
+				202: openapi.JSON("The commit was accepted", Import{}),
+				400: openapi.BadRequest,
+				404: openapi.NotFound,
+				409: openapi.JSON("The import is not awaiting commit", openapi.Error{}),
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/imports/:id/errors", Tag: "imports",
+			Summary: "Download the row errors of an import as CSV",
+			Responses: openapi.Responses{
+				200: {Description: "One line per row error", Content: map[string]any{"text/csv": openapi.Schema{"type": "string"}}},
+				400: openapi.BadRequest,
+				404: openapi.NotFound,
+			},
+		},
+	}
+}
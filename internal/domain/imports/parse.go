@@ -0,0 +1,268 @@
+package imports
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Timelog fields that can be mapped from a column.
+const (
+	FieldExternalRef  = "externalRef"
+	FieldContractorID = "contractorId"
+	FieldStartTime    = "startTime"
+	FieldEndTime      = "endTime"
+	FieldJobUID       = "jobUid"
+)
+
+var timelogFields = []string{FieldExternalRef, FieldContractorID, FieldStartTime, FieldEndTime, FieldJobUID}
+
+// optionalFields may be missing from a file unless the mapping names them.
+var optionalFields = map[string]bool{FieldJobUID: true}
+
+// Mapping maps a timelog field to the header of the column holding it.
+// Unmapped fields are looked up by a header matching the field name, ignoring
+// case, spaces, dashes and underscores.
+type Mapping map[string]string
+
+// Row is one data line of an upload, kept as raw text until it is validated.
+type Row struct {
+	Line         int    `json:"line"`
+	ExternalRef  string `json:"externalRef"`
+	ContractorID string `json:"contractorId"`
+	StartTime    string `json:"startTime"`
+	EndTime      string `json:"endTime"`
+	// JobUID is nil when the file has no job column, which keeps the link of
+	// a timelog the row versions; a blank cell unlinks it.
+	JobUID *string `json:"jobUid,omitempty"`
+}
+
+func parseRows(fileName string, data []byte, m Mapping) ([]Row, error) {
+	var table [][]string
+	var err error
+	switch strings.ToLower(path.Ext(fileName)) {
+	case ".csv":
+		table, err = readCSV(data)
+	case ".xlsx":
+		table, err = readXLSX(data)
+	default:
+		return nil, fmt.Errorf("unsupported file type %q, expected .csv or .xlsx", path.Ext(fileName))
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(table) == 0 {
+		return nil, errors.New("file has no header row")
+	}
+	if len(table)-1 > MaxRows {
+		return nil, fmt.Errorf("file has %d rows, at most %d are allowed", len(table)-1, MaxRows)
+	}
+
+	cols, err := resolveColumns(table[0], m)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []Row
+	for i, rec := range table[1:] {
+		cell := func(field string) string {
+			if c, ok := cols[field]; ok && c < len(rec) {
+				return strings.TrimSpace(rec[c])
+			}
+			return ""
+		}
+		row := Row{
+			Line:         i + 2,
+			ExternalRef:  cell(FieldExternalRef),
+			ContractorID: cell(FieldContractorID),
+			StartTime:    cell(FieldStartTime),
+			EndTime:      cell(FieldEndTime),
+		}
+		job := cell(FieldJobUID)
+		if row == (Row{Line: row.Line}) && job == "" {
+			continue // blank line
+		}
+		if _, ok := cols[FieldJobUID]; ok {
+			row.JobUID = &job
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func resolveColumns(header []string, m Mapping) (map[string]int, error) {
+	index := map[string]int{}
+	for i, h := range header {
+		index[normalizeHeader(h)] = i
+	}
+	cols := map[string]int{}
+	for _, field := range timelogFields {
+		name := field
+		if m[field] != "" {
+			name = m[field]
+		}
+		i, ok := index[normalizeHeader(name)]
+		if !ok && optionalFields[field] && m[field] == "" {
+			continue
+		}
+		if !ok {
+			return nil, fmt.Errorf("no column %q for field %s", name, field)
+		}
+		cols[field] = i
+	}
+	return cols, nil
+}
+
+func normalizeHeader(h string) string {
+	return strings.ToLower(strings.NewReplacer(" ", "", "_", "", "-", "", "\ufeff", "").Replace(strings.TrimSpace(h)))
+}
+
+func readCSV(data []byte) ([][]string, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	return r.ReadAll()
+}
+
+// readXLSX returns the cells of the first worksheet. Only what imports need is
+// supported: shared, inline and plain strings, numbers and booleans.
+func readXLSX(data []byte) ([][]string, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid xlsx: %w", err)
+	}
+	files := map[string]*zip.File{}
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	var shared []string
+	if f, ok := files["xl/sharedStrings.xml"]; ok {
+		var sst struct {
+			Items []struct {
+				T string `xml:"t"`
+				R []struct {
+					T string `xml:"t"`
+				} `xml:"r"`
+			} `xml:"si"`
+		}
+		if err := decodeZipXML(f, &sst); err != nil {
+			return nil, err
+		}
+		for _, si := range sst.Items {
+			s := si.T
+			for _, r := range si.R {
+				s += r.T
+			}
+			shared = append(shared, s)
+		}
+	}
+
+	sheet, ok := files["xl/worksheets/sheet1.xml"]
+	if !ok {
+		return nil, errors.New("invalid xlsx: no worksheet")
+	}
+	var ws struct {
+		Rows []struct {
+			Cells []struct {
+				Ref    string `xml:"r,attr"`
+				Type   string `xml:"t,attr"`
+				Value  string `xml:"v"`
+				Inline struct {
+					T string `xml:"t"`
+				} `xml:"is"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	if err := decodeZipXML(sheet, &ws); err != nil {
+		return nil, err
+	}
+
+	var table [][]string
+	for _, row := range ws.Rows {
+		var rec []string
+		for i, c := range row.Cells {
+			col := i
+			if c.Ref != "" {
+				col = columnIndex(c.Ref)
+			}
+			for len(rec) <= col {
+				rec = append(rec, "")
+			}
+			switch c.Type {
+			case "s":
+				n, err := strconv.Atoi(c.Value)
+				if err != nil || n < 0 || n >= len(shared) {
+					return nil, fmt.Errorf("invalid xlsx: bad shared string in %s", c.Ref)
+				}
+				rec[col] = shared[n]
+			case "inlineStr":
+				rec[col] = c.Inline.T
+			default:
+				rec[col] = c.Value
+			}
+		}
+		table = append(table, rec)
+	}
+	return table, nil
+}
+
+func decodeZipXML(f *zip.File, v any) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	if err := xml.NewDecoder(io.LimitReader(rc, MaxUploadBytes*8)).Decode(v); err != nil {
+		return fmt.Errorf("invalid xlsx: %s: %w", f.Name, err)
+	}
+	return nil
+}
+
+// columnIndex converts a cell reference such as "AB12" to a zero based column.
+func columnIndex(ref string) int {
+	n := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		n = n*26 + int(r-'A'+1)
+	}
+	return n - 1
+}
+
+var timeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+}
+
+// excelEpoch is day zero of spreadsheet serial dates.
+var excelEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+// parseTime accepts the layouts above (UTC unless an offset is given) and
+// spreadsheet serial dates, which is how XLSX stores date cells.
+func parseTime(s string) (time.Time, error) {
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil && f > 0 && f < 2958466 {
+		days := math.Floor(f)
+		secs := math.Round((f - days) * 86400)
+		return excelEpoch.AddDate(0, 0, int(days)).Add(time.Duration(secs) * time.Second), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q", s)
+}
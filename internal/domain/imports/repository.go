@@ -0,0 +1,54 @@
+package imports
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	Create(imp Import) error
+	FindByID(id uuid.UUID) (Import, error)
+	Save(imp Import) error
+	TransitionStatus(id uuid.UUID, from, to string) (bool, error)
+	TransitionAll(from, to, reason string) (int64, error)
+}
+
+type repo struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repo{db: db}
+}
+
+func (r *repo) Create(imp Import) error {
+	return r.db.Create(&imp).Error
+}
+
+func (r *repo) FindByID(id uuid.UUID) (Import, error) {
+	var imp Import
+	err := r.db.Where("id = ?", id).First(&imp).Error
+	return imp, err
+}
+
+func (r *repo) Save(imp Import) error {
+	return r.db.Save(&imp).Error
+}
+
+// TransitionStatus moves an import from one status to another, reporting false
+// if it was not in the expected status.
+func (r *repo) TransitionStatus(id uuid.UUID, from, to string) (bool, error) {
+	res := r.db.Model(&Import{}).
+		Where("id = ? AND status = ?", id, from).
+		Update("status", to)
+	return res.RowsAffected == 1, res.Error
+}
+
+// TransitionAll moves every import in one status to another, recording reason
+// as its error, and returns how many were moved.
+func (r *repo) TransitionAll(from, to, reason string) (int64, error) {
+	res := r.db.Model(&Import{}).
+		Where("status = ?", from).
+		Updates(map[string]any{"status": to, "error": reason})
+	return res.RowsAffected, res.Error
+}
@@ -0,0 +1,408 @@
+package imports
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"mercor/internal/domain/timelog"
+	"mercor/internal/scd"
+)
+
+// maxTimelogDuration is the longest interval a single row may cover.
+const maxTimelogDuration = 24 * time.Hour
+
+var ErrNotCommittable = errors.New("import is not awaiting commit")
+
+// ContractorChecker reports whether a contractor exists.
+type ContractorChecker func(contractorID uuid.UUID) (bool, error)
+
+type Service interface {
+	Start(fileName string, data []byte, m Mapping, dryRun bool) (Import, error)
+	Get(id uuid.UUID) (Import, []RowResult, error)
+	Commit(id uuid.UUID) (Import, error)
+	WriteErrorReport(id uuid.UUID, w io.Writer) error
+	Recover() (int64, error)
+}
+
+type service struct {
+	repo             Repository
+	timelogs         timelog.Service
+	contractorExists ContractorChecker
+}
+
+func NewService(r Repository, timelogs timelog.Service, contractorExists ContractorChecker) Service {
+	return &service{repo: r, timelogs: timelogs, contractorExists: contractorExists}
+}
+
+// Start parses the upload and records an import, then validates (and unless
+// dryRun, commits) it in the background. Parse errors are returned directly.
+func (s *service) Start(fileName string, data []byte, m Mapping, dryRun bool) (Import, error) {
+	rows, err := parseRows(fileName, data, m)
+	if err != nil {
+		return Import{}, err
+	}
+	raw, err := json.Marshal(rows)
+	if err != nil {
+		return Import{}, err
+	}
+	imp := Import{
+		ID:        uuid.New(),
+		Entity:    "timelogs",
+		FileName:  fileName,
+		Status:    StatusProcessing,
+		DryRun:    dryRun,
+		TotalRows: len(rows),
+		Rows:      string(raw),
+	}
+	if err := s.repo.Create(imp); err != nil {
+		return Import{}, err
+	}
+	go s.run(imp, rows)
+	return imp, nil
+}
+
+func (s *service) Get(id uuid.UUID) (Import, []RowResult, error) {
+	imp, err := s.repo.FindByID(id)
+	if err != nil {
+		return Import{}, nil, err
+	}
+	var results []RowResult
+	if imp.Results != "" {
+		if err := json.Unmarshal([]byte(imp.Results), &results); err != nil {
+			return Import{}, nil, err
+		}
+	}
+	return imp, results, nil
+}
+
+// Commit applies a previewed import. Rows are validated again because data may
+// have changed since the preview.
+func (s *service) Commit(id uuid.UUID) (Import, error) {
+	ok, err := s.repo.TransitionStatus(id, StatusPreviewed, StatusCommitting)
+	if err != nil {
+		return Import{}, err
+	}
+	if !ok {
+		return Import{}, ErrNotCommittable
+	}
+	imp, err := s.repo.FindByID(id)
+	if err != nil {
+		return Import{}, err
+	}
+	var rows []Row
+	if err := json.Unmarshal([]byte(imp.Rows), &rows); err != nil {
+		return Import{}, err
+	}
+	imp.DryRun = false
+	go s.run(imp, rows)
+	return imp, nil
+}
+
+func (s *service) WriteErrorReport(id uuid.UUID, w io.Writer) error {
+	_, results, err := s.Get(id)
+	if err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"line", "externalRef", "error"})
+	for _, r := range results {
+		for _, e := range r.Errors {
+			cw.Write([]string{strconv.Itoa(r.Line), r.ExternalRef, e})
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// Recover settles the imports a stopped server left unfinished, as nothing
+// runs them any more, and returns how many there were. Imports being
+// validated fail. Imports being committed are previewed again, so they can be
+// committed once more; rows the interrupted commit wrote are found unchanged
+// then. It must run before the server starts imports.
+func (s *service) Recover() (int64, error) {
+	failed, err := s.repo.TransitionAll(StatusProcessing, StatusFailed, "interrupted by a server restart, upload the file again")
+	if err != nil {
+		return failed, err
+	}
+	reopened, err := s.repo.TransitionAll(StatusCommitting, StatusPreviewed, "commit interrupted by a server restart, commit again")
+	return failed + reopened, err
+}
+
+// plannedRow is a validated row together with the timelog it would write.
+type plannedRow struct {
+	result  RowResult
+	timelog timelog.Timelog
+	keepJob bool // the file has no job column
+}
+
+func (s *service) run(imp Import, rows []Row) {
+	plans := s.plan(rows)
+
+	imp.Error = ""
+	imp.ValidRows, imp.InvalidRows = 0, 0
+	for _, p := range plans {
+		if p.result.Action == ActionError {
+			imp.InvalidRows++
+		} else {
+			imp.ValidRows++
+		}
+	}
+
+	imp.Status = StatusPreviewed
+	if !imp.DryRun {
+		imp.Status = StatusCompleted
+		if err := s.apply(&imp, plans); err != nil {
+			imp.Status = StatusFailed
+			imp.Error = err.Error()
+		}
+	}
+
+	results := make([]RowResult, len(plans))
+	for i, p := range plans {
+		results[i] = p.result
+	}
+	raw, err := json.Marshal(results)
+	if err != nil {
+		imp.Status = StatusFailed
+		imp.Error = err.Error()
+	}
+	imp.Results = string(raw)
+	if err := s.repo.Save(imp); err != nil {
+		log.Printf("import %s: failed to save results: %v", imp.ID, err)
+	}
+}
+
+// apply writes every create and version row in one atomic batch. Invalid rows
+// are skipped and stay in the error report.
+func (s *service) apply(imp *Import, plans []plannedRow) error {
+	var ops []scd.BatchOp[timelog.TimelogRequest]
+	var idx []int
+	for i, p := range plans {
+		data := timelog.TimelogRequest{
+			ContractorID: p.timelog.ContractorID.String(),
+			StartTime:    p.timelog.StartTime,
+			EndTime:      p.timelog.EndTime,
+			ExternalRef:  p.timelog.ExternalRef,
+		}
+		if p.timelog.JobUID != nil {
+			data.JobUID = p.timelog.JobUID.String()
+		}
+		switch p.result.Action {
+		case ActionCreate:
+			ops = append(ops, scd.BatchOp[timelog.TimelogRequest]{Op: scd.BatchOpCreate, Data: data})
+		case ActionVersion:
+			ops = append(ops, scd.BatchOp[timelog.TimelogRequest]{Op: scd.BatchOpUpdate, UID: p.result.TimelogUID, Data: data})
+		case ActionUnchanged:
+			imp.Unchanged++
+			continue
+		default:
+			continue
+		}
+		idx = append(idx, i)
+	}
+	if len(ops) == 0 {
+		return nil
+	}
+
+	results, err := s.timelogs.Batch(scd.BatchRequest[timelog.TimelogRequest]{Mode: scd.BatchAtomic, Items: ops})
+	if err != nil {
+		for n, r := range results {
+			if r.Error != "" {
+				plans[idx[n]].result.Action = ActionError
+				plans[idx[n]].result.Errors = append(plans[idx[n]].result.Errors, r.Error)
+			}
+		}
+		return err
+	}
+	for n, r := range results {
+		p := &plans[idx[n]]
+		p.result.TimelogUID = r.Item.UID.String()
+		if p.result.Action == ActionCreate {
+			imp.Created++
+		} else {
+			imp.Versioned++
+		}
+	}
+	return nil
+}
+
+func (s *service) plan(rows []Row) []plannedRow {
+	plans := make([]plannedRow, len(rows))
+	seenRefs := map[string]int{}
+	contractors := map[uuid.UUID]error{}
+
+	for i, row := range rows {
+		p := &plans[i]
+		p.result = RowResult{Line: row.Line, ExternalRef: row.ExternalRef}
+		fail := func(format string, args ...any) {
+			p.result.Errors = append(p.result.Errors, fmt.Sprintf(format, args...))
+		}
+
+		if row.ExternalRef == "" {
+			fail("externalRef is required")
+		} else if line, dup := seenRefs[row.ExternalRef]; dup {
+			fail("externalRef duplicates line %d", line)
+		} else {
+			seenRefs[row.ExternalRef] = row.Line
+		}
+
+		contractorID, err := uuid.Parse(row.ContractorID)
+		if err != nil {
+			fail("invalid contractorId %q", row.ContractorID)
+		} else {
+			cerr, checked := contractors[contractorID]
+			if !checked {
+				cerr = s.checkContractor(contractorID)
+				contractors[contractorID] = cerr
+			}
+			if cerr != nil {
+				fail("%v", cerr)
+			}
+		}
+
+		start, err := parseTime(row.StartTime)
+		if err != nil {
+			fail("startTime: %v", err)
+		}
+		end, err := parseTime(row.EndTime)
+		if err != nil {
+			fail("endTime: %v", err)
+		}
+		if !start.IsZero() && !end.IsZero() {
+			if !end.After(start) {
+				fail("endTime must be after startTime")
+			} else if end.Sub(start) > maxTimelogDuration {
+				fail("interval is longer than %s", maxTimelogDuration)
+			}
+		}
+
+		var jobUID *uuid.UUID
+		switch {
+		case row.JobUID == nil:
+			p.keepJob = true
+		case *row.JobUID != "":
+			id, err := uuid.Parse(*row.JobUID)
+			if err != nil {
+				fail("invalid jobUid %q", *row.JobUID)
+			}
+			jobUID = &id
+		}
+
+		p.timelog = timelog.Timelog{
+			ContractorID: contractorID,
+			StartTime:    start,
+			EndTime:      end,
+			ExternalRef:  row.ExternalRef,
+			JobUID:       jobUID,
+		}
+	}
+
+	s.checkOverlapsInFile(plans)
+	for i := range plans {
+		if len(plans[i].result.Errors) == 0 {
+			s.resolveAction(&plans[i], seenRefs)
+		}
+		if len(plans[i].result.Errors) > 0 {
+			plans[i].result.Action = ActionError
+		}
+	}
+	return plans
+}
+
+func sameUID(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func (s *service) checkContractor(id uuid.UUID) error {
+	ok, err := s.contractorExists(id)
+	if err != nil {
+		return fmt.Errorf("contractor %s: %v", id, err)
+	}
+	if !ok {
+		return fmt.Errorf("contractor %s does not exist", id)
+	}
+	return nil
+}
+
+// checkOverlapsInFile flags rows whose interval overlaps an earlier row of the
+// same contractor in the upload.
+func (s *service) checkOverlapsInFile(plans []plannedRow) {
+	byContractor := map[uuid.UUID][]*plannedRow{}
+	for i := range plans {
+		if len(plans[i].result.Errors) == 0 {
+			c := plans[i].timelog.ContractorID
+			byContractor[c] = append(byContractor[c], &plans[i])
+		}
+	}
+	for _, list := range byContractor {
+		sort.Slice(list, func(a, b int) bool {
+			return list[a].timelog.StartTime.Before(list[b].timelog.StartTime)
+		})
+		for i := 1; i < len(list); i++ {
+			prev, cur := list[i-1], list[i]
+			if cur.timelog.StartTime.Before(prev.timelog.EndTime) {
+				cur.result.Errors = append(cur.result.Errors,
+					fmt.Sprintf("overlaps line %d", prev.result.Line))
+			}
+		}
+	}
+}
+
+// resolveAction decides between create, version and unchanged by looking up
+// the current timelog with the row's external reference, and rejects rows
+// overlapping existing timelogs that the upload does not replace.
+func (s *service) resolveAction(p *plannedRow, fileRefs map[string]int) {
+	t := p.timelog
+	overlapping, err := s.timelogs.FindOverlapping(t.ContractorID, t.StartTime, t.EndTime)
+	if err != nil {
+		p.result.Errors = append(p.result.Errors, err.Error())
+		return
+	}
+	var clashes []string
+	for _, o := range overlapping {
+		if _, replaced := fileRefs[o.ExternalRef]; o.ExternalRef != "" && replaced {
+			continue
+		}
+		clashes = append(clashes, o.UID.String())
+	}
+	if len(clashes) > 0 {
+		p.result.Errors = append(p.result.Errors,
+			"overlaps existing timelog "+strings.Join(clashes, ", "))
+		return
+	}
+
+	head, err := s.timelogs.GetByExternalRef(t.ExternalRef)
+	if err == nil && p.keepJob {
+		p.timelog.JobUID = head.JobUID
+		t.JobUID = head.JobUID
+	}
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		p.result.Action = ActionCreate
+	case err != nil:
+		p.result.Errors = append(p.result.Errors, err.Error())
+	case head.ContractorID == t.ContractorID && head.StartTime.Equal(t.StartTime) && head.EndTime.Equal(t.EndTime) &&
+		sameUID(head.JobUID, t.JobUID):
+		p.result.Action = ActionUnchanged
+		p.result.TimelogUID = head.UID.String()
+	case head.ContractorID != t.ContractorID:
+		p.result.Errors = append(p.result.Errors,
+			"externalRef belongs to a timelog of contractor "+head.ContractorID.String())
+	default:
+		p.result.Action = ActionVersion
+		p.result.TimelogUID = head.UID.String()
+	}
+}
@@ -0,0 +1,87 @@
+package invoices
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GenerateRequest is the body of POST /invoices. The invoice bills the
+// approved line items of the company issued from PeriodStart up to, but not
+// including, PeriodEnd.
+type GenerateRequest struct {
+	CompanyID   uuid.UUID `json:"companyId" binding:"required"`
+	PeriodStart time.Time `json:"periodStart" binding:"required"`
+	PeriodEnd   time.Time `json:"periodEnd" binding:"required,gtfield=PeriodStart"`
+}
+
+// InvoiceResponse is an invoice version. Lines and totals are only included
+// where a single version is returned.
+type InvoiceResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	UID         uuid.UUID  `json:"uid"`
+	Version     int        `json:"version"`
+	Number      string     `json:"number"`
+	CompanyID   uuid.UUID  `json:"companyId"`
+	CompanyUID  uuid.UUID  `json:"companyUid"`
+	PeriodStart time.Time  `json:"periodStart"`
+	PeriodEnd   time.Time  `json:"periodEnd"`
+	Status      string     `json:"status"`
+	IssuedAt    *time.Time `json:"issuedAt"`
+	DueAt       *time.Time `json:"dueAt"`
+	PaidAt      *time.Time `json:"paidAt"`
+	Lines       []Line     `json:"lines,omitempty"`
+	Totals      []Total    `json:"totals,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+	// UnlinkedLineItems is only set when the invoice is generated: the UIDs
+	// of line items of the company's contractors that were left out because
+	// they have no timelog, or their timelog no job.
+	UnlinkedLineItems []uuid.UUID `json:"unlinkedLineItems,omitempty"`
+	// EffectiveFrom and EffectiveTo bound when the version applied;
+	// RecordedFrom and RecordedTo when it was the recorded state.
+	EffectiveFrom time.Time  `json:"effectiveFrom"`
+	EffectiveTo   *time.Time `json:"effectiveTo"`
+	RecordedFrom  time.Time  `json:"recordedFrom"`
+	RecordedTo    *time.Time `json:"recordedTo"`
+}
+
+func NewInvoiceResponse(i Invoice) InvoiceResponse {
+	return InvoiceResponse{
+		ID:            i.ID,
+		UID:           i.UID,
+		Version:       i.Version,
+		Number:        i.Number,
+		CompanyID:     i.CompanyID,
+		CompanyUID:    i.CompanyUID,
+		PeriodStart:   i.PeriodStart,
+		PeriodEnd:     i.PeriodEnd,
+		Status:        i.Status,
+		IssuedAt:      i.IssuedAt,
+		DueAt:         i.DueAt,
+		PaidAt:        i.PaidAt,
+		CreatedAt:     i.CreatedAt,
+		UpdatedAt:     i.UpdatedAt,
+		EffectiveFrom: i.EffectiveFrom,
+		EffectiveTo:   i.EffectiveTo,
+		RecordedFrom:  i.RecordedFrom,
+		RecordedTo:    i.RecordedTo,
+	}
+}
+
+func NewInvoiceResponses(list []Invoice) []InvoiceResponse {
+	out := make([]InvoiceResponse, len(list))
+	for i, inv := range list {
+		out[i] = NewInvoiceResponse(inv)
+	}
+	return out
+}
+
+// NewDocumentResponse returns the invoice version with its lines and totals.
+func NewDocumentResponse(d Document) InvoiceResponse {
+	resp := NewInvoiceResponse(d.Invoice)
+	resp.Lines = d.Lines
+	resp.Totals = d.Totals()
+	resp.UnlinkedLineItems = d.Unlinked
+	return resp
+}
@@ -0,0 +1,131 @@
+package invoices
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"mercor/internal/idempotency"
+	"mercor/internal/scd"
+	"mercor/internal/validation"
+)
+
+// pdfContentType is the media type of GET /invoices/:uid/pdf.
+const pdfContentType = "application/pdf"
+
+type Handler struct {
+	svc Service
+}
+
+func NewHandler(s Service) *Handler {
+	return &Handler{svc: s}
+}
+
+// service returns the service to serve c with: for a request with an
+// Idempotency-Key, one writing in its transaction, so the stored response
+// commits with the writes.
+func (h *Handler) service(c *gin.Context) Service {
+	if tx, ok := idempotency.Tx(c); ok {
+		return NewService(NewRepository(tx))
+	}
+	return h.svc
+}
+
+func (h *Handler) RegisterRoutes(r *gin.Engine) {
+	r.POST("/invoices", h.Generate)
+	r.GET("/invoices/:uid", h.Get)
+	r.GET("/invoices/:uid/history", h.History)
+	r.GET("/invoices/:uid/pdf", h.PDF)
+	r.PUT("/invoices/:uid/status", h.UpdateStatus)
+	r.GET("/companies/:id/invoices", h.GetByCompany)
+}
+
+// StatusCode maps the errors of invoice operations to HTTP statuses.
+func StatusCode(err error) int {
+	switch {
+	case errors.Is(err, ErrNothingToInvoice):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, ErrInvalidTransition):
+		return http.StatusConflict
+	}
+	return scd.StatusCode(err)
+}
+
+func (h *Handler) Generate(c *gin.Context) {
+	var req GenerateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, validation.Response(err))
+		return
+	}
+	doc, err := h.service(c).Generate(req.CompanyID, req.PeriodStart, req.PeriodEnd)
+	if err != nil {
+		c.JSON(StatusCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("ETag", scd.ETag(doc.UID.String()))
+	c.JSON(http.StatusCreated, NewDocumentResponse(doc))
+}
+
+func (h *Handler) Get(c *gin.Context) {
+	doc, err := h.service(c).Get(c.Param("uid"))
+	if err != nil {
+		c.JSON(StatusCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("ETag", scd.ETag(doc.UID.String()))
+	c.JSON(http.StatusOK, NewDocumentResponse(doc))
+}
+
+func (h *Handler) History(c *gin.Context) {
+	list, err := h.service(c).History(c.Param("uid"))
+	if err != nil {
+		c.JSON(StatusCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, NewInvoiceResponses(list))
+}
+
+// PDF renders the given version as the document sent to the company.
+func (h *Handler) PDF(c *gin.Context) {
+	doc, b, err := h.service(c).PDF(c.Param("uid"))
+	if err != nil {
+		c.JSON(StatusCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("Content-Disposition", `inline; filename="`+doc.Number+`.pdf"`)
+	c.Data(http.StatusOK, pdfContentType, b)
+}
+
+// UpdateStatus moves the invoice to ?status=. It honours If-Match: the write
+// fails with 412 unless the ETag of the head version is listed.
+func (h *Handler) UpdateStatus(c *gin.Context) {
+	status := c.Query("status")
+	if !knownStatus(status) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "status must be one of draft, issued, paid, void"})
+		return
+	}
+	inv, err := h.service(c).UpdateStatus(c.Param("uid"), status, scd.IfMatch(c.GetHeader("If-Match")))
+	if err != nil {
+		c.JSON(StatusCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("ETag", scd.ETag(inv.UID.String()))
+	c.JSON(http.StatusOK, NewInvoiceResponse(inv))
+}
+
+// GetByCompany lists the head versions of the invoices of the company with
+// the ID in :id, newest first.
+func (h *Handler) GetByCompany(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid company ID"})
+		return
+	}
+	list, err := h.service(c).ListByCompany(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, NewInvoiceResponses(list))
+}
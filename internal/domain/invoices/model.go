@@ -0,0 +1,167 @@
+package invoices
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"mercor/internal/scd"
+)
+
+const (
+	StatusDraft  = "draft"
+	StatusIssued = "issued"
+	StatusPaid   = "paid"
+	StatusVoid   = "void"
+)
+
+// transitions lists the statuses an invoice can move to from each status.
+// Paid and void invoices are final.
+var transitions = map[string][]string{
+	StatusDraft:  {StatusIssued, StatusVoid},
+	StatusIssued: {StatusPaid, StatusVoid},
+}
+
+var (
+	// ErrNothingToInvoice is returned when a company has no approved line
+	// items in the period that are not on another invoice.
+	ErrNothingToInvoice = errors.New("no approved line items to invoice in the period")
+	// ErrInvalidTransition is returned when the status of an invoice cannot
+	// change to the one requested.
+	ErrInvalidTransition = errors.New("invalid status transition")
+)
+
+// Invoice is one version of an invoice. Versions differ in their status and
+// in what is set when the invoice is issued or paid; the lines are fixed
+// when the invoice is generated.
+type Invoice struct {
+	ID        uuid.UUID `gorm:"type:uuid" json:"id"`
+	UID       uuid.UUID `gorm:"type:uuid;primaryKey" json:"uid"`
+	Version   int       `json:"version"`
+	Number    string    `gorm:"index" json:"number"`
+	CompanyID uuid.UUID `gorm:"type:uuid;index" json:"companyId"`
+	// CompanyUID pins the company version whose name, billing address and
+	// terms the invoice shows: the head version when it was generated, and
+	// again when it is issued.
+	CompanyUID  uuid.UUID  `gorm:"type:uuid" json:"companyUid"`
+	PeriodStart time.Time  `json:"periodStart"`
+	PeriodEnd   time.Time  `json:"periodEnd"`
+	Status      string     `json:"status"`
+	IssuedAt    *time.Time `json:"issuedAt"`
+	DueAt       *time.Time `json:"dueAt"`
+	PaidAt      *time.Time `json:"paidAt"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+	scd.Bitemporal
+	scd.Reversion
+}
+
+func (Invoice) TableName() string { return "invoices" }
+
+func (i Invoice) GetID() string   { return i.ID.String() }
+func (i Invoice) GetUID() string  { return i.UID.String() }
+func (i Invoice) GetVersion() int { return i.Version }
+
+// ChangePolicy versions every change, so the history shows when an invoice
+// was issued, paid or voided.
+func (Invoice) ChangePolicy() scd.Policy { return nil }
+
+// References requires the company of an invoice to be on record.
+func (Invoice) References() []scd.Reference {
+	return []scd.Reference{scd.RefersTo("company_id", "companies")}
+}
+
+func (i Invoice) CopyForNewVersion() Invoice {
+	return Invoice{
+		ID:          i.ID,
+		Number:      i.Number,
+		CompanyID:   i.CompanyID,
+		CompanyUID:  i.CompanyUID,
+		PeriodStart: i.PeriodStart,
+		PeriodEnd:   i.PeriodEnd,
+		Status:      i.Status,
+		IssuedAt:    i.IssuedAt,
+		DueAt:       i.DueAt,
+		PaidAt:      i.PaidAt,
+		Version:     i.Version + 1,
+		UID:         uuid.New(),
+	}
+}
+
+// knownStatus reports whether status is one an invoice can be in.
+func knownStatus(status string) bool {
+	switch status {
+	case StatusDraft, StatusIssued, StatusPaid, StatusVoid:
+		return true
+	}
+	return false
+}
+
+// canMove reports whether an invoice in status from can move to status to.
+func canMove(from, to string) bool {
+	for _, s := range transitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Line pins one payment line item version to an invoice, with the job and
+// contractor details it is billed under as they were when the invoice was
+// generated.
+type Line struct {
+	InvoiceID      uuid.UUID `gorm:"type:uuid;primaryKey" json:"-"`
+	LineItemUID    uuid.UUID `gorm:"type:uuid;primaryKey" json:"lineItemUid"`
+	LineItemID     uuid.UUID `gorm:"type:uuid;index" json:"lineItemId"`
+	JobUID         uuid.UUID `gorm:"type:uuid" json:"jobUid"`
+	Description    string    `json:"description"`
+	ContractorID   uuid.UUID `gorm:"type:uuid" json:"contractorId"`
+	ContractorName string    `json:"contractorName"`
+	IssuedAt       time.Time `json:"issuedAt"`
+	Amount         float64   `json:"amount"`
+	Currency       string    `json:"currency"`
+}
+
+func (Line) TableName() string { return "invoice_lines" }
+
+// Counter hands out invoice numbers. Its row is locked while an invoice is
+// generated, so numbers are taken in order and only by invoices that are
+// stored.
+type Counter struct {
+	Name string `gorm:"primaryKey"`
+	Last int
+}
+
+func (Counter) TableName() string { return "invoice_counters" }
+
+// Document is an invoice version with its lines. When the invoice is
+// generated, Unlinked lists the line items it left out for lacking a job
+// link.
+type Document struct {
+	Invoice
+	Lines    []Line
+	Unlinked []uuid.UUID
+}
+
+// Total is the sum of the lines of an invoice in one currency.
+type Total struct {
+	Currency string  `json:"currency"`
+	Amount   float64 `json:"amount"`
+}
+
+// Totals sums the lines per currency, in currency order, rounded to cents.
+func (d Document) Totals() []Total {
+	sums := map[string]float64{}
+	for _, l := range d.Lines {
+		sums[l.Currency] += l.Amount
+	}
+	totals := make([]Total, 0, len(sums))
+	for currency, amount := range sums {
+		totals = append(totals, Total{Currency: currency, Amount: math.Round(amount*100) / 100})
+	}
+	sort.Slice(totals, func(i, j int) bool { return totals[i].Currency < totals[j].Currency })
+	return totals
+}
@@ -0,0 +1,79 @@
+package invoices
+
+import (
+	"net/http"
+
+	"mercor/internal/openapi"
+)
+
+// Operations documents the routes registered by Handler.
+func Operations() []openapi.Operation {
+	invoice := openapi.JSON("The invoice version with its lines and totals", InvoiceResponse{})
+	return []openapi.Operation{
+		{
+			Method: http.MethodPost, Path: "/invoices", Tag: "invoices",
+			Summary:     "Generate an invoice",
+			Description: "Bills the approved payment line items of the company issued in [periodStart, periodEnd) that are on no other invoice, unless that one was voided. The invoice is stored as a draft with the next number and pins the line item versions it bills.",
+			Body:        openapi.Body(GenerateRequest{}),
+			Responses: openapi.Responses{
+				201: invoice,
+				400: openapi.Invalid,
+				422: openapi.JSON("The company does not exist or has nothing to invoice in the period", openapi.Error{}),
+				500: openapi.ServerError,
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/invoices/:uid", Tag: "invoices",
+			Summary: "Get an invoice version by UID",
+			Responses: openapi.Responses{
+				200: invoice,
+				404: openapi.NotFound,
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/invoices/:uid/history", Tag: "invoices",
+			Summary: "List every version of an invoice, oldest first",
+			Responses: openapi.Responses{
+				200: openapi.JSON("All versions of the invoice, without lines", []InvoiceResponse{}),
+				404: openapi.NotFound,
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/invoices/:uid/pdf", Tag: "invoices",
+			Summary: "Render an invoice version as PDF",
+			Responses: openapi.Responses{
+				200: {Description: "The invoice document", Content: map[string]any{
+					pdfContentType: openapi.Schema{"type": "string", "format": "binary"},
+				}},
+				404: openapi.NotFound,
+				500: openapi.ServerError,
+			},
+		},
+		{
+			Method: http.MethodPut, Path: "/invoices/:uid/status", Tag: "invoices",
+			Summary:     "Change the status of an invoice",
+			Description: "Drafts can be issued or voided, issued invoices paid or voided. Issuing sets the due date from the payment terms of the company.",
+			Params: []openapi.Param{
+				{Name: "status", In: "query", Required: true, Schema: openapi.Schema{"type": "string", "enum": []string{StatusDraft, StatusIssued, StatusPaid, StatusVoid}}},
+				openapi.IfMatch,
+			},
+			Responses: openapi.Responses{
+				200: openapi.JSON("The new invoice version", InvoiceResponse{}),
+				400: openapi.BadRequest,
+				404: openapi.NotFound,
+				409: openapi.JSON("The invoice cannot move to the status", openapi.Error{}),
+				412: openapi.PreconditionFailed,
+				500: openapi.ServerError,
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/companies/:id/invoices", Tag: "invoices",
+			Summary: "List the invoices of a company, newest first",
+			Responses: openapi.Responses{
+				200: openapi.JSON("Head versions of the invoices, without lines", []InvoiceResponse{}),
+				400: openapi.BadRequest,
+				500: openapi.ServerError,
+			},
+		},
+	}
+}
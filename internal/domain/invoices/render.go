@@ -0,0 +1,70 @@
+package invoices
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"mercor/internal/domain/companies"
+	"mercor/internal/pdf"
+)
+
+const dateLayout = "2006-01-02"
+
+// render lays out an invoice version for the company version it pins: the
+// header, one row per line and the totals per currency.
+func render(doc Document, company companies.Company) *pdf.Document {
+	d := &pdf.Document{Title: "Invoice " + doc.Number}
+	d.Heading("INVOICE " + doc.Number)
+	d.Add("")
+	d.Add(company.Name)
+	d.Add(strings.Split(company.BillingAddress, "\n")...)
+	d.Add("")
+	d.Addf("Status:  %s", doc.Status)
+	d.Addf("Period:  %s to %s", doc.PeriodStart.Format(dateLayout), doc.PeriodEnd.Format(dateLayout))
+	if doc.IssuedAt != nil {
+		d.Addf("Issued:  %s", date(doc.IssuedAt))
+	}
+	if doc.DueAt != nil {
+		d.Addf("Due:     %s", date(doc.DueAt))
+	}
+	if doc.PaidAt != nil {
+		d.Addf("Paid:    %s", date(doc.PaidAt))
+	}
+	d.Add("")
+	d.Heading(row("Date", "Contractor", "Description", "Amount", ""))
+	for _, l := range doc.Lines {
+		d.Add(row(l.IssuedAt.Format(dateLayout), l.ContractorName, l.Description, money(l.Amount), l.Currency))
+	}
+	d.Add("")
+	for _, t := range doc.Totals() {
+		d.Heading(row("", "", "Total", money(t.Amount), t.Currency))
+	}
+	return d
+}
+
+func date(t *time.Time) string { return t.Format(dateLayout) }
+
+// row formats the columns of a line; long names and descriptions are cut.
+func row(date, contractor, description, amount, currency string) string {
+	return pad(date, 11) + pad(contractor, 20) + pad(description, 24) + leftPad(amount, 12) + " " + currency
+}
+
+func pad(s string, n int) string {
+	r := []rune(s)
+	if len(r) >= n {
+		return string(r[:n-1]) + " "
+	}
+	return s + strings.Repeat(" ", n-len(r))
+}
+
+func leftPad(s string, n int) string {
+	if len(s) >= n {
+		return s
+	}
+	return strings.Repeat(" ", n-len(s)) + s
+}
+
+func money(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
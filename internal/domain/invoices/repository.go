@@ -0,0 +1,189 @@
+package invoices
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"mercor/internal/domain/companies"
+	"mercor/internal/domain/contractors"
+	"mercor/internal/domain/jobs"
+	payment "mercor/internal/domain/paymentLineItem"
+	"mercor/internal/scd"
+)
+
+// numberSequence names the counter invoice numbers are taken from.
+const numberSequence = "invoices"
+
+type Repository interface {
+	Generate(inv Invoice, defaultCurrency string) (Document, error)
+	FindByUID(uid string) (Invoice, error)
+	History(uid string) ([]Invoice, error)
+	Lines(invoiceID uuid.UUID) ([]Line, error)
+	FindLatestByCompany(companyID uuid.UUID) ([]Invoice, error)
+	Append(uid string, pre scd.Precondition, next func(head Invoice) (Invoice, error)) (Invoice, error)
+	FindCompany(id uuid.UUID) (companies.Company, error)
+	FindCompanyVersion(uid string) (companies.Company, error)
+}
+
+type repo struct {
+	db        *gorm.DB
+	scd       *scd.SCDManager[Invoice]
+	companies companies.Repository
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repo{db: db, scd: scd.NewManager[Invoice](db), companies: companies.NewRepository(db)}
+}
+
+// Generate stores inv with the next invoice number and a line for every
+// approved line item of its company issued in its period that is not on
+// another invoice, unless that one was voided. Lines without a job currency
+// are billed in defaultCurrency. Taking the number locks the counter, so
+// concurrent generations run one after the other and never bill a line item
+// twice. The document lists the line items left out for lacking a job link.
+func (r *repo) Generate(inv Invoice, defaultCurrency string) (Document, error) {
+	doc := Document{Invoice: inv}
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		number, err := nextNumber(tx)
+		if err != nil {
+			return err
+		}
+		lines, err := r.billable(tx, inv)
+		if err != nil {
+			return err
+		}
+		if doc.Unlinked, err = r.unlinked(tx, inv); err != nil {
+			return err
+		}
+		if len(lines) == 0 {
+			return ErrNothingToInvoice
+		}
+		doc.Number = number
+		if err := r.scd.WithTx(tx).Insert(&doc.Invoice); err != nil {
+			return err
+		}
+		for i := range lines {
+			lines[i].InvoiceID = doc.ID
+			if lines[i].Currency == "" {
+				lines[i].Currency = defaultCurrency
+			}
+		}
+		doc.Lines = lines
+		return tx.Create(&doc.Lines).Error
+	})
+	return doc, err
+}
+
+// nextNumber increments the invoice counter and formats its new value.
+func nextNumber(tx *gorm.DB) (string, error) {
+	c := Counter{Name: numberSequence}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&c).Error; err != nil {
+		return "", err
+	}
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&c, "name = ?", numberSequence).Error; err != nil {
+		return "", err
+	}
+	c.Last++
+	if err := tx.Model(&c).Update("last", c.Last).Error; err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("INV-%06d", c.Last), nil
+}
+
+// pending selects the latest versions of the approved line items issued in
+// the period of inv that are on no invoice, unless that one was voided.
+func (r *repo) pending(tx *gorm.DB, inv Invoice) *gorm.DB {
+	invoiced := r.scd.WithTx(tx).GetLatest().
+		Select("l.line_item_id").
+		Joins("JOIN invoice_lines l ON l.invoice_id = main.id").
+		Where("main.status <> ?", StatusVoid)
+	return scd.NewManager[payment.PaymentLineItem](tx).GetLatest().
+		Where("main.status = ?", payment.StatusApproved).
+		Where("main.issued_at >= ? AND main.issued_at < ?", inv.PeriodStart, inv.PeriodEnd).
+		Where("main.id NOT IN (?)", invoiced)
+}
+
+// billable returns the lines for the line items that inv bills. A line item
+// belongs to the company of the job its timelog was logged for; those without
+// a timelog, or whose timelog has no job, belong to no company until they are
+// linked.
+func (r *repo) billable(tx *gorm.DB, inv Invoice) ([]Line, error) {
+	names := scd.NewManager[contractors.Contractor](tx).GetLatest().
+		Select("main.id, main.name")
+
+	var lines []Line
+	err := r.pending(tx, inv).
+		Select(`main.uid AS line_item_uid, main.id AS line_item_id, j.uid AS job_uid,
+			j.title AS description, main.contractor_id, COALESCE(c.name, '') AS contractor_name,
+			main.issued_at, main.amount, COALESCE(j.currency, '') AS currency`).
+		Joins("JOIN timelogs t ON t.uid = main.timelog_uid").
+		Joins("JOIN jobs j ON j.uid = t.job_uid").
+		Joins("LEFT JOIN (?) AS c ON c.id = main.contractor_id", names).
+		Where("j.company_id = ?", inv.CompanyID).
+		Order("main.issued_at").Order("main.uid").
+		Scan(&lines).Error
+	return lines, err
+}
+
+// unlinked returns the UIDs of the line items inv would bill if they were
+// linked: those without a job link of contractors working a job of its
+// company.
+func (r *repo) unlinked(tx *gorm.DB, inv Invoice) ([]uuid.UUID, error) {
+	working := scd.NewManager[jobs.Job](tx).GetLatest().
+		Select("main.contractor_id").
+		Where("main.company_id = ?", inv.CompanyID)
+
+	var uids []uuid.UUID
+	err := r.pending(tx, inv).
+		Select("main.uid").
+		Joins("LEFT JOIN timelogs t ON t.uid = main.timelog_uid").
+		Where("t.job_uid IS NULL").
+		Where("main.contractor_id IN (?)", working).
+		Order("main.issued_at").Order("main.uid").
+		Scan(&uids).Error
+	return uids, err
+}
+
+func (r *repo) FindByUID(uid string) (Invoice, error) {
+	return r.scd.FindByUID(uid)
+}
+
+func (r *repo) History(uid string) ([]Invoice, error) {
+	return r.scd.HistoryByUID(uid)
+}
+
+// Lines returns the lines of the invoice with the given ID in the order they
+// were billed.
+func (r *repo) Lines(invoiceID uuid.UUID) ([]Line, error) {
+	var lines []Line
+	err := r.db.Where("invoice_id = ?", invoiceID).Order("issued_at").Order("line_item_uid").Find(&lines).Error
+	return lines, err
+}
+
+// FindLatestByCompany returns the head versions of the invoices of the
+// company, newest number first.
+func (r *repo) FindLatestByCompany(companyID uuid.UUID) ([]Invoice, error) {
+	var list []Invoice
+	err := r.scd.GetLatest().
+		Where("main.company_id = ?", companyID).
+		Order("main.number DESC").
+		Find(&list).Error
+	return list, err
+}
+
+// Append stores the version next builds from the locked head version.
+func (r *repo) Append(uid string, pre scd.Precondition, next func(head Invoice) (Invoice, error)) (Invoice, error) {
+	return r.scd.AppendVersion(uid, pre, next)
+}
+
+// FindCompany returns the head version of the company with the given ID.
+func (r *repo) FindCompany(id uuid.UUID) (companies.Company, error) {
+	return r.companies.FindLatest(id)
+}
+
+// FindCompanyVersion returns the company version an invoice pins.
+func (r *repo) FindCompanyVersion(uid string) (companies.Company, error) {
+	return r.companies.FindByUID(uid)
+}
@@ -0,0 +1,121 @@
+package invoices
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"mercor/internal/scd"
+)
+
+type Service interface {
+	Generate(companyID uuid.UUID, start, end time.Time) (Document, error)
+	Get(uid string) (Document, error)
+	History(uid string) ([]Invoice, error)
+	ListByCompany(companyID uuid.UUID) ([]Invoice, error)
+	UpdateStatus(uid, status string, pre scd.Precondition) (Invoice, error)
+	PDF(uid string) (Document, []byte, error)
+}
+
+type service struct {
+	repo Repository
+}
+
+func NewService(r Repository) Service {
+	return &service{repo: r}
+}
+
+// Generate stores a draft invoice for the approved line items of the company
+// issued in [start, end). It pins the head version of the company, whose
+// currency lines without one are billed in.
+func (s *service) Generate(companyID uuid.UUID, start, end time.Time) (Document, error) {
+	company, err := s.repo.FindCompany(companyID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return Document{}, fmt.Errorf("company_id %v: %w", companyID, scd.ErrUnknownReference)
+	}
+	if err != nil {
+		return Document{}, err
+	}
+	return s.repo.Generate(Invoice{
+		ID:          uuid.New(),
+		UID:         uuid.New(),
+		Version:     1,
+		CompanyID:   companyID,
+		CompanyUID:  company.UID,
+		PeriodStart: start,
+		PeriodEnd:   end,
+		Status:      StatusDraft,
+	}, company.DefaultCurrency)
+}
+
+// Get returns the given invoice version with its lines.
+func (s *service) Get(uid string) (Document, error) {
+	inv, err := s.repo.FindByUID(uid)
+	if err != nil {
+		return Document{}, err
+	}
+	lines, err := s.repo.Lines(inv.ID)
+	return Document{Invoice: inv, Lines: lines}, err
+}
+
+// History returns every version of the invoice, oldest first.
+func (s *service) History(uid string) ([]Invoice, error) {
+	return s.repo.History(uid)
+}
+
+// ListByCompany returns the head versions of the invoices of the company.
+func (s *service) ListByCompany(companyID uuid.UUID) ([]Invoice, error) {
+	return s.repo.FindLatestByCompany(companyID)
+}
+
+// UpdateStatus stores a version of the invoice in the given status. Issuing
+// pins the head version of the company again and sets the due date from its
+// payment terms; paying records when.
+func (s *service) UpdateStatus(uid, status string, pre scd.Precondition) (Invoice, error) {
+	inv, err := s.repo.FindByUID(uid)
+	if err != nil {
+		return Invoice{}, err
+	}
+	company, err := s.repo.FindCompany(inv.CompanyID)
+	if err != nil {
+		return Invoice{}, err
+	}
+	return s.repo.Append(uid, pre, func(head Invoice) (Invoice, error) {
+		if !canMove(head.Status, status) {
+			return Invoice{}, fmt.Errorf("%s to %s: %w", head.Status, status, ErrInvalidTransition)
+		}
+		next := head.CopyForNewVersion()
+		next.Status = status
+		now := time.Now()
+		switch status {
+		case StatusIssued:
+			due := now.AddDate(0, 0, company.PaymentTermsDays)
+			next.CompanyUID = company.UID
+			next.IssuedAt = &now
+			next.DueAt = &due
+		case StatusPaid:
+			next.PaidAt = &now
+		}
+		return next, nil
+	})
+}
+
+// PDF renders the given invoice version with the company version it pins.
+func (s *service) PDF(uid string) (Document, []byte, error) {
+	doc, err := s.Get(uid)
+	if err != nil {
+		return doc, nil, err
+	}
+	company, err := s.repo.FindCompanyVersion(doc.CompanyUID.String())
+	if err != nil {
+		return doc, nil, err
+	}
+	var buf bytes.Buffer
+	if _, err := render(doc, company).WriteTo(&buf); err != nil {
+		return doc, nil, err
+	}
+	return doc, buf.Bytes(), nil
+}
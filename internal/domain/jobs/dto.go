@@ -0,0 +1,99 @@
+package jobs
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"mercor/internal/scd"
+)
+
+// JobRequest is the body of POST /jobs and PUT /jobs/:uid and the data of
+// batch items. PUT takes the complete representation, so every field is
+// required there as well. Identity, version and timestamps are assigned by
+// the server. EffectiveFrom back-dates the change; it defaults to now.
+// Currency is an ISO 4217 code; a job written without one, created or
+// replaced, takes the default currency of its company.
+type JobRequest struct {
+	Title         string     `json:"title" binding:"required"`
+	Status        string     `json:"status" binding:"required"`
+	Rate          float64    `json:"rate" binding:"gt=0"`
+	Currency      string     `json:"currency,omitempty" binding:"omitempty,iso4217"`
+	CompanyID     string     `json:"companyId" binding:"required,uuid"`
+	ContractorID  string     `json:"contractorId" binding:"required,uuid"`
+	EffectiveFrom *time.Time `json:"effectiveFrom,omitempty"`
+}
+
+// toJob converts a validated request.
+func (r JobRequest) toJob() Job {
+	return Job{
+		Title:        r.Title,
+		Status:       r.Status,
+		Rate:         r.Rate,
+		Currency:     r.Currency,
+		CompanyID:    uuid.MustParse(r.CompanyID),
+		ContractorID: uuid.MustParse(r.ContractorID),
+		Bitemporal:   effectiveFrom(r.EffectiveFrom),
+	}
+}
+
+// effectiveFrom starts the effective period of a version at from, or leaves
+// it to the server if from is nil.
+func effectiveFrom(from *time.Time) scd.Bitemporal {
+	if from == nil {
+		return scd.Bitemporal{}
+	}
+	return scd.Bitemporal{EffectiveFrom: *from}
+}
+
+type JobResponse struct {
+	ID           uuid.UUID `json:"id"`
+	UID          uuid.UUID `json:"uid"`
+	Version      int       `json:"version"`
+	Title        string    `json:"title"`
+	Status       string    `json:"status"`
+	Rate         float64   `json:"rate"`
+	Currency     string    `json:"currency"`
+	CompanyID    uuid.UUID `json:"companyId"`
+	ContractorID uuid.UUID `json:"contractorId"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+	// EffectiveFrom and EffectiveTo bound when the version applied;
+	// RecordedFrom and RecordedTo when it was the recorded state.
+	EffectiveFrom time.Time  `json:"effectiveFrom"`
+	EffectiveTo   *time.Time `json:"effectiveTo"`
+	RecordedFrom  time.Time  `json:"recordedFrom"`
+	RecordedTo    *time.Time `json:"recordedTo"`
+	// RevertedFrom and RevertReason are set on versions written by a revert.
+	RevertedFrom *uuid.UUID `json:"revertedFrom,omitempty"`
+	RevertReason string     `json:"revertReason,omitempty"`
+}
+
+func NewJobResponse(j Job) JobResponse {
+	return JobResponse{
+		ID:            j.ID,
+		UID:           j.UID,
+		Version:       j.Version,
+		Title:         j.Title,
+		Status:        j.Status,
+		Rate:          j.Rate,
+		Currency:      j.Currency,
+		CompanyID:     j.CompanyID,
+		ContractorID:  j.ContractorID,
+		CreatedAt:     j.CreatedAt,
+		UpdatedAt:     j.UpdatedAt,
+		EffectiveFrom: j.EffectiveFrom,
+		EffectiveTo:   j.EffectiveTo,
+		RecordedFrom:  j.RecordedFrom,
+		RecordedTo:    j.RecordedTo,
+		RevertedFrom:  j.RevertedFrom,
+		RevertReason:  j.RevertReason,
+	}
+}
+
+func NewJobResponses(list []Job) []JobResponse {
+	out := make([]JobResponse, len(list))
+	for i, j := range list {
+		out[i] = NewJobResponse(j)
+	}
+	return out
+}
@@ -0,0 +1,131 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+
+	scdv1 "mercor/api/scd/v1"
+	"mercor/internal/patch"
+	"mercor/internal/rpc"
+	"mercor/internal/validation"
+)
+
+// GRPCServer exposes the Service over gRPC with the same semantics as Handler.
+type GRPCServer struct {
+	scdv1.UnimplementedJobServiceServer
+	svc Service
+}
+
+func NewGRPCServer(s Service) *GRPCServer {
+	return &GRPCServer{svc: s}
+}
+
+func (g *GRPCServer) CreateJob(_ context.Context, req *scdv1.CreateJobRequest) (*scdv1.Job, error) {
+	in, err := jobRequestFromProto(req.GetJob())
+	if err != nil {
+		return nil, err
+	}
+	job, err := g.svc.CreateJob(in.toJob())
+	if err != nil {
+		return nil, rpc.Error(err)
+	}
+	return jobToProto(job), nil
+}
+
+func (g *GRPCServer) GetJob(_ context.Context, req *scdv1.GetJobRequest) (*scdv1.Job, error) {
+	var job Job
+	var err error
+	if req.GetAsOf() != nil {
+		job, err = g.svc.GetAsOf(req.GetUid(), req.GetAsOf().AsTime())
+	} else {
+		job, err = g.svc.GetByUID(req.GetUid())
+	}
+	if err != nil {
+		return nil, rpc.Error(err)
+	}
+	return jobToProto(job), nil
+}
+
+// UpdateJob stores the fields of JobInput as a new version. They are merged
+// into the head, so the currency, which JobInput lacks, keeps its value.
+func (g *GRPCServer) UpdateJob(_ context.Context, req *scdv1.UpdateJobRequest) (*scdv1.Job, error) {
+	in, err := jobRequestFromProto(req.GetJob())
+	if err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(in)
+	if err != nil {
+		return nil, rpc.Error(err)
+	}
+	job, err := g.svc.Patch(req.GetUid(), patch.ContentTypeMergePatch, body, rpc.ExpectHead(req.GetExpectedHeadUid()))
+	if err != nil {
+		return nil, rpc.Error(err)
+	}
+	return jobToProto(job), nil
+}
+
+func (g *GRPCServer) UpdateJobStatus(_ context.Context, req *scdv1.UpdateJobStatusRequest) (*scdv1.Job, error) {
+	job, err := g.svc.UpdateStatus(req.GetUid(), req.GetStatus(), rpc.ExpectHead(req.GetExpectedHeadUid()))
+	if err != nil {
+		return nil, rpc.Error(err)
+	}
+	return jobToProto(job), nil
+}
+
+func (g *GRPCServer) ListJobHistory(_ context.Context, req *scdv1.ListJobHistoryRequest) (*scdv1.ListJobsResponse, error) {
+	jobs, err := g.svc.History(req.GetUid())
+	if err != nil {
+		return nil, rpc.Error(err)
+	}
+	return &scdv1.ListJobsResponse{Jobs: jobsToProto(jobs)}, nil
+}
+
+func (g *GRPCServer) ListCompanyJobs(_ context.Context, req *scdv1.ListCompanyJobsRequest) (*scdv1.ListJobsResponse, error) {
+	if err := rpc.UUIDArg("company_id", req.GetCompanyId()); err != nil {
+		return nil, err
+	}
+	jobs, err := g.svc.GetActiveJobsByCompany(req.GetCompanyId())
+	if err != nil {
+		return nil, rpc.Error(err)
+	}
+	return &scdv1.ListJobsResponse{Jobs: jobsToProto(jobs)}, nil
+}
+
+// jobRequestFromProto checks the input against the binding rules of
+// JobRequest, so both transports accept the same bodies.
+func jobRequestFromProto(in *scdv1.JobInput) (JobRequest, error) {
+	req := JobRequest{
+		Title:        in.GetTitle(),
+		Status:       in.GetStatus(),
+		Rate:         in.GetRate(),
+		CompanyID:    in.GetCompanyId(),
+		ContractorID: in.GetContractorId(),
+	}
+	if err := validation.Struct(req); err != nil {
+		return req, rpc.Invalid(err)
+	}
+	return req, nil
+}
+
+func jobToProto(j Job) *scdv1.Job {
+	return &scdv1.Job{
+		Id:           j.ID.String(),
+		Uid:          j.UID.String(),
+		Version:      int32(j.Version),
+		Title:        j.Title,
+		Status:       j.Status,
+		Rate:         j.Rate,
+		CompanyId:    j.CompanyID.String(),
+		ContractorId: j.ContractorID.String(),
+		CreatedAt:    rpc.Timestamp(j.CreatedAt),
+		UpdatedAt:    rpc.Timestamp(j.UpdatedAt),
+	}
+}
+
+func jobsToProto(list []Job) []*scdv1.Job {
+	out := make([]*scdv1.Job, len(list))
+	for i, j := range list {
+		out[i] = jobToProto(j)
+	}
+	return out
+}
@@ -1,8 +1,13 @@
 package jobs
 
 import (
+	"errors"
 	"net/http"
 	"github.com/gin-gonic/gin"
+	"mercor/internal/idempotency"
+	"mercor/internal/patch"
+	"mercor/internal/scd"
+	"mercor/internal/validation"
 )
 
 type Handler struct {
@@ -13,65 +18,196 @@ func NewHandler(s Service) *Handler {
 	return &Handler{svc: s}
 }
 
+// service returns the service to serve c with: for a request with an
+// Idempotency-Key, one writing in its transaction, so the stored response
+// commits with the writes.
+func (h *Handler) service(c *gin.Context) Service {
+	if tx, ok := idempotency.Tx(c); ok {
+		return NewService(NewRepository(tx))
+	}
+	return h.svc
+}
+
 func (h *Handler) RegisterRoutes(r *gin.Engine) {
 	r.POST("/jobs", h.Create)
+	r.POST("/jobs/batch", h.Batch)
+	r.GET("/jobs/scheduled", h.Scheduled)
+	r.DELETE("/jobs/scheduled/:uid", h.CancelScheduled)
 	r.GET("/jobs/:uid", h.GetByUID)
+	r.GET("/jobs/:uid/history", h.History)
+	r.POST("/jobs/:uid/revert", h.Revert)
 	r.PUT("/jobs/:uid", h.Update)
+	r.PATCH("/jobs/:uid", h.Patch)
 	r.PUT("/jobs/:uid/status", h.UpdateStatus)
 	r.GET("/companies/:id/jobs", h.GetByCompany)
 }
 
 func (h *Handler) Create(c *gin.Context) {
-	var job Job
-	if err := c.ShouldBindJSON(&job); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	var req JobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, validation.Response(err))
 		return
 	}
-	if err := h.svc.CreateJob(job); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	job, err := h.service(c).CreateJob(req.toJob())
+	if err != nil {
+		c.JSON(scd.StatusCode(err), gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusCreated, job)
+	c.JSON(http.StatusCreated, NewJobResponse(job))
 }
 
+// GetByUID returns the given version. With ?as_of=<RFC 3339 time> it returns
+// the version of the same job that was effective at that time, with
+// ?known_at= as it was on record at that time.
 func (h *Handler) GetByUID(c *gin.Context) {
-	job, err := h.svc.GetByUID(c.Param("uid"))
+	tt, err := scd.ParseTimeTravel(c.Query("as_of"), c.Query("known_at"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var job Job
+	switch {
+	case tt.KnownAt != nil:
+		job, err = h.service(c).GetAsKnown(c.Param("uid"), *tt.KnownAt, *tt.AsOf)
+	case tt.AsOf != nil:
+		job, err = h.service(c).GetAsOf(c.Param("uid"), *tt.AsOf)
+	default:
+		job, err = h.service(c).GetByUID(c.Param("uid"))
+	}
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, job)
+	c.Header("ETag", scd.ETag(job.UID.String()))
+	c.JSON(http.StatusOK, NewJobResponse(job))
 }
 
-func (h *Handler) Update(c *gin.Context) {
-	var updated Job
-	if err := c.ShouldBindJSON(&updated); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+func (h *Handler) History(c *gin.Context) {
+	jobs, err := h.service(c).History(c.Param("uid"))
+	if err != nil {
+		c.JSON(scd.StatusCode(err), gin.H{"error": err.Error()})
 		return
 	}
-	job, err := h.svc.Update(c.Param("uid"), updated)
+	c.JSON(http.StatusOK, NewJobResponses(jobs))
+}
+
+// Scheduled lists the versions that take effect later, soonest first.
+func (h *Handler) Scheduled(c *gin.Context) {
+	jobs, err := h.service(c).Scheduled()
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, job)
+	c.JSON(http.StatusOK, NewJobResponses(jobs))
+}
+
+// CancelScheduled withdraws a scheduled version and returns it, no longer
+// recorded. Versions that already took effect cannot be cancelled (409).
+func (h *Handler) CancelScheduled(c *gin.Context) {
+	job, err := h.service(c).CancelScheduled(c.Param("uid"))
+	if err != nil {
+		c.JSON(scd.StatusCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, NewJobResponse(job))
+}
+
+// Revert stores a new version with the values of the version named by
+// ?to=<uid>, recording the reason given in the body. It honours If-Match like
+// Update.
+func (h *Handler) Revert(c *gin.Context) {
+	to := c.Query("to")
+	if to == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to is required"})
+		return
+	}
+	var req scd.RevertRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, validation.Response(err))
+		return
+	}
+	job, err := h.service(c).Revert(c.Param("uid"), to, req.Reason, scd.IfMatch(c.GetHeader("If-Match")))
+	if err != nil {
+		c.JSON(scd.StatusCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("ETag", scd.ETag(job.UID.String()))
+	c.JSON(http.StatusOK, NewJobResponse(job))
+}
+
+// Update and UpdateStatus honour If-Match: the write fails with 412 unless
+// the ETag of the head version is listed.
+func (h *Handler) Update(c *gin.Context) {
+	var req JobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, validation.Response(err))
+		return
+	}
+	job, err := h.service(c).Update(c.Param("uid"), req.toJob(), scd.IfMatch(c.GetHeader("If-Match")))
+	if err != nil {
+		c.JSON(scd.StatusCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("ETag", scd.ETag(job.UID.String()))
+	c.JSON(http.StatusOK, NewJobResponse(job))
 }
 
 func (h *Handler) UpdateStatus(c *gin.Context) {
 	status := c.Query("status")
-	job, err := h.svc.UpdateStatus(c.Param("uid"), status)
+	job, err := h.service(c).UpdateStatus(c.Param("uid"), status, scd.IfMatch(c.GetHeader("If-Match")))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(scd.StatusCode(err), gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, job)
+	c.Header("ETag", scd.ETag(job.UID.String()))
+	c.JSON(http.StatusOK, NewJobResponse(job))
 }
 
 func (h *Handler) GetByCompany(c *gin.Context) {
-	jobs, err := h.svc.GetActiveJobsByCompany(c.Param("id"))
+	jobs, err := h.service(c).GetActiveJobsByCompany(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, NewJobResponses(jobs))
+}
+
+func (h *Handler) Batch(c *gin.Context) {
+	var req scd.BatchRequest[JobRequest]
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	items, err := h.service(c).Batch(req)
+	results := scd.MapBatchResults(items, NewJobResponse)
+	if errors.Is(err, scd.ErrBatchRejected) {
+		c.JSON(http.StatusUnprocessableEntity, scd.BatchResponse[JobResponse]{Error: err.Error(), Results: results})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, jobs)
+	c.JSON(http.StatusOK, scd.BatchResponse[JobResponse]{Results: results})
+}
+
+// Patch accepts application/merge-patch+json (or application/json) and
+// application/json-patch+json bodies.
+func (h *Handler) Patch(c *gin.Context) {
+	body, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	job, err := h.service(c).Patch(c.Param("uid"), c.ContentType(), body, scd.IfMatch(c.GetHeader("If-Match")))
+	if err != nil {
+		c.JSON(patch.StatusCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("ETag", scd.ETag(job.UID.String()))
+	c.JSON(http.StatusOK, NewJobResponse(job))
 }
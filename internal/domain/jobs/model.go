@@ -3,19 +3,24 @@ package jobs
 import (
 	"time"
 	"github.com/google/uuid"
+	"mercor/internal/domain/timelog"
+	"mercor/internal/scd"
 )
 
 type Job struct {
-	ID           uuid.UUID `gorm:"type:uuid"`
-	UID          uuid.UUID `gorm:"type:uuid;primaryKey"`
-	Version      int
-	Status       string
-	Rate         float64
-	Title        string
-	CompanyID    uuid.UUID
-	ContractorID uuid.UUID
-	CreatedAt    time.Time
-	UpdatedAt    time.Time
+	ID           uuid.UUID `gorm:"type:uuid" json:"id"`
+	UID          uuid.UUID `gorm:"type:uuid;primaryKey" json:"uid"`
+	Version      int       `json:"version"`
+	Status       string    `json:"status"`
+	Rate         float64   `json:"rate"`
+	Currency     string    `json:"currency"`
+	Title        string    `json:"title"`
+	CompanyID    uuid.UUID `gorm:"index" json:"companyId"`
+	ContractorID uuid.UUID `gorm:"index" json:"contractorId"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+	scd.Bitemporal
+	scd.Reversion
 }
 
 func (Job) TableName() string { return "jobs" }
@@ -23,11 +28,39 @@ func (Job) TableName() string { return "jobs" }
 func (j Job) GetID() string     { return j.ID.String() }
 func (j Job) GetUID() string    { return j.UID.String() }
 func (j Job) GetVersion() int   { return j.Version }
+// ChangePolicy overwrites title changes in place, as they are corrections
+// rather than changes to the job.
+func (Job) ChangePolicy() scd.Policy {
+	return scd.Policy{"title": scd.Overwrite()}
+}
+
+// Dependents re-points the current timelogs of a job at each new version of
+// it, so a timelog always names the job as it stands.
+func (Job) Dependents() []scd.Relationship {
+	return []scd.Relationship{scd.DependsOn[timelog.Timelog]("job_uid", scd.Repoint)}
+}
+
+// Identity ties a job to its company: the same work for another company is
+// another job.
+func (Job) Identity() []string {
+	return []string{"company_id"}
+}
+
+// References requires the company and the contractor of a job to be on
+// record.
+func (Job) References() []scd.Reference {
+	return []scd.Reference{
+		scd.RefersTo("company_id", "companies"),
+		scd.RefersTo("contractor_id", "contractors"),
+	}
+}
+
 func (j Job) CopyForNewVersion() Job {
 	return Job{
 		ID:           j.ID,
 		Status:       j.Status,
 		Rate:         j.Rate,
+		Currency:     j.Currency,
 		Title:        j.Title,
 		CompanyID:    j.CompanyID,
 		ContractorID: j.ContractorID,
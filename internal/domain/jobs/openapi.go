@@ -0,0 +1,143 @@
+package jobs
+
+import (
+	"net/http"
+
+	"mercor/internal/openapi"
+	"mercor/internal/scd"
+)
+
+// Operations documents the routes registered by Handler.
+func Operations() []openapi.Operation {
+	job := openapi.JSON("The job version", JobResponse{})
+	batch := openapi.JSON("Per-item results", scd.BatchResponse[JobResponse]{})
+	return []openapi.Operation{
+		{
+			Method: http.MethodPost, Path: "/jobs", Tag: "jobs",
+			Summary: "Create a job",
+			Body:    openapi.Body(JobRequest{}),
+			Responses: openapi.Responses{
+				201: openapi.JSON("The first version of the job", JobResponse{}),
+				400: openapi.Invalid,
+				422: openapi.InvalidPeriodOrReference,
+				500: openapi.ServerError,
+			},
+		},
+		{
+			Method: http.MethodPost, Path: "/jobs/batch", Tag: "jobs",
+			Summary:     "Create and version jobs in one transaction",
+			Description: "Also served at POST /jobs:batch.",
+			Body:        openapi.Body(scd.BatchRequest[JobRequest]{}),
+			Responses: openapi.Responses{
+				200: batch,
+				400: openapi.BadRequest,
+				422: openapi.JSON("An atomic batch was rejected", scd.BatchResponse[JobResponse]{}),
+				500: openapi.ServerError,
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/jobs/scheduled", Tag: "jobs",
+			Summary:     "List scheduled job versions",
+			Description: "Versions written with an effectiveFrom in the future, soonest first. Reads of the latest version leave them out until they take effect.",
+			Responses: openapi.Responses{
+				200: openapi.JSON("Pending versions", []JobResponse{}),
+				500: openapi.ServerError,
+			},
+		},
+		{
+			Method: http.MethodDelete, Path: "/jobs/scheduled/:uid", Tag: "jobs",
+			Summary: "Cancel a scheduled job version",
+			Responses: openapi.Responses{
+				200: openapi.JSON("The cancelled version, no longer recorded", JobResponse{}),
+				404: openapi.NotFound,
+				409: openapi.JSON("The version is not pending", openapi.Error{}),
+				500: openapi.ServerError,
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/jobs/:uid", Tag: "jobs",
+			Summary: "Get a job version by UID",
+			Params:  []openapi.Param{openapi.AsOf, openapi.KnownAt},
+			Responses: openapi.Responses{
+				200: job,
+				400: openapi.BadRequest,
+				404: openapi.NotFound,
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/jobs/:uid/history", Tag: "jobs",
+			Summary: "List every version of a job, oldest first",
+			Responses: openapi.Responses{
+				200: openapi.JSON("All versions of the job", []JobResponse{}),
+				404: openapi.NotFound,
+			},
+		},
+		{
+			Method: http.MethodPost, Path: "/jobs/:uid/revert", Tag: "jobs",
+			Summary:     "Revert a job to an earlier version",
+			Description: "Stores a new version with the values of the version named by to, recording it and the reason. The history is not rewritten.",
+			Params: []openapi.Param{
+				{Name: "to", In: "query", Required: true, Description: "UID of the version to restore."},
+				openapi.IfMatch,
+			},
+			Body: openapi.Body(scd.RevertRequest{}),
+			Responses: openapi.Responses{
+				200: job,
+				400: openapi.Invalid,
+				404: openapi.NotFound,
+				412: openapi.PreconditionFailed,
+				422: openapi.JSON("to is not a version of this job", openapi.Error{}),
+				500: openapi.ServerError,
+			},
+		},
+		{
+			Method: http.MethodPut, Path: "/jobs/:uid", Tag: "jobs",
+			Summary:     "Replace a job",
+			Description: "Stores the complete representation as a new version on top of the head version. An effectiveFrom in the future schedules the version.",
+			Params:      []openapi.Param{openapi.IfMatch},
+			Body:        openapi.Body(JobRequest{}),
+			Responses: openapi.Responses{
+				200: job,
+				400: openapi.Invalid,
+				404: openapi.NotFound,
+				422: openapi.InvalidPeriodOrReference,
+				412: openapi.PreconditionFailed,
+				500: openapi.ServerError,
+			},
+		},
+		{
+			Method: http.MethodPatch, Path: "/jobs/:uid", Tag: "jobs",
+			Summary: "Partially update a job",
+			Params:  []openapi.Param{openapi.IfMatch},
+			Body:    openapi.PatchBody(JobResponse{}),
+			Responses: openapi.Responses{
+				200: job,
+				400: openapi.BadRequest,
+				404: openapi.NotFound,
+				409: openapi.JSON("A JSON Patch test operation failed", openapi.Error{}),
+				412: openapi.PreconditionFailed,
+				415: openapi.JSON("Unsupported patch media type", openapi.Error{}),
+				422: openapi.JSON("The patched job is invalid, its effectiveFrom cannot be applied or its company or contractor does not exist", openapi.Error{}),
+			},
+		},
+		{
+			Method: http.MethodPut, Path: "/jobs/:uid/status", Tag: "jobs",
+			Summary: "Change the status of a job",
+			Params: []openapi.Param{
+				{Name: "status", In: "query", Required: true},
+				openapi.IfMatch,
+			},
+			Responses: openapi.Responses{
+				200: job,
+				404: openapi.NotFound,
+				412: openapi.PreconditionFailed,
+				500: openapi.ServerError,
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/companies/:id/jobs", Tag: "jobs",
+			Summary:   "List the current active jobs of a company",
+			Responses: openapi.Responses{200: openapi.JSON("Current job versions", []JobResponse{}), 500: openapi.ServerError},
+		},
+	}
+}
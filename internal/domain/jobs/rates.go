@@ -0,0 +1,56 @@
+package jobs
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RateHistory holds the versions of some jobs in the order they took effect,
+// to look up the rate that applied to work at a given time.
+type RateHistory struct {
+	versions map[uuid.UUID][]Job
+	jobs     map[uuid.UUID]uuid.UUID
+}
+
+// At returns the version, of the job that the version uid belongs to, that
+// was effective at t. Work done before the job was first effective is paid
+// at its first rate.
+func (h RateHistory) At(uid uuid.UUID, t time.Time) (Job, bool) {
+	versions := h.versions[h.jobs[uid]]
+	if len(versions) == 0 {
+		return Job{}, false
+	}
+	found := versions[0]
+	for _, v := range versions[1:] {
+		if v.EffectiveFrom.After(t) {
+			break
+		}
+		found = v
+	}
+	return found, true
+}
+
+// RateHistory returns the versions, as recorded at knownAt or now if knownAt
+// is nil, of the jobs the given versions belong to.
+func (r *repo) RateHistory(uids []uuid.UUID, knownAt *time.Time) (RateHistory, error) {
+	h := RateHistory{versions: map[uuid.UUID][]Job{}, jobs: map[uuid.UUID]uuid.UUID{}}
+	if len(uids) == 0 {
+		return h, nil
+	}
+	var pins []struct{ UID, ID uuid.UUID }
+	if err := r.db.Model(&Job{}).Select("uid, id").Where("uid IN ?", uids).Scan(&pins).Error; err != nil {
+		return h, err
+	}
+	ids := make([]uuid.UUID, len(pins))
+	for i, p := range pins {
+		h.jobs[p.UID] = p.ID
+		ids[i] = p.ID
+	}
+	var list []Job
+	err := r.scd.Recorded(knownAt).Where("v.id IN ?", ids).Order("v.effective_from").Find(&list).Error
+	for _, j := range list {
+		h.versions[j.ID] = append(h.versions[j.ID], j)
+	}
+	return h, err
+}
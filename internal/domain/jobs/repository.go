@@ -1,59 +1,140 @@
 package jobs
 
 import (
+	"errors"
+	"time"
+
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"mercor/internal/domain/companies"
 	"mercor/internal/scd"
+	"mercor/internal/validation"
 )
 
 type Repository interface {
-	Create(job Job) error
+	Create(job Job) (Job, error)
 	FindByUID(uid string) (Job, error)
-	Update(uid string, newJob Job) (Job, error)
-	UpdateStatus(uid string, newStatus string) (Job, error)
+	History(uid string) ([]Job, error)
+	FindAsOf(uid string, at time.Time) (Job, error)
+	FindAsKnown(uid string, knownAt, at time.Time) (Job, error)
+	Scheduled() ([]Job, error)
+	CancelScheduled(uid string) (Job, error)
+	Revert(uid, to, reason string, pre scd.Precondition) (Job, error)
+	VersionsByIDs(ids []uuid.UUID) ([]Job, error)
+	FindByUIDs(uids []uuid.UUID) ([]Job, error)
+	FindByIDs(ids []uuid.UUID, at *time.Time) ([]Job, error)
+	FindByContractors(contractorIDs []uuid.UUID, at *time.Time) ([]Job, error)
+	FindByCompanies(companyIDs []uuid.UUID, at *time.Time) ([]Job, error)
+	Update(uid string, newJob Job, pre scd.Precondition) (Job, error)
+	UpdateStatus(uid string, newStatus string, pre scd.Precondition) (Job, error)
+	Append(uid string, pre scd.Precondition, next func(head Job) (Job, error)) (Job, error)
 	FindLatestByCompany(companyID uuid.UUID) ([]Job, error)
+	DefaultCurrency(companyID uuid.UUID) (string, error)
+	RateHistory(uids []uuid.UUID, knownAt *time.Time) (RateHistory, error)
+	Batch(ops []scd.BatchOp[JobRequest], mode scd.BatchMode) ([]scd.BatchResult[Job], error)
 }
 
 type repo struct {
-	scd *scd.SCDManager[Job]
+	db        *gorm.DB
+	scd       *scd.SCDManager[Job]
+	companies companies.Repository
 }
 
 func NewRepository(db *gorm.DB) Repository {
-	return &repo{scd: scd.NewManager[Job](db)}
+	return &repo{db: db, scd: scd.NewManager[Job](db), companies: companies.NewRepository(db)}
 }
 
-func (r *repo) Create(j Job) error {
-	return r.scd.Insert(j)
+func (r *repo) Create(j Job) (Job, error) {
+	err := r.scd.Insert(&j)
+	return j, err
 }
 
 func (r *repo) FindByUID(uid string) (Job, error) {
 	return r.scd.FindByUID(uid)
 }
 
-func (r *repo) Update(uid string, newJob Job) (Job, error) {
-	old, err := r.FindByUID(uid)
-	if err != nil {
-		return Job{}, err
-	}
+func (r *repo) History(uid string) ([]Job, error) {
+	return r.scd.HistoryByUID(uid)
+}
+
+func (r *repo) FindAsOf(uid string, at time.Time) (Job, error) {
+	return r.scd.FindAsOfByUID(uid, at)
+}
+
+func (r *repo) FindAsKnown(uid string, knownAt, at time.Time) (Job, error) {
+	return r.scd.FindAsKnownByUID(uid, knownAt, at)
+}
+
+// Scheduled returns the versions that take effect later, soonest first.
+func (r *repo) Scheduled() ([]Job, error) {
+	var list []Job
+	err := r.scd.Scheduled().Find(&list).Error
+	return list, err
+}
+
+func (r *repo) CancelScheduled(uid string) (Job, error) {
+	return r.scd.CancelScheduled(uid)
+}
+
+func (r *repo) Revert(uid, to, reason string, pre scd.Precondition) (Job, error) {
+	return r.scd.Revert(uid, to, reason, pre)
+}
+
+// VersionsByIDs, FindByUIDs, FindByIDs and FindByContractors load many
+// entities in one query each, for the GraphQL dataloaders. A nil at selects
+// head versions.
+func (r *repo) VersionsByIDs(ids []uuid.UUID) ([]Job, error) {
+	return r.scd.VersionsOf(ids)
+}
+
+func (r *repo) FindByUIDs(uids []uuid.UUID) ([]Job, error) {
+	return r.scd.FindUIDs(uids)
+}
+
+func (r *repo) FindByIDs(ids []uuid.UUID, at *time.Time) ([]Job, error) {
+	return r.scd.FindIn("id", ids, at)
+}
+
+func (r *repo) FindByContractors(contractorIDs []uuid.UUID, at *time.Time) ([]Job, error) {
+	return r.scd.FindIn("contractor_id", contractorIDs, at)
+}
+
+func (r *repo) FindByCompanies(companyIDs []uuid.UUID, at *time.Time) ([]Job, error) {
+	return r.scd.FindIn("company_id", companyIDs, at)
+}
+
+func (r *repo) Update(uid string, newJob Job, pre scd.Precondition) (Job, error) {
+	return r.Append(uid, pre, func(old Job) (Job, error) {
+		in, err := r.withCurrency(newJob)
+		return nextVersion(old, in), err
+	})
+}
+
+// Append stores the version next builds from the locked head version.
+func (r *repo) Append(uid string, pre scd.Precondition, next func(head Job) (Job, error)) (Job, error) {
+	return r.scd.AppendVersion(uid, pre, next)
+}
+
+// nextVersion builds the version following old with the fields taken from in,
+// which replaces every one of them.
+func nextVersion(old, in Job) Job {
 	updated := old.CopyForNewVersion()
-	updated.Title = newJob.Title
-	updated.Rate = newJob.Rate
-	updated.Status = newJob.Status
-	updated.CompanyID = newJob.CompanyID
-	updated.ContractorID = newJob.ContractorID
-	err = r.scd.Insert(updated)
-	return updated, err
-}
-
-func (r *repo) UpdateStatus(uid string, newStatus string) (Job, error) {
-	old, err := r.FindByUID(uid)
-	if err != nil {
-		return Job{}, err
-	}
-	newItem := old.CopyForNewVersion()
-	newItem.Status = newStatus
-	err = r.scd.Insert(newItem)
-	return newItem, err
+	updated.Title = in.Title
+	updated.Rate = in.Rate
+	updated.Currency = in.Currency
+	updated.Status = in.Status
+	updated.CompanyID = in.CompanyID
+	updated.ContractorID = in.ContractorID
+	updated.EffectiveFrom = in.EffectiveFrom
+	return updated
+}
+
+func (r *repo) UpdateStatus(uid string, newStatus string, pre scd.Precondition) (Job, error) {
+	return r.Append(uid, pre, func(old Job) (Job, error) {
+		newItem := old.CopyForNewVersion()
+		newItem.Status = newStatus
+		return newItem, nil
+	})
 }
 
 func (r *repo) FindLatestByCompany(companyID uuid.UUID) ([]Job, error) {
@@ -64,3 +145,46 @@ func (r *repo) FindLatestByCompany(companyID uuid.UUID) ([]Job, error) {
 		Find(&jobs).Error
 	return jobs, err
 }
+
+// withCurrency gives a job written without a currency the default currency
+// of its company, as on create.
+func (r *repo) withCurrency(j Job) (Job, error) {
+	if j.Currency != "" {
+		return j, nil
+	}
+	currency, err := r.DefaultCurrency(j.CompanyID)
+	j.Currency = currency
+	return j, err
+}
+
+// DefaultCurrency returns the currency new jobs of the company are paid in,
+// or "" if there is no such company; storing a job for it fails later.
+func (r *repo) DefaultCurrency(companyID uuid.UUID) (string, error) {
+	c, err := r.companies.FindLatest(companyID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", nil
+	}
+	return c.DefaultCurrency, err
+}
+
+func (r *repo) Batch(ops []scd.BatchOp[JobRequest], mode scd.BatchMode) ([]scd.BatchResult[Job], error) {
+	return scd.ApplyBatch(r.scd, ops, mode, scd.BatchHooks[JobRequest, Job]{
+		Create: func(in JobRequest) (Job, error) {
+			if err := validation.Struct(in); err != nil {
+				return Job{}, err
+			}
+			j := in.toJob()
+			j.ID = uuid.New()
+			j.UID = uuid.New()
+			j.Version = 1
+			return j, nil
+		},
+		Update: func(old Job, in JobRequest) (Job, error) {
+			if err := validation.Struct(in); err != nil {
+				return Job{}, err
+			}
+			j, err := r.withCurrency(in.toJob())
+			return nextVersion(old, j), err
+		},
+	})
+}
@@ -1,15 +1,28 @@
 package jobs
 
 import (
+	"time"
+
 	"github.com/google/uuid"
+	"mercor/internal/patch"
+	"mercor/internal/scd"
+	"mercor/internal/validation"
 )
 
 type Service interface {
-	CreateJob(j Job) error
+	CreateJob(j Job) (Job, error)
 	GetByUID(uid string) (Job, error)
-	Update(uid string, updated Job) (Job, error)
-	UpdateStatus(uid, status string) (Job, error)
+	GetAsOf(uid string, at time.Time) (Job, error)
+	GetAsKnown(uid string, knownAt, at time.Time) (Job, error)
+	Scheduled() ([]Job, error)
+	CancelScheduled(uid string) (Job, error)
+	Revert(uid, to, reason string, pre scd.Precondition) (Job, error)
+	History(uid string) ([]Job, error)
+	Update(uid string, updated Job, pre scd.Precondition) (Job, error)
+	UpdateStatus(uid, status string, pre scd.Precondition) (Job, error)
 	GetActiveJobsByCompany(companyID string) ([]Job, error)
+	Patch(uid, contentType string, body []byte, pre scd.Precondition) (Job, error)
+	Batch(req scd.BatchRequest[JobRequest]) ([]scd.BatchResult[Job], error)
 }
 
 type service struct {
@@ -20,7 +33,20 @@ func NewService(r Repository) Service {
 	return &service{repo: r}
 }
 
-func (s *service) CreateJob(j Job) error {
+// CreateJob stores the first version of a new job. Identity and version are
+// always assigned here, never taken from the client. A job without a
+// currency is paid in the default currency of its company.
+func (s *service) CreateJob(j Job) (Job, error) {
+	if j.Currency == "" {
+		currency, err := s.repo.DefaultCurrency(j.CompanyID)
+		if err != nil {
+			return j, err
+		}
+		j.Currency = currency
+	}
+	j.ID = uuid.New()
+	j.UID = uuid.New()
+	j.Version = 1
 	return s.repo.Create(j)
 }
 
@@ -28,15 +54,86 @@ func (s *service) GetByUID(uid string) (Job, error) {
 	return s.repo.FindByUID(uid)
 }
 
-func (s *service) Update(uid string, updated Job) (Job, error) {
-	return s.repo.Update(uid, updated)
+// GetAsOf returns the version of the job that was effective at the given time.
+func (s *service) GetAsOf(uid string, at time.Time) (Job, error) {
+	return s.repo.FindAsOf(uid, at)
+}
+
+// GetAsKnown returns the version of the job that was effective at the given
+// time according to what was recorded at knownAt.
+func (s *service) GetAsKnown(uid string, knownAt, at time.Time) (Job, error) {
+	return s.repo.FindAsKnown(uid, knownAt, at)
+}
+
+// Scheduled returns the pending versions, stored with an effectiveFrom in the
+// future, that reads of the latest version leave out until they take effect.
+func (s *service) Scheduled() ([]Job, error) {
+	return s.repo.Scheduled()
+}
+
+// CancelScheduled withdraws a pending version before it takes effect.
+func (s *service) CancelScheduled(uid string) (Job, error) {
+	return s.repo.CancelScheduled(uid)
+}
+
+// Revert stores a new version of the job with the values of its version
+// `to`, recording that version and the reason on it.
+func (s *service) Revert(uid, to, reason string, pre scd.Precondition) (Job, error) {
+	return s.repo.Revert(uid, to, reason, pre)
 }
 
-func (s *service) UpdateStatus(uid, status string) (Job, error) {
-	return s.repo.UpdateStatus(uid, status)
+// History returns every version of the job, oldest first.
+func (s *service) History(uid string) ([]Job, error) {
+	return s.repo.History(uid)
+}
+
+func (s *service) Update(uid string, updated Job, pre scd.Precondition) (Job, error) {
+	return s.repo.Update(uid, updated, pre)
+}
+
+func (s *service) UpdateStatus(uid, status string, pre scd.Precondition) (Job, error) {
+	return s.repo.UpdateStatus(uid, status, pre)
 }
 
 func (s *service) GetActiveJobsByCompany(companyID string) ([]Job, error) {
 	id := uuid.MustParse(companyID)
 	return s.repo.FindLatestByCompany(id)
 }
+
+// Patch applies a merge patch or JSON patch to the response representation of
+// the head version of the entity that uid belongs to. The head is locked while
+// the patch is applied, so the result is based on the version it replaces. The
+// result must be a valid JobRequest and is stored as a new version.
+func (s *service) Patch(uid, contentType string, body []byte, pre scd.Precondition) (Job, error) {
+	return s.repo.Append(uid, pre, func(head Job) (Job, error) {
+		patched, err := patch.Entity(NewJobResponse(head), contentType, body, validation.Struct[JobRequest])
+		if err != nil {
+			return Job{}, err
+		}
+		// The representation carries the effectiveFrom of the head; only a
+		// changed one back-dates the patch.
+		if patched.EffectiveFrom != nil && patched.EffectiveFrom.Equal(head.EffectiveFrom) {
+			patched.EffectiveFrom = nil
+		}
+		return nextVersion(head, patched.toJob()), nil
+	})
+}
+
+// Batch applies the company defaults to the jobs it creates, as CreateJob
+// does, before the items are written.
+func (s *service) Batch(req scd.BatchRequest[JobRequest]) ([]scd.BatchResult[Job], error) {
+	for i, op := range req.Items {
+		if op.Op != scd.BatchOpCreate || op.Data.Currency != "" {
+			continue
+		}
+		// An invalid companyId fails validation when the item is applied.
+		companyID, err := uuid.Parse(op.Data.CompanyID)
+		if err != nil {
+			continue
+		}
+		if req.Items[i].Data.Currency, err = s.repo.DefaultCurrency(companyID); err != nil {
+			return nil, err
+		}
+	}
+	return s.repo.Batch(req.Items, req.Mode)
+}
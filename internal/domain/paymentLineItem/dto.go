@@ -0,0 +1,107 @@
+package payment
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"mercor/internal/scd"
+)
+
+// PaymentLineItemRequest is the body of POST /payment-line-items and
+// PUT /payment-line-items/:uid and the data of batch items. Amount is a
+// pointer so that an explicit 0 is accepted while a missing amount is not.
+// Identity, version and timestamps are assigned by the server. EffectiveFrom
+// back-dates the change; it defaults to now. TimelogUID links the line item
+// to the timelog version it pays for. Status is pending or approved, pending
+// unless it is set. PUT takes the complete representation, so an empty
+// TimelogUID or Status clears the link or sets the line item pending; partial
+// updates are left to PATCH.
+type PaymentLineItemRequest struct {
+	ContractorID  string     `json:"contractorId" binding:"required,uuid"`
+	TimelogUID    string     `json:"timelogUid,omitempty" binding:"omitempty,uuid"`
+	Amount        *float64   `json:"amount" binding:"required,gte=0"`
+	IssuedAt      time.Time  `json:"issuedAt" binding:"required"`
+	Status        string     `json:"status,omitempty" binding:"omitempty,oneof=pending approved"`
+	EffectiveFrom *time.Time `json:"effectiveFrom,omitempty"`
+}
+
+// toPaymentLineItem converts a validated request.
+func (r PaymentLineItemRequest) toPaymentLineItem() PaymentLineItem {
+	return PaymentLineItem{
+		ContractorID: uuid.MustParse(r.ContractorID),
+		TimelogUID:   optionalUID(r.TimelogUID),
+		Amount:       *r.Amount,
+		IssuedAt:     r.IssuedAt,
+		Status:       r.Status,
+		Bitemporal:   effectiveFrom(r.EffectiveFrom),
+	}
+}
+
+// effectiveFrom starts the effective period of a version at from, or leaves
+// it to the server if from is nil.
+func effectiveFrom(from *time.Time) scd.Bitemporal {
+	if from == nil {
+		return scd.Bitemporal{}
+	}
+	return scd.Bitemporal{EffectiveFrom: *from}
+}
+
+// optionalUID parses an optional UID of a validated request.
+func optionalUID(s string) *uuid.UUID {
+	if s == "" {
+		return nil
+	}
+	u := uuid.MustParse(s)
+	return &u
+}
+
+type PaymentLineItemResponse struct {
+	ID           uuid.UUID  `json:"id"`
+	UID          uuid.UUID  `json:"uid"`
+	Version      int        `json:"version"`
+	ContractorID uuid.UUID  `json:"contractorId"`
+	TimelogUID   *uuid.UUID `json:"timelogUid,omitempty"`
+	Amount       float64    `json:"amount"`
+	IssuedAt     time.Time  `json:"issuedAt"`
+	Status       string     `json:"status"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	UpdatedAt    time.Time  `json:"updatedAt"`
+	// EffectiveFrom and EffectiveTo bound when the version applied;
+	// RecordedFrom and RecordedTo when it was the recorded state.
+	EffectiveFrom time.Time  `json:"effectiveFrom"`
+	EffectiveTo   *time.Time `json:"effectiveTo"`
+	RecordedFrom  time.Time  `json:"recordedFrom"`
+	RecordedTo    *time.Time `json:"recordedTo"`
+	// RevertedFrom and RevertReason are set on versions written by a revert.
+	RevertedFrom *uuid.UUID `json:"revertedFrom,omitempty"`
+	RevertReason string     `json:"revertReason,omitempty"`
+}
+
+func NewPaymentLineItemResponse(p PaymentLineItem) PaymentLineItemResponse {
+	return PaymentLineItemResponse{
+		ID:            p.ID,
+		UID:           p.UID,
+		Version:       p.Version,
+		ContractorID:  p.ContractorID,
+		TimelogUID:    p.TimelogUID,
+		Amount:        p.Amount,
+		IssuedAt:      p.IssuedAt,
+		Status:        p.Status,
+		CreatedAt:     p.CreatedAt,
+		UpdatedAt:     p.UpdatedAt,
+		EffectiveFrom: p.EffectiveFrom,
+		EffectiveTo:   p.EffectiveTo,
+		RecordedFrom:  p.RecordedFrom,
+		RecordedTo:    p.RecordedTo,
+		RevertedFrom:  p.RevertedFrom,
+		RevertReason:  p.RevertReason,
+	}
+}
+
+func NewPaymentLineItemResponses(list []PaymentLineItem) []PaymentLineItemResponse {
+	out := make([]PaymentLineItemResponse, len(list))
+	for i, p := range list {
+		out[i] = NewPaymentLineItemResponse(p)
+	}
+	return out
+}
@@ -0,0 +1,131 @@
+package payment
+
+import (
+	"context"
+	"encoding/json"
+
+	scdv1 "mercor/api/scd/v1"
+	"mercor/internal/patch"
+	"mercor/internal/rpc"
+	"mercor/internal/validation"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// GRPCServer exposes the Service over gRPC with the same semantics as Handler.
+type GRPCServer struct {
+	scdv1.UnimplementedPaymentLineItemServiceServer
+	svc Service
+}
+
+func NewGRPCServer(s Service) *GRPCServer {
+	return &GRPCServer{svc: s}
+}
+
+func (g *GRPCServer) CreatePaymentLineItem(_ context.Context, req *scdv1.CreatePaymentLineItemRequest) (*scdv1.PaymentLineItem, error) {
+	in, err := paymentLineItemRequestFromProto(req.GetPaymentLineItem())
+	if err != nil {
+		return nil, err
+	}
+	p, err := g.svc.Create(in.toPaymentLineItem())
+	if err != nil {
+		return nil, rpc.Error(err)
+	}
+	return paymentLineItemToProto(p), nil
+}
+
+func (g *GRPCServer) GetPaymentLineItem(_ context.Context, req *scdv1.GetPaymentLineItemRequest) (*scdv1.PaymentLineItem, error) {
+	var p PaymentLineItem
+	var err error
+	if req.GetAsOf() != nil {
+		p, err = g.svc.GetAsOf(req.GetUid(), req.GetAsOf().AsTime())
+	} else {
+		p, err = g.svc.GetByUID(req.GetUid())
+	}
+	if err != nil {
+		return nil, rpc.Error(err)
+	}
+	return paymentLineItemToProto(p), nil
+}
+
+// UpdatePaymentLineItem stores the fields of PaymentLineItemInput as a new
+// version. They are merged into the head, so the timelog link and the status,
+// which PaymentLineItemInput lacks, keep their values.
+func (g *GRPCServer) UpdatePaymentLineItem(_ context.Context, req *scdv1.UpdatePaymentLineItemRequest) (*scdv1.PaymentLineItem, error) {
+	in, err := paymentLineItemRequestFromProto(req.GetPaymentLineItem())
+	if err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(in)
+	if err != nil {
+		return nil, rpc.Error(err)
+	}
+	p, err := g.svc.Patch(req.GetUid(), patch.ContentTypeMergePatch, body, rpc.ExpectHead(req.GetExpectedHeadUid()))
+	if err != nil {
+		return nil, rpc.Error(err)
+	}
+	return paymentLineItemToProto(p), nil
+}
+
+func (g *GRPCServer) DeletePaymentLineItem(_ context.Context, req *scdv1.DeletePaymentLineItemRequest) (*emptypb.Empty, error) {
+	if err := g.svc.Delete(req.GetUid(), rpc.ExpectHead(req.GetExpectedHeadUid())); err != nil {
+		return nil, rpc.Error(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (g *GRPCServer) ListPaymentLineItemHistory(_ context.Context, req *scdv1.ListPaymentLineItemHistoryRequest) (*scdv1.ListPaymentLineItemsResponse, error) {
+	list, err := g.svc.History(req.GetUid())
+	if err != nil {
+		return nil, rpc.Error(err)
+	}
+	return &scdv1.ListPaymentLineItemsResponse{PaymentLineItems: paymentLineItemsToProto(list)}, nil
+}
+
+func (g *GRPCServer) ListContractorPaymentLineItems(_ context.Context, req *scdv1.ListContractorPaymentLineItemsRequest) (*scdv1.ListPaymentLineItemsResponse, error) {
+	if err := rpc.UUIDArg("contractor_id", req.GetContractorId()); err != nil {
+		return nil, err
+	}
+	list, err := g.svc.GetByContractor(req.GetContractorId())
+	if err != nil {
+		return nil, rpc.Error(err)
+	}
+	return &scdv1.ListPaymentLineItemsResponse{PaymentLineItems: paymentLineItemsToProto(list)}, nil
+}
+
+// paymentLineItemRequestFromProto checks the input against the binding rules
+// of PaymentLineItemRequest, so both transports accept the same bodies. Proto3
+// cannot tell a zero amount from a missing one, so amount is always present.
+func paymentLineItemRequestFromProto(in *scdv1.PaymentLineItemInput) (PaymentLineItemRequest, error) {
+	amount := in.GetAmount()
+	req := PaymentLineItemRequest{
+		ContractorID: in.GetContractorId(),
+		Amount:       &amount,
+		IssuedAt:     rpc.Time(in.GetIssuedAt()),
+	}
+	if err := validation.Struct(req); err != nil {
+		return req, rpc.Invalid(err)
+	}
+	return req, nil
+}
+
+func paymentLineItemToProto(p PaymentLineItem) *scdv1.PaymentLineItem {
+	return &scdv1.PaymentLineItem{
+		Id:           p.ID.String(),
+		Uid:          p.UID.String(),
+		Version:      int32(p.Version),
+		ContractorId: p.ContractorID.String(),
+		Amount:       p.Amount,
+		IssuedAt:     rpc.Timestamp(p.IssuedAt),
+		CreatedAt:    rpc.Timestamp(p.CreatedAt),
+		UpdatedAt:    rpc.Timestamp(p.UpdatedAt),
+	}
+}
+
+func paymentLineItemsToProto(list []PaymentLineItem) []*scdv1.PaymentLineItem {
+	out := make([]*scdv1.PaymentLineItem, len(list))
+	for i, p := range list {
+		out[i] = paymentLineItemToProto(p)
+	}
+	return out
+}
@@ -1,8 +1,13 @@
 package payment
 
 import (
+	"errors"
 	"net/http"
 	"github.com/gin-gonic/gin"
+	"mercor/internal/idempotency"
+	"mercor/internal/patch"
+	"mercor/internal/scd"
+	"mercor/internal/validation"
 );
 
 type Handler struct {
@@ -13,65 +18,206 @@ func NewHandler(s Service) *Handler {
 	return &Handler{svc: s}
 }
 
+// service returns the service to serve c with: for a request with an
+// Idempotency-Key, one writing in its transaction, so the stored response
+// commits with the writes.
+func (h *Handler) service(c *gin.Context) Service {
+	if tx, ok := idempotency.Tx(c); ok {
+		return NewService(NewRepository(tx))
+	}
+	return h.svc
+}
+
 func (h *Handler) RegisterRoutes(r *gin.Engine) {
 	r.POST("/payment-line-items", h.Create)
+	r.POST("/payment-line-items/batch", h.Batch)
+	r.GET("/payment-line-items/scheduled", h.Scheduled)
+	r.DELETE("/payment-line-items/scheduled/:uid", h.CancelScheduled)
 	r.GET("/payment-line-items/:uid", h.GetByUID)
+	r.GET("/payment-line-items/:uid/history", h.History)
+	r.POST("/payment-line-items/:uid/revert", h.Revert)
 	r.PUT("/payment-line-items/:uid", h.Update)
+	r.PATCH("/payment-line-items/:uid", h.Patch)
 	r.DELETE("/payment-line-items/:uid", h.Delete)
 	r.GET("/contractors/:id/payment-line-items", h.GetByContractor)
+	r.GET("/timelogs/:uid/payment-line-items", h.GetByTimelog)
 }
 
 func (h *Handler) Create(c *gin.Context) {
-	var req PaymentLineItem
+	var req PaymentLineItemRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, validation.Response(err))
 		return
 	}
-	resp, err := h.svc.Create(req)
+	resp, err := h.service(c).Create(req.toPaymentLineItem())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(scd.StatusCode(err), gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusCreated, resp)
+	c.JSON(http.StatusCreated, NewPaymentLineItemResponse(resp))
 }
 
+// GetByUID returns the given version. With ?as_of=<RFC 3339 time> it returns
+// the version of the same line item that was effective at that time, with
+// ?known_at= as it was on record at that time.
 func (h *Handler) GetByUID(c *gin.Context) {
-	resp, err := h.svc.GetByUID(c.Param("uid"))
+	tt, err := scd.ParseTimeTravel(c.Query("as_of"), c.Query("known_at"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var resp PaymentLineItem
+	switch {
+	case tt.KnownAt != nil:
+		resp, err = h.service(c).GetAsKnown(c.Param("uid"), *tt.KnownAt, *tt.AsOf)
+	case tt.AsOf != nil:
+		resp, err = h.service(c).GetAsOf(c.Param("uid"), *tt.AsOf)
+	default:
+		resp, err = h.service(c).GetByUID(c.Param("uid"))
+	}
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, resp)
+	c.Header("ETag", scd.ETag(resp.UID.String()))
+	c.JSON(http.StatusOK, NewPaymentLineItemResponse(resp))
+}
+
+func (h *Handler) History(c *gin.Context) {
+	resp, err := h.service(c).History(c.Param("uid"))
+	if err != nil {
+		c.JSON(scd.StatusCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, NewPaymentLineItemResponses(resp))
+}
+
+// Scheduled lists the versions that take effect later, soonest first.
+func (h *Handler) Scheduled(c *gin.Context) {
+	resp, err := h.service(c).Scheduled()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, NewPaymentLineItemResponses(resp))
+}
+
+// CancelScheduled withdraws a scheduled version and returns it, no longer
+// recorded. Versions that already took effect cannot be cancelled (409).
+func (h *Handler) CancelScheduled(c *gin.Context) {
+	resp, err := h.service(c).CancelScheduled(c.Param("uid"))
+	if err != nil {
+		c.JSON(scd.StatusCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, NewPaymentLineItemResponse(resp))
+}
+
+// Revert stores a new version with the values of the version named by
+// ?to=<uid>, recording the reason given in the body. It honours If-Match like
+// Update.
+func (h *Handler) Revert(c *gin.Context) {
+	to := c.Query("to")
+	if to == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to is required"})
+		return
+	}
+	var req scd.RevertRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, validation.Response(err))
+		return
+	}
+	resp, err := h.service(c).Revert(c.Param("uid"), to, req.Reason, scd.IfMatch(c.GetHeader("If-Match")))
+	if err != nil {
+		c.JSON(scd.StatusCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("ETag", scd.ETag(resp.UID.String()))
+	c.JSON(http.StatusOK, NewPaymentLineItemResponse(resp))
 }
 
+// Update and Delete honour If-Match: the write fails with 412 unless the ETag
+// of the head version is listed.
 func (h *Handler) Update(c *gin.Context) {
-	var req PaymentLineItem
+	var req PaymentLineItemRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, validation.Response(err))
 		return
 	}
-	resp, err := h.svc.Update(c.Param("uid"), req)
+	resp, err := h.service(c).Update(c.Param("uid"), req.toPaymentLineItem(), scd.IfMatch(c.GetHeader("If-Match")))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(scd.StatusCode(err), gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, resp)
+	c.Header("ETag", scd.ETag(resp.UID.String()))
+	c.JSON(http.StatusOK, NewPaymentLineItemResponse(resp))
 }
 
 func (h *Handler) Delete(c *gin.Context) {
-	err := h.svc.Delete(c.Param("uid"))
+	err := h.service(c).Delete(c.Param("uid"), scd.IfMatch(c.GetHeader("If-Match")))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(scd.StatusCode(err), gin.H{"error": err.Error()})
 		return
 	}
 	c.Status(http.StatusNoContent)
 }
 
 func (h *Handler) GetByContractor(c *gin.Context) {
-	resp, err := h.svc.GetByContractor(c.Param("id"))
+	resp, err := h.service(c).GetByContractor(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, NewPaymentLineItemResponses(resp))
+}
+
+// GetByTimelog lists the current payment line items linked to any version of the timelog
+// named by :uid.
+func (h *Handler) GetByTimelog(c *gin.Context) {
+	resp, err := h.service(c).GetByTimelog(c.Param("uid"))
+	if err != nil {
+		c.JSON(scd.StatusCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, NewPaymentLineItemResponses(resp))
+}
+
+func (h *Handler) Batch(c *gin.Context) {
+	var req scd.BatchRequest[PaymentLineItemRequest]
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	items, err := h.service(c).Batch(req)
+	results := scd.MapBatchResults(items, NewPaymentLineItemResponse)
+	if errors.Is(err, scd.ErrBatchRejected) {
+		c.JSON(http.StatusUnprocessableEntity, scd.BatchResponse[PaymentLineItemResponse]{Error: err.Error(), Results: results})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, resp)
+	c.JSON(http.StatusOK, scd.BatchResponse[PaymentLineItemResponse]{Results: results})
+}
+
+// Patch accepts application/merge-patch+json (or application/json) and
+// application/json-patch+json bodies.
+func (h *Handler) Patch(c *gin.Context) {
+	body, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	resp, err := h.service(c).Patch(c.Param("uid"), c.ContentType(), body, scd.IfMatch(c.GetHeader("If-Match")))
+	if err != nil {
+		c.JSON(patch.StatusCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("ETag", scd.ETag(resp.UID.String()))
+	c.JSON(http.StatusOK, NewPaymentLineItemResponse(resp))
 }
@@ -3,29 +3,58 @@ package payment
 import (
   "time"
   "github.com/google/uuid"
+  "mercor/internal/scd"
+)
+
+// Approval states of a line item. Only approved line items are invoiced.
+const (
+  StatusPending  = "pending"
+  StatusApproved = "approved"
 )
 
 type PaymentLineItem struct {
-  ID           uuid.UUID `gorm:"type:uuid"`
-  UID          uuid.UUID `gorm:"type:uuid;primaryKey"`
-  Version      int
-  ContractorID uuid.UUID
-  Amount       float64
-  IssuedAt     time.Time
-  CreatedAt    time.Time
-  UpdatedAt    time.Time
+  ID           uuid.UUID `gorm:"type:uuid" json:"id"`
+  UID          uuid.UUID `gorm:"type:uuid;primaryKey" json:"uid"`
+  Version      int       `json:"version"`
+  ContractorID uuid.UUID `gorm:"index" json:"contractorId"`
+  // TimelogUID links the line item to the timelog version it pays for.
+  TimelogUID   *uuid.UUID `gorm:"type:uuid;index" json:"timelogUid"`
+  Amount       float64   `json:"amount"`
+  IssuedAt     time.Time `json:"issuedAt"`
+  Status       string    `gorm:"default:pending" json:"status"`
+  CreatedAt    time.Time `json:"createdAt"`
+  UpdatedAt    time.Time `json:"updatedAt"`
+  scd.Bitemporal
+  scd.Reversion
 }
 
 func (PaymentLineItem) TableName() string { return "payment_line_items" }
 func (p PaymentLineItem) GetID() string { return p.ID.String() }
 func (p PaymentLineItem) GetUID() string { return p.UID.String() }
 func (p PaymentLineItem) GetVersion() int { return p.Version }
+// ChangePolicy versions every change, since line items are paid out as of a
+// point in time.
+func (PaymentLineItem) ChangePolicy() scd.Policy { return nil }
+
+// Identity ties a line item to the contractor it pays.
+func (PaymentLineItem) Identity() []string {
+  return []string{"contractor_id"}
+}
+
+// References requires the contractor of a line item to be on record.
+func (PaymentLineItem) References() []scd.Reference {
+  return []scd.Reference{scd.RefersTo("contractor_id", "contractors")}
+}
+
 func (p PaymentLineItem) CopyForNewVersion() PaymentLineItem{
   return PaymentLineItem{
     ID:           p.ID,
     ContractorID: p.ContractorID,
+    TimelogUID:   p.TimelogUID,
     Amount:       p.Amount,
     IssuedAt:     p.IssuedAt,
+    Status:       p.Status,
     Version:      p.Version + 1,
+    UID:          uuid.New(),
   }
 }
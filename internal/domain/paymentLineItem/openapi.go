@@ -0,0 +1,147 @@
+package payment
+
+import (
+	"net/http"
+
+	"mercor/internal/openapi"
+	"mercor/internal/scd"
+)
+
+// Operations documents the routes registered by Handler.
+func Operations() []openapi.Operation {
+	item := openapi.JSON("The payment line item version", PaymentLineItemResponse{})
+	batch := openapi.JSON("Per-item results", scd.BatchResponse[PaymentLineItemResponse]{})
+	return []openapi.Operation{
+		{
+			Method: http.MethodPost, Path: "/payment-line-items", Tag: "payment-line-items",
+			Summary: "Create a payment line item",
+			Body:    openapi.Body(PaymentLineItemRequest{}),
+			Responses: openapi.Responses{
+				201: openapi.JSON("The first version of the line item", PaymentLineItemResponse{}),
+				400: openapi.Invalid,
+				422: openapi.InvalidPeriodOrReference,
+				500: openapi.ServerError,
+			},
+		},
+		{
+			Method: http.MethodPost, Path: "/payment-line-items/batch", Tag: "payment-line-items",
+			Summary:     "Create and version payment line items in one transaction",
+			Description: "Also served at POST /payment-line-items:batch.",
+			Body:        openapi.Body(scd.BatchRequest[PaymentLineItemRequest]{}),
+			Responses: openapi.Responses{
+				200: batch,
+				400: openapi.BadRequest,
+				422: openapi.JSON("An atomic batch was rejected", scd.BatchResponse[PaymentLineItemResponse]{}),
+				500: openapi.ServerError,
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/payment-line-items/scheduled", Tag: "payment-line-items",
+			Summary:     "List scheduled payment line item versions",
+			Description: "Versions written with an effectiveFrom in the future, soonest first. Reads of the latest version leave them out until they take effect.",
+			Responses: openapi.Responses{
+				200: openapi.JSON("Pending versions", []PaymentLineItemResponse{}),
+				500: openapi.ServerError,
+			},
+		},
+		{
+			Method: http.MethodDelete, Path: "/payment-line-items/scheduled/:uid", Tag: "payment-line-items",
+			Summary: "Cancel a scheduled payment line item version",
+			Responses: openapi.Responses{
+				200: openapi.JSON("The cancelled version, no longer recorded", PaymentLineItemResponse{}),
+				404: openapi.NotFound,
+				409: openapi.JSON("The version is not pending", openapi.Error{}),
+				500: openapi.ServerError,
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/payment-line-items/:uid", Tag: "payment-line-items",
+			Summary: "Get a payment line item version by UID",
+			Params:  []openapi.Param{openapi.AsOf, openapi.KnownAt},
+			Responses: openapi.Responses{
+				200: item,
+				400: openapi.BadRequest,
+				404: openapi.NotFound,
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/payment-line-items/:uid/history", Tag: "payment-line-items",
+			Summary: "List every version of a line item, oldest first",
+			Responses: openapi.Responses{
+				200: openapi.JSON("All versions of the line item", []PaymentLineItemResponse{}),
+				404: openapi.NotFound,
+			},
+		},
+		{
+			Method: http.MethodPost, Path: "/payment-line-items/:uid/revert", Tag: "payment-line-items",
+			Summary:     "Revert a line item to an earlier version",
+			Description: "Stores a new version with the values of the version named by to, recording it and the reason. The history is not rewritten.",
+			Params: []openapi.Param{
+				{Name: "to", In: "query", Required: true, Description: "UID of the version to restore."},
+				openapi.IfMatch,
+			},
+			Body: openapi.Body(scd.RevertRequest{}),
+			Responses: openapi.Responses{
+				200: item,
+				400: openapi.Invalid,
+				404: openapi.NotFound,
+				412: openapi.PreconditionFailed,
+				422: openapi.JSON("to is not a version of this line item", openapi.Error{}),
+				500: openapi.ServerError,
+			},
+		},
+		{
+			Method: http.MethodPut, Path: "/payment-line-items/:uid", Tag: "payment-line-items",
+			Summary:     "Replace a payment line item",
+			Description: "Stores the complete representation as a new version on top of the head version. An effectiveFrom in the future schedules the version.",
+			Params:      []openapi.Param{openapi.IfMatch},
+			Body:        openapi.Body(PaymentLineItemRequest{}),
+			Responses: openapi.Responses{
+				200: item,
+				400: openapi.Invalid,
+				404: openapi.NotFound,
+				422: openapi.InvalidPeriodOrReference,
+				412: openapi.PreconditionFailed,
+				500: openapi.ServerError,
+			},
+		},
+		{
+			Method: http.MethodPatch, Path: "/payment-line-items/:uid", Tag: "payment-line-items",
+			Summary: "Partially update a payment line item",
+			Params:  []openapi.Param{openapi.IfMatch},
+			Body:    openapi.PatchBody(PaymentLineItemResponse{}),
+			Responses: openapi.Responses{
+				200: item,
+				400: openapi.BadRequest,
+				404: openapi.NotFound,
+				409: openapi.JSON("A JSON Patch test operation failed", openapi.Error{}),
+				412: openapi.PreconditionFailed,
+				415: openapi.JSON("Unsupported patch media type", openapi.Error{}),
+				422: openapi.JSON("The patched line item is invalid, its effectiveFrom cannot be applied or its contractor does not exist", openapi.Error{}),
+			},
+		},
+		{
+			Method: http.MethodDelete, Path: "/payment-line-items/:uid", Tag: "payment-line-items",
+			Summary:     "Void a payment line item",
+			Description: "Stores a new version with a zero amount.",
+			Params:      []openapi.Param{openapi.IfMatch},
+			Responses: openapi.Responses{
+				204: openapi.Empty("Voided"),
+				404: openapi.NotFound,
+				412: openapi.PreconditionFailed,
+				500: openapi.ServerError,
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/contractors/:id/payment-line-items", Tag: "payment-line-items",
+			Summary:   "List the current payment line items of a contractor",
+			Responses: openapi.Responses{200: openapi.JSON("Current line item versions", []PaymentLineItemResponse{}), 500: openapi.ServerError},
+		},
+		{
+			Method: http.MethodGet, Path: "/timelogs/:uid/payment-line-items", Tag: "payment-line-items",
+			Summary:     "List the current payment line items of a timelog",
+			Description: "Matches line items linked to any version of the timelog. Line items keep the timelog version they pay for and are not re-pointed when the timelog changes.",
+			Responses:   openapi.Responses{200: openapi.JSON("Current line item versions", []PaymentLineItemResponse{}), 500: openapi.ServerError},
+		},
+	}
+}
@@ -1,17 +1,33 @@
 package payment
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 	"mercor/internal/scd"
+	"mercor/internal/validation"
 )
 
 type Repository interface {
 	Insert(p PaymentLineItem) (PaymentLineItem, error)
 	FindByUID(uid string) (PaymentLineItem, error)
-	Update(uid string, p PaymentLineItem) (PaymentLineItem, error)
-	SoftDelete(uid string) error
+	History(uid string) ([]PaymentLineItem, error)
+	FindAsOf(uid string, at time.Time) (PaymentLineItem, error)
+	FindAsKnown(uid string, knownAt, at time.Time) (PaymentLineItem, error)
+	Scheduled() ([]PaymentLineItem, error)
+	CancelScheduled(uid string) (PaymentLineItem, error)
+	Revert(uid, to, reason string, pre scd.Precondition) (PaymentLineItem, error)
+	VersionsByIDs(ids []uuid.UUID) ([]PaymentLineItem, error)
+	FindByIDs(ids []uuid.UUID, at *time.Time) ([]PaymentLineItem, error)
+	FindByContractors(contractorIDs []uuid.UUID, at *time.Time) ([]PaymentLineItem, error)
+	FindByTimelogs(timelogUIDs []uuid.UUID, at *time.Time) ([]PaymentLineItem, error)
+	Update(uid string, updated PaymentLineItem, pre scd.Precondition) (PaymentLineItem, error)
+	SoftDelete(uid string, pre scd.Precondition) error
+	Append(uid string, pre scd.Precondition, next func(head PaymentLineItem) (PaymentLineItem, error)) (PaymentLineItem, error)
 	FindLatestByContractor(contractorID uuid.UUID) ([]PaymentLineItem, error)
+	FindLatestByTimelog(uid string) ([]PaymentLineItem, error)
+	Batch(ops []scd.BatchOp[PaymentLineItemRequest], mode scd.BatchMode) ([]scd.BatchResult[PaymentLineItem], error)
 }
 
 type repo struct {
@@ -23,7 +39,10 @@ func NewRepository(db *gorm.DB) Repository {
 }
 
 func (r *repo) Insert(p PaymentLineItem) (PaymentLineItem, error) {
-	err := r.scd.Insert(p)
+	if p.Status == "" {
+		p.Status = StatusPending
+	}
+	err := r.scd.Insert(&p)
 	return p, err
 }
 
@@ -31,27 +50,88 @@ func (r *repo) FindByUID(uid string) (PaymentLineItem, error) {
 	return r.scd.FindByUID(uid)
 }
 
-func (r *repo) Update(uid string, updated PaymentLineItem) (PaymentLineItem, error) {
-	old, err := r.scd.FindByUID(uid)
-	if err != nil {
-		return PaymentLineItem{}, err
-	}
-	newVer := old.CopyForNewVersion()
-	newVer.Amount = updated.Amount
-	newVer.IssuedAt = updated.IssuedAt
-	newVer.ContractorID = updated.ContractorID
-	err = r.scd.Insert(newVer)
-	return newVer, err
+func (r *repo) History(uid string) ([]PaymentLineItem, error) {
+	return r.scd.HistoryByUID(uid)
 }
 
-func (r *repo) SoftDelete(uid string) error {
-	old, err := r.scd.FindByUID(uid)
-	if err != nil {
-		return err
-	}
+func (r *repo) FindAsOf(uid string, at time.Time) (PaymentLineItem, error) {
+	return r.scd.FindAsOfByUID(uid, at)
+}
+
+func (r *repo) FindAsKnown(uid string, knownAt, at time.Time) (PaymentLineItem, error) {
+	return r.scd.FindAsKnownByUID(uid, knownAt, at)
+}
+
+// Scheduled returns the versions that take effect later, soonest first.
+func (r *repo) Scheduled() ([]PaymentLineItem, error) {
+	var list []PaymentLineItem
+	err := r.scd.Scheduled().Find(&list).Error
+	return list, err
+}
+
+func (r *repo) CancelScheduled(uid string) (PaymentLineItem, error) {
+	return r.scd.CancelScheduled(uid)
+}
+
+func (r *repo) Revert(uid, to, reason string, pre scd.Precondition) (PaymentLineItem, error) {
+	return r.scd.Revert(uid, to, reason, pre)
+}
+
+// VersionsByIDs, FindByIDs and FindByContractors load many entities in one
+// query each, for the GraphQL dataloaders. A nil at selects head versions.
+func (r *repo) VersionsByIDs(ids []uuid.UUID) ([]PaymentLineItem, error) {
+	return r.scd.VersionsOf(ids)
+}
+
+func (r *repo) FindByIDs(ids []uuid.UUID, at *time.Time) ([]PaymentLineItem, error) {
+	return r.scd.FindIn("id", ids, at)
+}
+
+func (r *repo) FindByContractors(contractorIDs []uuid.UUID, at *time.Time) ([]PaymentLineItem, error) {
+	return r.scd.FindIn("contractor_id", contractorIDs, at)
+}
+
+// FindByTimelogs selects the line items linked to one of the timelog
+// versions.
+func (r *repo) FindByTimelogs(timelogUIDs []uuid.UUID, at *time.Time) ([]PaymentLineItem, error) {
+	return r.scd.FindIn("timelog_uid", timelogUIDs, at)
+}
+
+func (r *repo) Update(uid string, updated PaymentLineItem, pre scd.Precondition) (PaymentLineItem, error) {
+	return r.Append(uid, pre, func(old PaymentLineItem) (PaymentLineItem, error) {
+		return nextVersion(old, updated), nil
+	})
+}
+
+// Append stores the version next builds from the locked head version.
+func (r *repo) Append(uid string, pre scd.Precondition, next func(head PaymentLineItem) (PaymentLineItem, error)) (PaymentLineItem, error) {
+	return r.scd.AppendVersion(uid, pre, next)
+}
+
+// nextVersion builds the version following old with the fields taken from in,
+// which replaces every one of them: a line item without a timelog link is
+// unlinked, and one without a status is pending, as on create.
+func nextVersion(old, in PaymentLineItem) PaymentLineItem {
 	newVer := old.CopyForNewVersion()
-	newVer.Amount = 0
-	return r.scd.Insert(newVer)
+	newVer.Amount = in.Amount
+	newVer.IssuedAt = in.IssuedAt
+	newVer.ContractorID = in.ContractorID
+	newVer.EffectiveFrom = in.EffectiveFrom
+	newVer.TimelogUID = in.TimelogUID
+	newVer.Status = in.Status
+	if newVer.Status == "" {
+		newVer.Status = StatusPending
+	}
+	return newVer
+}
+
+func (r *repo) SoftDelete(uid string, pre scd.Precondition) error {
+	_, err := r.Append(uid, pre, func(old PaymentLineItem) (PaymentLineItem, error) {
+		newVer := old.CopyForNewVersion()
+		newVer.Amount = 0
+		return newVer, nil
+	})
+	return err
 }
 
 func (r *repo) FindLatestByContractor(contractorID uuid.UUID) ([]PaymentLineItem, error) {
@@ -59,3 +139,35 @@ func (r *repo) FindLatestByContractor(contractorID uuid.UUID) ([]PaymentLineItem
 	err := r.scd.GetLatest().Where("contractor_id = ?", contractorID).Find(&list).Error
 	return list, err
 }
+
+// FindLatestByTimelog returns the current payment line items linked to any version of the
+// timelog uid belongs to.
+func (r *repo) FindLatestByTimelog(uid string) ([]PaymentLineItem, error) {
+	var list []PaymentLineItem
+	err := r.scd.LinkedTo("timelogs", "timelog_uid", uid).Find(&list).Error
+	return list, err
+}
+
+func (r *repo) Batch(ops []scd.BatchOp[PaymentLineItemRequest], mode scd.BatchMode) ([]scd.BatchResult[PaymentLineItem], error) {
+	return scd.ApplyBatch(r.scd, ops, mode, scd.BatchHooks[PaymentLineItemRequest, PaymentLineItem]{
+		Create: func(in PaymentLineItemRequest) (PaymentLineItem, error) {
+			if err := validation.Struct(in); err != nil {
+				return PaymentLineItem{}, err
+			}
+			p := in.toPaymentLineItem()
+			if p.Status == "" {
+				p.Status = StatusPending
+			}
+			p.ID = uuid.New()
+			p.UID = uuid.New()
+			p.Version = 1
+			return p, nil
+		},
+		Update: func(old PaymentLineItem, in PaymentLineItemRequest) (PaymentLineItem, error) {
+			if err := validation.Struct(in); err != nil {
+				return PaymentLineItem{}, err
+			}
+			return nextVersion(old, in.toPaymentLineItem()), nil
+		},
+	})
+}
@@ -1,13 +1,29 @@
 package payment
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"mercor/internal/patch"
+	"mercor/internal/scd"
+	"mercor/internal/validation"
+)
 
 type Service interface {
 	Create(p PaymentLineItem) (PaymentLineItem, error)
 	GetByUID(uid string) (PaymentLineItem, error)
-	Update(uid string, p PaymentLineItem) (PaymentLineItem, error)
-	Delete(uid string) error
+	GetAsOf(uid string, at time.Time) (PaymentLineItem, error)
+	GetAsKnown(uid string, knownAt, at time.Time) (PaymentLineItem, error)
+	Scheduled() ([]PaymentLineItem, error)
+	CancelScheduled(uid string) (PaymentLineItem, error)
+	Revert(uid, to, reason string, pre scd.Precondition) (PaymentLineItem, error)
+	History(uid string) ([]PaymentLineItem, error)
+	Update(uid string, p PaymentLineItem, pre scd.Precondition) (PaymentLineItem, error)
+	Delete(uid string, pre scd.Precondition) error
 	GetByContractor(id string) ([]PaymentLineItem, error)
+	GetByTimelog(uid string) ([]PaymentLineItem, error)
+	Patch(uid, contentType string, body []byte, pre scd.Precondition) (PaymentLineItem, error)
+	Batch(req scd.BatchRequest[PaymentLineItemRequest]) ([]scd.BatchResult[PaymentLineItem], error)
 }
 
 type service struct {
@@ -18,7 +34,10 @@ func NewService(r Repository) Service {
 	return &service{repo: r}
 }
 
+// Create stores the first version of a new line item. Identity and version
+// are always assigned here, never taken from the client.
 func (s *service) Create(p PaymentLineItem) (PaymentLineItem, error) {
+	p.ID = uuid.New()
 	p.UID = uuid.New()
 	p.Version = 1
 	return s.repo.Insert(p)
@@ -28,14 +47,74 @@ func (s *service) GetByUID(uid string) (PaymentLineItem, error) {
 	return s.repo.FindByUID(uid)
 }
 
-func (s *service) Update(uid string, p PaymentLineItem) (PaymentLineItem, error) {
-	return s.repo.Update(uid, p)
+// GetAsOf returns the version of the line item that was effective at the given time.
+func (s *service) GetAsOf(uid string, at time.Time) (PaymentLineItem, error) {
+	return s.repo.FindAsOf(uid, at)
+}
+
+// GetAsKnown returns the version of the line item that was effective at the given
+// time according to what was recorded at knownAt.
+func (s *service) GetAsKnown(uid string, knownAt, at time.Time) (PaymentLineItem, error) {
+	return s.repo.FindAsKnown(uid, knownAt, at)
+}
+
+// Scheduled returns the pending versions, stored with an effectiveFrom in the
+// future, that reads of the latest version leave out until they take effect.
+func (s *service) Scheduled() ([]PaymentLineItem, error) {
+	return s.repo.Scheduled()
 }
 
-func (s *service) Delete(uid string) error {
-	return s.repo.SoftDelete(uid)
+// CancelScheduled withdraws a pending version before it takes effect.
+func (s *service) CancelScheduled(uid string) (PaymentLineItem, error) {
+	return s.repo.CancelScheduled(uid)
+}
+
+// Revert stores a new version of the line item with the values of its version
+// `to`, recording that version and the reason on it.
+func (s *service) Revert(uid, to, reason string, pre scd.Precondition) (PaymentLineItem, error) {
+	return s.repo.Revert(uid, to, reason, pre)
+}
+
+// History returns every version of the line item, oldest first.
+func (s *service) History(uid string) ([]PaymentLineItem, error) {
+	return s.repo.History(uid)
+}
+
+func (s *service) Update(uid string, p PaymentLineItem, pre scd.Precondition) (PaymentLineItem, error) {
+	return s.repo.Update(uid, p, pre)
+}
+
+func (s *service) Delete(uid string, pre scd.Precondition) error {
+	return s.repo.SoftDelete(uid, pre)
 }
 
 func (s *service) GetByContractor(id string) ([]PaymentLineItem, error) {
 	return s.repo.FindLatestByContractor(uuid.MustParse(id))
 }
+
+func (s *service) GetByTimelog(uid string) ([]PaymentLineItem, error) {
+	return s.repo.FindLatestByTimelog(uid)
+}
+
+// Patch applies a merge patch or JSON patch to the response representation of
+// the head version of the entity that uid belongs to. The head is locked while
+// the patch is applied, so the result is based on the version it replaces. The
+// result must be a valid PaymentLineItemRequest and is stored as a new version.
+func (s *service) Patch(uid, contentType string, body []byte, pre scd.Precondition) (PaymentLineItem, error) {
+	return s.repo.Append(uid, pre, func(head PaymentLineItem) (PaymentLineItem, error) {
+		patched, err := patch.Entity(NewPaymentLineItemResponse(head), contentType, body, validation.Struct[PaymentLineItemRequest])
+		if err != nil {
+			return PaymentLineItem{}, err
+		}
+		// The representation carries the effectiveFrom of the head; only a
+		// changed one back-dates the patch.
+		if patched.EffectiveFrom != nil && patched.EffectiveFrom.Equal(head.EffectiveFrom) {
+			patched.EffectiveFrom = nil
+		}
+		return nextVersion(head, patched.toPaymentLineItem()), nil
+	})
+}
+
+func (s *service) Batch(req scd.BatchRequest[PaymentLineItemRequest]) ([]scd.BatchResult[PaymentLineItem], error) {
+	return s.repo.Batch(req.Items, req.Mode)
+}
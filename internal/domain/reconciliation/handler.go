@@ -0,0 +1,71 @@
+package reconciliation
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"mercor/internal/scd"
+)
+
+// Formats a report is returned in, chosen with ?format=. The CLI also writes
+// FormatText.
+const (
+	FormatJSON = "json"
+	FormatCSV  = "csv"
+	FormatText = "text"
+)
+
+type Handler struct {
+	svc Service
+}
+
+func NewHandler(s Service) *Handler {
+	return &Handler{svc: s}
+}
+
+func (h *Handler) RegisterRoutes(r *gin.Engine) {
+	r.GET("/reconciliation", h.Get)
+}
+
+// Get reconciles the current timelogs with the current payment line items,
+// of one contractor with ?contractor_id= and of one company's jobs with
+// ?company_id=. ?format= is json (the default) or csv.
+func (h *Handler) Get(c *gin.Context) {
+	var f Filter
+	for param, dst := range map[string]**uuid.UUID{"contractor_id": &f.ContractorID, "company_id": &f.CompanyID} {
+		v := c.Query(param)
+		if v == "" {
+			continue
+		}
+		id, err := uuid.Parse(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + param})
+			return
+		}
+		*dst = &id
+	}
+	format := c.DefaultQuery("format", FormatJSON)
+	if format != FormatJSON && format != FormatCSV {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be json or csv"})
+		return
+	}
+
+	rep, err := h.svc.Reconcile(f)
+	if err != nil {
+		c.JSON(scd.StatusCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	if format == FormatJSON {
+		c.JSON(http.StatusOK, rep)
+		return
+	}
+	var buf bytes.Buffer
+	if err := rep.WriteCSV(&buf); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("Content-Disposition", `attachment; filename="reconciliation-`+rep.GeneratedAt.Format("2006-01-02")+`.csv"`)
+	c.Data(http.StatusOK, "text/csv", buf.Bytes())
+}
@@ -0,0 +1,201 @@
+package reconciliation
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"mercor/internal/domain/jobs"
+	"mercor/internal/domain/timelog"
+)
+
+// Kinds of discrepancies.
+const (
+	// KindUnderpaid is time paid less than its job's rate makes it worth.
+	KindUnderpaid = "underpaid"
+	// KindOverpaid is time paid more than its job's rate makes it worth.
+	KindOverpaid = "overpaid"
+	// KindUnpaidTime is time with no payment line item.
+	KindUnpaidTime = "unpaid_time"
+	// KindUnlinkedTime is time not logged for a job, which has no rate to
+	// be checked against.
+	KindUnlinkedTime = "unlinked_time"
+	// KindOrphanedPayment is a payment line item that is not linked to a
+	// current timelog logged for a job.
+	KindOrphanedPayment = "orphaned_payment"
+)
+
+// kindOrder lists the kinds in the order reports show them.
+var kindOrder = map[string]int{KindUnderpaid: 0, KindOverpaid: 1, KindUnpaidTime: 2, KindUnlinkedTime: 3, KindOrphanedPayment: 4}
+
+// Filter narrows a reconciliation to the timelogs and line items of one
+// contractor or the jobs of one company. Payments not linked to a job match
+// no company.
+type Filter struct {
+	ContractorID *uuid.UUID
+	CompanyID    *uuid.UUID
+}
+
+// Report lists the discrepancies between the hours worked and the amounts
+// paid, by kind and then job, with totals per kind and currency.
+type Report struct {
+	GeneratedAt time.Time `json:"generatedAt"`
+	// Checked is the number of timelogs compared with their payments.
+	Checked int     `json:"checked"`
+	Issues  []Issue `json:"issues"`
+	Totals  []Total `json:"totals"`
+}
+
+// Issue is one discrepancy. For time, Expected is the hours at the rate of
+// the job version effective when the timelog started and Paid the sum of the
+// timelog's current line items; Difference is Paid less Expected.
+type Issue struct {
+	Kind         string      `json:"kind"`
+	ContractorID uuid.UUID   `json:"contractorId"`
+	JobID        *uuid.UUID  `json:"jobId,omitempty"`
+	JobTitle     string      `json:"jobTitle,omitempty"`
+	TimelogUID   *uuid.UUID  `json:"timelogUid,omitempty"`
+	LineItemUIDs []uuid.UUID `json:"lineItemUids,omitempty"`
+	Date         time.Time   `json:"date"`
+	Hours        float64     `json:"hours,omitempty"`
+	Rate         float64     `json:"rate,omitempty"`
+	Expected     float64     `json:"expected"`
+	Paid         float64     `json:"paid"`
+	Difference   float64     `json:"difference"`
+	Currency     string      `json:"currency"`
+}
+
+// Total sums the issues of one kind in one currency. Amount is the absolute
+// difference.
+type Total struct {
+	Kind     string  `json:"kind"`
+	Currency string  `json:"currency"`
+	Count    int     `json:"count"`
+	Amount   float64 `json:"amount"`
+}
+
+// lineItem is the current version of a payment line item with the ID of the
+// timelog it is linked to, if any.
+type lineItem struct {
+	UID          uuid.UUID
+	ContractorID uuid.UUID
+	TimelogID    *uuid.UUID
+	Amount       float64
+	IssuedAt     time.Time
+	Currency     string
+}
+
+// reconcile compares every timelog logged for a job with the line items
+// linked to it. Timelogs without a job are unlinked time, and line items
+// linked to none of the compared timelogs are orphaned; voided ones, with a
+// zero amount, are left out.
+func (rep *Report) reconcile(logs []timelog.Timelog, items []lineItem, rates jobs.RateHistory) {
+	byTimelog := map[uuid.UUID][]lineItem{}
+	for _, li := range items {
+		if li.Amount == 0 {
+			continue
+		}
+		if li.TimelogID != nil {
+			byTimelog[*li.TimelogID] = append(byTimelog[*li.TimelogID], li)
+		}
+	}
+
+	seen := map[uuid.UUID]bool{}
+	for _, t := range logs {
+		uid := t.UID
+		var job jobs.Job
+		ok := t.JobUID != nil
+		if ok {
+			job, ok = rates.At(*t.JobUID, t.StartTime)
+		}
+		if !ok {
+			rep.Issues = append(rep.Issues, Issue{
+				Kind:         KindUnlinkedTime,
+				ContractorID: t.ContractorID,
+				TimelogUID:   &uid,
+				Date:         t.StartTime,
+				Hours:        t.EndTime.Sub(t.StartTime).Hours(),
+			})
+			continue
+		}
+		seen[t.ID] = true
+		rep.Checked++
+		issue := Issue{
+			ContractorID: t.ContractorID,
+			JobID:        &job.ID,
+			JobTitle:     job.Title,
+			TimelogUID:   &uid,
+			Date:         t.StartTime,
+			Hours:        t.EndTime.Sub(t.StartTime).Hours(),
+			Rate:         job.Rate,
+			Currency:     job.Currency,
+		}
+		issue.Expected = cents(issue.Hours * issue.Rate)
+		for _, li := range byTimelog[t.ID] {
+			issue.Paid = cents(issue.Paid + li.Amount)
+			issue.LineItemUIDs = append(issue.LineItemUIDs, li.UID)
+		}
+		issue.Difference = cents(issue.Paid - issue.Expected)
+		switch {
+		case len(issue.LineItemUIDs) == 0 && issue.Expected > 0:
+			issue.Kind = KindUnpaidTime
+		case issue.Difference < 0:
+			issue.Kind = KindUnderpaid
+		case issue.Difference > 0:
+			issue.Kind = KindOverpaid
+		default:
+			continue
+		}
+		rep.Issues = append(rep.Issues, issue)
+	}
+
+	for _, li := range items {
+		if li.Amount == 0 || (li.TimelogID != nil && seen[*li.TimelogID]) {
+			continue
+		}
+		rep.Issues = append(rep.Issues, Issue{
+			Kind:         KindOrphanedPayment,
+			ContractorID: li.ContractorID,
+			LineItemUIDs: []uuid.UUID{li.UID},
+			Date:         li.IssuedAt,
+			Paid:         li.Amount,
+			Difference:   li.Amount,
+			Currency:     li.Currency,
+		})
+	}
+
+	sort.SliceStable(rep.Issues, func(i, j int) bool {
+		a, b := rep.Issues[i], rep.Issues[j]
+		if a.Kind != b.Kind {
+			return kindOrder[a.Kind] < kindOrder[b.Kind]
+		}
+		if a.JobTitle != b.JobTitle {
+			return a.JobTitle < b.JobTitle
+		}
+		return a.Date.Before(b.Date)
+	})
+	rep.total()
+}
+
+// total sums the issues per kind and currency.
+func (rep *Report) total() {
+	type key struct{ kind, currency string }
+	sums := map[key]*Total{}
+	rep.Totals = []Total{}
+	for _, is := range rep.Issues {
+		k := key{is.Kind, is.Currency}
+		t := sums[k]
+		if t == nil {
+			rep.Totals = append(rep.Totals, Total{Kind: is.Kind, Currency: is.Currency})
+			t = &rep.Totals[len(rep.Totals)-1]
+			sums[k] = t
+		}
+		t.Count++
+		t.Amount = cents(t.Amount + math.Abs(is.Difference))
+	}
+}
+
+func cents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,32 @@
+package reconciliation
+
+import (
+	"net/http"
+
+	"mercor/internal/openapi"
+)
+
+// Operations documents the routes registered by Handler.
+func Operations() []openapi.Operation {
+	id := openapi.Schema{"type": "string", "format": "uuid"}
+	return []openapi.Operation{
+		{
+			Method: http.MethodGet, Path: "/reconciliation", Tag: "reconciliation",
+			Summary:     "Reconcile hours worked with amounts paid",
+			Description: "Compares every current timelog, worth its hours at the rate of the job version effective when it started, with the current payment line items linked to it. Reports underpaid and overpaid time, unpaid time, unlinked time without a job and orphaned payments, line items not linked to a current timelog of a job, with totals per kind and currency. Voided line items are left out.",
+			Params: []openapi.Param{
+				{Name: "contractor_id", In: "query", Description: "Only the timelogs and line items of this contractor.", Schema: id},
+				{Name: "company_id", In: "query", Description: "Only the timelogs and line items of this company's jobs.", Schema: id},
+				{Name: "format", In: "query", Schema: openapi.Schema{"type": "string", "enum": []string{FormatJSON, FormatCSV}, "default": FormatJSON}},
+			},
+			Responses: openapi.Responses{
+				200: {Description: "The reconciliation report", Content: map[string]any{
+					"application/json": Report{},
+					"text/csv":         openapi.Schema{"type": "string", "format": "binary"},
+				}},
+				400: openapi.BadRequest,
+				500: openapi.ServerError,
+			},
+		},
+	}
+}
@@ -0,0 +1,93 @@
+package reconciliation
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// csvHeader names the columns of WriteCSV.
+var csvHeader = []string{
+	"kind", "date", "contractor_id", "job_id", "job_title", "timelog_uid", "line_item_uids",
+	"hours", "rate", "expected", "paid", "difference", "currency",
+}
+
+// WriteCSV writes one row per issue. The line item UIDs of an issue are
+// separated by spaces.
+func (r Report) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, is := range r.Issues {
+		row := []string{
+			is.Kind, is.Date.Format(time.RFC3339), is.ContractorID.String(), optional(is.JobID), is.JobTitle,
+			optional(is.TimelogUID), uids(is.LineItemUIDs), number(is.Hours), number(is.Rate),
+			money(is.Expected), money(is.Paid), money(is.Difference), is.Currency,
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteText writes the report for reading in a terminal: the totals per kind
+// and currency, then the issues.
+func (r Report) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(tw, "Reconciliation at %s: %d timelogs checked, %d issues\n\n", r.GeneratedAt.Format(time.RFC3339), r.Checked, len(r.Issues))
+	if len(r.Issues) == 0 {
+		return tw.Flush()
+	}
+	fmt.Fprintln(tw, "kind\tcurrency\tcount\tamount\t")
+	for _, t := range r.Totals {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t\n", t.Kind, t.Currency, t.Count, money(t.Amount))
+	}
+	fmt.Fprintln(tw)
+	fmt.Fprintln(tw, "kind\tdate\tjob\thours\texpected\tpaid\tdifference\tcurrency\tsource\t")
+	for _, is := range r.Issues {
+		source := optional(is.TimelogUID)
+		if is.Kind == KindOrphanedPayment {
+			source = uids(is.LineItemUIDs)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t\n",
+			is.Kind, is.Date.Format("2006-01-02"), is.JobTitle, number(is.Hours),
+			money(is.Expected), money(is.Paid), money(is.Difference), is.Currency, source)
+	}
+	return tw.Flush()
+}
+
+func optional(id *uuid.UUID) string {
+	if id == nil {
+		return ""
+	}
+	return id.String()
+}
+
+func uids(ids []uuid.UUID) string {
+	s := make([]string, len(ids))
+	for i, id := range ids {
+		s[i] = id.String()
+	}
+	return strings.Join(s, " ")
+}
+
+func money(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
+
+// number formats hours and rates, leaving zero values empty.
+func number(v float64) string {
+	if v == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
@@ -0,0 +1,70 @@
+package reconciliation
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"mercor/internal/domain/jobs"
+	payment "mercor/internal/domain/paymentLineItem"
+	"mercor/internal/domain/timelog"
+	"mercor/internal/scd"
+)
+
+// Repository reads the current versions a reconciliation compares.
+type Repository interface {
+	Timelogs(f Filter) ([]timelog.Timelog, error)
+	LineItems(f Filter) ([]lineItem, error)
+	Rates(jobUIDs []uuid.UUID) (jobs.RateHistory, error)
+}
+
+type repo struct {
+	jobs     jobs.Repository
+	timelogs *scd.SCDManager[timelog.Timelog]
+	payments *scd.SCDManager[payment.PaymentLineItem]
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repo{
+		jobs:     jobs.NewRepository(db),
+		timelogs: scd.NewManager[timelog.Timelog](db),
+		payments: scd.NewManager[payment.PaymentLineItem](db),
+	}
+}
+
+// Timelogs returns the current timelogs, of the company's jobs with a company
+// filter.
+func (r *repo) Timelogs(f Filter) ([]timelog.Timelog, error) {
+	q := r.timelogs.GetLatest()
+	if f.ContractorID != nil {
+		q = q.Where("main.contractor_id = ?", *f.ContractorID)
+	}
+	if f.CompanyID != nil {
+		q = q.Joins("JOIN jobs j ON j.uid = main.job_uid").Where("j.company_id = ?", *f.CompanyID)
+	}
+	var list []timelog.Timelog
+	err := q.Order("main.start_time").Find(&list).Error
+	return list, err
+}
+
+// LineItems returns the current payment line items with the ID of the
+// timelog they are linked to and the currency of its job.
+func (r *repo) LineItems(f Filter) ([]lineItem, error) {
+	q := r.payments.GetLatest().
+		Select("main.uid, main.contractor_id, t.id AS timelog_id, main.amount, main.issued_at, COALESCE(j.currency, '') AS currency").
+		Joins("LEFT JOIN timelogs t ON t.uid = main.timelog_uid").
+		Joins("LEFT JOIN jobs j ON j.uid = t.job_uid")
+	if f.ContractorID != nil {
+		q = q.Where("main.contractor_id = ?", *f.ContractorID)
+	}
+	if f.CompanyID != nil {
+		q = q.Where("j.company_id = ?", *f.CompanyID)
+	}
+	var list []lineItem
+	err := q.Order("main.issued_at").Scan(&list).Error
+	return list, err
+}
+
+// Rates returns the rate history of the jobs the given job versions belong
+// to.
+func (r *repo) Rates(jobUIDs []uuid.UUID) (jobs.RateHistory, error) {
+	return r.jobs.RateHistory(jobUIDs, nil)
+}
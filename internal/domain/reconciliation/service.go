@@ -0,0 +1,46 @@
+package reconciliation
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Service interface {
+	Reconcile(f Filter) (Report, error)
+}
+
+type service struct {
+	repo Repository
+}
+
+func NewService(r Repository) Service {
+	return &service{repo: r}
+}
+
+// Reconcile compares the current timelogs with the current payment line
+// items. Time is worth its hours at the rate of the job version effective
+// when it started.
+func (s *service) Reconcile(f Filter) (Report, error) {
+	rep := Report{GeneratedAt: time.Now().UTC(), Issues: []Issue{}}
+	logs, err := s.repo.Timelogs(f)
+	if err != nil {
+		return rep, err
+	}
+	items, err := s.repo.LineItems(f)
+	if err != nil {
+		return rep, err
+	}
+	var jobUIDs []uuid.UUID
+	for _, t := range logs {
+		if t.JobUID != nil {
+			jobUIDs = append(jobUIDs, *t.JobUID)
+		}
+	}
+	rates, err := s.repo.Rates(jobUIDs)
+	if err != nil {
+		return rep, err
+	}
+	rep.reconcile(logs, items, rates)
+	return rep, nil
+}
@@ -0,0 +1,103 @@
+package retention
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"mercor/internal/scd"
+	"mercor/internal/validation"
+)
+
+type Handler struct {
+	db       *gorm.DB
+	policies Policies
+}
+
+// NewHandler compacts under the given policies, the ones the background job
+// uses.
+func NewHandler(db *gorm.DB, p Policies) *Handler {
+	return &Handler{db: db, policies: p}
+}
+
+// RegisterRoutes registers the retention routes under /admin, which is meant
+// for operators rather than clients.
+func (h *Handler) RegisterRoutes(r *gin.Engine) {
+	r.POST("/admin/compact", h.Compact)
+	r.GET("/admin/legal-holds", h.ListHolds)
+	r.POST("/admin/legal-holds", h.PlaceHold)
+	r.DELETE("/admin/legal-holds/:id", h.ReleaseHold)
+}
+
+// StatusCode maps the errors of retention to HTTP statuses.
+func StatusCode(err error) int {
+	if errors.Is(err, ErrAlreadyHeld) {
+		return http.StatusConflict
+	}
+	return scd.StatusCode(err)
+}
+
+// Compact compacts the entities named by ?entity=, a comma separated list, or
+// every entity, archiving the removed versions to the archive table. With
+// ?dry_run=true it only reports what would be removed.
+func (h *Handler) Compact(c *gin.Context) {
+	var entities []string
+	if raw := c.Query("entity"); raw != "" {
+		entities = strings.Split(raw, ",")
+		for _, name := range entities {
+			if _, ok := Entities[name]; !ok {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "unknown entity " + name})
+				return
+			}
+		}
+	}
+	rep, err := Run(h.db, h.policies, entities, scd.CompactOptions{DryRun: c.Query("dry_run") == "true"})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, rep)
+}
+
+func (h *Handler) ListHolds(c *gin.Context) {
+	list, err := Holds(h.db)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, list)
+}
+
+func (h *Handler) PlaceHold(c *gin.Context) {
+	var req HoldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, validation.Response(err))
+		return
+	}
+	if _, ok := Tables[req.Entity]; !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unknown entity " + req.Entity})
+		return
+	}
+	hold, err := PlaceHold(h.db, req)
+	if err != nil {
+		c.JSON(StatusCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, hold)
+}
+
+func (h *Handler) ReleaseHold(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid legal hold ID"})
+		return
+	}
+	if err := ReleaseHold(h.db, id); err != nil {
+		c.JSON(StatusCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,74 @@
+package retention
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"mercor/internal/scd"
+)
+
+// ErrAlreadyHeld is returned when placing a legal hold on an entity that is
+// already under one.
+var ErrAlreadyHeld = errors.New("entity is already under a legal hold")
+
+// HoldRequest is the body of a new legal hold.
+type HoldRequest struct {
+	Entity   string    `json:"entity" binding:"required"`
+	EntityID uuid.UUID `json:"entityId" binding:"required"`
+	Reason   string    `json:"reason" binding:"required"`
+}
+
+// Holds returns every legal hold, newest first.
+func Holds(db *gorm.DB) ([]scd.LegalHold, error) {
+	list := []scd.LegalHold{}
+	err := db.Order("created_at DESC").Find(&list).Error
+	return list, err
+}
+
+// PlaceHold keeps the entity from being compacted until the hold is
+// released. The entity has to be on record.
+func PlaceHold(db *gorm.DB, req HoldRequest) (scd.LegalHold, error) {
+	hold := scd.LegalHold{ID: uuid.New(), EntityTable: Tables[req.Entity], EntityID: req.EntityID, Reason: req.Reason}
+	if hold.EntityTable == "" {
+		return hold, fmt.Errorf("entity %q: %w", req.Entity, scd.ErrUnknownReference)
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		// Locking the versions waits for a compaction of the entity under
+		// way, and makes the next one see the hold.
+		var uids []string
+		err := tx.Table(hold.EntityTable).
+			Clauses(clause.Locking{Strength: "SHARE"}).
+			Where("id = ?", req.EntityID).
+			Pluck("uid", &uids).Error
+		if err != nil {
+			return err
+		}
+		if len(uids) == 0 {
+			return fmt.Errorf("%s %v: %w", req.Entity, req.EntityID, scd.ErrUnknownReference)
+		}
+		var count int64
+		err = tx.Model(&scd.LegalHold{}).
+			Where("entity_table = ? AND entity_id = ?", hold.EntityTable, hold.EntityID).
+			Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrAlreadyHeld
+		}
+		return tx.Create(&hold).Error
+	})
+	return hold, err
+}
+
+// ReleaseHold removes the legal hold with the given ID.
+func ReleaseHold(db *gorm.DB, id uuid.UUID) error {
+	res := db.Where("id = ?", id).Delete(&scd.LegalHold{})
+	if res.Error == nil && res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return res.Error
+}
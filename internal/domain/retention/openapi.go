@@ -0,0 +1,59 @@
+package retention
+
+import (
+	"net/http"
+	"strings"
+
+	"mercor/internal/openapi"
+	"mercor/internal/scd"
+)
+
+// Operations documents the routes registered by Handler.
+func Operations() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method: http.MethodPost, Path: "/admin/compact", Tag: "admin",
+			Summary:     "Compact old versions under the retention policies",
+			Description: "Collapses the versions on record whose effective period ended longer ago than the retention of their entity into one snapshot per calendar month, the last version of the month. Versions that other rows link to are kept, and entities under a legal hold are skipped. Removed versions are archived to the archive table.",
+			Params: []openapi.Param{
+				{Name: "entity", In: "query", Description: "Comma separated entities to compact, of " + strings.Join(Names(), ", ") + "; all by default.", Schema: openapi.Schema{"type": "string"}},
+				{Name: "dry_run", In: "query", Description: "Only report what would be removed.", Schema: openapi.Schema{"type": "boolean"}},
+			},
+			Responses: openapi.Responses{
+				200: openapi.JSON("What was removed per entity", Report{}),
+				400: openapi.BadRequest,
+				500: openapi.ServerError,
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/admin/legal-holds", Tag: "admin",
+			Summary: "List the legal holds, newest first",
+			Responses: openapi.Responses{
+				200: openapi.JSON("The legal holds", []scd.LegalHold{}),
+				500: openapi.ServerError,
+			},
+		},
+		{
+			Method: http.MethodPost, Path: "/admin/legal-holds", Tag: "admin",
+			Summary:     "Place a legal hold on an entity",
+			Description: "Keeps every version of the entity from being compacted until the hold is released.",
+			Body:        openapi.Body(HoldRequest{}),
+			Responses: openapi.Responses{
+				201: openapi.JSON("The legal hold", scd.LegalHold{}),
+				400: openapi.Invalid,
+				409: openapi.JSON("The entity is already under a legal hold", openapi.Error{}),
+				422: openapi.JSON("The entity does not exist", openapi.Error{}),
+				500: openapi.ServerError,
+			},
+		},
+		{
+			Method: http.MethodDelete, Path: "/admin/legal-holds/:id", Tag: "admin",
+			Summary: "Release a legal hold",
+			Responses: openapi.Responses{
+				204: openapi.Empty("Released"),
+				400: openapi.BadRequest,
+				404: openapi.NotFound,
+			},
+		},
+	}
+}
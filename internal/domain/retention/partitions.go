@@ -0,0 +1,60 @@
+package retention
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"mercor/internal/domain/jobs"
+	payment "mercor/internal/domain/paymentLineItem"
+	"mercor/internal/domain/timelog"
+	"mercor/internal/scd"
+)
+
+// Partitioner splits one entity's versioned table into current and history
+// partitions or, with split unset, adds the history partitions it is missing
+// once split.
+type Partitioner func(db *gorm.DB, split bool, through time.Time) (scd.Partitioning, error)
+
+func partition[T scd.SCDModel[T]](db *gorm.DB, split bool, through time.Time) (scd.Partitioning, error) {
+	m := scd.NewManager[T](db)
+	if split {
+		return m.Split(through)
+	}
+	return m.AddPartitions(through)
+}
+
+// Partitioners maps the entity names to their partitioners.
+var Partitioners = map[string]Partitioner{
+	"jobs":               partition[jobs.Job],
+	"timelogs":           partition[timelog.Timelog],
+	"payment-line-items": partition[payment.PaymentLineItem],
+}
+
+// PartitionsAhead is how many months of history partitions are kept ready
+// beyond the current one.
+const PartitionsAhead = 3
+
+// Partition splits the tables of the given entities, every one if none are
+// given, or, with split unset, adds the partitions the split ones are missing
+// through PartitionsAhead months from now. Tables that are not split are left
+// alone then, so it can run on every start.
+func Partition(db *gorm.DB, entities []string, split bool) ([]scd.Partitioning, error) {
+	if len(entities) == 0 {
+		entities = Names()
+	}
+	through := time.Now().AddDate(0, PartitionsAhead, 0)
+	out := []scd.Partitioning{}
+	for _, name := range entities {
+		partition, ok := Partitioners[name]
+		if !ok {
+			return out, fmt.Errorf("unknown entity %q, must be one of %v", name, Names())
+		}
+		p, err := partition(db, split, through)
+		if err != nil {
+			return out, fmt.Errorf("%s: %w", name, err)
+		}
+		out = append(out, p)
+	}
+	return out, nil
+}
@@ -0,0 +1,168 @@
+package retention
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"mercor/internal/domain/invoices"
+	"mercor/internal/domain/jobs"
+	payment "mercor/internal/domain/paymentLineItem"
+	"mercor/internal/domain/timelog"
+	"mercor/internal/scd"
+)
+
+// Compactor compacts one entity's versioned table.
+type Compactor func(db *gorm.DB, opts scd.CompactOptions) (scd.Compaction, error)
+
+func compact[T scd.SCDModel[T]](db *gorm.DB, opts scd.CompactOptions) (scd.Compaction, error) {
+	return scd.NewManager[T](db).Compact(opts)
+}
+
+// Entities maps the compacted entity names, as exported, to their
+// compactors.
+var Entities = map[string]Compactor{
+	"jobs":               compact[jobs.Job],
+	"timelogs":           compact[timelog.Timelog],
+	"payment-line-items": compact[payment.PaymentLineItem],
+}
+
+// Tables maps the entity names to their tables, which legal holds name.
+var Tables = map[string]string{
+	"jobs":               jobs.Job{}.TableName(),
+	"timelogs":           timelog.Timelog{}.TableName(),
+	"payment-line-items": payment.PaymentLineItem{}.TableName(),
+}
+
+// Pins lists, per entity name, the columns of other tables that name its
+// versions by uid besides its dependents. Compaction keeps the versions they
+// name, so every table storing a version uid has to be listed here.
+var Pins = map[string][]scd.Pin{
+	"jobs": {
+		{Table: invoices.Line{}.TableName(), Column: "job_uid"},
+		// Statements and reconciliation price a timelog at the rate of the
+		// job version effective when the work started.
+		{Table: timelog.Timelog{}.TableName(), Column: "job_uid", At: "start_time"},
+	},
+	"payment-line-items": {
+		{Table: invoices.Line{}.TableName(), Column: "line_item_uid"},
+	},
+}
+
+// Policies maps entity names to their retention. Entities without a policy
+// keep every version.
+type Policies map[string]scd.Retention
+
+const year = 365 * 24 * time.Hour
+
+// DefaultPolicies collapse job versions that ended more than three years
+// ago into monthly snapshots. Timelogs and payment line items, which are
+// paid out from, keep every version.
+var DefaultPolicies = Policies{
+	"jobs": {After: 3 * year},
+}
+
+// ParsePolicies parses entity=age pairs separated by commas, such as
+// "jobs=3y,timelogs=keep". An age is a number of years (y) or days (d), a Go
+// duration, or keep to keep every version.
+func ParsePolicies(s string) (Policies, error) {
+	out := Policies{}
+	for _, pair := range strings.Split(s, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		name, age, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if _, known := Entities[name]; !ok || !known {
+			return nil, fmt.Errorf("invalid policy %q, want entity=age with entity one of %v", pair, Names())
+		}
+		after, err := parseAge(age)
+		if err != nil {
+			return nil, fmt.Errorf("invalid age in policy %q: %w", pair, err)
+		}
+		out[name] = scd.Retention{After: after}
+	}
+	return out, nil
+}
+
+func parseAge(s string) (time.Duration, error) {
+	if s == "keep" {
+		return 0, nil
+	}
+	for suffix, unit := range map[string]time.Duration{"y": year, "d": 24 * time.Hour} {
+		if n, ok := strings.CutSuffix(s, suffix); ok {
+			v, err := strconv.Atoi(n)
+			if err != nil || v <= 0 {
+				return 0, fmt.Errorf("%q is not a positive number of %s", s, suffix)
+			}
+			return time.Duration(v) * unit, nil
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err == nil && d <= 0 {
+		err = fmt.Errorf("%q is not positive", s)
+	}
+	return d, err
+}
+
+// String formats the policies as ParsePolicies reads them.
+func (p Policies) String() string {
+	var pairs []string
+	for _, name := range Names() {
+		r, ok := p[name]
+		if !ok || r.KeepAll() {
+			pairs = append(pairs, name+"=keep")
+			continue
+		}
+		if r.After%year == 0 {
+			pairs = append(pairs, fmt.Sprintf("%s=%dy", name, r.After/year))
+		} else {
+			pairs = append(pairs, name+"="+r.After.String())
+		}
+	}
+	return strings.Join(pairs, ",")
+}
+
+// Names returns the entity names in order.
+func Names() []string {
+	names := make([]string, 0, len(Entities))
+	for name := range Entities {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Report lists the compaction of each entity with a retention policy.
+type Report struct {
+	RanAt       time.Time        `json:"ranAt"`
+	DryRun      bool             `json:"dryRun"`
+	Compactions []scd.Compaction `json:"compactions"`
+}
+
+// Run compacts the given entities, every one if none are given, under the
+// policies. opts carries everything but the retention.
+func Run(db *gorm.DB, policies Policies, entities []string, opts scd.CompactOptions) (Report, error) {
+	if len(entities) == 0 {
+		entities = Names()
+	}
+	rep := Report{RanAt: time.Now().UTC(), DryRun: opts.DryRun, Compactions: []scd.Compaction{}}
+	for _, name := range entities {
+		compact, ok := Entities[name]
+		if !ok {
+			return rep, fmt.Errorf("unknown entity %q, must be one of %v", name, Names())
+		}
+		opts.Retention, opts.Pins = policies[name], Pins[name]
+		if opts.Retention.KeepAll() {
+			continue
+		}
+		c, err := compact(db, opts)
+		if err != nil {
+			return rep, fmt.Errorf("%s: %w", name, err)
+		}
+		rep.Compactions = append(rep.Compactions, c)
+	}
+	return rep, nil
+}
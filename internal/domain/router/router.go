@@ -1,25 +1,185 @@
 package router
 
 import (
+	"log"
+	"net/http"
+
 	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"gorm.io/gorm"
+	scdv1 "mercor/api/scd/v1"
 	"mercor/internal/db"
+	"mercor/internal/domain/companies"
+	"mercor/internal/domain/contractors"
+	"mercor/internal/domain/exports"
+	"mercor/internal/domain/graph"
+	"mercor/internal/domain/imports"
+	"mercor/internal/domain/invoices"
 	job "mercor/internal/domain/jobs"
 	timelog "mercor/internal/domain/timelog"
 	payment "mercor/internal/domain/paymentLineItem"
+	"mercor/internal/domain/reconciliation"
+	"mercor/internal/domain/retention"
+	"mercor/internal/domain/statements"
+	"mercor/internal/domain/stream"
+	"mercor/internal/domain/verify"
+	"mercor/internal/events"
+	"mercor/internal/idempotency"
+	"mercor/internal/openapi"
 )
 
-func InitRoutes(r *gin.Engine) {
+// streamBacklog is the number of recent events kept for Last-Event-ID resume.
+const streamBacklog = 1000
+
+var apiInfo = openapi.Info{
+	Title:       "SCD Backend API",
+	Version:     "1.0.0",
+	Description: "Companies, contractors, jobs, timelogs, payment line items and invoices stored as SCD Type 2 versions, with contractor statements and reconciliation of hours worked with amounts paid.",
+}
+
+// Services are the domain services shared by the HTTP and gRPC transports.
+type Services struct {
+	DB             *gorm.DB
+	Broker         *events.Broker
+	Companies      companies.Service
+	Contractors    contractors.Service
+	Jobs           job.Service
+	Timelogs       timelog.Service
+	Payments       payment.Service
+	Invoices       invoices.Service
+	Statements     statements.Service
+	Reconciliation reconciliation.Service
+	Imports        imports.Service
+	// Retention is applied by compaction, on request and in the background.
+	Retention retention.Policies
+}
+
+// NewServices connects to the database and builds the domain services.
+func NewServices() *Services {
 	database := db.Connect()
 
+	broker := events.NewBroker(streamBacklog)
+	if err := events.Attach(database, broker); err != nil {
+		log.Fatalf("failed to attach event publisher: %v", err)
+	}
+
+	s := &Services{
+		DB:             database,
+		Broker:         broker,
+		Companies:      companies.NewService(companies.NewRepository(database)),
+		Contractors:    contractors.NewService(contractors.NewRepository(database)),
+		Jobs:           job.NewService(job.NewRepository(database)),
+		Timelogs:       timelog.NewService(timelog.NewRepository(database)),
+		Payments:       payment.NewService(payment.NewRepository(database)),
+		Invoices:       invoices.NewService(invoices.NewRepository(database)),
+		Statements:     statements.NewService(statements.NewRepository(database)),
+		Reconciliation: reconciliation.NewService(reconciliation.NewRepository(database)),
+		Retention:      retention.DefaultPolicies,
+	}
+	s.Imports = imports.NewService(imports.NewRepository(database), s.Timelogs, s.Contractors.Exists)
+	return s
+}
+
+func InitRoutes(r *gin.Engine) {
+	RegisterRoutes(r, NewServices())
+}
+
+// RegisterRoutes registers the HTTP API on r.
+func RegisterRoutes(r *gin.Engine, s *Services) {
+	r.Use(idempotency.Middleware(s.DB))
+
+	// COMPANY
+	companyHandler := companies.NewHandler(s.Companies)
+	companyHandler.RegisterRoutes(r)
+
+	// CONTRACTOR
+	contractorHandler := contractors.NewHandler(s.Contractors)
+	contractorHandler.RegisterRoutes(r)
+
 	// JOB
-	jobHandler := job.NewHandler(job.NewService(job.NewRepository(database)))
+	jobHandler := job.NewHandler(s.Jobs)
 	jobHandler.RegisterRoutes(r)
 
 	// TIMELOG
-	tlHandler := timelog.NewHandler(timelog.NewService(timelog.NewRepository(database)))
-	tlHandler.RegisterRoutes(r)
+	timelogHandler := timelog.NewHandler(s.Timelogs)
+	timelogHandler.RegisterRoutes(r)
 
 	// PAYMENT
-	plHandler := payment.NewHandler(payment.NewService(payment.NewRepository(database)))
-	plHandler.RegisterRoutes(r)
+	paymentHandler := payment.NewHandler(s.Payments)
+	paymentHandler.RegisterRoutes(r)
+
+	// BATCH
+	r.NoRoute(colonRoutes(http.MethodPost, map[string]gin.HandlerFunc{
+		"/companies:batch":          companyHandler.Batch,
+		"/contractors:batch":        contractorHandler.Batch,
+		"/jobs:batch":               jobHandler.Batch,
+		"/timelogs:batch":           timelogHandler.Batch,
+		"/payment-line-items:batch": paymentHandler.Batch,
+	}))
+
+	// INVOICES
+	invoices.NewHandler(s.Invoices).RegisterRoutes(r)
+
+	// STATEMENTS
+	statements.NewHandler(s.Statements).RegisterRoutes(r)
+
+	// RECONCILIATION
+	reconciliation.NewHandler(s.Reconciliation).RegisterRoutes(r)
+
+	// IMPORTS
+	imports.NewHandler(s.Imports).RegisterRoutes(r)
+
+	// EXPORTS
+	exports.NewHandler(s.DB).RegisterRoutes(r)
+
+	// VERIFY
+	verify.NewHandler(s.DB).RegisterRoutes(r)
+
+	// RETENTION
+	retention.NewHandler(s.DB, s.Retention).RegisterRoutes(r)
+
+	// STREAM
+	stream.NewHandler(s.Broker).RegisterRoutes(r)
+
+	// GRAPHQL
+	graph.NewHandler(job.NewRepository(s.DB), timelog.NewRepository(s.DB), payment.NewRepository(s.DB)).RegisterRoutes(r)
+
+	// DOCS
+	openapi.NewHandler(apiInfo,
+		companies.Operations(),
+		contractors.Operations(),
+		job.Operations(),
+		timelog.Operations(),
+		payment.Operations(),
+		invoices.Operations(),
+		statements.Operations(),
+		reconciliation.Operations(),
+		imports.Operations(),
+		exports.Operations(),
+		verify.Operations(),
+		retention.Operations(),
+		stream.Operations(),
+		graph.Operations(),
+	).RegisterRoutes(r)
+}
+
+// colonRoutes serves paths with a custom method after a colon, such as
+// /timelogs:batch, which Gin cannot register since a colon starts a
+// parameter there. Installed as the NoRoute handler, it runs after the global
+// middleware like any route; other paths keep the 404.
+func colonRoutes(method string, routes map[string]gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if h, ok := routes[c.Request.URL.Path]; ok && c.Request.Method == method {
+			h(c)
+		}
+	}
+}
+
+// RegisterGRPC registers the gRPC API on srv. It offers the operations of the
+// entity handlers and the change stream, backed by the same services.
+func RegisterGRPC(srv *grpc.Server, s *Services) {
+	scdv1.RegisterJobServiceServer(srv, job.NewGRPCServer(s.Jobs))
+	scdv1.RegisterTimelogServiceServer(srv, timelog.NewGRPCServer(s.Timelogs))
+	scdv1.RegisterPaymentLineItemServiceServer(srv, payment.NewGRPCServer(s.Payments))
+	scdv1.RegisterEventServiceServer(srv, stream.NewGRPCServer(s.Broker))
 }
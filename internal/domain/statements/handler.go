@@ -0,0 +1,93 @@
+package statements
+
+import (
+	"bytes"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"mercor/internal/scd"
+)
+
+// Formats a statement is returned in, chosen with ?format=.
+const (
+	FormatJSON = "json"
+	FormatCSV  = "csv"
+	FormatPDF  = "pdf"
+)
+
+var contentTypes = map[string]string{
+	FormatCSV: "text/csv",
+	FormatPDF: "application/pdf",
+}
+
+type Handler struct {
+	svc Service
+}
+
+func NewHandler(s Service) *Handler {
+	return &Handler{svc: s}
+}
+
+// RegisterRoutes registers the statement route. Like the other per-contractor
+// lists, :id holds the contractor ID.
+func (h *Handler) RegisterRoutes(r *gin.Engine) {
+	r.GET("/contractors/:id/statements", h.Get)
+}
+
+// Get returns the statement for ?from= up to ?to=, RFC 3339 times. With
+// ?as_of= and ?known_at= it is produced from the versions effective and on
+// record then, like the reads of single versions; ?known_at=<issue date>
+// reproduces a statement as it was issued. ?format= is json (the default),
+// csv or pdf.
+func (h *Handler) Get(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid contractor ID"})
+		return
+	}
+	from, err := time.Parse(time.RFC3339, c.Query("from"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from must be an RFC 3339 time"})
+		return
+	}
+	to, err := time.Parse(time.RFC3339, c.Query("to"))
+	if err != nil || !to.After(from) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to must be an RFC 3339 time after from"})
+		return
+	}
+	format := c.DefaultQuery("format", FormatJSON)
+	if format != FormatJSON && contentTypes[format] == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be json, csv or pdf"})
+		return
+	}
+	tt, err := scd.ParseTimeTravel(c.Query("as_of"), c.Query("known_at"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	st, err := h.svc.Statement(id, from, to, tt)
+	if err != nil {
+		c.JSON(scd.StatusCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	var buf bytes.Buffer
+	switch format {
+	case FormatJSON:
+		c.JSON(http.StatusOK, st)
+		return
+	case FormatCSV:
+		err = st.WriteCSV(&buf)
+	case FormatPDF:
+		_, err = st.PDF().WriteTo(&buf)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	name := "statement-" + id.String() + "-" + from.Format(dateLayout) + "." + format
+	c.Header("Content-Disposition", `attachment; filename="`+name+`"`)
+	c.Data(http.StatusOK, contentTypes[format], buf.Bytes())
+}
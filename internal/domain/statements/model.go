@@ -0,0 +1,173 @@
+package statements
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"mercor/internal/domain/jobs"
+	"mercor/internal/domain/timelog"
+)
+
+// Kinds of statement entries.
+const (
+	KindWork    = "work"
+	KindPayment = "payment"
+)
+
+// States of the payment line items on a statement. Line items billed on a
+// paid invoice are paid and count against the balance; the others are issued
+// but not yet paid, whether approved or pending; line items deleted down to a
+// zero amount are voided.
+const (
+	StateIssued = "issued"
+	StatePaid   = "paid"
+	StateVoided = "voided"
+)
+
+// Statement lists what a contractor earned and was paid in a period, as
+// recorded at KnownAt and effective at AsOf; nil times mean the current
+// state. Balances are kept per currency.
+type Statement struct {
+	ContractorID   uuid.UUID  `json:"contractorId"`
+	ContractorName string     `json:"contractorName"`
+	From           time.Time  `json:"from"`
+	To             time.Time  `json:"to"`
+	AsOf           *time.Time `json:"asOf,omitempty"`
+	KnownAt        *time.Time `json:"knownAt,omitempty"`
+	Entries        []Entry    `json:"entries"`
+	Summary        []Summary  `json:"summary"`
+}
+
+// Entry is a timelog or a payment line item. Balance is the running balance
+// in its currency after the entry.
+type Entry struct {
+	Date        time.Time `json:"date"`
+	Kind        string    `json:"kind"`
+	UID         uuid.UUID `json:"uid"`
+	Description string    `json:"description"`
+	Hours       float64   `json:"hours,omitempty"`
+	Rate        float64   `json:"rate,omitempty"`
+	Status      string    `json:"status,omitempty"`
+	Amount      float64   `json:"amount"`
+	Currency    string    `json:"currency"`
+	Balance     float64   `json:"balance"`
+}
+
+// Summary is the movement of the balance in one currency: Closing is Opening
+// plus Earned less Paid. Issued is the amount of line items not yet paid.
+type Summary struct {
+	Currency string  `json:"currency"`
+	Opening  float64 `json:"opening"`
+	Earned   float64 `json:"earned"`
+	Paid     float64 `json:"paid"`
+	Issued   float64 `json:"issued"`
+	Closing  float64 `json:"closing"`
+}
+
+// lineItem is a payment line item with the currency of the job it pays for,
+// empty for line items without a timelog. Paid is set when an invoice billing
+// it is paid.
+type lineItem struct {
+	UID      uuid.UUID
+	Amount   float64
+	IssuedAt time.Time
+	Currency string
+	Paid     bool
+}
+
+// build fills in the entries and summary of s. Timelogs and line items dated
+// before s.From make up the opening balances.
+func (s *Statement) build(logs []timelog.Timelog, items []lineItem, rates jobs.RateHistory) {
+	var all []Entry
+	for _, t := range logs {
+		e := Entry{
+			Date:        t.StartTime,
+			Kind:        KindWork,
+			UID:         t.UID,
+			Description: "Unassigned time",
+			Hours:       t.EndTime.Sub(t.StartTime).Hours(),
+		}
+		if t.JobUID != nil {
+			if job, ok := rates.At(*t.JobUID, t.StartTime); ok {
+				e.Description = job.Title
+				e.Rate = job.Rate
+				e.Currency = job.Currency
+			}
+		}
+		e.Amount = cents(e.Hours * e.Rate)
+		all = append(all, e)
+	}
+	for _, li := range items {
+		e := Entry{
+			Date:        li.IssuedAt,
+			Kind:        KindPayment,
+			UID:         li.UID,
+			Description: "Payment",
+			Status:      state(li),
+			Amount:      li.Amount,
+			Currency:    li.Currency,
+		}
+		all = append(all, e)
+	}
+	sort.SliceStable(all, func(i, j int) bool {
+		if !all[i].Date.Equal(all[j].Date) {
+			return all[i].Date.Before(all[j].Date)
+		}
+		return all[i].Kind > all[j].Kind // work before payments
+	})
+
+	sums := map[string]*Summary{}
+	s.Entries = []Entry{}
+	for _, e := range all {
+		sum := sums[e.Currency]
+		if sum == nil {
+			sum = &Summary{Currency: e.Currency}
+			sums[e.Currency] = sum
+		}
+		delta := 0.0
+		switch {
+		case e.Kind == KindWork:
+			delta = e.Amount
+		case e.Status == StatePaid:
+			delta = -e.Amount
+		}
+		if e.Date.Before(s.From) {
+			sum.Opening = cents(sum.Opening + delta)
+			sum.Closing = sum.Opening
+			continue
+		}
+		switch {
+		case e.Kind == KindWork:
+			sum.Earned = cents(sum.Earned + e.Amount)
+		case e.Status == StatePaid:
+			sum.Paid = cents(sum.Paid + e.Amount)
+		case e.Status == StateIssued:
+			sum.Issued = cents(sum.Issued + e.Amount)
+		}
+		sum.Closing = cents(sum.Closing + delta)
+		e.Balance = sum.Closing
+		s.Entries = append(s.Entries, e)
+	}
+	s.Summary = make([]Summary, 0, len(sums))
+	for _, sum := range sums {
+		s.Summary = append(s.Summary, *sum)
+	}
+	sort.Slice(s.Summary, func(i, j int) bool { return s.Summary[i].Currency < s.Summary[j].Currency })
+}
+
+// state tells how a line item counts on a statement.
+func state(li lineItem) string {
+	switch {
+	case li.Amount == 0:
+		return StateVoided
+	case li.Paid:
+		return StatePaid
+	}
+	return StateIssued
+}
+
+func cents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,38 @@
+package statements
+
+import (
+	"net/http"
+
+	"mercor/internal/openapi"
+)
+
+// Operations documents the routes registered by Handler.
+func Operations() []openapi.Operation {
+	file := openapi.Schema{"type": "string", "format": "binary"}
+	at := openapi.Schema{"type": "string", "format": "date-time"}
+	return []openapi.Operation{
+		{
+			Method: http.MethodGet, Path: "/contractors/:id/statements", Tag: "statements",
+			Summary:     "Get a contractor's payout statement for a period",
+			Description: "Lists the current timelogs, paid at the rate of the job version effective when they started, and the payment line items of the period with running balances per currency. Earlier entries make up the opening balances. Line items on a paid invoice count as paid, the others as issued and zero ones as voided.",
+			Params: []openapi.Param{
+				{Name: "id", In: "path", Required: true, Description: "ID of the contractor.", Schema: openapi.Schema{"type": "string", "format": "uuid"}},
+				{Name: "from", In: "query", Required: true, Description: "Start of the period.", Schema: at},
+				{Name: "to", In: "query", Required: true, Description: "End of the period, excluded.", Schema: at},
+				{Name: "format", In: "query", Schema: openapi.Schema{"type": "string", "enum": []string{FormatJSON, FormatCSV, FormatPDF}, "default": FormatJSON}},
+				{Name: "as_of", In: "query", Description: "Use the versions effective at this RFC 3339 time.", Schema: at},
+				{Name: "known_at", In: "query", Description: "Use the versions on record at this RFC 3339 time, e.g. the issue date of a statement to reproduce it.", Schema: at},
+			},
+			Responses: openapi.Responses{
+				200: {Description: "The statement", Content: map[string]any{
+					"application/json":      Statement{},
+					contentTypes[FormatCSV]: file,
+					contentTypes[FormatPDF]: file,
+				}},
+				400: openapi.BadRequest,
+				404: openapi.NotFound,
+				500: openapi.ServerError,
+			},
+		},
+	}
+}
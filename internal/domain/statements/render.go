@@ -0,0 +1,112 @@
+package statements
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"mercor/internal/pdf"
+)
+
+const dateLayout = "2006-01-02"
+
+// csvHeader names the columns of WriteCSV.
+var csvHeader = []string{"date", "kind", "uid", "description", "hours", "rate", "status", "amount", "currency", "balance"}
+
+// WriteCSV writes one row per entry. The opening balances are rows of kind
+// "opening" dated at the start of the period.
+func (s Statement) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, sum := range s.Summary {
+		row := []string{s.From.Format(time.RFC3339), "opening", "", "Opening balance", "", "", "", "", sum.Currency, money(sum.Opening)}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	for _, e := range s.Entries {
+		row := []string{
+			e.Date.Format(time.RFC3339), e.Kind, e.UID.String(), e.Description,
+			number(e.Hours), number(e.Rate), e.Status, money(e.Amount), e.Currency, money(e.Balance),
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// PDF lays out the statement: the period, the entries with their running
+// balances and the summary per currency.
+func (s Statement) PDF() *pdf.Document {
+	d := &pdf.Document{Title: "Statement " + s.ContractorName}
+	d.Heading("STATEMENT")
+	d.Add("")
+	d.Add(s.ContractorName)
+	d.Addf("Period:  %s to %s", s.From.Format(dateLayout), s.To.Format(dateLayout))
+	if s.KnownAt != nil {
+		d.Addf("As recorded at %s", s.KnownAt.Format(time.RFC3339))
+	} else if s.AsOf != nil {
+		d.Addf("As of %s", s.AsOf.Format(time.RFC3339))
+	}
+	d.Add("")
+	d.Heading(row("Date", "Description", "Hours", "Status", "Amount", "Balance", ""))
+	for _, e := range s.Entries {
+		d.Add(row(e.Date.Format(dateLayout), e.Description, hours(e), e.Status, money(e.Amount), money(e.Balance), e.Currency))
+	}
+	for _, sum := range s.Summary {
+		d.Add("")
+		d.Heading("Summary " + sum.Currency)
+		d.Addf("Opening balance  %12s", money(sum.Opening))
+		d.Addf("Earned           %12s", money(sum.Earned))
+		d.Addf("Paid             %12s", money(sum.Paid))
+		d.Addf("Closing balance  %12s", money(sum.Closing))
+		d.Addf("Issued, unpaid   %12s", money(sum.Issued))
+	}
+	return d
+}
+
+func hours(e Entry) string {
+	if e.Kind != KindWork {
+		return ""
+	}
+	return strconv.FormatFloat(e.Hours, 'f', 2, 64)
+}
+
+// row formats the columns of an entry; long descriptions are cut.
+func row(date, description, hours, status, amount, balance, currency string) string {
+	return pad(date, 11) + pad(description, 22) + leftPad(hours, 6) + "  " + pad(status, 7) +
+		leftPad(amount, 11) + leftPad(balance, 12) + " " + currency
+}
+
+func pad(s string, n int) string {
+	r := []rune(s)
+	if len(r) >= n {
+		return string(r[:n-1]) + " "
+	}
+	return s + strings.Repeat(" ", n-len(r))
+}
+
+func leftPad(s string, n int) string {
+	if len(s) >= n {
+		return s
+	}
+	return strings.Repeat(" ", n-len(s)) + s
+}
+
+func money(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
+
+// number formats hours and rates, leaving zero values empty.
+func number(v float64) string {
+	if v == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,85 @@
+package statements
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"mercor/internal/domain/contractors"
+	"mercor/internal/domain/invoices"
+	"mercor/internal/domain/jobs"
+	payment "mercor/internal/domain/paymentLineItem"
+	"mercor/internal/domain/timelog"
+	"mercor/internal/scd"
+)
+
+// Repository reads the versions a statement is made of. Every read takes the
+// time travel of the statement, so a statement can be produced again as it
+// was on record at any earlier time.
+type Repository interface {
+	Contractor(id uuid.UUID, tt scd.TimeTravel) (contractors.Contractor, error)
+	Timelogs(contractorID uuid.UUID, before time.Time, tt scd.TimeTravel) ([]timelog.Timelog, error)
+	LineItems(contractorID uuid.UUID, before time.Time, tt scd.TimeTravel) ([]lineItem, error)
+	Rates(jobUIDs []uuid.UUID, knownAt *time.Time) (jobs.RateHistory, error)
+}
+
+type repo struct {
+	contractors *scd.SCDManager[contractors.Contractor]
+	jobs        jobs.Repository
+	timelogs    *scd.SCDManager[timelog.Timelog]
+	payments    *scd.SCDManager[payment.PaymentLineItem]
+	invoices    *scd.SCDManager[invoices.Invoice]
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repo{
+		contractors: scd.NewManager[contractors.Contractor](db),
+		jobs:        jobs.NewRepository(db),
+		timelogs:    scd.NewManager[timelog.Timelog](db),
+		payments:    scd.NewManager[payment.PaymentLineItem](db),
+		invoices:    scd.NewManager[invoices.Invoice](db),
+	}
+}
+
+func (r *repo) Contractor(id uuid.UUID, tt scd.TimeTravel) (contractors.Contractor, error) {
+	var c contractors.Contractor
+	err := r.contractors.At(tt).Where("v.id = ?", id).Take(&c).Error
+	return c, err
+}
+
+// Timelogs returns the contractor's timelogs that started before the given
+// time.
+func (r *repo) Timelogs(contractorID uuid.UUID, before time.Time, tt scd.TimeTravel) ([]timelog.Timelog, error) {
+	var list []timelog.Timelog
+	err := r.timelogs.At(tt).
+		Where("v.contractor_id = ? AND v.start_time < ?", contractorID, before).
+		Find(&list).Error
+	return list, err
+}
+
+// LineItems returns the contractor's payment line items issued before the
+// given time, in the currency of the job version their timelog version was
+// logged for. A line item is paid once an invoice billing it is paid.
+func (r *repo) LineItems(contractorID uuid.UUID, before time.Time, tt scd.TimeTravel) ([]lineItem, error) {
+	paid := r.invoices.At(tt).
+		Distinct("l.line_item_id").
+		Joins("JOIN invoice_lines l ON l.invoice_id = v.id").
+		Where("v.status = ?", invoices.StatusPaid)
+
+	var list []lineItem
+	err := r.payments.At(tt).
+		Select(`v.uid, v.amount, v.issued_at, COALESCE(j.currency, '') AS currency,
+			p.line_item_id IS NOT NULL AS paid`).
+		Joins("LEFT JOIN (?) AS p ON p.line_item_id = v.id", paid).
+		Joins("LEFT JOIN timelogs t ON t.uid = v.timelog_uid").
+		Joins("LEFT JOIN jobs j ON j.uid = t.job_uid").
+		Where("v.contractor_id = ? AND v.issued_at < ?", contractorID, before).
+		Scan(&list).Error
+	return list, err
+}
+
+// Rates returns the rate history, as recorded at knownAt, of the jobs the
+// given job versions belong to.
+func (r *repo) Rates(jobUIDs []uuid.UUID, knownAt *time.Time) (jobs.RateHistory, error) {
+	return r.jobs.RateHistory(jobUIDs, knownAt)
+}
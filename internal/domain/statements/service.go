@@ -0,0 +1,52 @@
+package statements
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"mercor/internal/scd"
+)
+
+type Service interface {
+	Statement(contractorID uuid.UUID, from, to time.Time, tt scd.TimeTravel) (Statement, error)
+}
+
+type service struct {
+	repo Repository
+}
+
+func NewService(r Repository) Service {
+	return &service{repo: r}
+}
+
+// Statement returns the contractor's statement for [from, to) as tt asks for
+// it. Work is paid at the rate of the job version effective when it started,
+// as recorded at the same time as the rest of the statement.
+func (s *service) Statement(contractorID uuid.UUID, from, to time.Time, tt scd.TimeTravel) (Statement, error) {
+	st := Statement{ContractorID: contractorID, From: from, To: to, AsOf: tt.AsOf, KnownAt: tt.KnownAt}
+	c, err := s.repo.Contractor(contractorID, tt)
+	if err != nil {
+		return st, err
+	}
+	st.ContractorName = c.Name
+	logs, err := s.repo.Timelogs(contractorID, to, tt)
+	if err != nil {
+		return st, err
+	}
+	items, err := s.repo.LineItems(contractorID, to, tt)
+	if err != nil {
+		return st, err
+	}
+	var jobUIDs []uuid.UUID
+	for _, t := range logs {
+		if t.JobUID != nil {
+			jobUIDs = append(jobUIDs, *t.JobUID)
+		}
+	}
+	rates, err := s.repo.Rates(jobUIDs, tt.KnownAt)
+	if err != nil {
+		return st, err
+	}
+	st.build(logs, items, rates)
+	return st, nil
+}
@@ -0,0 +1,79 @@
+package stream
+
+import (
+	"encoding/json"
+
+	scdv1 "mercor/api/scd/v1"
+	"mercor/internal/events"
+	"mercor/internal/rpc"
+
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// GRPCServer streams change events over gRPC with the filters of the /stream
+// endpoint.
+type GRPCServer struct {
+	scdv1.UnimplementedEventServiceServer
+	broker *events.Broker
+}
+
+func NewGRPCServer(b *events.Broker) *GRPCServer {
+	return &GRPCServer{broker: b}
+}
+
+func (g *GRPCServer) Subscribe(req *scdv1.SubscribeRequest, stream scdv1.EventService_SubscribeServer) error {
+	filter := events.Filter{Entity: req.GetEntity(), Keys: map[string]string{}}
+	if v := req.GetCompanyId(); v != "" {
+		filter.Keys["company_id"] = v
+	}
+	if v := req.GetContractorId(); v != "" {
+		filter.Keys["contractor_id"] = v
+	}
+
+	ch, cancel := g.broker.Subscribe(filter, req.GetAfterSeq())
+	defer cancel()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case e, ok := <-ch:
+			if !ok {
+				return nil
+			}
+			msg, err := eventToProto(e)
+			if err != nil {
+				return rpc.Error(err)
+			}
+			if err := stream.Send(msg); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func eventToProto(e events.Event) (*scdv1.Event, error) {
+	// Data is the stored row; its JSON encoding is what /stream sends.
+	b, err := json.Marshal(e.Data)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+	data, err := structpb.NewStruct(fields)
+	if err != nil {
+		return nil, err
+	}
+	return &scdv1.Event{
+		Seq:       e.Seq,
+		Entity:    e.Entity,
+		Id:        e.ID,
+		Uid:       e.UID,
+		Version:   int32(e.Version),
+		Keys:      e.Keys,
+		Data:      data,
+		CreatedAt: rpc.Timestamp(e.CreatedAt),
+	}, nil
+}
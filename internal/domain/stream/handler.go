@@ -0,0 +1,82 @@
+package stream
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"mercor/internal/events"
+)
+
+const heartbeatInterval = 15 * time.Second
+
+type Handler struct {
+	broker *events.Broker
+}
+
+func NewHandler(b *events.Broker) *Handler {
+	return &Handler{broker: b}
+}
+
+func (h *Handler) RegisterRoutes(r *gin.Engine) {
+	r.GET("/stream", h.Stream)
+}
+
+// Stream pushes new versions as Server-Sent Events. Supported query filters are
+// entity (companies, contractors, jobs, timelogs, payment-line-items, invoices),
+// company_id and contractor_id. Timelogs and payment line items match the
+// company of the job they are linked to.
+// A Last-Event-ID the backlog cannot replay from gets a reset event first.
+func (h *Handler) Stream(c *gin.Context) {
+	filter := events.Filter{Entity: c.Query("entity"), Keys: map[string]string{}}
+	for _, key := range []string{"company_id", "contractor_id"} {
+		if v := c.Query(key); v != "" {
+			filter.Keys[key] = v
+		}
+	}
+
+	var after uint64
+	if last := c.GetHeader("Last-Event-ID"); last != "" {
+		n, err := strconv.ParseUint(last, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid Last-Event-ID"})
+			return
+		}
+		after = n
+	}
+
+	ch, cancel := h.broker.Subscribe(filter, after)
+	defer cancel()
+
+	ticker := time.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
+
+	c.Header("Content-Type", sse.ContentType)
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case <-ticker.C:
+			_, err := io.WriteString(w, ": ping\n\n")
+			return err == nil
+		case e, ok := <-ch:
+			if !ok {
+				return false
+			}
+			err := sse.Encode(w, sse.Event{
+				Id:    strconv.FormatUint(e.Seq, 10),
+				Event: e.Entity,
+				Data:  e,
+			})
+			return err == nil
+		}
+	})
+}
@@ -0,0 +1,29 @@
+package stream
+
+import (
+	"net/http"
+
+	"mercor/internal/events"
+	"mercor/internal/openapi"
+)
+
+// Operations documents the routes registered by Handler.
+func Operations() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method: http.MethodGet, Path: "/stream", Tag: "stream",
+			Summary:     "Server-Sent Events of new versions",
+			Description: "Each event's data is an Event; its id can be sent back as Last-Event-ID to resume. A Last-Event-ID the server can no longer replay from gets a reset event first.",
+			Params: []openapi.Param{
+				{Name: "entity", In: "query", Schema: openapi.Schema{"type": "string", "enum": []string{"companies", "contractors", "jobs", "timelogs", "payment-line-items", "invoices"}}},
+				{Name: "company_id", In: "query", Schema: openapi.Schema{"type": "string", "format": "uuid"}},
+				{Name: "contractor_id", In: "query", Schema: openapi.Schema{"type": "string", "format": "uuid"}},
+				{Name: "Last-Event-ID", In: "header", Schema: openapi.Schema{"type": "string"}},
+			},
+			Responses: openapi.Responses{
+				200: {Description: "An event stream", Content: map[string]any{"text/event-stream": events.Event{}}},
+				400: openapi.BadRequest,
+			},
+		},
+	}
+}
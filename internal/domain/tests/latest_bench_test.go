@@ -0,0 +1,108 @@
+package tests
+
+import (
+	"mercor/internal/db"
+	"mercor/internal/domain/jobs"
+	"mercor/internal/scd"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// The latest-version benchmarks read a million job versions, seeded in a
+// transaction that is rolled back: four versions each of 250,000 jobs, spread
+// over 1,000 companies. Run them with
+//
+//	go test ./internal/domain/tests -run '^$' -bench Latest
+//
+// The _group_by cases time the query GetLatest made before, which grouped the
+// whole table by entity before the caller's filter applied.
+const (
+	benchCompanies = 1000
+	benchJobs      = 250000
+	benchVersions  = 4
+)
+
+func BenchmarkLatest(b *testing.B) {
+	tx, companyID, jobID := seedJobVersions(b)
+	repo := jobs.NewRepository(tx)
+	m := scd.NewManager[jobs.Job](tx)
+
+	b.Run("by_company", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			list, err := repo.FindLatestByCompany(companyID)
+			if err != nil || len(list) != benchJobs/benchCompanies {
+				b.Fatalf("got %d jobs: %v", len(list), err)
+			}
+		}
+	})
+	b.Run("by_company_group_by", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			var list []jobs.Job
+			err := groupByLatest(tx).Where("company_id = ?", companyID).Where("status = ?", "active").Find(&list).Error
+			if err != nil || len(list) != benchJobs/benchCompanies {
+				b.Fatalf("got %d jobs: %v", len(list), err)
+			}
+		}
+	})
+	b.Run("by_id", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			job, err := m.FindLatestByID(jobID.String())
+			if err != nil || job.Version != benchVersions {
+				b.Fatalf("got version %d: %v", job.Version, err)
+			}
+		}
+	})
+	b.Run("by_id_group_by", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			var job jobs.Job
+			err := groupByLatest(tx).Where("main.id = ?", jobID).First(&job).Error
+			if err != nil || job.Version != benchVersions {
+				b.Fatalf("got version %d: %v", job.Version, err)
+			}
+		}
+	})
+}
+
+// seedJobVersions stores the job versions of the benchmarks in a transaction
+// rolled back when the benchmark ends, and returns it with the ID of one of
+// the companies and of one of the jobs.
+func seedJobVersions(b *testing.B) (*gorm.DB, uuid.UUID, uuid.UUID) {
+	b.Helper()
+	tx := db.Connect().Begin()
+	b.Cleanup(func() { tx.Rollback() })
+	err := tx.Exec(`INSERT INTO jobs (id, uid, version, status, rate, currency, title, company_id, contractor_id,
+			created_at, updated_at, effective_from, effective_to, recorded_from, revert_reason)
+		SELECT md5('bench-job-' || j)::uuid, md5('bench-job-' || j || '-' || v)::uuid, v, 'active', 10 + v, 'USD', 'Bench',
+			md5('bench-company-' || j % ?)::uuid, md5('bench-contractor-' || j)::uuid,
+			now(), now(), timestamptz '2020-01-01' + v * interval '30 days',
+			CASE WHEN v < ? THEN timestamptz '2020-01-01' + (v + 1) * interval '30 days' END,
+			timestamptz '2020-01-01' + v * interval '30 days', ''
+		FROM generate_series(1, ?) AS j, generate_series(1, ?) AS v`,
+		benchCompanies, benchVersions, benchJobs, benchVersions).Error
+	if err != nil {
+		b.Fatal(err)
+	}
+	if err := tx.Exec("ANALYZE jobs").Error; err != nil {
+		b.Fatal(err)
+	}
+	var ids struct{ CompanyID, JobID uuid.UUID }
+	err = tx.Raw("SELECT md5('bench-company-1')::uuid AS company_id, md5('bench-job-1')::uuid AS job_id").Scan(&ids).Error
+	if err != nil {
+		b.Fatal(err)
+	}
+	return tx, ids.CompanyID, ids.JobID
+}
+
+// groupByLatest is the latest-version query GetLatest made before it looked
+// for later versions of each row instead.
+func groupByLatest(tx *gorm.DB) *gorm.DB {
+	sub := tx.Table("jobs as s").
+		Select("id, MAX(version) as max_ver").
+		Where("recorded_to IS NULL AND effective_from <= ?", time.Now()).
+		Group("id")
+	return tx.Table("jobs as main").
+		Joins("JOIN (?) as latest ON main.id = latest.id AND main.version = latest.max_ver", sub)
+}
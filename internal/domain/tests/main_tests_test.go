@@ -1,19 +1,53 @@
 package tests
 
 import (
+	"archive/zip"
+	"bufio"
 	"bytes"
+	"context"
+	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	scdv1 "mercor/api/scd/v1"
+	"mercor/client"
+	"mercor/internal/db"
+	"mercor/internal/domain/companies"
+	"mercor/internal/domain/contractors"
+	"mercor/internal/domain/imports"
+	"mercor/internal/domain/invoices"
 	"mercor/internal/domain/jobs"
+	payment "mercor/internal/domain/paymentLineItem"
+	"mercor/internal/domain/reconciliation"
+	"mercor/internal/domain/retention"
 	"mercor/internal/domain/router"
+	"mercor/internal/domain/statements"
+	"mercor/internal/domain/timelog"
+	"mercor/internal/domain/verify"
+	"mercor/internal/events"
+	"mercor/internal/idempotency"
+	"mercor/internal/openapi"
+	"mercor/internal/scd"
+	"mime/multipart"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/parquet-go/parquet-go"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
 )
 
 func setupRouter() *gin.Engine {
@@ -22,6 +56,34 @@ func setupRouter() *gin.Engine {
 	return r
 }
 
+// createCompany stores a company and returns its ID, which jobs have to
+// reference.
+func createCompany(t *testing.T, r *gin.Engine) string {
+	body := `{"name":"Test Company","billingAddress":"1 Main St, Springfield","defaultCurrency":"USD","paymentTermsDays":30}`
+	req, _ := http.NewRequest("POST", "/companies", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	var created companies.CompanyResponse
+	json.Unmarshal(resp.Body.Bytes(), &created)
+	return created.ID.String()
+}
+
+// createContractor stores a contractor and returns its ID, which jobs,
+// timelogs and payment line items have to reference.
+func createContractor(t *testing.T, r *gin.Engine) string {
+	body := `{"name":"Test Contractor","email":"contractor@example.com","taxRegion":"US-CA","payoutMethod":"bank_transfer","payoutAccount":"000123456789"}`
+	req, _ := http.NewRequest("POST", "/contractors", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	var created contractors.ContractorResponse
+	json.Unmarshal(resp.Body.Bytes(), &created)
+	return created.ID.String()
+}
+
 func TestJobCRUD(t *testing.T) {
 	r := setupRouter()
 
@@ -30,8 +92,8 @@ func TestJobCRUD(t *testing.T) {
 		"title":        "Backend Developer",
 		"status":       "active",
 		"rate":         42.5,
-		"companyId":    uuid.New().String(),
-		"contractorId": uuid.New().String(),
+		"companyId":    createCompany(t, r),
+		"contractorId": createContractor(t, r),
 	}
 	body, _ := json.Marshal(jobCreate)
 	req, _ := http.NewRequest("POST", "/jobs", bytes.NewBuffer(body))
@@ -40,7 +102,7 @@ func TestJobCRUD(t *testing.T) {
 	r.ServeHTTP(resp, req)
 	assert.Equal(t, http.StatusCreated, resp.Code)
 
-	var createdJob jobs.Job
+	var createdJob jobs.JobResponse
 	err := json.Unmarshal(resp.Body.Bytes(), &createdJob)
 	assert.Nil(t, err)
 	assert.Equal(t, "Backend Developer", createdJob.Title)
@@ -52,11 +114,11 @@ func TestJobCRUD(t *testing.T) {
 	r.ServeHTTP(resp, req)
 	assert.Equal(t, http.StatusOK, resp.Code)
 
-	var fetched jobs.Job
+	var fetched jobs.JobResponse
 	json.Unmarshal(resp.Body.Bytes(), &fetched)
 	assert.Equal(t, createdJob.UID, fetched.UID)
 
-	// --- UPDATE (PUT) → creates new version
+	// --- UPDATE (PUT) without a complete representation is rejected
 	update := map[string]any{
 		"title":  "Backend Engineer",
 		"status": "extended",
@@ -66,12 +128,124 @@ func TestJobCRUD(t *testing.T) {
 	req.Header.Set("Content-Type", "application/json")
 	resp = httptest.NewRecorder()
 	r.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	// --- UPDATE (PUT) → creates new version
+	update["rate"] = jobCreate["rate"]
+	update["companyId"] = jobCreate["companyId"]
+	update["contractorId"] = jobCreate["contractorId"]
+	updateJSON, _ = json.Marshal(update)
+	req, _ = http.NewRequest("PUT", "/jobs/"+createdJob.UID.String(), bytes.NewBuffer(updateJSON))
+	req.Header.Set("Content-Type", "application/json")
+	resp = httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
 	assert.Equal(t, http.StatusOK, resp.Code)
 
-	var updatedJob jobs.Job
+	var updatedJob jobs.JobResponse
 	json.Unmarshal(resp.Body.Bytes(), &updatedJob)
 	assert.Equal(t, "Backend Engineer", updatedJob.Title)
 	assert.Equal(t, 2, updatedJob.Version)
+
+	// --- PATCH (merge patch) → only the given field changes
+	req, _ = http.NewRequest("PATCH", "/jobs/"+createdJob.UID.String(), bytes.NewBufferString(`{"rate":50}`))
+	req.Header.Set("Content-Type", "application/merge-patch+json")
+	resp = httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	var patchedJob jobs.JobResponse
+	json.Unmarshal(resp.Body.Bytes(), &patchedJob)
+	assert.Equal(t, "Backend Engineer", patchedJob.Title)
+	assert.Equal(t, 50.0, patchedJob.Rate)
+	assert.Equal(t, updatedJob.CompanyID, patchedJob.CompanyID)
+	assert.Equal(t, 3, patchedJob.Version)
+
+	// --- Title changes are corrections (Type 1) → overwritten on every version
+	req, _ = http.NewRequest("PATCH", "/jobs/"+createdJob.UID.String(), bytes.NewBufferString(`{"title":"Staff Engineer"}`))
+	req.Header.Set("Content-Type", "application/merge-patch+json")
+	resp = httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	var correctedJob jobs.JobResponse
+	json.Unmarshal(resp.Body.Bytes(), &correctedJob)
+	assert.Equal(t, "Staff Engineer", correctedJob.Title)
+	assert.Equal(t, patchedJob.UID, correctedJob.UID)
+	assert.Equal(t, 3, correctedJob.Version)
+
+	req, _ = http.NewRequest("GET", "/jobs/"+createdJob.UID.String()+"/history", nil)
+	resp = httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	var history []jobs.JobResponse
+	json.Unmarshal(resp.Body.Bytes(), &history)
+	if assert.Len(t, history, 3) {
+		assert.Equal(t, "Staff Engineer", history[0].Title)
+		assert.Equal(t, 42.5, history[0].Rate)
+	}
+
+	// --- JSON Patch: "value": null is a value, member names are exact
+	jsonPatch := func(ops string) int {
+		req, _ := http.NewRequest("PATCH", "/jobs/"+createdJob.UID.String(), bytes.NewBufferString(ops))
+		req.Header.Set("Content-Type", "application/json-patch+json")
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+		return resp.Code
+	}
+	assert.Equal(t, http.StatusConflict, jsonPatch(`[{"op":"test","path":"/title","value":null}]`))
+	assert.Equal(t, http.StatusBadRequest, jsonPatch(`[{"op":"test","path":"/title"}]`))
+	assert.Equal(t, http.StatusBadRequest, jsonPatch(`[{"op":"replace","path":"/Rate","value":60}]`))
+	assert.Equal(t, http.StatusOK, jsonPatch(`[{"op":"test","path":"/title","value":"Staff Engineer"},{"op":"replace","path":"/rate","value":60}]`))
+}
+
+func TestJobRequestValidation(t *testing.T) {
+	r := setupRouter()
+
+	// Invalid fields are reported by their JSON names.
+	invalid := map[string]any{
+		"title":        "Invalid",
+		"status":       "active",
+		"rate":         -1,
+		"companyId":    "not-a-uuid",
+		"contractorId": uuid.New().String(),
+	}
+	body, _ := json.Marshal(invalid)
+	req, _ := http.NewRequest("POST", "/jobs", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	var errResp struct {
+		Fields map[string]string `json:"fields"`
+	}
+	json.Unmarshal(resp.Body.Bytes(), &errResp)
+	assert.Contains(t, errResp.Fields, "rate")
+	assert.Contains(t, errResp.Fields, "companyId")
+
+	// Identity and version sent by the client are ignored.
+	clientUID := uuid.New()
+	valid := map[string]any{
+		"uid":          clientUID.String(),
+		"version":      7,
+		"title":        "Valid",
+		"status":       "active",
+		"rate":         10,
+		"companyId":    createCompany(t, r),
+		"contractorId": createContractor(t, r),
+	}
+	body, _ = json.Marshal(valid)
+	req, _ = http.NewRequest("POST", "/jobs", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp = httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusCreated, resp.Code)
+
+	var created jobs.JobResponse
+	json.Unmarshal(resp.Body.Bytes(), &created)
+	assert.NotEqual(t, clientUID, created.UID)
+	assert.NotEqual(t, uuid.Nil, created.ID)
+	assert.Equal(t, 1, created.Version)
+	assert.False(t, created.CreatedAt.IsZero())
 }
 
 func TestTimeLogCRUD(t *testing.T) {
@@ -82,8 +256,8 @@ func TestTimeLogCRUD(t *testing.T) {
 		"title":        "TimeLogJob",
 		"status":       "active",
 		"rate":         55.5,
-		"companyId":    uuid.New().String(),
-		"contractorId": uuid.New().String(),
+		"companyId":    createCompany(t, r),
+		"contractorId": createContractor(t, r),
 	}
 	body, _ := json.Marshal(jobPayload)
 	req, _ := http.NewRequest("POST", "/jobs", bytes.NewBuffer(body))
@@ -91,7 +265,7 @@ func TestTimeLogCRUD(t *testing.T) {
 	resp := httptest.NewRecorder()
 	r.ServeHTTP(resp, req)
 
-	var createdJob jobs.Job
+	var createdJob jobs.JobResponse
 	json.Unmarshal(resp.Body.Bytes(), &createdJob)
 
 	// --- CREATE Timelog
@@ -107,6 +281,31 @@ func TestTimeLogCRUD(t *testing.T) {
 	resp = httptest.NewRecorder()
 	r.ServeHTTP(resp, req)
 	assert.Equal(t, http.StatusCreated, resp.Code)
+	var created timelog.TimelogResponse
+	json.Unmarshal(resp.Body.Bytes(), &created)
+
+	// PATCH keeps the fields it leaves out; PUT replaces every one, so one
+	// without a jobUid unlinks the timelog.
+	req, _ = http.NewRequest("PATCH", "/timelogs/"+created.UID.String(), bytes.NewBufferString(`{"externalRef":"crud"}`))
+	req.Header.Set("Content-Type", "application/merge-patch+json")
+	resp = httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var patched timelog.TimelogResponse
+	json.Unmarshal(resp.Body.Bytes(), &patched)
+	assert.Equal(t, &createdJob.UID, patched.JobUID)
+
+	delete(tlog, "jobUid")
+	tlogJSON, _ = json.Marshal(tlog)
+	req, _ = http.NewRequest("PUT", "/timelogs/"+created.UID.String(), bytes.NewBuffer(tlogJSON))
+	req.Header.Set("Content-Type", "application/json")
+	resp = httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var replaced timelog.TimelogResponse
+	json.Unmarshal(resp.Body.Bytes(), &replaced)
+	assert.Nil(t, replaced.JobUID)
+	assert.Empty(t, replaced.ExternalRef)
 }
 
 func TestPaymentLineItemFlow(t *testing.T) {
@@ -117,8 +316,8 @@ func TestPaymentLineItemFlow(t *testing.T) {
 		"title":        "PaymentJob",
 		"status":       "active",
 		"rate":         100,
-		"companyId":    uuid.New().String(),
-		"contractorId": uuid.New().String(),
+		"companyId":    createCompany(t, r),
+		"contractorId": createContractor(t, r),
 	}
 	body, _ := json.Marshal(job)
 	req, _ := http.NewRequest("POST", "/jobs", bytes.NewBuffer(body))
@@ -126,7 +325,7 @@ func TestPaymentLineItemFlow(t *testing.T) {
 	resp := httptest.NewRecorder()
 	r.ServeHTTP(resp, req)
 
-	var createdJob jobs.Job
+	var createdJob jobs.JobResponse
 	json.Unmarshal(resp.Body.Bytes(), &createdJob)
 
 	// Create Timelog
@@ -163,3 +362,1941 @@ func TestPaymentLineItemFlow(t *testing.T) {
 
 	fmt.Println("✅ Payment Line Item created and linked successfully.")
 }
+
+func TestStreamReplaysJobEvents(t *testing.T) {
+	r := setupRouter()
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	companyID := createCompany(t, r)
+	job := map[string]any{
+		"title":        "StreamJob",
+		"status":       "active",
+		"rate":         10,
+		"companyId":    companyID,
+		"contractorId": createContractor(t, r),
+	}
+	body, _ := json.Marshal(job)
+	req, _ := http.NewRequest("POST", "/jobs", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusCreated, resp.Code)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	streamReq, _ := http.NewRequestWithContext(ctx, "GET", srv.URL+"/stream?entity=jobs&company_id="+companyID, nil)
+	streamReq.Header.Set("Last-Event-ID", "0")
+	streamResp, err := http.DefaultClient.Do(streamReq)
+	assert.Nil(t, err)
+	defer streamResp.Body.Close()
+	assert.Equal(t, "text/event-stream", streamResp.Header.Get("Content-Type"))
+
+	scanner := bufio.NewScanner(streamResp.Body)
+	var data string
+	for scanner.Scan() {
+		if line := scanner.Text(); strings.HasPrefix(line, "data:") {
+			data = strings.TrimPrefix(line, "data:")
+			break
+		}
+	}
+	var event map[string]any
+	assert.Nil(t, json.Unmarshal([]byte(data), &event))
+	assert.Equal(t, "jobs", event["entity"])
+	assert.Equal(t, float64(1), event["version"])
+}
+
+func TestStreamPublishesOnCommit(t *testing.T) {
+	r := setupRouter()
+	companyID, contractorID := uuid.MustParse(createCompany(t, r)), uuid.MustParse(createContractor(t, r))
+
+	broker := events.NewBroker(10)
+	database := db.Connect()
+	if !assert.NoError(t, events.Attach(database, broker)) {
+		return
+	}
+	ch, cancel := broker.Subscribe(events.Filter{Entity: "jobs", Keys: map[string]string{"company_id": companyID.String()}}, 0)
+	defer cancel()
+	create := func(tx *gorm.DB, title string) error {
+		_, err := jobs.NewService(jobs.NewRepository(tx)).CreateJob(jobs.Job{Title: title, Status: "active", Rate: 10, CompanyID: companyID, ContractorID: contractorID})
+		return err
+	}
+
+	// A transaction rolled back publishes nothing.
+	err := database.Transaction(func(tx *gorm.DB) error {
+		if err := create(tx, "RolledBack"); err != nil {
+			return err
+		}
+		return errors.New("roll back")
+	})
+	assert.EqualError(t, err, "roll back")
+
+	// Neither do writes rolled back to a savepoint; the others are published
+	// when the transaction commits.
+	err = database.Transaction(func(tx *gorm.DB) error {
+		tx.Transaction(func(tx *gorm.DB) error {
+			if err := create(tx, "Undone"); err != nil {
+				return err
+			}
+			return errors.New("roll back to savepoint")
+		})
+		if err := create(tx, "Committed"); err != nil {
+			return err
+		}
+		select {
+		case e := <-ch:
+			t.Errorf("published before commit: %v", e.Data)
+		default:
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+
+	select {
+	case e := <-ch:
+		assert.Equal(t, "Committed", e.Data.(jobs.Job).Title)
+	case <-time.After(time.Second):
+		t.Fatal("no event after commit")
+	}
+	select {
+	case e := <-ch:
+		t.Errorf("unexpected event: %v", e.Data)
+	default:
+	}
+}
+
+func TestStreamFiltersLinkedCompany(t *testing.T) {
+	r := setupRouter()
+	companyID, contractorID := uuid.MustParse(createCompany(t, r)), uuid.MustParse(createContractor(t, r))
+
+	broker := events.NewBroker(10)
+	database := db.Connect()
+	if !assert.NoError(t, events.Attach(database, broker)) {
+		return
+	}
+	ch, cancel := broker.Subscribe(events.Filter{Keys: map[string]string{"company_id": companyID.String()}}, 0)
+	defer cancel()
+
+	// Timelogs and line items carry no company; theirs is the one of the
+	// job they are linked to.
+	job, err := jobs.NewService(jobs.NewRepository(database)).CreateJob(jobs.Job{Title: "Linked", Status: "active", Rate: 10, CompanyID: companyID, ContractorID: contractorID})
+	if !assert.NoError(t, err) {
+		return
+	}
+	start := time.Now().Add(-time.Hour)
+	tl, err := timelog.NewService(timelog.NewRepository(database)).Create(timelog.Timelog{ContractorID: contractorID, JobUID: &job.UID, StartTime: start, EndTime: start.Add(30 * time.Minute)})
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = payment.NewService(payment.NewRepository(database)).Create(payment.PaymentLineItem{ContractorID: contractorID, TimelogUID: &tl.UID, Amount: 5, IssuedAt: start})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var entities []string
+	for len(entities) < 3 {
+		select {
+		case e := <-ch:
+			entities = append(entities, e.Entity)
+		case <-time.After(time.Second):
+			t.Fatalf("got only %v", entities)
+		}
+	}
+	assert.Equal(t, []string{"jobs", "timelogs", "payment-line-items"}, entities)
+}
+
+func TestStreamResetsStaleResume(t *testing.T) {
+	r := setupRouter()
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	// The sequence numbers of the server start past 1, so it cannot replay
+	// what followed it.
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, "GET", srv.URL+"/stream?entity=jobs", nil)
+	req.Header.Set("Last-Event-ID", "1")
+	resp, err := http.DefaultClient.Do(req)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer resp.Body.Close()
+
+	scanner := bufio.NewScanner(resp.Body)
+	var id, event string
+	for scanner.Scan() {
+		line := scanner.Text()
+		if v, ok := strings.CutPrefix(line, "id:"); ok {
+			id = v
+		}
+		if v, ok := strings.CutPrefix(line, "event:"); ok {
+			event = v
+			break
+		}
+	}
+	assert.Equal(t, events.Reset, event)
+	seq, err := strconv.ParseUint(id, 10, 64)
+	assert.NoError(t, err)
+	assert.Greater(t, seq, uint64(1))
+}
+
+func TestTimelogBatch(t *testing.T) {
+	r := setupRouter()
+
+	contractorID := createContractor(t, r)
+	batch := map[string]any{
+		"mode": "continue",
+		"items": []map[string]any{
+			{"op": "create", "data": map[string]any{
+				"contractorId": contractorID,
+				"startTime":    time.Now().Add(-2 * time.Hour).Format(time.RFC3339),
+				"endTime":      time.Now().Add(-1 * time.Hour).Format(time.RFC3339),
+			}},
+			{"op": "create", "data": map[string]any{
+				"contractorId": contractorID,
+				"startTime":    time.Now().Format(time.RFC3339),
+				"endTime":      time.Now().Add(-1 * time.Hour).Format(time.RFC3339),
+			}},
+		},
+	}
+	body, _ := json.Marshal(batch)
+	req, _ := http.NewRequest("POST", "/timelogs/batch", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	var result struct {
+		Results []struct {
+			OK    bool   `json:"ok"`
+			Error string `json:"error"`
+		} `json:"results"`
+	}
+	json.Unmarshal(resp.Body.Bytes(), &result)
+	assert.Len(t, result.Results, 2)
+	assert.True(t, result.Results[0].OK)
+	assert.False(t, result.Results[1].OK)
+
+	// All-or-nothing mode rejects the whole batch, at the :batch path too.
+	batch["mode"] = "atomic"
+	body, _ = json.Marshal(batch)
+	req, _ = http.NewRequest("POST", "/timelogs:batch", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp = httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+
+	req, _ = http.NewRequest("GET", "/timelogs:batch", nil)
+	resp = httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
+
+func TestTimelogImport(t *testing.T) {
+	r := setupRouter()
+	contractorID := createContractor(t, r)
+	ref := "import-" + uuid.NewString()
+	start := time.Now().Add(-48 * time.Hour).UTC().Truncate(time.Second)
+
+	upload := func(name string, file []byte, fields map[string]string) *httptest.ResponseRecorder {
+		var buf bytes.Buffer
+		mw := multipart.NewWriter(&buf)
+		fw, _ := mw.CreateFormFile("file", name)
+		fw.Write(file)
+		for k, v := range fields {
+			mw.WriteField(k, v)
+		}
+		mw.Close()
+		req, _ := http.NewRequest("POST", "/imports/timelogs", &buf)
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+		return resp
+	}
+	started := func(resp *httptest.ResponseRecorder) uuid.UUID {
+		assert.Equal(t, http.StatusAccepted, resp.Code)
+		var imp imports.Import
+		json.Unmarshal(resp.Body.Bytes(), &imp)
+		return imp.ID
+	}
+	// finished polls an import until it is no longer being worked on.
+	finished := func(id uuid.UUID) imports.Details {
+		var d imports.Details
+		for i := 0; i < 100; i++ {
+			req, _ := http.NewRequest("GET", "/imports/"+id.String(), nil)
+			resp := httptest.NewRecorder()
+			r.ServeHTTP(resp, req)
+			assert.Equal(t, http.StatusOK, resp.Code)
+			d = imports.Details{}
+			json.Unmarshal(resp.Body.Bytes(), &d)
+			if d.Import.Status != imports.StatusProcessing && d.Import.Status != imports.StatusCommitting {
+				break
+			}
+			time.Sleep(50 * time.Millisecond)
+		}
+		return d
+	}
+
+	// A dry run previews the rows with mapped columns; the second row names
+	// an unknown contractor.
+	csvFile := fmt.Sprintf("Ref,Contractor,Start,End\n%s,%s,%s,%s\n%s-2,%s,%s,%s\n",
+		ref, contractorID, start.Format(time.RFC3339), start.Add(time.Hour).Format(time.RFC3339),
+		ref, uuid.NewString(), start.Format(time.RFC3339), start.Add(time.Hour).Format(time.RFC3339))
+	mapping := `{"externalRef":"Ref","contractorId":"Contractor","startTime":"Start","endTime":"End"}`
+	id := started(upload("timelogs.csv", []byte(csvFile), map[string]string{"mapping": mapping, "dryRun": "true"}))
+	preview := finished(id)
+	assert.Equal(t, imports.StatusPreviewed, preview.Import.Status)
+	assert.Equal(t, 2, preview.Import.TotalRows)
+	assert.Equal(t, 1, preview.Import.ValidRows)
+	assert.Equal(t, 1, preview.Import.InvalidRows)
+	if assert.Len(t, preview.Rows, 2) {
+		assert.Equal(t, imports.ActionCreate, preview.Rows[0].Action)
+		assert.Equal(t, imports.ActionError, preview.Rows[1].Action)
+	}
+
+	// The error report lists the rejected row.
+	req, _ := http.NewRequest("GET", "/imports/"+id.String()+"/errors", nil)
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "text/csv", resp.Header().Get("Content-Type"))
+	report := strings.Split(strings.TrimSpace(resp.Body.String()), "\n")
+	if assert.Len(t, report, 2) {
+		assert.Equal(t, "line,externalRef,error", report[0])
+		assert.True(t, strings.HasPrefix(report[1], "3,"+ref+"-2,"))
+		assert.Contains(t, report[1], "does not exist")
+	}
+
+	// Committing the preview writes the valid row, once.
+	commit := func() *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("POST", "/imports/"+id.String()+"/commit", nil)
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+		return resp
+	}
+	assert.Equal(t, http.StatusAccepted, commit().Code)
+	done := finished(id)
+	assert.Equal(t, imports.StatusCompleted, done.Import.Status)
+	assert.Equal(t, 1, done.Import.Created)
+	assert.NotEmpty(t, done.Rows[0].TimelogUID)
+	assert.Equal(t, http.StatusConflict, commit().Code)
+
+	// An XLSX upload with a later end versions the timelog, and uploading it
+	// again leaves it unchanged.
+	xlsx := xlsxFile(t, [][]string{
+		{"externalRef", "contractorId", "startTime", "endTime"},
+		{ref, contractorID, start.Format(time.RFC3339), start.Add(2 * time.Hour).Format(time.RFC3339)},
+	})
+	versioned := finished(started(upload("timelogs.xlsx", xlsx, nil)))
+	assert.Equal(t, imports.StatusCompleted, versioned.Import.Status)
+	assert.Equal(t, 1, versioned.Import.Versioned)
+	again := finished(started(upload("timelogs.xlsx", xlsx, nil)))
+	assert.Equal(t, imports.StatusCompleted, again.Import.Status)
+	assert.Equal(t, 1, again.Import.Unchanged)
+
+	// A jobUid column links the timelog to a job; a later file without the
+	// column keeps the link.
+	body, _ := json.Marshal(map[string]any{
+		"title": "Imported Work", "status": "active", "rate": 30,
+		"companyId": createCompany(t, r), "contractorId": contractorID,
+	})
+	req, _ = http.NewRequest("POST", "/jobs", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp = httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	var job jobs.JobResponse
+	json.Unmarshal(resp.Body.Bytes(), &job)
+	linked := finished(started(upload("timelogs.csv", []byte(fmt.Sprintf(
+		"externalRef,contractorId,startTime,endTime,jobUid\n%s,%s,%s,%s,%s\n",
+		ref, contractorID, start.Format(time.RFC3339), start.Add(2*time.Hour).Format(time.RFC3339), job.UID)), nil)))
+	assert.Equal(t, imports.StatusCompleted, linked.Import.Status)
+	assert.Equal(t, 1, linked.Import.Versioned)
+	again = finished(started(upload("timelogs.xlsx", xlsx, nil)))
+	assert.Equal(t, 1, again.Import.Unchanged)
+
+	req, _ = http.NewRequest("GET", "/timelogs/"+done.Rows[0].TimelogUID+"/history", nil)
+	resp = httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	var history []timelog.TimelogResponse
+	json.Unmarshal(resp.Body.Bytes(), &history)
+	if assert.Len(t, history, 3) {
+		assert.Nil(t, history[1].JobUID)
+		if assert.NotNil(t, history[2].JobUID) {
+			assert.Equal(t, job.UID, *history[2].JobUID)
+		}
+	}
+
+	// Other files are refused outright.
+	assert.Equal(t, http.StatusBadRequest, upload("timelogs.txt", []byte(csvFile), nil).Code)
+}
+
+// xlsxFile builds a workbook whose first sheet holds rows, the header as
+// shared strings and the other cells as inline strings.
+func xlsxFile(t *testing.T, rows [][]string) []byte {
+	var sheet, shared strings.Builder
+	sheet.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	shared.WriteString(`<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	for i, row := range rows {
+		fmt.Fprintf(&sheet, `<row r="%d">`, i+1)
+		for j, cell := range row {
+			ref := fmt.Sprintf("%c%d", 'A'+j, i+1)
+			if i == 0 {
+				fmt.Fprintf(&sheet, `<c r="%s" t="s"><v>%d</v></c>`, ref, j)
+				fmt.Fprintf(&shared, `<si><t>%s</t></si>`, cell)
+			} else {
+				fmt.Fprintf(&sheet, `<c r="%s" t="inlineStr"><is><t>%s</t></is></c>`, ref, cell)
+			}
+		}
+		sheet.WriteString(`</row>`)
+	}
+	sheet.WriteString(`</sheetData></worksheet>`)
+	shared.WriteString(`</sst>`)
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range map[string]string{
+		"xl/worksheets/sheet1.xml": sheet.String(),
+		"xl/sharedStrings.xml":     shared.String(),
+	} {
+		w, err := zw.Create(name)
+		assert.NoError(t, err)
+		w.Write([]byte(content))
+	}
+	assert.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func TestImportRecover(t *testing.T) {
+	repo := imports.NewRepository(db.Connect())
+	processing := imports.Import{ID: uuid.New(), Entity: "timelogs", FileName: "a.csv", Status: imports.StatusProcessing}
+	committing := imports.Import{ID: uuid.New(), Entity: "timelogs", FileName: "b.csv", Status: imports.StatusCommitting, DryRun: true}
+	assert.NoError(t, repo.Create(processing))
+	assert.NoError(t, repo.Create(committing))
+
+	// Nothing runs the imports a stopped server left unfinished: those being
+	// validated fail and those being committed can be committed again.
+	n, err := imports.NewService(repo, nil, nil).Recover()
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, n, int64(2))
+	got, err := repo.FindByID(processing.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, imports.StatusFailed, got.Status)
+	assert.NotEmpty(t, got.Error)
+	got, err = repo.FindByID(committing.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, imports.StatusPreviewed, got.Status)
+}
+
+func TestExportFormats(t *testing.T) {
+	r := setupRouter()
+	job := map[string]any{
+		"title":        "ExportJob",
+		"status":       "active",
+		"rate":         10,
+		"companyId":    createCompany(t, r),
+		"contractorId": createContractor(t, r),
+	}
+	body, _ := json.Marshal(job)
+	req, _ := http.NewRequest("POST", "/jobs", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	var v1 jobs.JobResponse
+	json.Unmarshal(resp.Body.Bytes(), &v1)
+
+	between := time.Now()
+	req, _ = http.NewRequest("PATCH", "/jobs/"+v1.UID.String(), bytes.NewBufferString(`{"rate":20}`))
+	req.Header.Set("Content-Type", "application/merge-patch+json")
+	resp = httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	export := func(query string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", "/exports/jobs?"+query, nil)
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+		assert.Equal(t, http.StatusOK, resp.Code)
+		return resp
+	}
+	id := v1.ID.String()
+
+	// CSV of the history: both versions, the first closed by the second.
+	resp = export("format=csv&scope=history")
+	assert.Equal(t, "text/csv", resp.Header().Get("Content-Type"))
+	records, err := csv.NewReader(resp.Body).ReadAll()
+	if !assert.NoError(t, err) {
+		return
+	}
+	col := map[string]int{}
+	for i, name := range records[0] {
+		col[name] = i
+	}
+	var history [][]string
+	for _, rec := range records[1:] {
+		if rec[col["id"]] == id {
+			history = append(history, rec)
+		}
+	}
+	if assert.Len(t, history, 2) {
+		assert.Equal(t, []string{"1", "10"}, []string{history[0][col["version"]], history[0][col["rate"]]})
+		assert.Equal(t, []string{"2", "20"}, []string{history[1][col["version"]], history[1][col["rate"]]})
+		assert.NotEmpty(t, history[0][col["valid_to"]])
+		assert.Empty(t, history[1][col["valid_to"]])
+	}
+
+	// NDJSON of the current versions: only the second.
+	resp = export("format=ndjson&scope=current")
+	var current []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(resp.Body.String()), "\n") {
+		var row map[string]any
+		if assert.NoError(t, json.Unmarshal([]byte(line), &row)) && row["id"] == id {
+			current = append(current, row)
+		}
+	}
+	if assert.Len(t, current, 1) {
+		assert.Equal(t, float64(2), current[0]["version"])
+		assert.Equal(t, float64(20), current[0]["rate"])
+		assert.Nil(t, current[0]["valid_to"])
+	}
+
+	// Parquet as of before the change: the first version.
+	resp = export("format=parquet&scope=as_of&as_of=" + url.QueryEscape(between.Format(time.RFC3339Nano)))
+	reader := parquet.NewReader(bytes.NewReader(resp.Body.Bytes()))
+	var asOf []map[string]any
+	for {
+		row := map[string]any{}
+		if err := reader.Read(&row); err != nil {
+			assert.ErrorIs(t, err, io.EOF)
+			break
+		}
+		if row["id"] == id {
+			asOf = append(asOf, row)
+		}
+	}
+	if assert.Len(t, asOf, 1) {
+		assert.Equal(t, int64(1), asOf[0]["version"])
+		assert.Equal(t, 10.0, asOf[0]["rate"])
+		assert.Equal(t, v1.UID.String(), asOf[0]["uid"])
+	}
+
+	req, _ = http.NewRequest("GET", "/exports/jobs?format=xml", nil)
+	resp = httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+func TestIdempotencyKeyReplaysResponse(t *testing.T) {
+	r := setupRouter()
+
+	payment := map[string]any{
+		"contractorId": createContractor(t, r),
+		"amount":       25,
+		"issuedAt":     time.Now().Format(time.RFC3339),
+	}
+	body, _ := json.Marshal(payment)
+	key := uuid.New().String()
+
+	send := func(b []byte) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("POST", "/payment-line-items", bytes.NewBuffer(b))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Idempotency-Key", key)
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+		return resp
+	}
+
+	first := send(body)
+	assert.Equal(t, http.StatusCreated, first.Code)
+
+	retry := send(body)
+	assert.Equal(t, http.StatusCreated, retry.Code)
+	assert.Equal(t, "true", retry.Header().Get("Idempotent-Replayed"))
+	assert.Equal(t, first.Body.String(), retry.Body.String())
+
+	payment["amount"] = 26
+	changed, _ := json.Marshal(payment)
+	assert.Equal(t, http.StatusUnprocessableEntity, send(changed).Code)
+}
+
+func TestIdempotencyKeyReleasedAfterPanic(t *testing.T) {
+	database := db.Connect()
+	r := gin.New()
+	r.Use(gin.Recovery(), idempotency.Middleware(database))
+	calls := 0
+	r.POST("/flaky", func(c *gin.Context) {
+		calls++
+		if calls == 1 {
+			panic("handler crashed")
+		}
+		c.JSON(http.StatusCreated, gin.H{"calls": calls})
+	})
+
+	send := func(key string, body []byte) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("POST", "/flaky", bytes.NewReader(body))
+		req.Header.Set("Idempotency-Key", key)
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+		return resp
+	}
+
+	key := uuid.New().String()
+	assert.Equal(t, http.StatusInternalServerError, send(key, []byte("{}")).Code)
+	assert.Equal(t, http.StatusCreated, send(key, []byte("{}")).Code)
+	assert.Equal(t, 2, calls)
+
+	big := bytes.Repeat([]byte(" "), idempotency.MaxBodyBytes+1)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, send(uuid.New().String(), big).Code)
+	assert.Equal(t, 2, calls)
+}
+
+func TestIdempotencyKeyHeldWhileRunning(t *testing.T) {
+	database := db.Connect()
+	r := gin.New()
+	r.Use(idempotency.Middleware(database))
+	started, finish := make(chan struct{}), make(chan struct{})
+	var companyID uuid.UUID
+	r.POST("/slow", func(c *gin.Context) {
+		// Written in the transaction of the key, so it commits with the
+		// stored response or not at all.
+		tx, _ := idempotency.Tx(c)
+		company, err := companies.NewService(companies.NewRepository(tx)).Create(companies.Company{Name: "Idempotent"})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		companyID = company.ID
+		close(started)
+		<-finish
+		if c.Query("fail") == "true" {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed"})
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"id": company.ID})
+	})
+	send := func(key, path string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("POST", path, nil)
+		req.Header.Set("Idempotency-Key", key)
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+		return resp
+	}
+	exists := func(id uuid.UUID) bool {
+		var n int64
+		database.Model(&companies.Company{}).Where("id = ?", id).Count(&n)
+		return n > 0
+	}
+
+	// A retry gets 409 for as long as the first request runs.
+	key := uuid.New().String()
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- send(key, "/slow") }()
+	<-started
+	assert.Equal(t, http.StatusConflict, send(key, "/slow").Code)
+	assert.False(t, exists(companyID), "visible before the response was stored")
+	close(finish)
+	first := <-done
+	assert.Equal(t, http.StatusCreated, first.Code)
+	assert.True(t, exists(companyID))
+	retry := send(key, "/slow")
+	assert.Equal(t, "true", retry.Header().Get(idempotency.HeaderReplayed))
+	assert.Equal(t, first.Body.String(), retry.Body.String())
+
+	// A server error rolls back what the request wrote.
+	started, finish = make(chan struct{}), make(chan struct{})
+	close(finish)
+	assert.Equal(t, http.StatusInternalServerError, send(uuid.New().String(), "/slow?fail=true").Code)
+	assert.False(t, exists(companyID))
+}
+
+func TestOpenAPICoversEveryRoute(t *testing.T) {
+	r := setupRouter()
+
+	req, _ := http.NewRequest("GET", "/openapi.json", nil)
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	var doc openapi.Document
+	assert.Nil(t, json.Unmarshal(resp.Body.Bytes(), &doc))
+	assert.Equal(t, openapi.Version, doc.OpenAPI)
+	assert.Empty(t, openapi.Undocumented(r.Routes(), doc), "routes missing from the OpenAPI document")
+}
+
+func TestClientSDK(t *testing.T) {
+	srv := httptest.NewServer(setupRouter())
+	defer srv.Close()
+	c := client.New(srv.URL, client.WithHTTPClient(srv.Client()))
+	ctx := context.Background()
+
+	company, err := c.Companies.Create(ctx, client.CompanyInput{
+		Name:            "SDK Company",
+		BillingAddress:  "Unter den Linden 1, Berlin",
+		DefaultCurrency: "EUR",
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	contractor, err := c.Contractors.Create(ctx, client.ContractorInput{
+		Name:          "SDK Contractor",
+		Email:         "sdk@example.com",
+		TaxRegion:     "DE",
+		PayoutMethod:  "wise",
+		PayoutAccount: "sdk@example.com",
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	v1, err := c.Jobs.Create(ctx, client.JobInput{
+		Title:        "SDK Developer",
+		Status:       "active",
+		Rate:         30,
+		CompanyID:    company.ID,
+		ContractorID: contractor.ID,
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, 1, v1.Version)
+	assert.Equal(t, "EUR", v1.Currency)
+
+	fetched, err := c.Jobs.Get(ctx, v1.UID)
+	assert.Nil(t, err)
+	assert.Equal(t, v1.UID, fetched.UID)
+
+	in := v1.Input()
+	in.Rate = 35
+	v2, err := c.Jobs.Update(ctx, v1.UID, in, client.IfMatch(v1.ETag()))
+	assert.Nil(t, err)
+	assert.Equal(t, 2, v2.Version)
+	assert.Equal(t, 35.0, v2.Rate)
+
+	// A write based on the outdated first version is refused.
+	in.Rate = 40
+	_, err = c.Jobs.Update(ctx, v1.UID, in, client.IfMatch(v1.ETag()))
+	assert.True(t, errors.Is(err, client.ErrPreconditionFailed), "got %v", err)
+
+	v3, err := c.Jobs.Modify(ctx, v1.UID, func(in *client.JobInput) error {
+		in.Status = "paused"
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, v3.Version)
+	assert.Equal(t, 35.0, v3.Rate)
+
+	history, err := c.Jobs.History(ctx, v1.UID)
+	assert.Nil(t, err)
+	assert.Len(t, history, 3)
+
+	asOf, err := c.Jobs.GetAsOf(ctx, v3.UID, v2.CreatedAt)
+	assert.Nil(t, err)
+	assert.Equal(t, v2.UID, asOf.UID)
+
+	_, err = c.Jobs.Get(ctx, uuid.New())
+	assert.True(t, errors.Is(err, client.ErrNotFound), "got %v", err)
+
+	item, err := c.PaymentLineItems.Create(ctx, client.PaymentLineItemInput{
+		ContractorID: contractor.ID,
+		Amount:       12.5,
+		IssuedAt:     time.Now(),
+	})
+	assert.Nil(t, err)
+	assert.Nil(t, c.PaymentLineItems.Delete(ctx, item.UID, client.IfMatch(item.ETag())))
+	voided, err := c.PaymentLineItems.History(ctx, item.UID)
+	assert.Nil(t, err)
+	if assert.Len(t, voided, 2) {
+		assert.Equal(t, 0.0, voided[1].Amount)
+	}
+}
+
+func TestGRPCAPI(t *testing.T) {
+	services := router.NewServices()
+	company, err := services.Companies.Create(companies.Company{
+		Name: "gRPC Company", BillingAddress: "1 Rue de Rivoli, Paris", DefaultCurrency: "EUR",
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	contractor, err := services.Contractors.Create(contractors.Contractor{
+		Name: "gRPC Contractor", Email: "grpc@example.com", TaxRegion: "FR",
+		PayoutMethod: "bank_transfer", PayoutAccount: "FR7630006000011234567890189",
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	srv := grpc.NewServer()
+	router.RegisterGRPC(srv, services)
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if !assert.Nil(t, err) {
+		return
+	}
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer conn.Close()
+	jobsClient := scdv1.NewJobServiceClient(conn)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	companyID := company.ID.String()
+	events, err := scdv1.NewEventServiceClient(conn).Subscribe(ctx, &scdv1.SubscribeRequest{Entity: "jobs", CompanyId: companyID})
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	input := &scdv1.JobInput{
+		Title:        "gRPC Developer",
+		Status:       "active",
+		Rate:         20,
+		CompanyId:    companyID,
+		ContractorId: contractor.ID.String(),
+	}
+	v1, err := jobsClient.CreateJob(ctx, &scdv1.CreateJobRequest{Job: input})
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, int32(1), v1.Version)
+
+	event, err := events.Recv()
+	assert.Nil(t, err)
+	assert.Equal(t, v1.Uid, event.GetUid())
+
+	input.Rate = 25
+	v2, err := jobsClient.UpdateJob(ctx, &scdv1.UpdateJobRequest{Uid: v1.Uid, Job: input, ExpectedHeadUid: v1.Uid})
+	assert.Nil(t, err)
+	assert.Equal(t, int32(2), v2.GetVersion())
+
+	_, err = jobsClient.UpdateJob(ctx, &scdv1.UpdateJobRequest{Uid: v1.Uid, Job: input, ExpectedHeadUid: v1.Uid})
+	assert.Equal(t, codes.Aborted, status.Code(err))
+
+	input.Rate = 0
+	_, err = jobsClient.CreateJob(ctx, &scdv1.CreateJobRequest{Job: input})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	history, err := jobsClient.ListJobHistory(ctx, &scdv1.ListJobHistoryRequest{Uid: v1.Uid})
+	assert.Nil(t, err)
+	assert.Len(t, history.GetJobs(), 2)
+
+	current, err := jobsClient.ListCompanyJobs(ctx, &scdv1.ListCompanyJobsRequest{CompanyId: companyID})
+	assert.Nil(t, err)
+	if assert.Len(t, current.GetJobs(), 1) {
+		assert.Equal(t, v2.GetUid(), current.GetJobs()[0].GetUid())
+	}
+
+	_, err = jobsClient.GetJob(ctx, &scdv1.GetJobRequest{Uid: uuid.New().String()})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestGraphQL(t *testing.T) {
+	r := setupRouter()
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+		return resp
+	}
+	created := func(path, body string) string {
+		resp := send("POST", path, body)
+		assert.Equal(t, http.StatusCreated, resp.Code)
+		var v struct{ UID string }
+		json.Unmarshal(resp.Body.Bytes(), &v)
+		return v.UID
+	}
+
+	// Two jobs of the same contractor, each with a timelog and a line item.
+	companyID, contractorID := createCompany(t, r), createContractor(t, r)
+	v1 := created("/jobs", `{"title":"Graph Developer","status":"active","rate":30,"companyId":"`+companyID+`","contractorId":"`+contractorID+`"}`)
+	other := created("/jobs", `{"title":"Graph Reviewer","status":"active","rate":40,"companyId":"`+createCompany(t, r)+`","contractorId":"`+contractorID+`"}`)
+	timelogUID := created("/timelogs", `{"contractorId":"`+contractorID+`","jobUid":"`+v1+`","startTime":"2025-01-01T09:00:00Z","endTime":"2025-01-01T17:00:00Z"}`)
+	otherTimelog := created("/timelogs", `{"contractorId":"`+contractorID+`","jobUid":"`+other+`","startTime":"2025-01-02T09:00:00Z","endTime":"2025-01-02T17:00:00Z"}`)
+	created("/payment-line-items", `{"contractorId":"`+contractorID+`","timelogUid":"`+timelogUID+`","amount":240,"issuedAt":"2025-01-03T00:00:00Z"}`)
+	created("/payment-line-items", `{"contractorId":"`+contractorID+`","timelogUid":"`+otherTimelog+`","amount":320,"issuedAt":"2025-01-03T00:00:00Z"}`)
+	between := time.Now()
+
+	req, _ := http.NewRequest("PATCH", "/jobs/"+v1, bytes.NewBufferString(`{"rate":35}`))
+	req.Header.Set("Content-Type", "application/merge-patch+json")
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	// The first version as of before the raise, with the timelogs linked to it.
+	query, _ := json.Marshal(map[string]any{
+		"query": `query($company: ID!, $at: Time!) {
+			companyJobs(companyId: $company) {
+				version
+				rate
+				versions { version rate }
+				timelogs { uid paymentLineItems { amount } }
+				paymentLineItems { amount timelog { uid } job { rate } }
+				asOf(time: $at) { version rate timelogs { uid jobUid job { version } } }
+			}
+		}`,
+		"variables": map[string]any{"company": companyID, "at": between},
+	})
+	resp = send("POST", "/graphql", string(query))
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	type timelog struct {
+		UID              string
+		JobUID           string
+		Job              struct{ Version int }
+		PaymentLineItems []struct{ Amount float64 }
+	}
+	type job struct {
+		Version  int
+		Rate     float64
+		Versions []struct {
+			Version int
+			Rate    float64
+		}
+		Timelogs         []timelog
+		PaymentLineItems []struct {
+			Amount  float64
+			Timelog struct{ UID string }
+			Job     struct{ Rate float64 }
+		}
+		AsOf struct {
+			Version  int
+			Rate     float64
+			Timelogs []timelog
+		}
+	}
+	var result struct {
+		Data struct {
+			CompanyJobs []job
+		}
+		Errors []struct{ Message string }
+	}
+	json.Unmarshal(resp.Body.Bytes(), &result)
+	assert.Empty(t, result.Errors)
+	if assert.Len(t, result.Data.CompanyJobs, 1) {
+		got := result.Data.CompanyJobs[0]
+		assert.Equal(t, 2, got.Version)
+		assert.Equal(t, 35.0, got.Rate)
+		assert.Len(t, got.Versions, 2)
+		// The timelog links to version 1 and still belongs to the job.
+		if assert.Len(t, got.Timelogs, 1) {
+			assert.Equal(t, timelogUID, got.Timelogs[0].UID)
+			if assert.Len(t, got.Timelogs[0].PaymentLineItems, 1) {
+				assert.Equal(t, 240.0, got.Timelogs[0].PaymentLineItems[0].Amount)
+			}
+		}
+		if assert.Len(t, got.PaymentLineItems, 1) {
+			assert.Equal(t, 240.0, got.PaymentLineItems[0].Amount)
+			assert.Equal(t, timelogUID, got.PaymentLineItems[0].Timelog.UID)
+			assert.Equal(t, 35.0, got.PaymentLineItems[0].Job.Rate)
+		}
+		assert.Equal(t, 1, got.AsOf.Version)
+		assert.Equal(t, 30.0, got.AsOf.Rate)
+		if assert.Len(t, got.AsOf.Timelogs, 1) {
+			assert.Equal(t, v1, got.AsOf.Timelogs[0].JobUID)
+			assert.Equal(t, 1, got.AsOf.Timelogs[0].Job.Version)
+		}
+	}
+}
+
+func TestBitemporalCorrection(t *testing.T) {
+	r := setupRouter()
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+		return resp
+	}
+	at := func(d time.Duration) string {
+		return time.Now().UTC().Add(d).Format(time.RFC3339)
+	}
+
+	companyID, contractorID := createCompany(t, r), createContractor(t, r)
+	resp := send("POST", "/jobs", `{"title":"Analyst","status":"active","rate":20,"companyId":"`+companyID+`","contractorId":"`+contractorID+`","effectiveFrom":"`+at(-30*24*time.Hour)+`"}`)
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	var created jobs.JobResponse
+	json.Unmarshal(resp.Body.Bytes(), &created)
+	beforeCorrection := created.RecordedFrom.Add(time.Millisecond).Format(time.RFC3339Nano)
+
+	// A rate change that took effect ten days ago, recorded today.
+	resp = send("PATCH", "/jobs/"+created.UID.String(), `{"rate":25,"effectiveFrom":"`+at(-10*24*time.Hour)+`"}`)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var corrected jobs.JobResponse
+	json.Unmarshal(resp.Body.Bytes(), &corrected)
+	assert.Equal(t, 25.0, corrected.Rate)
+	assert.Nil(t, corrected.EffectiveTo)
+
+	get := func(query string) jobs.JobResponse {
+		resp := send("GET", "/jobs/"+created.UID.String()+"?"+query, "")
+		assert.Equal(t, http.StatusOK, resp.Code)
+		var j jobs.JobResponse
+		json.Unmarshal(resp.Body.Bytes(), &j)
+		return j
+	}
+	assert.Equal(t, 20.0, get("as_of="+at(-20*24*time.Hour)).Rate)
+	assert.Equal(t, 25.0, get("as_of="+at(-5*24*time.Hour)).Rate)
+	// Before the correction was recorded, the old rate was on record for then.
+	assert.Equal(t, 20.0, get("as_of="+at(-5*24*time.Hour)+"&known_at="+beforeCorrection).Rate)
+
+	// A title correction (Type 1) rewrites the versions on record, but not
+	// the one the rate correction took off the record.
+	resp = send("PATCH", "/jobs/"+corrected.UID.String(), `{"title":"Senior Analyst"}`)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "Senior Analyst", get("as_of="+at(-20*24*time.Hour)).Title)
+	assert.Equal(t, "Senior Analyst", get("as_of="+at(-5*24*time.Hour)).Title)
+	assert.Equal(t, "Analyst", get("as_of="+at(-5*24*time.Hour)+"&known_at="+beforeCorrection).Title)
+
+	resp = send("PATCH", "/jobs/"+corrected.UID.String(), `{"rate":30,"effectiveFrom":"`+at(-60*24*time.Hour)+`"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+}
+
+func TestTimelogExternalRefKeepsPrevious(t *testing.T) {
+	r := setupRouter()
+	send := func(method, path, body string) timelog.TimelogResponse {
+		req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+		assert.Contains(t, []int{http.StatusOK, http.StatusCreated}, resp.Code, resp.Body.String())
+		var out timelog.TimelogResponse
+		json.Unmarshal(resp.Body.Bytes(), &out)
+		return out
+	}
+	contractorID := createContractor(t, r)
+	body := func(start, ref string) string {
+		return `{"contractorId":"` + contractorID + `","startTime":"` + start + `","endTime":"2025-03-01T17:00:00Z","externalRef":"` + ref + `"}`
+	}
+	ref := func(n int) string { return fmt.Sprintf("TICKET-%d-%s", n, contractorID[:8]) }
+
+	v1 := send("POST", "/timelogs", body("2025-03-01T09:00:00Z", ref(1)))
+
+	// Only the reference changes: the head version is updated in place.
+	renamed := send("PUT", "/timelogs/"+v1.UID.String(), body("2025-03-01T09:00:00Z", ref(2)))
+	assert.Equal(t, v1.UID, renamed.UID)
+	assert.Equal(t, 1, renamed.Version)
+	assert.Equal(t, ref(2), renamed.ExternalRef)
+	assert.Equal(t, ref(1), renamed.PreviousExternalRef)
+
+	// With a versioned change the new version keeps the replaced reference.
+	v2 := send("PUT", "/timelogs/"+v1.UID.String(), body("2025-03-01T10:00:00Z", ref(3)))
+	assert.NotEqual(t, v1.UID, v2.UID)
+	assert.Equal(t, 2, v2.Version)
+	assert.Equal(t, ref(3), v2.ExternalRef)
+	assert.Equal(t, ref(2), v2.PreviousExternalRef)
+
+	// The version it replaced is closed with the reference it had then.
+	old := send("GET", "/timelogs/"+v1.UID.String(), "")
+	assert.Equal(t, ref(2), old.ExternalRef)
+}
+
+func TestScheduledVersion(t *testing.T) {
+	r := setupRouter()
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+		return resp
+	}
+
+	companyID, contractorID := createCompany(t, r), createContractor(t, r)
+	resp := send("POST", "/jobs", `{"title":"Designer","status":"active","rate":40,"companyId":"`+companyID+`","contractorId":"`+contractorID+`"}`)
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	var created jobs.JobResponse
+	json.Unmarshal(resp.Body.Bytes(), &created)
+
+	// New entities cannot start in the future.
+	nextMonth := time.Now().UTC().Add(30 * 24 * time.Hour).Format(time.RFC3339)
+	resp = send("POST", "/jobs", `{"title":"Designer","status":"active","rate":40,"companyId":"`+companyID+`","contractorId":"`+contractorID+`","effectiveFrom":"`+nextMonth+`"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+
+	resp = send("PATCH", "/jobs/"+created.UID.String(), `{"rate":45,"effectiveFrom":"`+nextMonth+`"}`)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var scheduled jobs.JobResponse
+	json.Unmarshal(resp.Body.Bytes(), &scheduled)
+	assert.Equal(t, 45.0, scheduled.Rate)
+
+	// The latest version stays the current one until the rate change is due.
+	resp = send("GET", "/companies/"+companyID+"/jobs", "")
+	var latest []jobs.JobResponse
+	json.Unmarshal(resp.Body.Bytes(), &latest)
+	if assert.Len(t, latest, 1) {
+		assert.Equal(t, created.UID, latest[0].UID)
+		assert.Equal(t, 40.0, latest[0].Rate)
+	}
+
+	resp = send("GET", "/jobs/scheduled", "")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var pending []jobs.JobResponse
+	json.Unmarshal(resp.Body.Bytes(), &pending)
+	var uids []uuid.UUID
+	for _, j := range pending {
+		uids = append(uids, j.UID)
+	}
+	assert.Contains(t, uids, scheduled.UID)
+
+	resp = send("DELETE", "/jobs/scheduled/"+scheduled.UID.String(), "")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	resp = send("DELETE", "/jobs/scheduled/"+scheduled.UID.String(), "")
+	assert.Equal(t, http.StatusConflict, resp.Code)
+	resp = send("DELETE", "/jobs/scheduled/"+created.UID.String(), "")
+	assert.Equal(t, http.StatusConflict, resp.Code)
+
+	resp = send("GET", "/jobs/"+created.UID.String()+"?as_of="+time.Now().UTC().Add(60*24*time.Hour).Format(time.RFC3339), "")
+	var later jobs.JobResponse
+	json.Unmarshal(resp.Body.Bytes(), &later)
+	assert.Equal(t, 40.0, later.Rate)
+}
+
+func TestRevertToEarlierVersion(t *testing.T) {
+	r := setupRouter()
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+		return resp
+	}
+
+	resp := send("POST", "/payment-line-items", `{"contractorId":"`+createContractor(t, r)+`","amount":120,"issuedAt":"2025-01-01T10:00:00Z"}`)
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	var v1 payment.PaymentLineItemResponse
+	json.Unmarshal(resp.Body.Bytes(), &v1)
+
+	resp = send("PATCH", "/payment-line-items/"+v1.UID.String(), `{"amount":1200}`)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp = send("POST", "/payment-line-items/"+v1.UID.String()+"/revert?to="+v1.UID.String(), `{}`)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	resp = send("POST", "/payment-line-items/"+v1.UID.String()+"/revert?to="+v1.UID.String(), `{"reason":"extra zero"}`)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var reverted payment.PaymentLineItemResponse
+	json.Unmarshal(resp.Body.Bytes(), &reverted)
+	assert.Equal(t, 3, reverted.Version)
+	assert.Equal(t, 120.0, reverted.Amount)
+	if assert.NotNil(t, reverted.RevertedFrom) {
+		assert.Equal(t, v1.UID, *reverted.RevertedFrom)
+	}
+	assert.Equal(t, "extra zero", reverted.RevertReason)
+
+	// The mistaken version stays in the history.
+	resp = send("GET", "/payment-line-items/"+v1.UID.String()+"/history", "")
+	var history []payment.PaymentLineItemResponse
+	json.Unmarshal(resp.Body.Bytes(), &history)
+	if assert.Len(t, history, 3) {
+		assert.Equal(t, 1200.0, history[1].Amount)
+	}
+
+	other := send("POST", "/payment-line-items", `{"contractorId":"`+createContractor(t, r)+`","amount":5,"issuedAt":"2025-01-01T10:00:00Z"}`)
+	var foreign payment.PaymentLineItemResponse
+	json.Unmarshal(other.Body.Bytes(), &foreign)
+	resp = send("POST", "/payment-line-items/"+v1.UID.String()+"/revert?to="+foreign.UID.String(), `{"reason":"wrong entity"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+}
+
+func TestCorrectionAfterRevert(t *testing.T) {
+	r := setupRouter()
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		if method == "PATCH" {
+			req.Header.Set("Content-Type", "application/merge-patch+json")
+		}
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+		return resp
+	}
+	resp := send("POST", "/jobs", `{"title":"Reverted","status":"active","rate":10,"companyId":"`+createCompany(t, r)+`","contractorId":"`+createContractor(t, r)+`"}`)
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	var v1 jobs.JobResponse
+	json.Unmarshal(resp.Body.Bytes(), &v1)
+	assert.Equal(t, http.StatusOK, send("PATCH", "/jobs/"+v1.UID.String(), `{"rate":100}`).Code)
+	resp = send("POST", "/jobs/"+v1.UID.String()+"/revert?to="+v1.UID.String(), `{"reason":"extra zero"}`)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var reverted jobs.JobResponse
+	json.Unmarshal(resp.Body.Bytes(), &reverted)
+
+	// A title correction on the reverted head is still made in place: the
+	// reversion columns, which the next version would not carry, are not a
+	// change.
+	resp = send("PATCH", "/jobs/"+v1.UID.String(), `{"title":"Reverted job"}`)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var corrected jobs.JobResponse
+	json.Unmarshal(resp.Body.Bytes(), &corrected)
+	assert.Equal(t, reverted.UID, corrected.UID)
+	assert.Equal(t, 3, corrected.Version)
+	assert.Equal(t, "Reverted job", corrected.Title)
+	if assert.NotNil(t, corrected.RevertedFrom) {
+		assert.Equal(t, v1.UID, *corrected.RevertedFrom)
+	}
+	var history []jobs.JobResponse
+	json.Unmarshal(send("GET", "/jobs/"+v1.UID.String()+"/history", "").Body.Bytes(), &history)
+	assert.Len(t, history, 3)
+
+	// A versioned change after it does not carry the reversion.
+	resp = send("PATCH", "/jobs/"+v1.UID.String(), `{"rate":12}`)
+	var next jobs.JobResponse
+	json.Unmarshal(resp.Body.Bytes(), &next)
+	assert.Equal(t, 4, next.Version)
+	assert.Nil(t, next.RevertedFrom)
+}
+
+func TestCascadeRelationships(t *testing.T) {
+	r := setupRouter()
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+		return resp
+	}
+	contractor := createContractor(t, r)
+
+	resp := send("POST", "/jobs", `{"title":"Cascade","status":"active","rate":20,"companyId":"`+createCompany(t, r)+`","contractorId":"`+contractor+`"}`)
+	var job jobs.JobResponse
+	json.Unmarshal(resp.Body.Bytes(), &job)
+	resp = send("POST", "/timelogs", `{"contractorId":"`+contractor+`","jobUid":"`+job.UID.String()+`","startTime":"2025-01-01T10:00:00Z","endTime":"2025-01-01T12:00:00Z"}`)
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	var tl timelog.TimelogResponse
+	json.Unmarshal(resp.Body.Bytes(), &tl)
+	resp = send("POST", "/payment-line-items", `{"contractorId":"`+contractor+`","timelogUid":"`+tl.UID.String()+`","amount":40,"issuedAt":"2025-01-02T00:00:00Z"}`)
+	assert.Equal(t, http.StatusCreated, resp.Code)
+
+	// A new job version re-points the timelog at it.
+	resp = send("PATCH", "/jobs/"+job.UID.String(), `{"rate":25}`)
+	var job2 jobs.JobResponse
+	json.Unmarshal(resp.Body.Bytes(), &job2)
+	resp = send("GET", "/jobs/"+job.UID.String()+"/timelogs", "")
+	var linked []timelog.TimelogResponse
+	json.Unmarshal(resp.Body.Bytes(), &linked)
+	if assert.Len(t, linked, 1) {
+		assert.Equal(t, 2, linked[0].Version)
+		assert.Equal(t, &job2.UID, linked[0].JobUID)
+	}
+
+	// A scheduled job version leaves the timelog linked to the version in
+	// effect; it is still found through the job's ID.
+	resp = send("PATCH", "/jobs/"+job.UID.String(), `{"rate":30,"effectiveFrom":"`+time.Now().Add(24*time.Hour).UTC().Format(time.RFC3339)+`"}`)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var scheduled jobs.JobResponse
+	json.Unmarshal(resp.Body.Bytes(), &scheduled)
+	resp = send("GET", "/jobs/"+scheduled.UID.String()+"/timelogs", "")
+	linked = nil
+	json.Unmarshal(resp.Body.Bytes(), &linked)
+	if assert.Len(t, linked, 1) {
+		assert.Equal(t, 2, linked[0].Version)
+		assert.Equal(t, &job2.UID, linked[0].JobUID)
+	}
+
+	// The line item keeps the timelog version it paid for and is found
+	// through the timelog's ID.
+	resp = send("GET", "/timelogs/"+tl.UID.String()+"/payment-line-items", "")
+	var items []payment.PaymentLineItemResponse
+	json.Unmarshal(resp.Body.Bytes(), &items)
+	if assert.Len(t, items, 1) {
+		assert.Equal(t, 1, items[0].Version)
+		assert.Equal(t, &tl.UID, items[0].TimelogUID)
+	}
+}
+
+func TestContractorProfileAndReferences(t *testing.T) {
+	r := setupRouter()
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+		return resp
+	}
+
+	resp := send("POST", "/contractors", `{"name":"Payee","email":"not-an-email","taxRegion":"GB","payoutMethod":"bank_transfer","payoutAccount":"GB00"}`)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	resp = send("POST", "/contractors", `{"name":"Payee","email":"payee@example.com","taxRegion":"GB","payoutMethod":"bank_transfer","payoutAccount":"GB00"}`)
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	var v1 contractors.ContractorResponse
+	json.Unmarshal(resp.Body.Bytes(), &v1)
+
+	// New payout details are a new version; the old ones stay in the history.
+	resp = send("PATCH", "/contractors/"+v1.UID.String(), `{"payoutMethod":"paypal","payoutAccount":"payee@example.com"}`)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	resp = send("GET", "/contractors/"+v1.UID.String()+"/history", "")
+	var history []contractors.ContractorResponse
+	json.Unmarshal(resp.Body.Bytes(), &history)
+	if assert.Len(t, history, 2) {
+		assert.Equal(t, "bank_transfer", history[0].PayoutMethod)
+		assert.Equal(t, "paypal", history[1].PayoutMethod)
+		assert.Equal(t, v1.ID, history[1].ID)
+	}
+
+	// References to contractors that do not exist are rejected.
+	unknown := uuid.New().String()
+	resp = send("POST", "/jobs", `{"title":"Orphan","status":"active","rate":10,"companyId":"`+createCompany(t, r)+`","contractorId":"`+unknown+`"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	resp = send("POST", "/timelogs", `{"contractorId":"`+unknown+`","startTime":"2025-01-01T10:00:00Z","endTime":"2025-01-01T11:00:00Z"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+
+	resp = send("POST", "/payment-line-items", `{"contractorId":"`+v1.ID.String()+`","amount":10,"issuedAt":"2025-01-01T10:00:00Z"}`)
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	var item payment.PaymentLineItemResponse
+	json.Unmarshal(resp.Body.Bytes(), &item)
+	resp = send("PATCH", "/payment-line-items/"+item.UID.String(), `{"contractorId":"`+unknown+`"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+}
+
+func TestCompanySettingsAndJobDefaults(t *testing.T) {
+	r := setupRouter()
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+		return resp
+	}
+
+	resp := send("POST", "/companies", `{"name":"Globex","billingAddress":"2 Harbour Rd, Dublin","defaultCurrency":"euro","paymentTermsDays":30}`)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	resp = send("POST", "/companies", `{"name":"Globex","billingAddress":"2 Harbour Rd, Dublin","defaultCurrency":"EUR","paymentTermsDays":30}`)
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	var v1 companies.CompanyResponse
+	json.Unmarshal(resp.Body.Bytes(), &v1)
+	contractorID := createContractor(t, r)
+
+	// Jobs take the default currency of their company unless they name one.
+	resp = send("POST", "/jobs", `{"title":"Support","status":"active","rate":25,"companyId":"`+v1.ID.String()+`","contractorId":"`+contractorID+`"}`)
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	var defaulted jobs.JobResponse
+	json.Unmarshal(resp.Body.Bytes(), &defaulted)
+	assert.Equal(t, "EUR", defaulted.Currency)
+	resp = send("POST", "/jobs", `{"title":"Support","status":"active","rate":25,"currency":"USD","companyId":"`+v1.ID.String()+`","contractorId":"`+contractorID+`"}`)
+	var explicit jobs.JobResponse
+	json.Unmarshal(resp.Body.Bytes(), &explicit)
+	assert.Equal(t, "USD", explicit.Currency)
+
+	// A new default applies to jobs created afterwards; existing jobs keep
+	// their currency, also when they are updated.
+	resp = send("PUT", "/companies/"+v1.UID.String(), `{"name":"Globex","billingAddress":"2 Harbour Rd, Dublin","defaultCurrency":"GBP","paymentTermsDays":45}`)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	resp = send("GET", "/companies/"+v1.UID.String()+"/history", "")
+	var history []companies.CompanyResponse
+	json.Unmarshal(resp.Body.Bytes(), &history)
+	if assert.Len(t, history, 2) {
+		assert.Equal(t, 30, history[0].PaymentTermsDays)
+		assert.Equal(t, "GBP", history[1].DefaultCurrency)
+	}
+	resp = send("PATCH", "/jobs/"+defaulted.UID.String(), `{"rate":30}`)
+	var patched jobs.JobResponse
+	json.Unmarshal(resp.Body.Bytes(), &patched)
+	assert.Equal(t, "EUR", patched.Currency)
+	resp = send("POST", "/jobs", `{"title":"Support","status":"active","rate":25,"companyId":"`+v1.ID.String()+`","contractorId":"`+contractorID+`"}`)
+	var later jobs.JobResponse
+	json.Unmarshal(resp.Body.Bytes(), &later)
+	assert.Equal(t, "GBP", later.Currency)
+
+	resp = send("GET", "/companies/"+v1.ID.String()+"/jobs", "")
+	var listed []jobs.JobResponse
+	json.Unmarshal(resp.Body.Bytes(), &listed)
+	assert.Len(t, listed, 3)
+
+	// Jobs of companies that do not exist are rejected.
+	resp = send("POST", "/jobs", `{"title":"Orphan","status":"active","rate":10,"companyId":"`+uuid.New().String()+`","contractorId":"`+contractorID+`"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+}
+
+func TestInvoiceGeneration(t *testing.T) {
+	r := setupRouter()
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+		return resp
+	}
+	companyID := createCompany(t, r)
+	contractorID := createContractor(t, r)
+
+	// Two approved line items in different currencies and a pending one.
+	item := func(currency, status string, amount float64) payment.PaymentLineItemResponse {
+		resp := send("POST", "/jobs", `{"title":"Design","status":"active","rate":20,"currency":"`+currency+`","companyId":"`+companyID+`","contractorId":"`+contractorID+`"}`)
+		var j jobs.JobResponse
+		json.Unmarshal(resp.Body.Bytes(), &j)
+		resp = send("POST", "/timelogs", `{"contractorId":"`+contractorID+`","jobUid":"`+j.UID.String()+`","startTime":"2025-03-03T10:00:00Z","endTime":"2025-03-03T12:00:00Z"}`)
+		var tl timelog.TimelogResponse
+		json.Unmarshal(resp.Body.Bytes(), &tl)
+		resp = send("POST", "/payment-line-items", fmt.Sprintf(`{"contractorId":"%s","timelogUid":"%s","amount":%g,"issuedAt":"2025-03-04T00:00:00Z","status":"%s"}`, contractorID, tl.UID, amount, status))
+		assert.Equal(t, http.StatusCreated, resp.Code)
+		var created payment.PaymentLineItemResponse
+		json.Unmarshal(resp.Body.Bytes(), &created)
+		return created
+	}
+	usd := item("USD", "approved", 40)
+	item("EUR", "approved", 12.5)
+	pending := item("USD", "pending", 99)
+
+	period := `"companyId":"` + companyID + `","periodStart":"2025-03-01T00:00:00Z","periodEnd":"2025-04-01T00:00:00Z"`
+	resp := send("POST", "/invoices", `{`+period+`}`)
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	var inv invoices.InvoiceResponse
+	json.Unmarshal(resp.Body.Bytes(), &inv)
+	assert.Equal(t, invoices.StatusDraft, inv.Status)
+	assert.True(t, strings.HasPrefix(inv.Number, "INV-"))
+	if assert.Len(t, inv.Lines, 2) {
+		for _, l := range inv.Lines {
+			assert.NotEqual(t, pending.UID, l.LineItemUID)
+		}
+	}
+	assert.Equal(t, []invoices.Total{{Currency: "EUR", Amount: 12.5}, {Currency: "USD", Amount: 40}}, inv.Totals)
+
+	// Billed line items are not invoiced again, and later versions of them
+	// leave the invoice as it was.
+	resp = send("POST", "/invoices", `{`+period+`}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	resp = send("PATCH", "/payment-line-items/"+usd.UID.String(), `{"amount":45}`)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	resp = send("GET", "/invoices/"+inv.UID.String(), "")
+	var again invoices.InvoiceResponse
+	json.Unmarshal(resp.Body.Bytes(), &again)
+	assert.Equal(t, inv.Totals, again.Totals)
+
+	resp = send("PUT", "/invoices/"+inv.UID.String()+"/status?status=paid", "")
+	assert.Equal(t, http.StatusConflict, resp.Code)
+	resp = send("PUT", "/invoices/"+inv.UID.String()+"/status?status=issued", "")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var issued invoices.InvoiceResponse
+	json.Unmarshal(resp.Body.Bytes(), &issued)
+	assert.Equal(t, 2, issued.Version)
+	if assert.NotNil(t, issued.DueAt) {
+		assert.Equal(t, issued.IssuedAt.AddDate(0, 0, 30).Unix(), issued.DueAt.Unix())
+	}
+
+	resp = send("GET", "/invoices/"+issued.UID.String()+"/pdf", "")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "application/pdf", resp.Header().Get("Content-Type"))
+	assert.True(t, bytes.HasPrefix(resp.Body.Bytes(), []byte("%PDF-")))
+
+	// Voiding releases the line items for another invoice.
+	resp = send("PUT", "/invoices/"+issued.UID.String()+"/status?status=void", "")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	resp = send("POST", "/invoices", `{`+period+`}`)
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	var reissued invoices.InvoiceResponse
+	json.Unmarshal(resp.Body.Bytes(), &reissued)
+	assert.Greater(t, reissued.Number, inv.Number)
+	assert.Equal(t, []invoices.Total{{Currency: "EUR", Amount: 12.5}, {Currency: "USD", Amount: 45}}, reissued.Totals)
+
+	resp = send("GET", "/companies/"+companyID+"/invoices", "")
+	var listed []invoices.InvoiceResponse
+	json.Unmarshal(resp.Body.Bytes(), &listed)
+	if assert.Len(t, listed, 2) {
+		assert.Equal(t, reissued.UID, listed[0].UID)
+		assert.Equal(t, invoices.StatusVoid, listed[1].Status)
+	}
+}
+
+func TestInvoiceUnlinkedLineItems(t *testing.T) {
+	r := setupRouter()
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+		return resp
+	}
+	companyID := createCompany(t, r)
+
+	// A line item without a timelog is left out even when its contractor has
+	// a single job, and is reported; a linked one is billed.
+	contractorID := createContractor(t, r)
+	resp := send("POST", "/jobs", `{"title":"Support","status":"active","rate":20,"currency":"USD","companyId":"`+companyID+`","contractorId":"`+contractorID+`"}`)
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	var job jobs.JobResponse
+	json.Unmarshal(resp.Body.Bytes(), &job)
+	resp = send("POST", "/timelogs", `{"contractorId":"`+contractorID+`","jobUid":"`+job.UID.String()+`","startTime":"2025-05-03T10:00:00Z","endTime":"2025-05-03T12:00:00Z"}`)
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	var tl timelog.TimelogResponse
+	json.Unmarshal(resp.Body.Bytes(), &tl)
+	resp = send("POST", "/payment-line-items", `{"contractorId":"`+contractorID+`","timelogUid":"`+tl.UID.String()+`","amount":40,"issuedAt":"2025-05-04T00:00:00Z","status":"approved"}`)
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	var billed payment.PaymentLineItemResponse
+	json.Unmarshal(resp.Body.Bytes(), &billed)
+	resp = send("POST", "/payment-line-items", `{"contractorId":"`+contractorID+`","amount":30,"issuedAt":"2025-05-04T00:00:00Z","status":"approved"}`)
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	var skipped payment.PaymentLineItemResponse
+	json.Unmarshal(resp.Body.Bytes(), &skipped)
+
+	resp = send("POST", "/invoices", `{"companyId":"`+companyID+`","periodStart":"2025-05-01T00:00:00Z","periodEnd":"2025-06-01T00:00:00Z"}`)
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	var inv invoices.InvoiceResponse
+	json.Unmarshal(resp.Body.Bytes(), &inv)
+	if assert.Len(t, inv.Lines, 1) {
+		assert.Equal(t, billed.UID, inv.Lines[0].LineItemUID)
+		assert.Equal(t, "Support", inv.Lines[0].Description)
+	}
+	assert.Equal(t, []uuid.UUID{skipped.UID}, inv.UnlinkedLineItems)
+
+	// Once linked, the line item is billed by the next invoice.
+	resp = send("POST", "/timelogs", `{"contractorId":"`+contractorID+`","jobUid":"`+job.UID.String()+`","startTime":"2025-05-05T10:00:00Z","endTime":"2025-05-05T11:00:00Z"}`)
+	json.Unmarshal(resp.Body.Bytes(), &tl)
+	resp = send("PATCH", "/payment-line-items/"+skipped.UID.String(), `{"timelogUid":"`+tl.UID.String()+`"}`)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	resp = send("POST", "/invoices", `{"companyId":"`+companyID+`","periodStart":"2025-05-01T00:00:00Z","periodEnd":"2025-06-01T00:00:00Z"}`)
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	inv = invoices.InvoiceResponse{}
+	json.Unmarshal(resp.Body.Bytes(), &inv)
+	if assert.Len(t, inv.Lines, 1) {
+		assert.Equal(t, skipped.ID, inv.Lines[0].LineItemID)
+	}
+	assert.Empty(t, inv.UnlinkedLineItems)
+}
+
+func TestContractorStatement(t *testing.T) {
+	r := setupRouter()
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+		return resp
+	}
+	companyID, contractorID := createCompany(t, r), createContractor(t, r)
+
+	// The rate goes from 20 to 30 on March 10th.
+	resp := send("POST", "/jobs", `{"title":"Review","status":"active","rate":20,"currency":"USD","companyId":"`+companyID+`","contractorId":"`+contractorID+`","effectiveFrom":"2025-01-01T00:00:00Z"}`)
+	var job jobs.JobResponse
+	json.Unmarshal(resp.Body.Bytes(), &job)
+	resp = send("PATCH", "/jobs/"+job.UID.String(), `{"rate":30,"effectiveFrom":"2025-03-10T00:00:00Z"}`)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	logTime := func(start, end string) timelog.TimelogResponse {
+		resp := send("POST", "/timelogs", `{"contractorId":"`+contractorID+`","jobUid":"`+job.UID.String()+`","startTime":"`+start+`","endTime":"`+end+`"}`)
+		assert.Equal(t, http.StatusCreated, resp.Code)
+		var tl timelog.TimelogResponse
+		json.Unmarshal(resp.Body.Bytes(), &tl)
+		return tl
+	}
+	logTime("2025-02-10T10:00:00Z", "2025-02-10T12:00:00Z") // 40, before the period
+	march := logTime("2025-03-05T09:00:00Z", "2025-03-05T12:00:00Z")
+	later := logTime("2025-03-15T10:00:00Z", "2025-03-15T11:00:00Z")
+	resp = send("POST", "/payment-line-items", `{"contractorId":"`+contractorID+`","timelogUid":"`+march.UID.String()+`","amount":50,"issuedAt":"2025-03-20T00:00:00Z","status":"approved"}`)
+	var paid payment.PaymentLineItemResponse
+	json.Unmarshal(resp.Body.Bytes(), &paid)
+
+	get := func(query string) statements.Statement {
+		resp := send("GET", "/contractors/"+contractorID+"/statements?from=2025-03-01T00:00:00Z&to=2025-04-01T00:00:00Z"+query, "")
+		assert.Equal(t, http.StatusOK, resp.Code)
+		var st statements.Statement
+		json.Unmarshal(resp.Body.Bytes(), &st)
+		return st
+	}
+	// Approval alone does not pay a line item; the invoice billing it being
+	// paid does.
+	resp = send("POST", "/invoices", `{"companyId":"`+companyID+`","periodStart":"2025-03-01T00:00:00Z","periodEnd":"2025-04-01T00:00:00Z"}`)
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	var inv invoices.InvoiceResponse
+	json.Unmarshal(resp.Body.Bytes(), &inv)
+	if st := get(""); assert.Len(t, st.Entries, 3) {
+		assert.Equal(t, statements.StateIssued, st.Entries[2].Status)
+		assert.Equal(t, 0.0, st.Summary[0].Paid)
+	}
+	for _, status := range []string{invoices.StatusIssued, invoices.StatusPaid} {
+		resp = send("PUT", "/invoices/"+inv.UID.String()+"/status?status="+status, "")
+		assert.Equal(t, http.StatusOK, resp.Code)
+		json.Unmarshal(resp.Body.Bytes(), &inv)
+	}
+
+	resp = send("POST", "/payment-line-items", `{"contractorId":"`+contractorID+`","timelogUid":"`+later.UID.String()+`","amount":30,"issuedAt":"2025-03-21T00:00:00Z"}`)
+	var pending payment.PaymentLineItemResponse
+	json.Unmarshal(resp.Body.Bytes(), &pending)
+	issuedAt := pending.RecordedFrom.Add(time.Millisecond).Format(time.RFC3339Nano)
+
+	st := get("")
+	if assert.Len(t, st.Entries, 4) {
+		assert.Equal(t, 20.0, st.Entries[0].Rate)
+		assert.Equal(t, 60.0, st.Entries[0].Amount)
+		assert.Equal(t, 30.0, st.Entries[1].Rate)
+		assert.Equal(t, statements.StatePaid, st.Entries[2].Status)
+		assert.Equal(t, statements.StateIssued, st.Entries[3].Status)
+		assert.Equal(t, 80.0, st.Entries[3].Balance)
+	}
+	assert.Equal(t, []statements.Summary{{Currency: "USD", Opening: 40, Earned: 90, Paid: 50, Issued: 30, Closing: 80}}, st.Summary)
+
+	// A correction after the statement was issued shows up in the current
+	// statement but not when it is reproduced as of its issue date.
+	resp = send("PATCH", "/payment-line-items/"+paid.UID.String(), `{"amount":55}`)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, 75.0, get("").Summary[0].Closing)
+	assert.Equal(t, st.Summary, get("&known_at="+issuedAt).Summary)
+
+	resp = send("GET", "/contractors/"+contractorID+"/statements?from=2025-03-01T00:00:00Z&to=2025-04-01T00:00:00Z&format=csv", "")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "text/csv", resp.Header().Get("Content-Type"))
+	assert.Len(t, strings.Split(strings.TrimSpace(resp.Body.String()), "\n"), 6)
+	resp = send("GET", "/contractors/"+contractorID+"/statements?from=2025-03-01T00:00:00Z&to=2025-04-01T00:00:00Z&format=pdf", "")
+	assert.True(t, bytes.HasPrefix(resp.Body.Bytes(), []byte("%PDF-")))
+
+	resp = send("GET", "/contractors/"+contractorID+"/statements?from=2025-04-01T00:00:00Z&to=2025-03-01T00:00:00Z", "")
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+func TestReconciliation(t *testing.T) {
+	r := setupRouter()
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+		return resp
+	}
+	contractorID := createContractor(t, r)
+
+	// The rate goes from 20 to 30 on March 10th.
+	resp := send("POST", "/jobs", `{"title":"Review","status":"active","rate":20,"currency":"USD","companyId":"`+createCompany(t, r)+`","contractorId":"`+contractorID+`","effectiveFrom":"2025-01-01T00:00:00Z"}`)
+	var job jobs.JobResponse
+	json.Unmarshal(resp.Body.Bytes(), &job)
+	resp = send("PATCH", "/jobs/"+job.UID.String(), `{"rate":30,"effectiveFrom":"2025-03-10T00:00:00Z"}`)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	logTime := func(start, end string) timelog.TimelogResponse {
+		resp := send("POST", "/timelogs", `{"contractorId":"`+contractorID+`","jobUid":"`+job.UID.String()+`","startTime":"`+start+`","endTime":"`+end+`"}`)
+		assert.Equal(t, http.StatusCreated, resp.Code)
+		var tl timelog.TimelogResponse
+		json.Unmarshal(resp.Body.Bytes(), &tl)
+		return tl
+	}
+	pay := func(timelogUID string, amount string) {
+		link := ""
+		if timelogUID != "" {
+			link = `"timelogUid":"` + timelogUID + `",`
+		}
+		resp := send("POST", "/payment-line-items", `{"contractorId":"`+contractorID+`",`+link+`"amount":`+amount+`,"issuedAt":"2025-03-20T00:00:00Z"}`)
+		assert.Equal(t, http.StatusCreated, resp.Code)
+	}
+	pay(logTime("2025-03-02T09:00:00Z", "2025-03-02T11:00:00Z").UID.String(), "40") // paid in full
+	pay(logTime("2025-03-03T09:00:00Z", "2025-03-03T10:00:00Z").UID.String(), "10") // underpaid by 10
+	pay(logTime("2025-03-12T09:00:00Z", "2025-03-12T10:00:00Z").UID.String(), "35") // overpaid by 5 at the new rate
+	unpaid := logTime("2025-03-13T09:00:00Z", "2025-03-13T11:00:00Z")
+	pay("", "15")
+	// Time without a job cannot be priced, so it is reported and so is the
+	// line item paying for it.
+	resp = send("POST", "/timelogs", `{"contractorId":"`+contractorID+`","startTime":"2025-03-14T09:00:00Z","endTime":"2025-03-14T10:00:00Z"}`)
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	var unlinked timelog.TimelogResponse
+	json.Unmarshal(resp.Body.Bytes(), &unlinked)
+	pay(unlinked.UID.String(), "8")
+
+	resp = send("GET", "/reconciliation?contractor_id="+contractorID, "")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var rep reconciliation.Report
+	json.Unmarshal(resp.Body.Bytes(), &rep)
+	assert.Equal(t, 4, rep.Checked)
+	if assert.Len(t, rep.Issues, 6) {
+		assert.Equal(t, reconciliation.KindUnderpaid, rep.Issues[0].Kind)
+		assert.Equal(t, -10.0, rep.Issues[0].Difference)
+		assert.Equal(t, reconciliation.KindOverpaid, rep.Issues[1].Kind)
+		assert.Equal(t, 30.0, rep.Issues[1].Rate)
+		assert.Equal(t, 5.0, rep.Issues[1].Difference)
+		assert.Equal(t, reconciliation.KindUnpaidTime, rep.Issues[2].Kind)
+		assert.Equal(t, &unpaid.UID, rep.Issues[2].TimelogUID)
+		assert.Equal(t, 60.0, rep.Issues[2].Expected)
+		assert.Equal(t, reconciliation.KindUnlinkedTime, rep.Issues[3].Kind)
+		assert.Equal(t, &unlinked.UID, rep.Issues[3].TimelogUID)
+		assert.Equal(t, 1.0, rep.Issues[3].Hours)
+		assert.Equal(t, reconciliation.KindOrphanedPayment, rep.Issues[4].Kind)
+		assert.Equal(t, reconciliation.KindOrphanedPayment, rep.Issues[5].Kind)
+		assert.ElementsMatch(t, []float64{15, 8}, []float64{rep.Issues[4].Paid, rep.Issues[5].Paid})
+	}
+	assert.Contains(t, rep.Totals, reconciliation.Total{Kind: reconciliation.KindUnpaidTime, Currency: "USD", Count: 1, Amount: 60})
+
+	resp = send("GET", "/reconciliation?contractor_id="+contractorID+"&format=csv", "")
+	assert.Equal(t, "text/csv", resp.Header().Get("Content-Type"))
+	assert.Len(t, strings.Split(strings.TrimSpace(resp.Body.String()), "\n"), 7)
+
+	resp = send("GET", "/reconciliation?company_id=nope", "")
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+func TestVerify(t *testing.T) {
+	r := setupRouter()
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+		return resp
+	}
+	resp := send("POST", "/jobs", `{"title":"Audit","status":"active","rate":20,"companyId":"`+createCompany(t, r)+`","contractorId":"`+createContractor(t, r)+`"}`)
+	var v1 jobs.JobResponse
+	json.Unmarshal(resp.Body.Bytes(), &v1)
+	resp = send("PATCH", "/jobs/"+v1.UID.String(), `{"rate":25}`)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	// A job cannot move to another company.
+	resp = send("PATCH", "/jobs/"+v1.UID.String(), `{"companyId":"`+createCompany(t, r)+`"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+
+	// Reopen the first version, as a faulty writer might have.
+	assert.NoError(t, db.Connect().Table("jobs").Where("uid = ?", v1.UID).UpdateColumn("effective_to", nil).Error)
+
+	heads := func(method, path string) *scd.Violation {
+		resp := send(method, path, "")
+		assert.Equal(t, http.StatusOK, resp.Code)
+		var rep verify.Report
+		json.Unmarshal(resp.Body.Bytes(), &rep)
+		for _, v := range rep.Violations {
+			if v.ID == v1.ID.String() {
+				assert.Equal(t, scd.ViolationMultipleHeads, v.Kind)
+				return &v
+			}
+		}
+		return nil
+	}
+	found := heads("GET", "/admin/verify?entity=jobs")
+	if assert.NotNil(t, found) {
+		assert.Len(t, found.UIDs, 2)
+		assert.True(t, found.Repairable)
+		assert.False(t, found.Repaired)
+	}
+	repaired := heads("POST", "/admin/verify/repair?entity=jobs")
+	if assert.NotNil(t, repaired) {
+		assert.True(t, repaired.Repaired)
+	}
+	assert.Nil(t, heads("GET", "/admin/verify?entity=jobs"))
+
+	resp = send("GET", "/admin/verify?entity=nope", "")
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+func TestCompaction(t *testing.T) {
+	r := setupRouter()
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		if method == "PATCH" {
+			req.Header.Set("Content-Type", "application/merge-patch+json")
+		}
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+		return resp
+	}
+	// A job whose rate changed twice in January 2019 and again in March,
+	// corrected after the fact.
+	oldJob := func() jobs.JobResponse {
+		resp := send("POST", "/jobs", `{"title":"Archive","status":"active","rate":10,"companyId":"`+createCompany(t, r)+`","contractorId":"`+createContractor(t, r)+`","effectiveFrom":"2019-01-01T00:00:00Z"}`)
+		assert.Equal(t, http.StatusCreated, resp.Code)
+		var job jobs.JobResponse
+		json.Unmarshal(resp.Body.Bytes(), &job)
+		for _, change := range []string{`"rate":11,"effectiveFrom":"2019-01-10T00:00:00Z"`, `"rate":12,"effectiveFrom":"2019-01-20T00:00:00Z"`, `"rate":13,"effectiveFrom":"2019-03-01T00:00:00Z"`} {
+			resp = send("PATCH", "/jobs/"+job.UID.String(), "{"+change+"}")
+			assert.Equal(t, http.StatusOK, resp.Code)
+		}
+		return job
+	}
+	history := func(job jobs.JobResponse) []jobs.JobResponse {
+		var list []jobs.JobResponse
+		json.Unmarshal(send("GET", "/jobs/"+job.UID.String()+"/history", "").Body.Bytes(), &list)
+		return list
+	}
+	compacted, held := oldJob(), oldJob()
+	assert.Len(t, history(compacted), 7)
+
+	resp := send("POST", "/admin/legal-holds", `{"entity":"jobs","entityId":"`+held.ID.String()+`","reason":"litigation"}`)
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	var hold struct{ ID uuid.UUID }
+	json.Unmarshal(resp.Body.Bytes(), &hold)
+	resp = send("POST", "/admin/legal-holds", `{"entity":"jobs","entityId":"`+held.ID.String()+`","reason":"again"}`)
+	assert.Equal(t, http.StatusConflict, resp.Code)
+
+	resp = send("POST", "/admin/compact?entity=jobs&dry_run=true", "")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var rep retention.Report
+	json.Unmarshal(resp.Body.Bytes(), &rep)
+	if assert.Len(t, rep.Compactions, 1) {
+		assert.GreaterOrEqual(t, rep.Compactions[0].Removed, 2)
+		assert.GreaterOrEqual(t, rep.Compactions[0].Held, 1)
+	}
+	assert.Len(t, history(compacted), 7)
+
+	// The January versions on record collapse into the last one; the
+	// restated versions and the held job stay as they were.
+	resp = send("POST", "/admin/compact?entity=jobs", "")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Len(t, history(compacted), 5)
+	assert.Len(t, history(held), 7)
+	resp = send("GET", "/jobs/"+compacted.UID.String()+"?as_of=2019-01-05T00:00:00Z", "")
+	var asOf jobs.JobResponse
+	json.Unmarshal(resp.Body.Bytes(), &asOf)
+	assert.Equal(t, 12.0, asOf.Rate)
+
+	// Verification counts the archived versions.
+	resp = send("GET", "/admin/verify?entity=jobs", "")
+	var verified verify.Report
+	json.Unmarshal(resp.Body.Bytes(), &verified)
+	for _, v := range verified.Violations {
+		assert.NotEqual(t, compacted.ID.String(), v.ID)
+	}
+
+	resp = send("DELETE", "/admin/legal-holds/"+hold.ID.String(), "")
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+	send("POST", "/admin/compact?entity=jobs", "")
+	assert.Len(t, history(held), 5)
+}
+
+func TestCompactionKeepsInvoicedVersions(t *testing.T) {
+	r := setupRouter()
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		if method == "PATCH" {
+			req.Header.Set("Content-Type", "application/merge-patch+json")
+		}
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+		return resp
+	}
+	companyID, contractorID := createCompany(t, r), createContractor(t, r)
+	resp := send("POST", "/jobs", `{"title":"Billed","status":"active","rate":20,"companyId":"`+companyID+`","contractorId":"`+contractorID+`"}`)
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	var job jobs.JobResponse
+	json.Unmarshal(resp.Body.Bytes(), &job)
+	resp = send("POST", "/timelogs", `{"contractorId":"`+contractorID+`","jobUid":"`+job.UID.String()+`","startTime":"2025-05-03T10:00:00Z","endTime":"2025-05-03T12:00:00Z"}`)
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	var tl timelog.TimelogResponse
+	json.Unmarshal(resp.Body.Bytes(), &tl)
+	resp = send("POST", "/payment-line-items", `{"contractorId":"`+contractorID+`","timelogUid":"`+tl.UID.String()+`","amount":40,"issuedAt":"2025-05-04T00:00:00Z","status":"approved"}`)
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	var item payment.PaymentLineItemResponse
+	json.Unmarshal(resp.Body.Bytes(), &item)
+
+	resp = send("POST", "/invoices", `{"companyId":"`+companyID+`","periodStart":"2025-05-01T00:00:00Z","periodEnd":"2025-06-01T00:00:00Z"}`)
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	var inv invoices.InvoiceResponse
+	json.Unmarshal(resp.Body.Bytes(), &inv)
+	if !assert.Len(t, inv.Lines, 1) {
+		return
+	}
+	assert.Equal(t, job.UID, inv.Lines[0].JobUID)
+	assert.Equal(t, item.UID, inv.Lines[0].LineItemUID)
+
+	// Both billed versions end, and the versions after them end in the same
+	// month, which would collapse the billed ones away.
+	for _, rate := range []string{"21", "22"} {
+		assert.Equal(t, http.StatusOK, send("PATCH", "/jobs/"+job.UID.String(), `{"rate":`+rate+`}`).Code)
+	}
+	for _, amount := range []string{"41", "42"} {
+		assert.Equal(t, http.StatusOK, send("PATCH", "/payment-line-items/"+item.UID.String(), `{"amount":`+amount+`}`).Code)
+	}
+
+	// Compact four years from now, in a transaction rolled back at the end so
+	// the other tests keep their histories.
+	tx := db.Connect().Begin()
+	defer tx.Rollback()
+	policies := retention.Policies{"jobs": {After: 3 * 365 * 24 * time.Hour}, "payment-line-items": {After: 365 * 24 * time.Hour}}
+	_, err := retention.Run(tx, policies, []string{"jobs", "payment-line-items"}, scd.CompactOptions{Now: time.Now().AddDate(4, 0, 0)})
+	if !assert.NoError(t, err) {
+		return
+	}
+	jobHistory, err := scd.NewManager[jobs.Job](tx).HistoryByUID(job.UID.String())
+	assert.NoError(t, err)
+	if assert.Len(t, jobHistory, 3) {
+		assert.Equal(t, job.UID, jobHistory[0].UID)
+	}
+	itemHistory, err := scd.NewManager[payment.PaymentLineItem](tx).HistoryByUID(item.UID.String())
+	assert.NoError(t, err)
+	if assert.Len(t, itemHistory, 3) {
+		assert.Equal(t, item.UID, itemHistory[0].UID)
+	}
+}
+
+func TestSplitHistory(t *testing.T) {
+	r := setupRouter()
+	companyID, contractorID := createCompany(t, r), createContractor(t, r)
+
+	// Split the jobs table in a transaction rolled back at the end, so the
+	// other tests keep the layout they were written for.
+	tx := db.Connect().Begin()
+	defer tx.Rollback()
+	made, err := scd.NewManager[jobs.Job](tx).Split(time.Now())
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.True(t, made.Split)
+	month := fmt.Sprintf("jobs_history_%s", time.Now().UTC().Format("2006_01"))
+	assert.Contains(t, made.Partitions, month)
+
+	partitionOf := func(uid uuid.UUID) string {
+		var name string
+		tx.Raw("SELECT tableoid::regclass::text FROM jobs WHERE uid = ?", uid).Scan(&name)
+		return name
+	}
+	svc := jobs.NewService(jobs.NewRepository(tx))
+	v1, err := svc.CreateJob(jobs.Job{Title: "Split", Status: "active", Rate: 10, CompanyID: uuid.MustParse(companyID), ContractorID: uuid.MustParse(contractorID)})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "jobs_current", partitionOf(v1.UID))
+
+	// Ending the first version moves it to the partition of this month.
+	v2, err := svc.Patch(v1.UID.String(), "application/merge-patch+json", []byte(`{"rate":20}`), scd.Precondition{})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, month, partitionOf(v1.UID))
+	assert.Equal(t, "jobs_current", partitionOf(v2.UID))
+
+	// Reads see both partitions.
+	history, err := svc.History(v1.UID.String())
+	assert.NoError(t, err)
+	assert.Len(t, history, 2)
+	latest, err := svc.GetActiveJobsByCompany(companyID)
+	assert.NoError(t, err)
+	if assert.Len(t, latest, 1) {
+		assert.Equal(t, v2.UID, latest[0].UID)
+	}
+	old, err := svc.GetByUID(v1.UID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, 10.0, old.Rate)
+
+	// A uid still names one version, across the partitions.
+	tx.SavePoint("duplicate")
+	dup := old
+	dup.Version, dup.EffectiveTo = 3, nil
+	assert.Error(t, tx.Create(&dup).Error)
+	tx.RollbackTo("duplicate")
+	var uids int64
+	tx.Raw("SELECT count(*) FROM jobs_uids WHERE uid IN ?", []uuid.UUID{v1.UID, v2.UID}).Scan(&uids)
+	assert.Equal(t, int64(2), uids)
+
+	// With the trigger bypassed, verification finds the uid held twice.
+	tx.SavePoint("unchecked")
+	assert.NoError(t, tx.Exec("SET LOCAL session_replication_role = replica").Error)
+	assert.NoError(t, tx.Create(&dup).Error)
+	violations, err := scd.NewManager[jobs.Job](tx).Verify(false)
+	assert.NoError(t, err)
+	var duplicate *scd.Violation
+	for i, v := range violations {
+		if v.Kind == scd.ViolationDuplicateUID && v.Value == v1.UID.String() {
+			duplicate = &violations[i]
+		}
+	}
+	if assert.NotNil(t, duplicate) {
+		assert.Equal(t, v1.ID.String(), duplicate.ID)
+		assert.Contains(t, duplicate.Detail, "held by 2 versions")
+	}
+	tx.RollbackTo("unchecked")
+
+	// Partitions of the months to come are added once.
+	now := time.Now().UTC()
+	next := time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+	added, err := scd.NewManager[jobs.Job](tx).AddPartitions(next)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"jobs_history_" + next.Format("2006_01")}, added.Partitions)
+	added, err = scd.NewManager[jobs.Job](tx).AddPartitions(next)
+	assert.NoError(t, err)
+	assert.Empty(t, added.Partitions)
+}
@@ -0,0 +1,107 @@
+package timelog
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"mercor/internal/scd"
+)
+
+// TimelogRequest is the body of POST /timelogs and PUT /timelogs/:uid and the
+// data of batch items. PUT takes the complete representation: an empty
+// ExternalRef or JobUID clears the stored one, and partial updates are left to
+// PATCH. Identity, version and timestamps are assigned by the server.
+// EffectiveFrom back-dates the change; it defaults to now. JobUID links the
+// timelog to a job version.
+type TimelogRequest struct {
+	ContractorID  string     `json:"contractorId" binding:"required,uuid"`
+	JobUID        string     `json:"jobUid,omitempty" binding:"omitempty,uuid"`
+	StartTime     time.Time  `json:"startTime" binding:"required"`
+	EndTime       time.Time  `json:"endTime" binding:"required,gtfield=StartTime"`
+	ExternalRef   string     `json:"externalRef"`
+	EffectiveFrom *time.Time `json:"effectiveFrom,omitempty"`
+}
+
+// toTimelog converts a validated request.
+func (r TimelogRequest) toTimelog() Timelog {
+	return Timelog{
+		ContractorID: uuid.MustParse(r.ContractorID),
+		JobUID:       optionalUID(r.JobUID),
+		StartTime:    r.StartTime,
+		EndTime:      r.EndTime,
+		ExternalRef:  r.ExternalRef,
+		Bitemporal:   effectiveFrom(r.EffectiveFrom),
+	}
+}
+
+// effectiveFrom starts the effective period of a version at from, or leaves
+// it to the server if from is nil.
+func effectiveFrom(from *time.Time) scd.Bitemporal {
+	if from == nil {
+		return scd.Bitemporal{}
+	}
+	return scd.Bitemporal{EffectiveFrom: *from}
+}
+
+// optionalUID parses an optional UID of a validated request.
+func optionalUID(s string) *uuid.UUID {
+	if s == "" {
+		return nil
+	}
+	u := uuid.MustParse(s)
+	return &u
+}
+
+type TimelogResponse struct {
+	ID           uuid.UUID  `json:"id"`
+	UID          uuid.UUID  `json:"uid"`
+	Version      int        `json:"version"`
+	ContractorID uuid.UUID  `json:"contractorId"`
+	JobUID       *uuid.UUID `json:"jobUid,omitempty"`
+	StartTime    time.Time  `json:"startTime"`
+	EndTime      time.Time  `json:"endTime"`
+	ExternalRef  string     `json:"externalRef,omitempty"`
+	// PreviousExternalRef is the reference ExternalRef replaced in place.
+	PreviousExternalRef string    `json:"previousExternalRef,omitempty"`
+	CreatedAt           time.Time `json:"createdAt"`
+	UpdatedAt           time.Time `json:"updatedAt"`
+	// EffectiveFrom and EffectiveTo bound when the version applied;
+	// RecordedFrom and RecordedTo when it was the recorded state.
+	EffectiveFrom time.Time  `json:"effectiveFrom"`
+	EffectiveTo   *time.Time `json:"effectiveTo"`
+	RecordedFrom  time.Time  `json:"recordedFrom"`
+	RecordedTo    *time.Time `json:"recordedTo"`
+	// RevertedFrom and RevertReason are set on versions written by a revert.
+	RevertedFrom *uuid.UUID `json:"revertedFrom,omitempty"`
+	RevertReason string     `json:"revertReason,omitempty"`
+}
+
+func NewTimelogResponse(t Timelog) TimelogResponse {
+	return TimelogResponse{
+		ID:                  t.ID,
+		UID:                 t.UID,
+		Version:             t.Version,
+		ContractorID:        t.ContractorID,
+		JobUID:              t.JobUID,
+		StartTime:           t.StartTime,
+		EndTime:             t.EndTime,
+		ExternalRef:         t.ExternalRef,
+		PreviousExternalRef: t.PreviousExternalRef,
+		CreatedAt:           t.CreatedAt,
+		UpdatedAt:           t.UpdatedAt,
+		EffectiveFrom:       t.EffectiveFrom,
+		EffectiveTo:         t.EffectiveTo,
+		RecordedFrom:        t.RecordedFrom,
+		RecordedTo:          t.RecordedTo,
+		RevertedFrom:        t.RevertedFrom,
+		RevertReason:        t.RevertReason,
+	}
+}
+
+func NewTimelogResponses(list []Timelog) []TimelogResponse {
+	out := make([]TimelogResponse, len(list))
+	for i, t := range list {
+		out[i] = NewTimelogResponse(t)
+	}
+	return out
+}
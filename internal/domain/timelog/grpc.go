@@ -0,0 +1,135 @@
+package timelog
+
+import (
+	"context"
+	"encoding/json"
+
+	scdv1 "mercor/api/scd/v1"
+	"mercor/internal/patch"
+	"mercor/internal/rpc"
+	"mercor/internal/validation"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// GRPCServer exposes the Service over gRPC with the same semantics as Handler.
+type GRPCServer struct {
+	scdv1.UnimplementedTimelogServiceServer
+	svc Service
+}
+
+func NewGRPCServer(s Service) *GRPCServer {
+	return &GRPCServer{svc: s}
+}
+
+func (g *GRPCServer) CreateTimelog(_ context.Context, req *scdv1.CreateTimelogRequest) (*scdv1.Timelog, error) {
+	in, err := timelogRequestFromProto(req.GetTimelog())
+	if err != nil {
+		return nil, err
+	}
+	t, err := g.svc.Create(in.toTimelog())
+	if err != nil {
+		return nil, rpc.Error(err)
+	}
+	return timelogToProto(t), nil
+}
+
+func (g *GRPCServer) GetTimelog(_ context.Context, req *scdv1.GetTimelogRequest) (*scdv1.Timelog, error) {
+	var t Timelog
+	var err error
+	if req.GetAsOf() != nil {
+		t, err = g.svc.GetAsOf(req.GetUid(), req.GetAsOf().AsTime())
+	} else {
+		t, err = g.svc.GetByUID(req.GetUid())
+	}
+	if err != nil {
+		return nil, rpc.Error(err)
+	}
+	return timelogToProto(t), nil
+}
+
+// UpdateTimelog stores the fields of TimelogInput as a new version. They are
+// merged into the head, so the job link, which TimelogInput lacks, and an
+// empty external_ref keep their values.
+func (g *GRPCServer) UpdateTimelog(_ context.Context, req *scdv1.UpdateTimelogRequest) (*scdv1.Timelog, error) {
+	in, err := timelogRequestFromProto(req.GetTimelog())
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]any{"contractorId": in.ContractorID, "startTime": in.StartTime, "endTime": in.EndTime}
+	if in.ExternalRef != "" {
+		fields["externalRef"] = in.ExternalRef
+	}
+	body, err := json.Marshal(fields)
+	if err != nil {
+		return nil, rpc.Error(err)
+	}
+	t, err := g.svc.Patch(req.GetUid(), patch.ContentTypeMergePatch, body, rpc.ExpectHead(req.GetExpectedHeadUid()))
+	if err != nil {
+		return nil, rpc.Error(err)
+	}
+	return timelogToProto(t), nil
+}
+
+func (g *GRPCServer) DeleteTimelog(_ context.Context, req *scdv1.DeleteTimelogRequest) (*emptypb.Empty, error) {
+	if err := g.svc.Delete(req.GetUid(), rpc.ExpectHead(req.GetExpectedHeadUid())); err != nil {
+		return nil, rpc.Error(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (g *GRPCServer) ListTimelogHistory(_ context.Context, req *scdv1.ListTimelogHistoryRequest) (*scdv1.ListTimelogsResponse, error) {
+	list, err := g.svc.History(req.GetUid())
+	if err != nil {
+		return nil, rpc.Error(err)
+	}
+	return &scdv1.ListTimelogsResponse{Timelogs: timelogsToProto(list)}, nil
+}
+
+func (g *GRPCServer) ListContractorTimelogs(_ context.Context, req *scdv1.ListContractorTimelogsRequest) (*scdv1.ListTimelogsResponse, error) {
+	if err := rpc.UUIDArg("contractor_id", req.GetContractorId()); err != nil {
+		return nil, err
+	}
+	list, err := g.svc.GetByContractor(req.GetContractorId())
+	if err != nil {
+		return nil, rpc.Error(err)
+	}
+	return &scdv1.ListTimelogsResponse{Timelogs: timelogsToProto(list)}, nil
+}
+
+// timelogRequestFromProto checks the input against the binding rules of
+// TimelogRequest, so both transports accept the same bodies.
+func timelogRequestFromProto(in *scdv1.TimelogInput) (TimelogRequest, error) {
+	req := TimelogRequest{
+		ContractorID: in.GetContractorId(),
+		StartTime:    rpc.Time(in.GetStartTime()),
+		EndTime:      rpc.Time(in.GetEndTime()),
+		ExternalRef:  in.GetExternalRef(),
+	}
+	if err := validation.Struct(req); err != nil {
+		return req, rpc.Invalid(err)
+	}
+	return req, nil
+}
+
+func timelogToProto(t Timelog) *scdv1.Timelog {
+	return &scdv1.Timelog{
+		Id:           t.ID.String(),
+		Uid:          t.UID.String(),
+		Version:      int32(t.Version),
+		ContractorId: t.ContractorID.String(),
+		StartTime:    rpc.Timestamp(t.StartTime),
+		EndTime:      rpc.Timestamp(t.EndTime),
+		ExternalRef:  t.ExternalRef,
+		CreatedAt:    rpc.Timestamp(t.CreatedAt),
+		UpdatedAt:    rpc.Timestamp(t.UpdatedAt),
+	}
+}
+
+func timelogsToProto(list []Timelog) []*scdv1.Timelog {
+	out := make([]*scdv1.Timelog, len(list))
+	for i, t := range list {
+		out[i] = timelogToProto(t)
+	}
+	return out
+}
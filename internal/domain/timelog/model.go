@@ -4,29 +4,65 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	payment "mercor/internal/domain/paymentLineItem"
+	"mercor/internal/scd"
 )
 
 type Timelog struct {
-  ID           uuid.UUID `gorm:"type:uuid"`
-  UID          uuid.UUID `gorm:"type:uuid;primaryKey"`
-  Version      int
-  ContractorID uuid.UUID
-  StartTime    time.Time
-  EndTime      time.Time
-  CreatedAt    time.Time
-  UpdatedAt    time.Time
+  ID           uuid.UUID `gorm:"type:uuid" json:"id"`
+  UID          uuid.UUID `gorm:"type:uuid;primaryKey" json:"uid"`
+  Version      int       `json:"version"`
+  ContractorID uuid.UUID `gorm:"index" json:"contractorId"`
+  // JobUID links the timelog to the version of the job it was logged for.
+  JobUID       *uuid.UUID `gorm:"type:uuid;index" json:"jobUid"`
+  StartTime    time.Time `json:"startTime"`
+  EndTime      time.Time `json:"endTime"`
+  ExternalRef  string    `gorm:"index" json:"externalRef"`
+  // PreviousExternalRef is the reference ExternalRef last replaced.
+  PreviousExternalRef string `json:"previousExternalRef"`
+  CreatedAt    time.Time `json:"createdAt"`
+  UpdatedAt    time.Time `json:"updatedAt"`
+  scd.Bitemporal
+  scd.Reversion
 }
 
 func (Timelog) TableName() string { return "timelogs" }
 func (t Timelog) GetID() string { return t.ID.String() }
 func (t Timelog) GetUID() string { return t.UID.String() }
 func (t Timelog) GetVersion() int { return t.Version }
+
+// ChangePolicy re-keys a timelog in place when its external reference
+// changes, keeping the previous one so records of the source system still
+// match.
+func (Timelog) ChangePolicy() scd.Policy {
+  return scd.Policy{"external_ref": scd.KeepPrevious("previous_external_ref")}
+}
+
+// Dependents leaves payment line items linked to the timelog version they
+// were computed from; reads find them through the timelog's ID.
+func (Timelog) Dependents() []scd.Relationship {
+  return []scd.Relationship{scd.DependsOn[payment.PaymentLineItem]("timelog_uid", scd.ResolveByID)}
+}
+
+// Identity ties a timelog to the contractor who logged the time.
+func (Timelog) Identity() []string {
+  return []string{"contractor_id"}
+}
+
+// References requires the contractor of a timelog to be on record.
+func (Timelog) References() []scd.Reference {
+  return []scd.Reference{scd.RefersTo("contractor_id", "contractors")}
+}
+
 func (t Timelog) CopyForNewVersion() Timelog {
   return Timelog{
     ID:           t.ID,
     ContractorID: t.ContractorID,
+    JobUID:       t.JobUID,
     StartTime:    t.StartTime,
     EndTime:      t.EndTime,
+    ExternalRef:  t.ExternalRef,
+    PreviousExternalRef: t.PreviousExternalRef,
     UID:        uuid.New(),
 		Version:    t.Version + 1,
   }
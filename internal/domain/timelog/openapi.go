@@ -0,0 +1,147 @@
+package timelog
+
+import (
+	"net/http"
+
+	"mercor/internal/openapi"
+	"mercor/internal/scd"
+)
+
+// Operations documents the routes registered by Handler.
+func Operations() []openapi.Operation {
+	timelog := openapi.JSON("The timelog version", TimelogResponse{})
+	batch := openapi.JSON("Per-item results", scd.BatchResponse[TimelogResponse]{})
+	return []openapi.Operation{
+		{
+			Method: http.MethodPost, Path: "/timelogs", Tag: "timelogs",
+			Summary: "Create a timelog",
+			Body:    openapi.Body(TimelogRequest{}),
+			Responses: openapi.Responses{
+				201: openapi.JSON("The first version of the timelog", TimelogResponse{}),
+				400: openapi.Invalid,
+				422: openapi.InvalidPeriodOrReference,
+				500: openapi.ServerError,
+			},
+		},
+		{
+			Method: http.MethodPost, Path: "/timelogs/batch", Tag: "timelogs",
+			Summary:     "Create and version timelogs in one transaction",
+			Description: "Also served at POST /timelogs:batch.",
+			Body:        openapi.Body(scd.BatchRequest[TimelogRequest]{}),
+			Responses: openapi.Responses{
+				200: batch,
+				400: openapi.BadRequest,
+				422: openapi.JSON("An atomic batch was rejected", scd.BatchResponse[TimelogResponse]{}),
+				500: openapi.ServerError,
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/timelogs/scheduled", Tag: "timelogs",
+			Summary:     "List scheduled timelog versions",
+			Description: "Versions written with an effectiveFrom in the future, soonest first. Reads of the latest version leave them out until they take effect.",
+			Responses: openapi.Responses{
+				200: openapi.JSON("Pending versions", []TimelogResponse{}),
+				500: openapi.ServerError,
+			},
+		},
+		{
+			Method: http.MethodDelete, Path: "/timelogs/scheduled/:uid", Tag: "timelogs",
+			Summary: "Cancel a scheduled timelog version",
+			Responses: openapi.Responses{
+				200: openapi.JSON("The cancelled version, no longer recorded", TimelogResponse{}),
+				404: openapi.NotFound,
+				409: openapi.JSON("The version is not pending", openapi.Error{}),
+				500: openapi.ServerError,
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/timelogs/:uid", Tag: "timelogs",
+			Summary: "Get a timelog version by UID",
+			Params:  []openapi.Param{openapi.AsOf, openapi.KnownAt},
+			Responses: openapi.Responses{
+				200: timelog,
+				400: openapi.BadRequest,
+				404: openapi.NotFound,
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/timelogs/:uid/history", Tag: "timelogs",
+			Summary: "List every version of a timelog, oldest first",
+			Responses: openapi.Responses{
+				200: openapi.JSON("All versions of the timelog", []TimelogResponse{}),
+				404: openapi.NotFound,
+			},
+		},
+		{
+			Method: http.MethodPost, Path: "/timelogs/:uid/revert", Tag: "timelogs",
+			Summary:     "Revert a timelog to an earlier version",
+			Description: "Stores a new version with the values of the version named by to, recording it and the reason. The history is not rewritten.",
+			Params: []openapi.Param{
+				{Name: "to", In: "query", Required: true, Description: "UID of the version to restore."},
+				openapi.IfMatch,
+			},
+			Body: openapi.Body(scd.RevertRequest{}),
+			Responses: openapi.Responses{
+				200: timelog,
+				400: openapi.Invalid,
+				404: openapi.NotFound,
+				412: openapi.PreconditionFailed,
+				422: openapi.JSON("to is not a version of this timelog", openapi.Error{}),
+				500: openapi.ServerError,
+			},
+		},
+		{
+			Method: http.MethodPut, Path: "/timelogs/:uid", Tag: "timelogs",
+			Summary:     "Replace a timelog",
+			Description: "Stores the complete representation as a new version on top of the head version. An effectiveFrom in the future schedules the version.",
+			Params:      []openapi.Param{openapi.IfMatch},
+			Body:        openapi.Body(TimelogRequest{}),
+			Responses: openapi.Responses{
+				200: timelog,
+				400: openapi.Invalid,
+				404: openapi.NotFound,
+				422: openapi.InvalidPeriodOrReference,
+				412: openapi.PreconditionFailed,
+				500: openapi.ServerError,
+			},
+		},
+		{
+			Method: http.MethodPatch, Path: "/timelogs/:uid", Tag: "timelogs",
+			Summary: "Partially update a timelog",
+			Params:  []openapi.Param{openapi.IfMatch},
+			Body:    openapi.PatchBody(TimelogResponse{}),
+			Responses: openapi.Responses{
+				200: timelog,
+				400: openapi.BadRequest,
+				404: openapi.NotFound,
+				409: openapi.JSON("A JSON Patch test operation failed", openapi.Error{}),
+				412: openapi.PreconditionFailed,
+				415: openapi.JSON("Unsupported patch media type", openapi.Error{}),
+				422: openapi.JSON("The patched timelog is invalid, its effectiveFrom cannot be applied or its contractor does not exist", openapi.Error{}),
+			},
+		},
+		{
+			Method: http.MethodDelete, Path: "/timelogs/:uid", Tag: "timelogs",
+			Summary:     "Void a timelog",
+			Description: "Stores a new version with an empty interval.",
+			Params:      []openapi.Param{openapi.IfMatch},
+			Responses: openapi.Responses{
+				204: openapi.Empty("Voided"),
+				404: openapi.NotFound,
+				412: openapi.PreconditionFailed,
+				500: openapi.ServerError,
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/contractors/:id/timelogs", Tag: "timelogs",
+			Summary:   "List the current timelogs of a contractor",
+			Responses: openapi.Responses{200: openapi.JSON("Current timelog versions", []TimelogResponse{}), 500: openapi.ServerError},
+		},
+		{
+			Method: http.MethodGet, Path: "/jobs/:uid/timelogs", Tag: "timelogs",
+			Summary:     "List the current timelogs of a job",
+			Description: "Matches timelogs linked to any version of the job. Timelogs are re-pointed at every new job version, so each names the current one.",
+			Responses:   openapi.Responses{200: openapi.JSON("Current timelog versions", []TimelogResponse{}), 500: openapi.ServerError},
+		},
+	}
+}
@@ -1,17 +1,36 @@
 package timelog
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 	"mercor/internal/scd"
+	"mercor/internal/validation"
 )
 
 type Repository interface {
 	Insert(t Timelog) (Timelog, error)
 	FindByUID(uid string) (Timelog, error)
-	Update(uid string, updated Timelog) (Timelog, error)
-	SoftDelete(uid string) error
+	History(uid string) ([]Timelog, error)
+	FindAsOf(uid string, at time.Time) (Timelog, error)
+	FindAsKnown(uid string, knownAt, at time.Time) (Timelog, error)
+	Scheduled() ([]Timelog, error)
+	CancelScheduled(uid string) (Timelog, error)
+	Revert(uid, to, reason string, pre scd.Precondition) (Timelog, error)
+	VersionsByIDs(ids []uuid.UUID) ([]Timelog, error)
+	FindByUIDs(uids []uuid.UUID) ([]Timelog, error)
+	FindByIDs(ids []uuid.UUID, at *time.Time) ([]Timelog, error)
+	FindByContractors(contractorIDs []uuid.UUID, at *time.Time) ([]Timelog, error)
+	FindByJobs(jobUIDs []uuid.UUID, at *time.Time) ([]Timelog, error)
+	Update(uid string, updated Timelog, pre scd.Precondition) (Timelog, error)
+	SoftDelete(uid string, pre scd.Precondition) error
+	Append(uid string, pre scd.Precondition, next func(head Timelog) (Timelog, error)) (Timelog, error)
 	FindLatestByContractor(contractorID uuid.UUID) ([]Timelog, error)
+	FindLatestByJob(uid string) ([]Timelog, error)
+	FindLatestByExternalRef(ref string) (Timelog, error)
+	FindOverlapping(contractorID uuid.UUID, start, end time.Time) ([]Timelog, error)
+	Batch(ops []scd.BatchOp[TimelogRequest], mode scd.BatchMode) ([]scd.BatchResult[Timelog], error)
 }
 
 type repo struct {
@@ -23,7 +42,7 @@ func NewRepository(db *gorm.DB) Repository {
 }
 
 func (r *repo) Insert(t Timelog) (Timelog, error) {
-	err := r.scd.Insert(t)
+	err := r.scd.Insert(&t)
 	return t, err
 }
 
@@ -31,27 +50,88 @@ func (r *repo) FindByUID(uid string) (Timelog, error) {
 	return r.scd.FindByUID(uid)
 }
 
-func (r *repo) Update(uid string, updated Timelog) (Timelog, error) {
-	old, err := r.scd.FindByUID(uid)
-	if err != nil {
-		return Timelog{}, err
-	}
-	newVer := old.CopyForNewVersion()
-	newVer.StartTime = updated.StartTime
-	newVer.EndTime = updated.EndTime
-	newVer.ContractorID = updated.ContractorID
-	err = r.scd.Insert(newVer)
-	return newVer, err
-}
-
-func (r *repo) SoftDelete(uid string) error {
-	old, err := r.FindByUID(uid)
-	if err != nil {
-		return err
-	}
+func (r *repo) History(uid string) ([]Timelog, error) {
+	return r.scd.HistoryByUID(uid)
+}
+
+func (r *repo) FindAsOf(uid string, at time.Time) (Timelog, error) {
+	return r.scd.FindAsOfByUID(uid, at)
+}
+
+func (r *repo) FindAsKnown(uid string, knownAt, at time.Time) (Timelog, error) {
+	return r.scd.FindAsKnownByUID(uid, knownAt, at)
+}
+
+// Scheduled returns the versions that take effect later, soonest first.
+func (r *repo) Scheduled() ([]Timelog, error) {
+	var list []Timelog
+	err := r.scd.Scheduled().Find(&list).Error
+	return list, err
+}
+
+func (r *repo) CancelScheduled(uid string) (Timelog, error) {
+	return r.scd.CancelScheduled(uid)
+}
+
+func (r *repo) Revert(uid, to, reason string, pre scd.Precondition) (Timelog, error) {
+	return r.scd.Revert(uid, to, reason, pre)
+}
+
+// VersionsByIDs, FindByUIDs, FindByIDs and FindByContractors load many
+// entities in one query each, for the GraphQL dataloaders. A nil at selects
+// head versions.
+func (r *repo) VersionsByIDs(ids []uuid.UUID) ([]Timelog, error) {
+	return r.scd.VersionsOf(ids)
+}
+
+func (r *repo) FindByUIDs(uids []uuid.UUID) ([]Timelog, error) {
+	return r.scd.FindUIDs(uids)
+}
+
+func (r *repo) FindByIDs(ids []uuid.UUID, at *time.Time) ([]Timelog, error) {
+	return r.scd.FindIn("id", ids, at)
+}
+
+func (r *repo) FindByContractors(contractorIDs []uuid.UUID, at *time.Time) ([]Timelog, error) {
+	return r.scd.FindIn("contractor_id", contractorIDs, at)
+}
+
+// FindByJobs selects the timelogs linked to one of the job versions.
+func (r *repo) FindByJobs(jobUIDs []uuid.UUID, at *time.Time) ([]Timelog, error) {
+	return r.scd.FindIn("job_uid", jobUIDs, at)
+}
+
+func (r *repo) Update(uid string, updated Timelog, pre scd.Precondition) (Timelog, error) {
+	return r.Append(uid, pre, func(old Timelog) (Timelog, error) {
+		return nextVersion(old, updated), nil
+	})
+}
+
+// Append stores the version next builds from the locked head version.
+func (r *repo) Append(uid string, pre scd.Precondition, next func(head Timelog) (Timelog, error)) (Timelog, error) {
+	return r.scd.AppendVersion(uid, pre, next)
+}
+
+// nextVersion builds the version following old with the fields taken from in,
+// which replaces every one of them: a timelog without a job link is unlinked.
+func nextVersion(old, in Timelog) Timelog {
 	newVer := old.CopyForNewVersion()
-	newVer.EndTime = newVer.StartTime // Mark invalid
-	return r.scd.Insert(newVer)
+	newVer.StartTime = in.StartTime
+	newVer.EndTime = in.EndTime
+	newVer.ContractorID = in.ContractorID
+	newVer.EffectiveFrom = in.EffectiveFrom
+	newVer.ExternalRef = in.ExternalRef
+	newVer.JobUID = in.JobUID
+	return newVer
+}
+
+func (r *repo) SoftDelete(uid string, pre scd.Precondition) error {
+	_, err := r.Append(uid, pre, func(old Timelog) (Timelog, error) {
+		newVer := old.CopyForNewVersion()
+		newVer.EndTime = newVer.StartTime // Mark invalid
+		return newVer, nil
+	})
+	return err
 }
 
 func (r *repo) FindLatestByContractor(contractorID uuid.UUID) ([]Timelog, error) {
@@ -59,3 +139,49 @@ func (r *repo) FindLatestByContractor(contractorID uuid.UUID) ([]Timelog, error)
 	err := r.scd.GetLatest().Where("contractor_id = ?", contractorID).Find(&list).Error
 	return list, err
 }
+
+// FindLatestByJob returns the current timelogs linked to any version of the
+// job uid belongs to.
+func (r *repo) FindLatestByJob(uid string) ([]Timelog, error) {
+	var list []Timelog
+	err := r.scd.LinkedTo("jobs", "job_uid", uid).Find(&list).Error
+	return list, err
+}
+
+func (r *repo) Batch(ops []scd.BatchOp[TimelogRequest], mode scd.BatchMode) ([]scd.BatchResult[Timelog], error) {
+	return scd.ApplyBatch(r.scd, ops, mode, scd.BatchHooks[TimelogRequest, Timelog]{
+		Create: func(in TimelogRequest) (Timelog, error) {
+			if err := validation.Struct(in); err != nil {
+				return Timelog{}, err
+			}
+			t := in.toTimelog()
+			t.ID = uuid.New()
+			t.UID = uuid.New()
+			t.Version = 1
+			return t, nil
+		},
+		Update: func(old Timelog, in TimelogRequest) (Timelog, error) {
+			if err := validation.Struct(in); err != nil {
+				return Timelog{}, err
+			}
+			return nextVersion(old, in.toTimelog()), nil
+		},
+	})
+}
+
+func (r *repo) FindLatestByExternalRef(ref string) (Timelog, error) {
+	var t Timelog
+	err := r.scd.GetLatest().Where("external_ref = ?", ref).First(&t).Error
+	return t, err
+}
+
+// FindOverlapping returns the current timelogs of a contractor whose interval
+// intersects [start, end).
+func (r *repo) FindOverlapping(contractorID uuid.UUID, start, end time.Time) ([]Timelog, error) {
+	var list []Timelog
+	err := r.scd.GetLatest().
+		Where("contractor_id = ?", contractorID).
+		Where("start_time < ? AND end_time > ?", end, start).
+		Find(&list).Error
+	return list, err
+}
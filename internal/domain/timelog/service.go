@@ -1,15 +1,31 @@
 package timelog
 
 import (
+	"time"
+
 	"github.com/google/uuid"
+	"mercor/internal/patch"
+	"mercor/internal/scd"
+	"mercor/internal/validation"
 )
 
 type Service interface {
 	Create(t Timelog) (Timelog, error)
 	GetByUID(uid string) (Timelog, error)
-	Update(uid string, updated Timelog) (Timelog, error)
-	Delete(uid string) error
+	GetAsOf(uid string, at time.Time) (Timelog, error)
+	GetAsKnown(uid string, knownAt, at time.Time) (Timelog, error)
+	Scheduled() ([]Timelog, error)
+	CancelScheduled(uid string) (Timelog, error)
+	Revert(uid, to, reason string, pre scd.Precondition) (Timelog, error)
+	History(uid string) ([]Timelog, error)
+	Update(uid string, updated Timelog, pre scd.Precondition) (Timelog, error)
+	Delete(uid string, pre scd.Precondition) error
 	GetByContractor(id string) ([]Timelog, error)
+	GetByJob(uid string) ([]Timelog, error)
+	GetByExternalRef(ref string) (Timelog, error)
+	FindOverlapping(contractorID uuid.UUID, start, end time.Time) ([]Timelog, error)
+	Patch(uid, contentType string, body []byte, pre scd.Precondition) (Timelog, error)
+	Batch(req scd.BatchRequest[TimelogRequest]) ([]scd.BatchResult[Timelog], error)
 }
 
 type service struct {
@@ -20,7 +36,10 @@ func NewService(r Repository) Service {
 	return &service{repo: r}
 }
 
+// Create stores the first version of a new timelog. Identity and version are
+// always assigned here, never taken from the client.
 func (s *service) Create(t Timelog) (Timelog, error) {
+	t.ID = uuid.New()
 	t.UID = uuid.New()
 	t.Version = 1
 	return s.repo.Insert(t)
@@ -30,14 +49,82 @@ func (s *service) GetByUID(uid string) (Timelog, error) {
 	return s.repo.FindByUID(uid)
 }
 
-func (s *service) Update(uid string, updated Timelog) (Timelog, error) {
-	return s.repo.Update(uid, updated)
+// GetAsOf returns the version of the timelog that was effective at the given time.
+func (s *service) GetAsOf(uid string, at time.Time) (Timelog, error) {
+	return s.repo.FindAsOf(uid, at)
+}
+
+// GetAsKnown returns the version of the timelog that was effective at the given
+// time according to what was recorded at knownAt.
+func (s *service) GetAsKnown(uid string, knownAt, at time.Time) (Timelog, error) {
+	return s.repo.FindAsKnown(uid, knownAt, at)
+}
+
+// Scheduled returns the pending versions, stored with an effectiveFrom in the
+// future, that reads of the latest version leave out until they take effect.
+func (s *service) Scheduled() ([]Timelog, error) {
+	return s.repo.Scheduled()
+}
+
+// CancelScheduled withdraws a pending version before it takes effect.
+func (s *service) CancelScheduled(uid string) (Timelog, error) {
+	return s.repo.CancelScheduled(uid)
+}
+
+// Revert stores a new version of the timelog with the values of its version
+// `to`, recording that version and the reason on it.
+func (s *service) Revert(uid, to, reason string, pre scd.Precondition) (Timelog, error) {
+	return s.repo.Revert(uid, to, reason, pre)
 }
 
-func (s *service) Delete(uid string) error {
-	return s.repo.SoftDelete(uid)
+// History returns every version of the timelog, oldest first.
+func (s *service) History(uid string) ([]Timelog, error) {
+	return s.repo.History(uid)
+}
+
+func (s *service) Update(uid string, updated Timelog, pre scd.Precondition) (Timelog, error) {
+	return s.repo.Update(uid, updated, pre)
+}
+
+func (s *service) Delete(uid string, pre scd.Precondition) error {
+	return s.repo.SoftDelete(uid, pre)
 }
 
 func (s *service) GetByContractor(id string) ([]Timelog, error) {
 	return s.repo.FindLatestByContractor(uuid.MustParse(id))
 }
+
+func (s *service) GetByJob(uid string) ([]Timelog, error) {
+	return s.repo.FindLatestByJob(uid)
+}
+
+func (s *service) GetByExternalRef(ref string) (Timelog, error) {
+	return s.repo.FindLatestByExternalRef(ref)
+}
+
+func (s *service) FindOverlapping(contractorID uuid.UUID, start, end time.Time) ([]Timelog, error) {
+	return s.repo.FindOverlapping(contractorID, start, end)
+}
+
+// Patch applies a merge patch or JSON patch to the response representation of
+// the head version of the entity that uid belongs to. The head is locked while
+// the patch is applied, so the result is based on the version it replaces. The
+// result must be a valid TimelogRequest and is stored as a new version.
+func (s *service) Patch(uid, contentType string, body []byte, pre scd.Precondition) (Timelog, error) {
+	return s.repo.Append(uid, pre, func(head Timelog) (Timelog, error) {
+		patched, err := patch.Entity(NewTimelogResponse(head), contentType, body, validation.Struct[TimelogRequest])
+		if err != nil {
+			return Timelog{}, err
+		}
+		// The representation carries the effectiveFrom of the head; only a
+		// changed one back-dates the patch.
+		if patched.EffectiveFrom != nil && patched.EffectiveFrom.Equal(head.EffectiveFrom) {
+			patched.EffectiveFrom = nil
+		}
+		return nextVersion(head, patched.toTimelog()), nil
+	})
+}
+
+func (s *service) Batch(req scd.BatchRequest[TimelogRequest]) ([]scd.BatchResult[Timelog], error) {
+	return s.repo.Batch(req.Items, req.Mode)
+}
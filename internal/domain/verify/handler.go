@@ -0,0 +1,54 @@
+package verify
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type Handler struct {
+	db *gorm.DB
+}
+
+func NewHandler(db *gorm.DB) *Handler {
+	return &Handler{db: db}
+}
+
+// RegisterRoutes registers the verification routes under /admin, which is
+// meant for operators rather than clients.
+func (h *Handler) RegisterRoutes(r *gin.Engine) {
+	r.GET("/admin/verify", h.Verify)
+	r.POST("/admin/verify/repair", h.Repair)
+}
+
+// Verify reports the violations in the entities named by ?entity=, a comma
+// separated list, or in every entity.
+func (h *Handler) Verify(c *gin.Context) {
+	h.run(c, false)
+}
+
+// Repair repairs the safe violations and reports every violation found.
+func (h *Handler) Repair(c *gin.Context) {
+	h.run(c, true)
+}
+
+func (h *Handler) run(c *gin.Context, repair bool) {
+	var entities []string
+	if raw := c.Query("entity"); raw != "" {
+		entities = strings.Split(raw, ",")
+		for _, name := range entities {
+			if _, ok := Entities[name]; !ok {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "unknown entity " + name})
+				return
+			}
+		}
+	}
+	rep, err := Run(h.db, entities, repair)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, rep)
+}
@@ -0,0 +1,36 @@
+package verify
+
+import (
+	"net/http"
+	"strings"
+
+	"mercor/internal/openapi"
+)
+
+// Operations documents the routes registered by Handler.
+func Operations() []openapi.Operation {
+	params := []openapi.Param{
+		{Name: "entity", In: "query", Description: "Comma separated entities to verify, of " + strings.Join(Names(), ", ") + "; all by default.", Schema: openapi.Schema{"type": "string"}},
+	}
+	responses := openapi.Responses{
+		200: {Description: "The violations found", Content: map[string]any{"application/json": Report{}}},
+		400: openapi.BadRequest,
+		500: openapi.ServerError,
+	}
+	return []openapi.Operation{
+		{
+			Method: http.MethodGet, Path: "/admin/verify", Tag: "admin",
+			Summary:     "Verify the invariants of the versioned tables",
+			Description: "Scans every version ever recorded for gaps in version numbers, duplicate versions, entities with more than one open-ended version, identity columns that changed between versions and references or links to entities that were never stored.",
+			Params:      params,
+			Responses:   responses,
+		},
+		{
+			Method: http.MethodPost, Path: "/admin/verify/repair", Tag: "admin",
+			Summary:     "Repair the safe violations of the versioned tables",
+			Description: "Verifies like GET /admin/verify and repairs the repairable violations: of the versions of an entity effective without end, each but the last to take effect ends where the next one starts. The other violations are reported and counted in remaining.",
+			Params:      params,
+			Responses:   responses,
+		},
+	}
+}
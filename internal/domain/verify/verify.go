@@ -0,0 +1,102 @@
+package verify
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"gorm.io/gorm"
+	"mercor/internal/domain/jobs"
+	payment "mercor/internal/domain/paymentLineItem"
+	"mercor/internal/domain/timelog"
+	"mercor/internal/scd"
+)
+
+// Checker verifies one entity's versioned table, repairing the safe cases if
+// asked to.
+type Checker func(db *gorm.DB, repair bool) ([]scd.Violation, error)
+
+func check[T scd.SCDModel[T]](db *gorm.DB, repair bool) ([]scd.Violation, error) {
+	return scd.NewManager[T](db).Verify(repair)
+}
+
+// Entities maps the verified entity names, as exported, to their checkers.
+// Links to a table are checked with it, so jobs covers the job UIDs of
+// timelogs and timelogs the timelog UIDs of payment line items.
+var Entities = map[string]Checker{
+	"jobs":               check[jobs.Job],
+	"timelogs":           check[timelog.Timelog],
+	"payment-line-items": check[payment.PaymentLineItem],
+}
+
+// Names returns the entity names in order.
+func Names() []string {
+	names := make([]string, 0, len(Entities))
+	for name := range Entities {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Report lists the violations found in the verified entities. Remaining
+// counts the ones that were not repaired.
+type Report struct {
+	CheckedAt  time.Time       `json:"checkedAt"`
+	Entities   []string        `json:"entities"`
+	Repair     bool            `json:"repair"`
+	Violations []scd.Violation `json:"violations"`
+	Remaining  int             `json:"remaining"`
+}
+
+// Run verifies the given entities, every one if none are given. Each entity
+// is repaired in its own transaction.
+func Run(db *gorm.DB, entities []string, repair bool) (Report, error) {
+	if len(entities) == 0 {
+		entities = Names()
+	}
+	rep := Report{CheckedAt: time.Now().UTC(), Entities: entities, Repair: repair, Violations: []scd.Violation{}}
+	for _, name := range entities {
+		check, ok := Entities[name]
+		if !ok {
+			return rep, fmt.Errorf("unknown entity %q, must be one of %v", name, Names())
+		}
+		found, err := check(db, repair)
+		if err != nil {
+			return rep, fmt.Errorf("%s: %w", name, err)
+		}
+		rep.Violations = append(rep.Violations, found...)
+	}
+	for _, v := range rep.Violations {
+		if !v.Repaired {
+			rep.Remaining++
+		}
+	}
+	return rep, nil
+}
+
+// WriteText writes the report for reading in a terminal, one violation per
+// line.
+func (r Report) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Verified %s at %s: %d violations, %d remaining\n", strings.Join(r.Entities, ", "), r.CheckedAt.Format(time.RFC3339), len(r.Violations), r.Remaining)
+	if len(r.Violations) == 0 {
+		return tw.Flush()
+	}
+	fmt.Fprintln(tw)
+	fmt.Fprintln(tw, "kind\ttable\tid\tdetail\tstate")
+	for _, v := range r.Violations {
+		state := "manual"
+		switch {
+		case v.Repaired:
+			state = "repaired"
+		case v.Repairable:
+			state = "repairable"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", v.Kind, v.Table, v.ID, v.Detail, state)
+	}
+	return tw.Flush()
+}
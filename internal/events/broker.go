@@ -0,0 +1,139 @@
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+// Event describes a single version written to one of the SCD tables.
+type Event struct {
+	Seq       uint64            `json:"seq"`
+	Entity    string            `json:"entity"`
+	ID        string            `json:"id"`
+	UID       string            `json:"uid"`
+	Version   int               `json:"version"`
+	Keys      map[string]string `json:"keys,omitempty"`
+	Data      any               `json:"data"`
+	CreatedAt time.Time         `json:"createdAt"`
+}
+
+// Filter selects the events a subscriber is interested in. Empty fields match
+// everything; a key filter only matches events carrying that key.
+type Filter struct {
+	Entity string
+	Keys   map[string]string
+}
+
+func (f Filter) Match(e Event) bool {
+	if f.Entity != "" && f.Entity != e.Entity {
+		return false
+	}
+	for k, v := range f.Keys {
+		if e.Keys[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+type subscriber struct {
+	filter Filter
+	ch     chan Event
+}
+
+// Reset is the entity of the event a subscriber gets first when the broker
+// cannot replay what followed its resume position: the position is older than
+// the backlog, or from before a restart, which empties it. The subscriber
+// should reload the state it follows; the Seq of the event is the position to
+// resume from afterwards. No events are replayed with it.
+const Reset = "reset"
+
+// Broker fans out events to subscribers and keeps a bounded backlog so that
+// reconnecting clients can resume from a Last-Event-ID. Sequence numbers
+// start at the time the broker was made, in microseconds, so those of a
+// restarted server are past any it handed out before.
+type Broker struct {
+	mu      sync.Mutex
+	seq     uint64
+	backlog []Event
+	size    int
+	subs    map[*subscriber]struct{}
+}
+
+func NewBroker(backlogSize int) *Broker {
+	return &Broker{seq: uint64(time.Now().UnixMicro()), size: backlogSize, subs: map[*subscriber]struct{}{}}
+}
+
+func (b *Broker) Publish(e Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.seq++
+	e.Seq = b.seq
+	if e.CreatedAt.IsZero() {
+		e.CreatedAt = time.Now()
+	}
+	b.backlog = append(b.backlog, e)
+	if len(b.backlog) > b.size {
+		b.backlog = b.backlog[len(b.backlog)-b.size:]
+	}
+
+	for s := range b.subs {
+		if !s.filter.Match(e) {
+			continue
+		}
+		select {
+		case s.ch <- e:
+		default:
+			// Slow consumer: drop it rather than block writers. The client
+			// reconnects with Last-Event-ID and replays from the backlog.
+			delete(b.subs, s)
+			close(s.ch)
+		}
+	}
+}
+
+// Subscribe returns a channel of events matching f, preceded by any backlog
+// events with a sequence greater than after, or by a Reset event if the
+// backlog does not reach back to after. An after of 0 replays the whole
+// backlog. The returned func must be called to release the subscription.
+func (b *Broker) Subscribe(f Filter, after uint64) (<-chan Event, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var replay []Event
+	if after == 0 || b.replays(after) {
+		for _, e := range b.backlog {
+			if e.Seq > after && f.Match(e) {
+				replay = append(replay, e)
+			}
+		}
+	} else {
+		replay = append(replay, Event{Seq: b.seq, Entity: Reset, CreatedAt: time.Now()})
+	}
+
+	s := &subscriber{filter: f, ch: make(chan Event, len(replay)+64)}
+	for _, e := range replay {
+		s.ch <- e
+	}
+	b.subs[s] = struct{}{}
+
+	return s.ch, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.subs[s]; ok {
+			delete(b.subs, s)
+			close(s.ch)
+		}
+	}
+}
+
+// replays reports whether every event after the sequence number after is
+// still in the backlog.
+func (b *Broker) replays(after uint64) bool {
+	oldest := b.seq + 1
+	if len(b.backlog) > 0 {
+		oldest = b.backlog[0].Seq
+	}
+	return after+1 >= oldest && after <= b.seq
+}
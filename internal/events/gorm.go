@@ -0,0 +1,129 @@
+package events
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// keyColumns are copied onto each event so subscribers can filter on them.
+var keyColumns = []string{"company_id", "contractor_id"}
+
+// linkedKeys find the keys of the rows of a table that do not carry them
+// through the column linking each row to another version: the company of a
+// timelog is the one of its job, and the company of a payment line item the
+// one of its timelog's job.
+var linkedKeys = map[string]map[string]keyLink{
+	"timelogs": {
+		"company_id": {Column: "job_uid", Query: "SELECT company_id FROM jobs WHERE uid = ?"},
+	},
+	"payment_line_items": {
+		"company_id": {Column: "timelog_uid", Query: "SELECT j.company_id FROM timelogs t JOIN jobs j ON j.uid = t.job_uid WHERE t.uid = ?"},
+	},
+}
+
+// keyLink looks up a key with Query, given the value of Column.
+type keyLink struct {
+	Column string
+	Query  string
+}
+
+type versioned interface {
+	GetID() string
+	GetUID() string
+	GetVersion() int
+}
+
+// Attach registers GORM create and update callbacks that publish every
+// committed SCD row to the broker: new versions, and head versions changed in
+// place under a Type1 or Type3 policy. It must be called before db is used.
+// Writes inside a transaction are published when the transaction commits, in
+// the order they were written; nothing is published for a transaction rolled
+// back, nor for writes undone by rolling back to a savepoint.
+func Attach(db *gorm.DB, b *Broker) error {
+	p := &pool{ConnPool: db.ConnPool, broker: b}
+	db.ConnPool = p
+	db.Statement.ConnPool = p
+	db.Dialector = dialector{Dialector: db.Dialector}
+
+	publish := func(tx *gorm.DB) {
+		if tx.Error != nil || tx.Statement.Schema == nil {
+			return
+		}
+		emit := b.Publish
+		if t, ok := tx.Statement.ConnPool.(*transaction); ok {
+			emit = t.add
+		}
+		rv := reflect.Indirect(tx.Statement.ReflectValue)
+		switch rv.Kind() {
+		case reflect.Slice, reflect.Array:
+			for i := 0; i < rv.Len(); i++ {
+				publishRow(tx, emit, reflect.Indirect(rv.Index(i)))
+			}
+		case reflect.Struct:
+			publishRow(tx, emit, rv)
+		}
+	}
+	err := db.Callback().Create().
+		After("gorm:commit_or_rollback_transaction").
+		Register("events:publish", publish)
+	if err != nil {
+		return err
+	}
+	return db.Callback().Update().
+		After("gorm:commit_or_rollback_transaction").
+		Register("events:publish", publish)
+}
+
+func publishRow(tx *gorm.DB, emit func(Event), rv reflect.Value) {
+	row, ok := rv.Interface().(versioned)
+	if !ok {
+		return
+	}
+	keys := map[string]string{}
+	for _, col := range keyColumns {
+		f := tx.Statement.Schema.LookUpField(col)
+		if f == nil {
+			continue
+		}
+		if v, zero := f.ValueOf(tx.Statement.Context, rv); !zero {
+			keys[col] = fmt.Sprint(v)
+		}
+	}
+	for col, link := range linkedKeys[tx.Statement.Schema.Table] {
+		if _, ok := keys[col]; ok {
+			continue
+		}
+		f := tx.Statement.Schema.LookUpField(link.Column)
+		if f == nil {
+			continue
+		}
+		v, zero := f.ValueOf(tx.Statement.Context, rv)
+		if zero {
+			continue
+		}
+		var key string
+		// Read through the connection of the write, so a link made in the
+		// same transaction is seen.
+		err := tx.Session(&gorm.Session{NewDB: true}).Raw(link.Query, v).Row().Scan(&key)
+		if err == nil && key != "" {
+			keys[col] = key
+		}
+	}
+	emit(Event{
+		Entity:  EntityName(tx.Statement.Schema.Table),
+		ID:      row.GetID(),
+		UID:     row.GetUID(),
+		Version: row.GetVersion(),
+		Keys:    keys,
+		Data:    rv.Interface(),
+	})
+}
+
+// EntityName maps a table name to the name used in routes, e.g.
+// payment_line_items -> payment-line-items.
+func EntityName(table string) string {
+	return strings.ReplaceAll(table, "_", "-")
+}
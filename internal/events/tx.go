@@ -0,0 +1,142 @@
+package events
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// pool wraps the connection pool of a database so that the transactions
+// begun on it hold their events until they commit.
+type pool struct {
+	gorm.ConnPool
+	broker *Broker
+}
+
+func (p *pool) BeginTx(ctx context.Context, opts *sql.TxOptions) (gorm.ConnPool, error) {
+	var tx gorm.ConnPool
+	var err error
+	switch beginner := p.ConnPool.(type) {
+	case gorm.TxBeginner:
+		tx, err = beginner.BeginTx(ctx, opts)
+	case gorm.ConnPoolBeginner:
+		tx, err = beginner.BeginTx(ctx, opts)
+	default:
+		return nil, gorm.ErrInvalidTransaction
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &transaction{ConnPool: tx, broker: p.broker, savepoints: map[string]int{}}, nil
+}
+
+// GetDBConn returns the wrapped *sql.DB, for gorm.DB.DB.
+func (p *pool) GetDBConn() (*sql.DB, error) {
+	switch conn := p.ConnPool.(type) {
+	case *sql.DB:
+		return conn, nil
+	case gorm.GetDBConnector:
+		return conn.GetDBConn()
+	}
+	return nil, gorm.ErrInvalidDB
+}
+
+// transaction collects the events of the writes made in it and publishes
+// them once it commits.
+type transaction struct {
+	gorm.ConnPool
+	broker *Broker
+
+	mu      sync.Mutex
+	pending []Event
+	// savepoints maps the savepoints of nested transactions to the number
+	// of events written before them.
+	savepoints map[string]int
+}
+
+func (t *transaction) add(e Event) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pending = append(t.pending, e)
+}
+
+// savePoint records the number of events written before the savepoint name.
+func (t *transaction) savePoint(name string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.savepoints[name] = len(t.pending)
+}
+
+// rollbackTo drops the events written after the savepoint name.
+func (t *transaction) rollbackTo(name string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if n, ok := t.savepoints[name]; ok {
+		t.pending = t.pending[:n]
+	}
+}
+
+func (t *transaction) Commit() error {
+	if err := t.ConnPool.(gorm.TxCommitter).Commit(); err != nil {
+		return err
+	}
+	t.mu.Lock()
+	pending := t.pending
+	t.pending = nil
+	t.mu.Unlock()
+	for _, e := range pending {
+		t.broker.Publish(e)
+	}
+	return nil
+}
+
+func (t *transaction) Rollback() error {
+	t.mu.Lock()
+	t.pending = nil
+	t.mu.Unlock()
+	return t.ConnPool.(gorm.TxCommitter).Rollback()
+}
+
+// dialector hands the savepoints GORM sets and rolls back to, as for nested
+// transactions, to the transaction they are set in.
+type dialector struct {
+	gorm.Dialector
+}
+
+func (d dialector) SavePoint(tx *gorm.DB, name string) error {
+	sp, ok := d.Dialector.(gorm.SavePointerDialectorInterface)
+	if !ok {
+		return gorm.ErrUnsupportedDriver
+	}
+	if err := sp.SavePoint(tx, name); err != nil {
+		return err
+	}
+	if t, ok := tx.Statement.ConnPool.(*transaction); ok {
+		t.savePoint(name)
+	}
+	return nil
+}
+
+func (d dialector) RollbackTo(tx *gorm.DB, name string) error {
+	sp, ok := d.Dialector.(gorm.SavePointerDialectorInterface)
+	if !ok {
+		return gorm.ErrUnsupportedDriver
+	}
+	if err := sp.RollbackTo(tx, name); err != nil {
+		return err
+	}
+	if t, ok := tx.Statement.ConnPool.(*transaction); ok {
+		t.rollbackTo(name)
+	}
+	return nil
+}
+
+// Translate keeps the error translation of the wrapped dialector.
+func (d dialector) Translate(err error) error {
+	if tr, ok := d.Dialector.(gorm.ErrorTranslator); ok {
+		return tr.Translate(err)
+	}
+	return err
+}
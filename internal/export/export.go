@@ -0,0 +1,244 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+	"mercor/internal/scd"
+)
+
+type Format string
+
+const (
+	FormatCSV     Format = "csv"
+	FormatNDJSON  Format = "ndjson"
+	FormatParquet Format = "parquet"
+)
+
+type Scope string
+
+const (
+	ScopeCurrent Scope = "current"
+	ScopeHistory Scope = "history"
+	ScopeAsOf    Scope = "as_of"
+)
+
+// flushEvery is the number of rows written between flushes of the output.
+const flushEvery = 1000
+
+type Options struct {
+	Format Format
+	Scope  Scope
+	AsOf   time.Time
+}
+
+func (o Options) Validate() error {
+	switch o.Format {
+	case FormatCSV, FormatNDJSON, FormatParquet:
+	default:
+		return fmt.Errorf("invalid format %q, expected csv, ndjson or parquet", o.Format)
+	}
+	switch o.Scope {
+	case ScopeCurrent, ScopeHistory:
+	case ScopeAsOf:
+		if o.AsOf.IsZero() {
+			return errors.New("as_of scope requires an as_of time")
+		}
+	default:
+		return fmt.Errorf("invalid scope %q, expected current, history or as_of", o.Scope)
+	}
+	return nil
+}
+
+func (f Format) ContentType() string {
+	switch f {
+	case FormatCSV:
+		return "text/csv"
+	case FormatNDJSON:
+		return "application/x-ndjson"
+	default:
+		return "application/vnd.apache.parquet"
+	}
+}
+
+// Column is one exported column. Names are the database column names plus
+// valid_from and valid_to, so they stay stable across Go refactors.
+type Column struct {
+	Name     string
+	Kind     reflect.Kind
+	Time     bool
+	Nullable bool
+}
+
+type rowWriter interface {
+	WriteRow(values []any) error
+	Flush() error
+	Close() error
+}
+
+// versionRow is scanned from the validity query: the model's own columns plus
+// the derived validity interval.
+type versionRow[T any] struct {
+	Model     T `gorm:"embedded"`
+	ValidFrom time.Time
+	ValidTo   *time.Time
+}
+
+// Write streams the versions of T selected by opts to w, one row at a time.
+func Write[T scd.SCDModel[T]](db *gorm.DB, w io.Writer, opts Options) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+
+	var model T
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(&model); err != nil {
+		return err
+	}
+	fields, cols := columns(stmt.Schema)
+
+	m := scd.NewManager[T](db)
+	var q *gorm.DB
+	switch opts.Scope {
+	case ScopeCurrent:
+		q = m.Current()
+	case ScopeHistory:
+		q = m.WithValidity()
+	case ScopeAsOf:
+		q = m.AsOf(opts.AsOf)
+	}
+	rows, err := q.Order("v.id, v.version").Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	out, err := newRowWriter(opts.Format, w, stmt.Schema.Table, cols)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	values := make([]any, len(cols))
+	for n := 1; rows.Next(); n++ {
+		var row versionRow[T]
+		if err := db.ScanRows(rows, &row); err != nil {
+			return err
+		}
+		rv := reflect.ValueOf(row.Model)
+		for i, f := range fields {
+			v, _ := f.ValueOf(ctx, rv)
+			values[i] = normalize(v)
+		}
+		values[len(fields)] = row.ValidFrom.UTC()
+		if row.ValidTo != nil {
+			values[len(fields)+1] = row.ValidTo.UTC()
+		} else {
+			values[len(fields)+1] = nil
+		}
+		if err := out.WriteRow(values); err != nil {
+			return err
+		}
+		if n%flushEvery == 0 {
+			if err := out.Flush(); err != nil {
+				return err
+			}
+			if f, ok := w.(interface{ Flush() }); ok {
+				f.Flush()
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+func columns(s *schema.Schema) ([]*schema.Field, []Column) {
+	var fields []*schema.Field
+	var cols []Column
+	for _, f := range s.Fields {
+		if f.DBName == "" {
+			continue
+		}
+		fields = append(fields, f)
+		cols = append(cols, columnFor(f.DBName, f.FieldType))
+	}
+	cols = append(cols,
+		Column{Name: "valid_from", Time: true},
+		Column{Name: "valid_to", Time: true, Nullable: true},
+	)
+	return fields, cols
+}
+
+var (
+	timeType = reflect.TypeOf(time.Time{})
+	uuidType = reflect.TypeOf(uuid.UUID{})
+)
+
+func columnFor(name string, t reflect.Type) Column {
+	c := Column{Name: name}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+		c.Nullable = true
+	}
+	switch {
+	case t == timeType:
+		c.Time = true
+		return c
+	case t == uuidType:
+		c.Kind = reflect.String
+		return c
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		c.Kind = reflect.Int64
+	case reflect.Float32, reflect.Float64:
+		c.Kind = reflect.Float64
+	case reflect.Bool:
+		c.Kind = reflect.Bool
+	default:
+		c.Kind = reflect.String
+	}
+	return c
+}
+
+// normalize converts model values to the small set of types the writers
+// handle: string, int64, float64, bool and time.Time.
+func normalize(v any) any {
+	switch x := v.(type) {
+	case uuid.UUID:
+		return x.String()
+	case *uuid.UUID:
+		if x == nil {
+			return nil
+		}
+		return x.String()
+	case time.Time:
+		return x.UTC()
+	case *time.Time:
+		if x == nil {
+			return nil
+		}
+		return x.UTC()
+	case int:
+		return int64(x)
+	case int32:
+		return int64(x)
+	case uint:
+		return int64(x)
+	case float32:
+		return float64(x)
+	case fmt.Stringer:
+		return x.String()
+	}
+	return v
+}
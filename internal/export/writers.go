@@ -0,0 +1,160 @@
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+func newRowWriter(f Format, w io.Writer, name string, cols []Column) (rowWriter, error) {
+	switch f {
+	case FormatCSV:
+		return newCSVWriter(w, cols)
+	case FormatNDJSON:
+		return newNDJSONWriter(w, cols), nil
+	default:
+		return newParquetWriter(w, name, cols), nil
+	}
+}
+
+type csvWriter struct {
+	w      *csv.Writer
+	record []string
+}
+
+func newCSVWriter(w io.Writer, cols []Column) (*csvWriter, error) {
+	cw := &csvWriter{w: csv.NewWriter(w), record: make([]string, len(cols))}
+	for i, c := range cols {
+		cw.record[i] = c.Name
+	}
+	return cw, cw.w.Write(cw.record)
+}
+
+func (cw *csvWriter) WriteRow(values []any) error {
+	for i, v := range values {
+		cw.record[i] = formatValue(v)
+	}
+	return cw.w.Write(cw.record)
+}
+
+func (cw *csvWriter) Flush() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+func (cw *csvWriter) Close() error { return cw.Flush() }
+
+func formatValue(v any) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return x
+	case int64:
+		return strconv.FormatInt(x, 10)
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(x)
+	case time.Time:
+		return x.Format(time.RFC3339Nano)
+	}
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+// ndjsonWriter writes one JSON object per line, keys in column order.
+type ndjsonWriter struct {
+	w    *bufio.Writer
+	keys [][]byte
+}
+
+func newNDJSONWriter(w io.Writer, cols []Column) *ndjsonWriter {
+	nw := &ndjsonWriter{w: bufio.NewWriter(w)}
+	for _, c := range cols {
+		k, _ := json.Marshal(c.Name)
+		nw.keys = append(nw.keys, append(k, ':'))
+	}
+	return nw
+}
+
+func (nw *ndjsonWriter) WriteRow(values []any) error {
+	nw.w.WriteByte('{')
+	for i, v := range values {
+		if i > 0 {
+			nw.w.WriteByte(',')
+		}
+		nw.w.Write(nw.keys[i])
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		nw.w.Write(b)
+	}
+	nw.w.WriteString("}\n")
+	return nil
+}
+
+func (nw *ndjsonWriter) Flush() error { return nw.w.Flush() }
+
+func (nw *ndjsonWriter) Close() error { return nw.w.Flush() }
+
+// parquetRowGroupRows is the number of rows buffered per parquet row group.
+const parquetRowGroupRows = 64 * 1024
+
+// parquetWriter closes a row group every parquetRowGroupRows rows so memory
+// stays bounded.
+type parquetWriter struct {
+	w       *parquet.Writer
+	cols    []Column
+	pending int
+}
+
+func newParquetWriter(w io.Writer, name string, cols []Column) *parquetWriter {
+	group := parquet.Group{}
+	for _, c := range cols {
+		var node parquet.Node
+		switch {
+		case c.Time:
+			node = parquet.Timestamp(parquet.Microsecond)
+		case c.Kind == reflect.Int64:
+			node = parquet.Int(64)
+		case c.Kind == reflect.Float64:
+			node = parquet.Leaf(parquet.DoubleType)
+		case c.Kind == reflect.Bool:
+			node = parquet.Leaf(parquet.BooleanType)
+		default:
+			node = parquet.String()
+		}
+		if c.Nullable {
+			node = parquet.Optional(node)
+		}
+		group[c.Name] = node
+	}
+	return &parquetWriter{w: parquet.NewWriter(w, parquet.NewSchema(name, group)), cols: cols}
+}
+
+func (pw *parquetWriter) WriteRow(values []any) error {
+	row := make(map[string]any, len(values))
+	for i, v := range values {
+		row[pw.cols[i].Name] = v
+	}
+	pw.pending++
+	return pw.w.Write(row)
+}
+
+func (pw *parquetWriter) Flush() error {
+	if pw.pending < parquetRowGroupRows {
+		return nil
+	}
+	pw.pending = 0
+	return pw.w.Flush()
+}
+
+func (pw *parquetWriter) Close() error { return pw.w.Close() }
@@ -0,0 +1,229 @@
+package idempotency
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	HeaderKey      = "Idempotency-Key"
+	HeaderReplayed = "Idempotent-Replayed"
+	// MaxKeyLength bounds the accepted key size.
+	MaxKeyLength = 255
+	// TTL is how long a key is remembered; after that it may be reused.
+	TTL = 24 * time.Hour
+	// MaxBodyBytes bounds the request body buffered for the fingerprint; it
+	// is above the largest upload any endpoint accepts.
+	MaxBodyBytes = 16 << 20
+)
+
+// txKey is the gin context key of the transaction of a request with a key.
+const txKey = "idempotency.tx"
+
+// Tx returns the transaction the request with an Idempotency-Key runs in.
+// Handlers write through it so that their writes and the stored response
+// commit together: a crash in between loses neither or both.
+func Tx(c *gin.Context) (*gorm.DB, bool) {
+	v, ok := c.Get(txKey)
+	if !ok {
+		return nil, false
+	}
+	tx, ok := v.(*gorm.DB)
+	return tx, ok
+}
+
+// Middleware makes POST, PUT, PATCH and DELETE requests carrying an
+// Idempotency-Key header exactly-once from the client's view:
+//   - the first request runs and its response is stored;
+//   - a retry with the same key and request replays the stored response;
+//   - a retry while the first is still running gets 409, however long it runs;
+//   - reusing the key for a different request gets 422.
+//
+// The request runs in a transaction holding a lock on the key, which Tx
+// hands to the handler; the response is stored in it and sent once it
+// commits. Server errors (5xx) and panics roll it back, writes included, so
+// the client can retry them. Should the server stop midway, the lock goes
+// with its connection.
+func Middleware(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(HeaderKey)
+		if key == "" || !mutating(c.Request.Method) {
+			c.Next()
+			return
+		}
+		if len(key) > MaxKeyLength {
+			abort(c, http.StatusBadRequest, "Idempotency-Key is too long")
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, MaxBodyBytes))
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				abort(c, http.StatusRequestEntityTooLarge, "request body is too large")
+				return
+			}
+			abort(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		fp := fingerprint(c.Request, body)
+
+		tx := db.Begin()
+		if tx.Error != nil {
+			abort(c, http.StatusInternalServerError, tx.Error.Error())
+			return
+		}
+		// Rolls back unless committed below; the deferred call also runs when
+		// the handler panics.
+		defer tx.Rollback()
+		existing, claimed, err := claim(tx, key)
+		if err != nil {
+			abort(c, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if !claimed {
+			abort(c, http.StatusConflict, "a request with this Idempotency-Key is still in progress")
+			return
+		}
+		if existing != nil {
+			replay(c, *existing, fp)
+			return
+		}
+
+		// A rejected request is stored without whatever it wrote, and without
+		// a statement that failed in it, which would abort the transaction.
+		if err := tx.SavePoint("request").Error; err != nil {
+			abort(c, http.StatusInternalServerError, err.Error())
+			return
+		}
+		w := &recorder{ResponseWriter: c.Writer}
+		c.Writer = w
+		defer func() { c.Writer = w.ResponseWriter }()
+		c.Set(txKey, tx)
+		c.Next()
+		c.Writer = w.ResponseWriter
+
+		if w.Status() >= http.StatusInternalServerError {
+			tx.Rollback()
+			w.send()
+			return
+		}
+		if w.Status() >= http.StatusBadRequest {
+			err = tx.RollbackTo("request").Error
+		}
+		if err == nil {
+			err = tx.Create(&Record{
+				Key:         key,
+				Method:      c.Request.Method,
+				Path:        c.Request.URL.RequestURI(),
+				Fingerprint: fp,
+				Status:      StatusCompleted,
+				StatusCode:  w.Status(),
+				ContentType: w.Header().Get("Content-Type"),
+				Body:        w.body.Bytes(),
+				CreatedAt:   time.Now(),
+			}).Error
+		}
+		if err == nil {
+			err = tx.Commit().Error
+		}
+		if err != nil {
+			log.Printf("idempotency key %q: failed to store the response: %v", key, err)
+			for name := range w.Header() {
+				w.Header().Del(name)
+			}
+			abort(c, http.StatusInternalServerError, "failed to store the response: "+err.Error())
+			return
+		}
+		w.send()
+	}
+}
+
+// claim locks key for the transaction tx, reporting false if another
+// request holds it. It returns the stored record of the key, if there is one
+// still to replay; an expired one is deleted.
+func claim(tx *gorm.DB, key string) (*Record, bool, error) {
+	var locked bool
+	if err := tx.Raw("SELECT pg_try_advisory_xact_lock(hashtextextended(?, 0))", key).Scan(&locked).Error; err != nil || !locked {
+		return nil, false, err
+	}
+	var existing Record
+	err := tx.Where("key = ?", key).First(&existing).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, true, nil
+	}
+	if err != nil {
+		return nil, true, err
+	}
+	if existing.Status == StatusCompleted && time.Since(existing.CreatedAt) <= TTL {
+		return &existing, true, nil
+	}
+	return nil, true, tx.Delete(&existing).Error
+}
+
+func replay(c *gin.Context, rec Record, fp string) {
+	switch {
+	case rec.Fingerprint != fp:
+		abort(c, http.StatusUnprocessableEntity, "Idempotency-Key was already used for a different request")
+	default:
+		c.Header(HeaderReplayed, "true")
+		c.Data(rec.StatusCode, rec.ContentType, rec.Body)
+		c.Abort()
+	}
+}
+
+func fingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+	io.WriteString(h, r.Method+" "+r.URL.RequestURI()+"\n")
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func mutating(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func abort(c *gin.Context, code int, msg string) {
+	c.AbortWithStatusJSON(code, gin.H{"error": msg})
+}
+
+// recorder holds back the response until it is stored. The status and
+// headers are kept by the wrapped writer, which sends nothing until send.
+type recorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recorder) Write(b []byte) (int, error) {
+	return w.body.Write(b)
+}
+
+func (w *recorder) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
+
+func (w *recorder) WriteHeaderNow() {}
+
+func (w *recorder) Flush() {}
+
+func (w *recorder) Written() bool { return false }
+
+// send writes the held back response.
+func (w *recorder) send() {
+	w.ResponseWriter.WriteHeaderNow()
+	w.ResponseWriter.Write(w.body.Bytes())
+}
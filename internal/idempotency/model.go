@@ -0,0 +1,27 @@
+package idempotency
+
+import "time"
+
+const (
+	// StatusInProgress marks the claims of an earlier release, which were
+	// stored before the response; a key still holding one runs again.
+	StatusInProgress = "in_progress"
+	StatusCompleted  = "completed"
+)
+
+// Record stores the outcome of the first request made with an Idempotency-Key
+// so that retries can be answered with the same response.
+type Record struct {
+	Key         string `gorm:"primaryKey"`
+	Method      string
+	Path        string
+	Fingerprint string
+	Status      string
+	StatusCode  int
+	ContentType string
+	Body        []byte
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (Record) TableName() string { return "idempotency_keys" }
@@ -0,0 +1,79 @@
+package openapi
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// docsPage renders /openapi.json with Redoc.
+const docsPage = `<!DOCTYPE html>
+<html>
+<head>
+  <title>API reference</title>
+  <meta charset="utf-8"/>
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+</head>
+<body>
+  <redoc spec-url="/openapi.json"></redoc>
+  <script src="https://cdn.redoc.ly/redoc/v2.1.5/bundles/redoc.standalone.js"></script>
+</body>
+</html>
+`
+
+type Handler struct {
+	info   Info
+	ops    []Operation
+	engine *gin.Engine
+
+	once sync.Once
+	doc  Document
+}
+
+func NewHandler(info Info, ops ...[]Operation) *Handler {
+	h := &Handler{info: info}
+	for _, o := range ops {
+		h.ops = append(h.ops, o...)
+	}
+	h.ops = append(h.ops, Operations()...)
+	return h
+}
+
+func (h *Handler) RegisterRoutes(r *gin.Engine) {
+	h.engine = r
+	r.GET("/openapi.json", h.Spec)
+	r.GET("/docs", h.Docs)
+}
+
+// Spec serves the document, built on first use so that it covers every route
+// registered by then.
+func (h *Handler) Spec(c *gin.Context) {
+	h.once.Do(func() {
+		h.doc = Build(h.info, h.engine.Routes(), h.ops)
+	})
+	c.JSON(http.StatusOK, h.doc)
+}
+
+func (h *Handler) Docs(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(docsPage))
+}
+
+// Operations documents the routes of this package.
+func Operations() []Operation {
+	return []Operation{
+		{
+			Method: http.MethodGet, Path: "/openapi.json", Tag: "docs",
+			Summary:   "OpenAPI 3.1 document of this API",
+			Responses: Responses{200: JSON("The OpenAPI document", Schema{"type": "object"})},
+		},
+		{
+			Method: http.MethodGet, Path: "/docs", Tag: "docs",
+			Summary: "API reference rendered from /openapi.json",
+			Responses: Responses{200: {
+				Description: "HTML page",
+				Content:     map[string]any{"text/html": Schema{"type": "string"}},
+			}},
+		},
+	}
+}
@@ -0,0 +1,196 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Schema is a JSON Schema object as used by OpenAPI 3.1.
+type Schema map[string]any
+
+var (
+	timeType    = reflect.TypeOf(time.Time{})
+	uuidType    = reflect.TypeOf(uuid.UUID{})
+	rawJSONType = reflect.TypeOf(json.RawMessage{})
+)
+
+// generator derives component schemas from Go types. Structs become named
+// components referenced with $ref; everything else is inlined.
+type generator struct {
+	schemas map[string]Schema
+}
+
+func newGenerator() *generator {
+	return &generator{schemas: map[string]Schema{}}
+}
+
+// partial marks a value whose schema is documented without required members,
+// as for merge patches.
+type partial struct{ v any }
+
+// schemaOf returns the schema for v, which is either a Schema used as is or a
+// value whose type is described.
+func (g *generator) schemaOf(v any) Schema {
+	switch x := v.(type) {
+	case Schema:
+		return x
+	case partial:
+		return g.partialSchema(reflect.TypeOf(x.v))
+	}
+	return g.schemaFor(reflect.TypeOf(v))
+}
+
+// partialSchema derives a <Name>Patch component from the struct schema of t
+// in which every member is optional.
+func (g *generator) partialSchema(t reflect.Type) Schema {
+	g.schemaFor(t)
+	name := schemaName(t) + "Patch"
+	if _, ok := g.schemas[name]; !ok {
+		full := g.schemas[schemaName(t)]
+		g.schemas[name] = Schema{"type": "object", "properties": full["properties"]}
+	}
+	return Schema{"$ref": "#/components/schemas/" + name}
+}
+
+func (g *generator) schemaFor(t reflect.Type) Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t {
+	case timeType:
+		return Schema{"type": "string", "format": "date-time"}
+	case uuidType:
+		return Schema{"type": "string", "format": "uuid"}
+	case rawJSONType:
+		return Schema{}
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return Schema{"type": "string"}
+	case reflect.Bool:
+		return Schema{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Schema{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return Schema{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return Schema{"type": "string", "format": "byte"}
+		}
+		return Schema{"type": "array", "items": g.schemaFor(t.Elem())}
+	case reflect.Map:
+		return Schema{"type": "object", "additionalProperties": g.schemaFor(t.Elem())}
+	case reflect.Struct:
+		name := schemaName(t)
+		if _, ok := g.schemas[name]; !ok {
+			g.schemas[name] = Schema{} // placeholder for recursive types
+			g.schemas[name] = g.structSchema(t)
+		}
+		return Schema{"$ref": "#/components/schemas/" + name}
+	}
+	return Schema{}
+}
+
+// structSchema lists the JSON members of t. A struct with binding tags is a
+// request type and its required members are the ones bound as required; for
+// other structs every member without omitempty is required.
+func (g *generator) structSchema(t reflect.Type) Schema {
+	props := Schema{}
+	var required []string
+	isRequest := hasBindingTags(t)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		s := g.schemaFor(f.Type)
+		rules := f.Tag.Get("binding")
+		applyRules(s, rules)
+		props[name] = s
+		if isRequest && hasRule(rules, "required") || !isRequest && !strings.Contains(opts, "omitempty") {
+			required = append(required, name)
+		}
+	}
+	out := Schema{"type": "object", "properties": props}
+	if len(required) > 0 {
+		out["required"] = required
+	}
+	return out
+}
+
+func hasBindingTags(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Tag.Get("binding") != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func hasRule(rules, name string) bool {
+	for _, r := range strings.Split(rules, ",") {
+		if r == name {
+			return true
+		}
+	}
+	return false
+}
+
+// applyRules translates the validator rules used by the request types.
+func applyRules(s Schema, rules string) {
+	if rules == "" {
+		return
+	}
+	for _, r := range strings.Split(rules, ",") {
+		name, param, _ := strings.Cut(r, "=")
+		switch name {
+		case "uuid":
+			s["format"] = "uuid"
+		case "gt":
+			s["exclusiveMinimum"] = number(param)
+		case "gte":
+			s["minimum"] = number(param)
+		case "max":
+			if s["type"] == "string" {
+				s["maxLength"] = number(param)
+			} else {
+				s["maximum"] = number(param)
+			}
+		case "oneof":
+			s["enum"] = strings.Fields(param)
+		case "gtfield":
+			s["description"] = "Must be after " + strings.ToLower(param[:1]) + param[1:] + "."
+		}
+	}
+}
+
+func number(s string) any {
+	if n, err := strconv.ParseFloat(s, 64); err == nil {
+		return n
+	}
+	return s
+}
+
+var packagePath = regexp.MustCompile(`[A-Za-z0-9_\-./]+\.`)
+
+// schemaName turns a Go type name into a component name; generic types such
+// as BatchResult[jobs.JobResponse] become BatchResult_JobResponse.
+func schemaName(t reflect.Type) string {
+	name := packagePath.ReplaceAllString(t.Name(), "")
+	name = strings.NewReplacer("[", "_", ",", "_", "]", "", " ", "").Replace(name)
+	return name
+}
@@ -0,0 +1,300 @@
+// Package openapi builds the OpenAPI 3.1 document of the service from the Gin
+// route table and the operations each domain package describes.
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const Version = "3.1.0"
+
+// Operation documents one route. Path uses Gin syntax, e.g. /jobs/:uid.
+type Operation struct {
+	Method      string
+	Path        string
+	Tag         string
+	Summary     string
+	Description string
+	Params      []Param
+	Body        *RequestBody
+	Responses   Responses
+}
+
+// Param is a query, header or path parameter. Path parameters that are not
+// listed are documented as required strings, UUIDs when named id or uid.
+type Param struct {
+	Name        string
+	In          string
+	Description string
+	Required    bool
+	Schema      Schema
+}
+
+// RequestBody and Response map media types to either a Schema or a value of
+// the Go type that is sent, from which the schema is derived.
+type RequestBody struct {
+	Description string
+	Content     map[string]any
+}
+
+type Response struct {
+	Description string
+	Content     map[string]any
+}
+
+type Responses map[int]Response
+
+// Error is the body of every error response.
+type Error struct {
+	Error string `json:"error"`
+}
+
+// ValidationError is returned when a request body fails its binding rules.
+type ValidationError struct {
+	Error  string            `json:"error"`
+	Fields map[string]string `json:"fields,omitempty"`
+}
+
+// JSONPatchOperation is one operation of an RFC 6902 JSON Patch.
+type JSONPatchOperation struct {
+	Op    string `json:"op" binding:"required,oneof=add remove replace move copy test"`
+	Path  string `json:"path" binding:"required"`
+	From  string `json:"from"`
+	Value any    `json:"value"`
+}
+
+// Common responses shared by the domain packages.
+var (
+	BadRequest  = JSON("Malformed request", Error{})
+	Invalid     = JSON("Validation failed", ValidationError{})
+	NotFound    = JSON("Not found", Error{})
+	Conflict    = JSON("Conflict with the current state", Error{})
+	ServerError = JSON("Internal error", Error{})
+	// PreconditionFailed is returned when If-Match does not list the ETag of
+	// the head version.
+	PreconditionFailed = JSON("The entity has a newer version than If-Match names", Error{})
+	// InvalidPeriod is returned when effectiveFrom lies before the entity
+	// existed, or in the future for a new entity.
+	InvalidPeriod = JSON("effectiveFrom cannot be applied to the history", Error{})
+	// InvalidPeriodOrReference is InvalidPeriod for writes that reference
+	// other entities, which are also rejected when one of them does not exist.
+	InvalidPeriodOrReference = JSON("effectiveFrom cannot be applied to the history, or a referenced company or contractor does not exist", Error{})
+)
+
+// Common parameters of the versioned resources.
+var (
+	IfMatch = Param{
+		Name:        "If-Match",
+		In:          "header",
+		Description: "ETag of the head version the write is based on. The write fails with 412 if a newer version exists.",
+	}
+	AsOf = Param{
+		Name:        "as_of",
+		In:          "query",
+		Description: "Return the version of the same entity that was effective at this RFC 3339 time instead.",
+		Schema:      Schema{"type": "string", "format": "date-time"},
+	}
+	KnownAt = Param{
+		Name:        "known_at",
+		In:          "query",
+		Description: "Answer as recorded at this RFC 3339 time, before later back-dated corrections. Without as_of it also sets the effective time.",
+		Schema:      Schema{"type": "string", "format": "date-time"},
+	}
+)
+
+// JSON is a response with an application/json body of v's type.
+func JSON(description string, v any) Response {
+	return Response{Description: description, Content: map[string]any{"application/json": v}}
+}
+
+// Empty is a response without a body.
+func Empty(description string) Response {
+	return Response{Description: description}
+}
+
+// Body is an application/json request body of v's type.
+func Body(v any) *RequestBody {
+	return &RequestBody{Content: map[string]any{"application/json": v}}
+}
+
+// PatchBody documents the PATCH media types for a resource whose response
+// representation is v.
+func PatchBody(v any) *RequestBody {
+	return &RequestBody{
+		Description: "A merge patch (RFC 7386) or JSON Patch (RFC 6902) of the response representation.",
+		Content: map[string]any{
+			"application/merge-patch+json": partial{v},
+			"application/json-patch+json":  []JSONPatchOperation{},
+		},
+	}
+}
+
+// Info describes the API as a whole.
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+type Document struct {
+	OpenAPI    string                       `json:"openapi"`
+	Info       Info                         `json:"info"`
+	Paths      map[string]map[string]Schema `json:"paths"`
+	Components map[string]map[string]Schema `json:"components"`
+}
+
+// Build documents every route in routes that has an operation in ops. Routes
+// without one are left out, which the route coverage test reports.
+func Build(info Info, routes gin.RoutesInfo, ops []Operation) Document {
+	byRoute := map[string]Operation{}
+	for _, op := range ops {
+		byRoute[op.Method+" "+op.Path] = op
+	}
+	g := newGenerator()
+	doc := Document{
+		OpenAPI: Version,
+		Info:    info,
+		Paths:   map[string]map[string]Schema{},
+	}
+	for _, rt := range routes {
+		op, ok := byRoute[rt.Method+" "+rt.Path]
+		if !ok {
+			continue
+		}
+		path := Path(rt.Path)
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = map[string]Schema{}
+		}
+		doc.Paths[path][strings.ToLower(rt.Method)] = g.operation(op)
+	}
+	doc.Components = map[string]map[string]Schema{"schemas": g.schemas}
+	return doc
+}
+
+// Path converts a Gin path to an OpenAPI path template.
+func Path(ginPath string) string {
+	segments := strings.Split(ginPath, "/")
+	for i, s := range segments {
+		if strings.HasPrefix(s, ":") || strings.HasPrefix(s, "*") {
+			segments[i] = "{" + s[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func (g *generator) operation(op Operation) Schema {
+	out := Schema{
+		"operationId": operationID(op),
+		"summary":     op.Summary,
+	}
+	if op.Tag != "" {
+		out["tags"] = []string{op.Tag}
+	}
+	if op.Description != "" {
+		out["description"] = op.Description
+	}
+	if params := g.parameters(op); len(params) > 0 {
+		out["parameters"] = params
+	}
+	if op.Body != nil {
+		body := Schema{"required": true, "content": g.content(op.Body.Content)}
+		if op.Body.Description != "" {
+			body["description"] = op.Body.Description
+		}
+		out["requestBody"] = body
+	}
+	responses := Schema{}
+	for status, r := range op.Responses {
+		resp := Schema{"description": r.Description}
+		if len(r.Content) > 0 {
+			resp["content"] = g.content(r.Content)
+		}
+		responses[fmt.Sprint(status)] = resp
+	}
+	out["responses"] = responses
+	return out
+}
+
+func (g *generator) parameters(op Operation) []Schema {
+	listed := map[string]bool{}
+	var params []Schema
+	add := func(p Param) {
+		s := p.Schema
+		if s == nil {
+			s = Schema{"type": "string"}
+		}
+		param := Schema{"name": p.Name, "in": p.In, "schema": s}
+		if p.Required || p.In == "path" {
+			param["required"] = true
+		}
+		if p.Description != "" {
+			param["description"] = p.Description
+		}
+		params = append(params, param)
+	}
+	for _, p := range op.Params {
+		listed[p.In+" "+p.Name] = true
+		add(p)
+	}
+	for _, s := range strings.Split(op.Path, "/") {
+		name := strings.TrimPrefix(s, ":")
+		if name == s || listed["path "+name] {
+			continue
+		}
+		p := Param{Name: name, In: "path"}
+		if name == "id" || name == "uid" {
+			p.Schema = Schema{"type": "string", "format": "uuid"}
+		}
+		add(p)
+	}
+	if mutating(op.Method) {
+		add(Param{
+			Name:        "Idempotency-Key",
+			In:          "header",
+			Description: "Makes retries of this request return the stored response of the first attempt.",
+			Schema:      Schema{"type": "string", "maxLength": 255},
+		})
+	}
+	return params
+}
+
+func (g *generator) content(c map[string]any) Schema {
+	out := Schema{}
+	for mediaType, v := range c {
+		out[mediaType] = Schema{"schema": g.schemaOf(v)}
+	}
+	return out
+}
+
+func mutating(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// operationID derives a stable identifier such as put_jobs_uid_status.
+func operationID(op Operation) string {
+	parts := strings.FieldsFunc(op.Path, func(r rune) bool {
+		return r == '/' || r == ':' || r == '-' || r == '.'
+	})
+	return strings.ToLower(op.Method) + "_" + strings.Join(parts, "_")
+}
+
+// Undocumented lists the routes that have no operation in doc.
+func Undocumented(routes gin.RoutesInfo, doc Document) []string {
+	var missing []string
+	for _, rt := range routes {
+		if _, ok := doc.Paths[Path(rt.Path)][strings.ToLower(rt.Method)]; !ok {
+			missing = append(missing, rt.Method+" "+rt.Path)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
@@ -0,0 +1,385 @@
+// Package patch applies JSON Merge Patch (RFC 7386) and JSON Patch (RFC 6902)
+// documents to JSON encoded entities.
+//
+// Object members are matched exactly, as RFC 6901 requires: "companyID" in a
+// patch does not address the "companyId" member of the document.
+package patch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"mercor/internal/scd"
+)
+
+const (
+	ContentTypeMergePatch = "application/merge-patch+json"
+	ContentTypeJSONPatch  = "application/json-patch+json"
+)
+
+var (
+	ErrInvalidPatch         = errors.New("invalid patch")
+	ErrUnsupportedMediaType = errors.New("unsupported patch content type")
+	// ErrTestFailed is returned when a JSON Patch "test" operation fails.
+	ErrTestFailed = errors.New("patch test operation failed")
+	// ErrInvalidResult wraps validation errors of the patched entity.
+	ErrInvalidResult = errors.New("patched entity is invalid")
+)
+
+// StatusCode maps errors returned while patching an entity to HTTP statuses.
+func StatusCode(err error) int {
+	switch {
+	case errors.Is(err, ErrUnsupportedMediaType):
+		return http.StatusUnsupportedMediaType
+	case errors.Is(err, ErrInvalidPatch):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrTestFailed):
+		return http.StatusConflict
+	case errors.Is(err, ErrInvalidResult):
+		return http.StatusUnprocessableEntity
+	}
+	return scd.StatusCode(err)
+}
+
+// Entity applies a patch to the JSON encoding of current, usually the response
+// representation of the head version, and decodes the result into a T, which
+// is then checked with validate.
+func Entity[T any](current any, contentType string, p []byte, validate func(T) error) (T, error) {
+	var out T
+	doc, err := json.Marshal(current)
+	if err != nil {
+		return out, err
+	}
+	patched, err := Apply(contentType, doc, p)
+	if err != nil {
+		return out, err
+	}
+	if err := json.Unmarshal(patched, &out); err != nil {
+		return out, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+	if err := validate(out); err != nil {
+		return out, fmt.Errorf("%w: %v", ErrInvalidResult, err)
+	}
+	return out, nil
+}
+
+// Apply dispatches on the request content type. Plain application/json is
+// treated as a merge patch.
+func Apply(contentType string, doc, p []byte) ([]byte, error) {
+	switch mediaType(contentType) {
+	case ContentTypeJSONPatch:
+		return JSONPatch(doc, p)
+	case ContentTypeMergePatch, "application/json", "":
+		return MergePatch(doc, p)
+	}
+	return nil, fmt.Errorf("%w %q", ErrUnsupportedMediaType, contentType)
+}
+
+func mediaType(ct string) string {
+	if i := strings.IndexByte(ct, ';'); i >= 0 {
+		ct = ct[:i]
+	}
+	return strings.TrimSpace(strings.ToLower(ct))
+}
+
+// MergePatch applies an RFC 7386 merge patch to doc.
+func MergePatch(doc, p []byte) ([]byte, error) {
+	var target, patch any
+	if err := json.Unmarshal(doc, &target); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(p, &patch); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+	return json.Marshal(mergeValue(target, patch))
+}
+
+func mergeValue(target, patch any) any {
+	p, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	t, ok := target.(map[string]any)
+	if !ok {
+		t = map[string]any{}
+	}
+	for k, v := range p {
+		if v == nil {
+			delete(t, k)
+			continue
+		}
+		t[k] = mergeValue(t[k], v)
+	}
+	return t
+}
+
+type operation struct {
+	Op   string `json:"op"`
+	Path string `json:"path"`
+	From string `json:"from"`
+	// Value is nil when the operation has no "value" member; "value": null
+	// is kept as the literal null.
+	Value json.RawMessage `json:"-"`
+}
+
+func (o *operation) UnmarshalJSON(b []byte) error {
+	type plain operation
+	if err := json.Unmarshal(b, (*plain)(o)); err != nil {
+		return err
+	}
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(b, &members); err != nil {
+		return err
+	}
+	o.Value = members["value"]
+	return nil
+}
+
+// JSONPatch applies an RFC 6902 patch to doc. The patch is atomic: any failing
+// operation leaves doc unchanged.
+func JSONPatch(doc, p []byte) ([]byte, error) {
+	var root any
+	if err := json.Unmarshal(doc, &root); err != nil {
+		return nil, err
+	}
+	var ops []operation
+	if err := json.Unmarshal(p, &ops); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+	for i, op := range ops {
+		var err error
+		root, err = applyOp(root, op)
+		if err != nil {
+			if errors.Is(err, ErrTestFailed) {
+				return nil, fmt.Errorf("operation %d: %w", i, err)
+			}
+			return nil, fmt.Errorf("%w: operation %d (%s %s): %v", ErrInvalidPatch, i, op.Op, op.Path, err)
+		}
+	}
+	return json.Marshal(root)
+}
+
+func applyOp(root any, op operation) (any, error) {
+	value := func() (any, error) {
+		if op.Value == nil {
+			return nil, errors.New("missing value")
+		}
+		var v any
+		err := json.Unmarshal(op.Value, &v)
+		return v, err
+	}
+	switch op.Op {
+	case "add":
+		v, err := value()
+		if err != nil {
+			return nil, err
+		}
+		return add(root, op.Path, v)
+	case "remove":
+		root, _, err := remove(root, op.Path)
+		return root, err
+	case "replace":
+		v, err := value()
+		if err != nil {
+			return nil, err
+		}
+		root, _, err = remove(root, op.Path)
+		if err != nil {
+			return nil, err
+		}
+		return add(root, op.Path, v)
+	case "move":
+		if strings.HasPrefix(op.Path, op.From+"/") {
+			return nil, errors.New("cannot move a value into itself")
+		}
+		root, v, err := remove(root, op.From)
+		if err != nil {
+			return nil, err
+		}
+		return add(root, op.Path, v)
+	case "copy":
+		v, err := get(root, op.From)
+		if err != nil {
+			return nil, err
+		}
+		return add(root, op.Path, deepCopy(v))
+	case "test":
+		want, err := value()
+		if err != nil {
+			return nil, err
+		}
+		got, err := get(root, op.Path)
+		if err != nil {
+			return nil, err
+		}
+		if !equal(got, want) {
+			return nil, ErrTestFailed
+		}
+		return root, nil
+	}
+	return nil, fmt.Errorf("unknown op %q", op.Op)
+}
+
+// splitPointer parses an RFC 6901 JSON pointer into reference tokens.
+func splitPointer(ptr string) ([]string, error) {
+	if ptr == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(ptr, "/") {
+		return nil, fmt.Errorf("invalid pointer %q", ptr)
+	}
+	parts := strings.Split(ptr[1:], "/")
+	for i, p := range parts {
+		parts[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(p)
+	}
+	return parts, nil
+}
+
+func get(root any, ptr string) (any, error) {
+	tokens, err := splitPointer(ptr)
+	if err != nil {
+		return nil, err
+	}
+	cur := root
+	for _, tok := range tokens {
+		switch c := cur.(type) {
+		case map[string]any:
+			v, ok := c[tok]
+			if !ok {
+				return nil, fmt.Errorf("path %q not found", ptr)
+			}
+			cur = v
+		case []any:
+			i, err := index(tok, len(c))
+			if err != nil {
+				return nil, err
+			}
+			cur = c[i]
+		default:
+			return nil, fmt.Errorf("path %q not found", ptr)
+		}
+	}
+	return cur, nil
+}
+
+// parent resolves every token but the last, returning the container and the
+// final token.
+func parent(root any, ptr string) (any, string, error) {
+	tokens, err := splitPointer(ptr)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(tokens) == 0 {
+		return nil, "", nil
+	}
+	last := tokens[len(tokens)-1]
+	prefix := ""
+	for _, tok := range tokens[:len(tokens)-1] {
+		prefix += "/" + strings.NewReplacer("~", "~0", "/", "~1").Replace(tok)
+	}
+	container, err := get(root, prefix)
+	return container, last, err
+}
+
+func add(root any, ptr string, v any) (any, error) {
+	container, tok, err := parent(root, ptr)
+	if err != nil {
+		return nil, err
+	}
+	if ptr == "" {
+		return v, nil
+	}
+	switch c := container.(type) {
+	case map[string]any:
+		c[tok] = v
+		return root, nil
+	case []any:
+		i := len(c)
+		if tok != "-" {
+			if i, err = index(tok, len(c)+1); err != nil {
+				return nil, err
+			}
+		}
+		grown := append(c[:i:i], append([]any{v}, c[i:]...)...)
+		return replaceContainer(root, ptr, grown)
+	}
+	return nil, fmt.Errorf("path %q not found", ptr)
+}
+
+func remove(root any, ptr string) (any, any, error) {
+	if ptr == "" {
+		return nil, nil, errors.New("cannot remove the whole document")
+	}
+	container, tok, err := parent(root, ptr)
+	if err != nil {
+		return nil, nil, err
+	}
+	switch c := container.(type) {
+	case map[string]any:
+		v, ok := c[tok]
+		if !ok {
+			return nil, nil, fmt.Errorf("path %q not found", ptr)
+		}
+		delete(c, tok)
+		return root, v, nil
+	case []any:
+		i, err := index(tok, len(c))
+		if err != nil {
+			return nil, nil, err
+		}
+		v := c[i]
+		shrunk := append(c[:i:i], c[i+1:]...)
+		root, err = replaceContainer(root, ptr, shrunk)
+		return root, v, err
+	}
+	return nil, nil, fmt.Errorf("path %q not found", ptr)
+}
+
+// replaceContainer stores a resized array back into its own parent, since
+// slices cannot grow or shrink in place.
+func replaceContainer(root any, ptr string, arr []any) (any, error) {
+	containerPtr := ptr[:strings.LastIndexByte(ptr, '/')]
+	if containerPtr == "" {
+		return arr, nil
+	}
+	holder, tok, err := parent(root, containerPtr)
+	if err != nil {
+		return nil, err
+	}
+	switch h := holder.(type) {
+	case map[string]any:
+		h[tok] = arr
+	case []any:
+		i, err := index(tok, len(h))
+		if err != nil {
+			return nil, err
+		}
+		h[i] = arr
+	}
+	return root, nil
+}
+
+func index(tok string, n int) (int, error) {
+	i, err := strconv.Atoi(tok)
+	if err != nil || i < 0 || i >= n || (len(tok) > 1 && tok[0] == '0') {
+		return 0, fmt.Errorf("invalid array index %q", tok)
+	}
+	return i, nil
+}
+
+func deepCopy(v any) any {
+	b, _ := json.Marshal(v)
+	var out any
+	json.Unmarshal(b, &out)
+	return out
+}
+
+func equal(a, b any) bool {
+	ab, _ := json.Marshal(a)
+	bb, _ := json.Marshal(b)
+	return string(ab) == string(bb)
+}
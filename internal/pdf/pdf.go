@@ -0,0 +1,143 @@
+// Package pdf renders plain text documents as PDF: lines of Courier on A4
+// pages, enough for invoices and statements without a layout engine.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	pageWidth    = 595 // A4 in points
+	pageHeight   = 842
+	margin       = 50
+	fontSize     = 10
+	leading      = 13
+	linesPerPage = (pageHeight - 2*margin) / leading
+)
+
+// Line is one line of text. Bold lines are set in Courier-Bold.
+type Line struct {
+	Text string
+	Bold bool
+}
+
+// Document collects lines and breaks them into pages when written.
+type Document struct {
+	Title string
+	lines []Line
+}
+
+// Add appends lines of regular text.
+func (d *Document) Add(text ...string) {
+	for _, t := range text {
+		d.lines = append(d.lines, Line{Text: t})
+	}
+}
+
+// Heading appends a bold line.
+func (d *Document) Heading(text string) {
+	d.lines = append(d.lines, Line{Text: text, Bold: true})
+}
+
+// Addf appends a line formatted with fmt.Sprintf.
+func (d *Document) Addf(format string, args ...any) {
+	d.Add(fmt.Sprintf(format, args...))
+}
+
+// WriteTo writes the document as a PDF 1.4 file.
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	pages := paginate(d.lines)
+
+	// Objects 1-4 are the catalog, the page tree, the fonts and the info
+	// dictionary; each page takes two more, the page and its content stream.
+	var objects []string
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+	objects = append(objects,
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)),
+		"<< /F1 << /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>"+
+			" /F2 << /Type /Font /Subtype /Type1 /BaseFont /Courier-Bold /Encoding /WinAnsiEncoding >> >>",
+		fmt.Sprintf("<< /Title (%s) /Producer (mercor) >>", escape(d.Title)),
+	)
+	for i, page := range pages {
+		content := pageContent(page)
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font 3 0 R >> /Contents %d 0 R >>",
+				pageWidth, pageHeight, 6+2*i),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content),
+		)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R /Info 4 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return buf.WriteTo(w)
+}
+
+// paginate splits lines into pages; an empty document still has one page.
+func paginate(lines []Line) [][]Line {
+	var pages [][]Line
+	for len(lines) > linesPerPage {
+		pages = append(pages, lines[:linesPerPage])
+		lines = lines[linesPerPage:]
+	}
+	return append(pages, lines)
+}
+
+func pageContent(lines []Line) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "BT\n%d TL\n%d %d Td\n", leading, margin, pageHeight-margin-fontSize)
+	font := ""
+	for _, l := range lines {
+		f := "/F1"
+		if l.Bold {
+			f = "/F2"
+		}
+		if f != font {
+			fmt.Fprintf(&b, "%s %d Tf\n", f, fontSize)
+			font = f
+		}
+		fmt.Fprintf(&b, "(%s) Tj T*\n", escape(l.Text))
+	}
+	b.WriteString("ET")
+	return b.String()
+}
+
+// escape encodes s as the body of a PDF literal string in WinAnsiEncoding.
+// Characters outside Latin-1 are replaced with '?'.
+func escape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '\t':
+			b.WriteString("    ")
+		case r < 0x20:
+		case r < 0x80:
+			b.WriteRune(r)
+		case r < 0x100:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
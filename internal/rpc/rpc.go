@@ -0,0 +1,109 @@
+// Package rpc holds what the gRPC servers of the domain packages share:
+// mapping errors to status codes and converting times and preconditions.
+package rpc
+
+import (
+	"context"
+	"errors"
+	"log"
+	"runtime/debug"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+	"mercor/internal/patch"
+	"mercor/internal/scd"
+	"mercor/internal/validation"
+)
+
+// Error converts an error returned by a domain service into a gRPC status.
+// A failed If-Match style precondition is ABORTED, so clients re-read and
+// retry, as for any concurrency conflict.
+func Error(err error) error {
+	if err == nil {
+		return nil
+	}
+	var verr *validation.Error
+	switch {
+	case errors.As(err, &verr):
+		return status.Error(codes.InvalidArgument, verr.Error())
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, scd.ErrPreconditionFailed):
+		return status.Error(codes.Aborted, err.Error())
+	case errors.Is(err, scd.ErrInvalidPeriod):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, scd.ErrNotScheduled):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, scd.ErrNotInHistory):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, scd.ErrUnknownReference):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, scd.ErrIdentityChanged):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, patch.ErrInvalidResult):
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+// Invalid reports a request that failed validation.
+func Invalid(err error) error {
+	return status.Error(codes.InvalidArgument, validation.Translate(err).Error())
+}
+
+// ExpectHead is the precondition for an expected_head_uid field; empty
+// matches any head version.
+func ExpectHead(uid string) scd.Precondition {
+	if uid == "" {
+		return scd.Precondition{}
+	}
+	return scd.IfMatch(scd.ETag(uid))
+}
+
+// Timestamp converts t, leaving the zero time unset.
+func Timestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+// Time converts ts, mapping an unset timestamp to the zero time.
+func Time(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
+
+// UUIDArg checks that a request field holds a UUID.
+func UUIDArg(name, v string) error {
+	if _, err := uuid.Parse(v); err != nil {
+		return status.Errorf(codes.InvalidArgument, "%s must be a valid UUID", name)
+	}
+	return nil
+}
+
+// RecoverUnary and RecoverStream turn a panicking call into an INTERNAL
+// error, as gin.Recovery does for HTTP, instead of crashing the process.
+func RecoverUnary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+	defer recoverTo(info.FullMethod, &err)
+	return handler(ctx, req)
+}
+
+func RecoverStream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer recoverTo(info.FullMethod, &err)
+	return handler(srv, ss)
+}
+
+func recoverTo(method string, err *error) {
+	if r := recover(); r != nil {
+		log.Printf("panic in %s: %v\n%s", method, r, debug.Stack())
+		*err = status.Error(codes.Internal, "internal error")
+	}
+}
@@ -0,0 +1,242 @@
+package scd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MaxBatchItems bounds the number of operations accepted in one batch.
+const MaxBatchItems = 1000
+
+// insertBatchSize is the number of rows sent per INSERT statement.
+const insertBatchSize = 100
+
+type BatchMode string
+
+const (
+	// BatchAtomic writes nothing if any item fails.
+	BatchAtomic BatchMode = "atomic"
+	// BatchContinue writes the valid items and reports the failed ones.
+	BatchContinue BatchMode = "continue"
+)
+
+const (
+	BatchOpCreate = "create"
+	BatchOpUpdate = "update"
+)
+
+var ErrBatchRejected = errors.New("batch rejected: one or more items failed")
+
+type BatchOp[T any] struct {
+	Op   string `json:"op"`
+	UID  string `json:"uid,omitempty"`
+	Data T      `json:"data"`
+}
+
+type BatchRequest[T any] struct {
+	Mode  BatchMode    `json:"mode,omitempty"`
+	Items []BatchOp[T] `json:"items"`
+}
+
+// Validate checks the envelope of the request and fills in the default mode.
+func (r *BatchRequest[T]) Validate() error {
+	if r.Mode == "" {
+		r.Mode = BatchAtomic
+	}
+	if r.Mode != BatchAtomic && r.Mode != BatchContinue {
+		return fmt.Errorf("invalid mode %q", r.Mode)
+	}
+	if len(r.Items) == 0 {
+		return errors.New("items must not be empty")
+	}
+	if len(r.Items) > MaxBatchItems {
+		return fmt.Errorf("at most %d items per batch", MaxBatchItems)
+	}
+	return nil
+}
+
+type BatchResult[T any] struct {
+	Index int    `json:"index"`
+	Op    string `json:"op"`
+	OK    bool   `json:"ok"`
+	Item  *T     `json:"item,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// BatchResponse is the body returned for a batch. Error is set when an atomic
+// batch was rejected.
+type BatchResponse[T any] struct {
+	Error   string           `json:"error,omitempty"`
+	Results []BatchResult[T] `json:"results"`
+}
+
+// BatchHooks supply the entity specific parts of a batch: validating the
+// client input I and building either a first version or the version following
+// the stored one from it.
+type BatchHooks[I, T any] struct {
+	Create func(in I) (T, error)
+	Update func(old T, in I) (T, error)
+}
+
+// MapBatchResults converts the items of results with f, typically from models
+// to response types.
+func MapBatchResults[T, R any](results []BatchResult[T], f func(T) R) []BatchResult[R] {
+	out := make([]BatchResult[R], len(results))
+	for i, r := range results {
+		out[i] = BatchResult[R]{Index: r.Index, Op: r.Op, OK: r.OK, Error: r.Error}
+		if r.Item != nil {
+			item := f(*r.Item)
+			out[i].Item = &item
+		}
+	}
+	return out
+}
+
+func (m *SCDManager[T]) WithTx(tx *gorm.DB) *SCDManager[T] {
+	return &SCDManager[T]{db: tx}
+}
+
+// Transaction runs fn in a transaction. On a split table the version a
+// writer waits to lock may be moved to a history partition by the write it
+// waited for, which Postgres reports as a serialization failure; fn is then
+// run again, and finds the version where it was moved.
+func (m *SCDManager[T]) Transaction(fn func(tx *SCDManager[T]) error) error {
+	for attempt := 1; ; attempt++ {
+		err := m.db.Transaction(func(tx *gorm.DB) error {
+			return fn(m.WithTx(tx))
+		})
+		if attempt == maxAttempts || !movedConcurrently(err) {
+			return err
+		}
+	}
+}
+
+func (m *SCDManager[T]) InsertBatch(items []T) error {
+	if len(items) == 0 {
+		return nil
+	}
+	return m.db.CreateInBatches(&items, insertBatchSize).Error
+}
+
+// ApplyBatch prepares every operation, then writes the resulting versions in
+// a single transaction. New entities are written with batched inserts;
+// updates go through the change policy and the effective period of the
+// entity like single writes. In atomic mode any failed item aborts the whole
+// batch and ErrBatchRejected is returned with the results.
+func ApplyBatch[T SCDModel[T], I any](m *SCDManager[T], ops []BatchOp[I], mode BatchMode, hooks BatchHooks[I, T]) ([]BatchResult[T], error) {
+	results := make([]BatchResult[T], len(ops))
+	err := m.Transaction(func(tx *SCDManager[T]) error {
+		var prepared []preparedOp[T]
+		var idx []int
+		updated := map[string]bool{}
+		failed := false
+		now := time.Now()
+
+		for i, op := range ops {
+			results[i] = BatchResult[T]{Index: i, Op: op.Op}
+			p, err := prepareBatchOp(tx, op, hooks, updated, now)
+			if err != nil {
+				results[i].Error = err.Error()
+				failed = true
+				continue
+			}
+			prepared = append(prepared, p)
+			idx = append(idx, i)
+		}
+		if failed && mode == BatchAtomic {
+			return ErrBatchRejected
+		}
+
+		var rows []T
+		for n, p := range prepared {
+			if !p.update {
+				rows = append(rows, p.row)
+				continue
+			}
+			row, err := tx.store(p.head, p.row)
+			if errors.Is(err, ErrInvalidPeriod) || errors.Is(err, ErrUnknownReference) || errors.Is(err, ErrIdentityChanged) {
+				results[idx[n]].Error = err.Error()
+				if mode == BatchAtomic {
+					return ErrBatchRejected
+				}
+				prepared[n].failed = true
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			prepared[n].row = row
+		}
+		if err := tx.InsertBatch(rows); err != nil {
+			return err
+		}
+		for n, i := range idx {
+			p := &prepared[n]
+			if !p.update {
+				p.row, rows = rows[0], rows[1:]
+			}
+			if p.failed {
+				continue
+			}
+			row := p.row
+			results[i].OK = true
+			results[i].Item = &row
+		}
+		return nil
+	})
+	if err != nil {
+		for i := range results {
+			results[i].OK = false
+			results[i].Item = nil
+		}
+	}
+	return results, err
+}
+
+// preparedOp is an operation ready to be written: the row it stores and, for
+// an update, the head version it applies to.
+type preparedOp[T any] struct {
+	head   T
+	row    T
+	update bool
+	failed bool
+}
+
+func prepareBatchOp[T SCDModel[T], I any](m *SCDManager[T], op BatchOp[I], hooks BatchHooks[I, T], updated map[string]bool, now time.Time) (preparedOp[T], error) {
+	var p preparedOp[T]
+	switch op.Op {
+	case BatchOpCreate:
+		row, err := hooks.Create(op.Data)
+		if err != nil {
+			return p, err
+		}
+		p.row = row
+		var none T
+		if err := m.checkReferences(none, row); err != nil {
+			return p, err
+		}
+		return p, m.stamp(&p.row, now)
+	case BatchOpUpdate:
+		if op.UID == "" {
+			return p, errors.New("uid is required for update")
+		}
+		old, err := m.lockHead(op.UID)
+		if err != nil {
+			return p, fmt.Errorf("uid %s: %w", op.UID, err)
+		}
+		if updated[old.GetID()] {
+			return p, fmt.Errorf("entity %s is updated more than once in this batch", old.GetID())
+		}
+		updated[old.GetID()] = true
+		row, err := hooks.Update(old, op.Data)
+		if err != nil {
+			return p, err
+		}
+		p.head, p.row, p.update = old, row, true
+		return p, nil
+	}
+	return p, fmt.Errorf("invalid op %q", op.Op)
+}
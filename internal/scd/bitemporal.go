@@ -0,0 +1,407 @@
+package scd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// Bitemporal holds the two time axes of a version, embedded in every SCD
+// model. The effective period says when the values applied in the real world
+// and may be back-dated by the client; the recorded period says when the
+// version was part of what the system knew and is managed by SCDManager. Open
+// ends are NULL.
+type Bitemporal struct {
+	EffectiveFrom time.Time  `gorm:"index" json:"effectiveFrom"`
+	EffectiveTo   *time.Time `json:"effectiveTo"`
+	RecordedFrom  time.Time  `json:"recordedFrom"`
+	RecordedTo    *time.Time `gorm:"index" json:"recordedTo"`
+}
+
+const (
+	colEffectiveFrom = "effective_from"
+	colEffectiveTo   = "effective_to"
+	colRecordedFrom  = "recorded_from"
+	colRecordedTo    = "recorded_to"
+)
+
+var (
+	// ErrInvalidPeriod is returned for an effective time the history cannot
+	// take.
+	ErrInvalidPeriod = errors.New("invalid effective time")
+	// ErrNotScheduled is returned when cancelling a version that is not a
+	// pending scheduled version.
+	ErrNotScheduled = errors.New("not a pending scheduled version")
+)
+
+// AsKnownAt selects the version of each entity that was effective at
+// effectiveAt according to what was recorded at knownAt.
+func (m *SCDManager[T]) AsKnownAt(knownAt, effectiveAt time.Time) *gorm.DB {
+	return m.Recorded(&knownAt).
+		Where("v.valid_from <= ? AND (v.valid_to IS NULL OR v.valid_to > ?)", effectiveAt, effectiveAt)
+}
+
+// Recorded selects every version on record at knownAt, or now if knownAt is
+// nil, with the valid_from and valid_to columns of its effective period as it
+// was then known.
+func (m *SCDManager[T]) Recorded(knownAt *time.Time) *gorm.DB {
+	if knownAt == nil {
+		return m.recordedValidity("recorded_to IS NULL")
+	}
+	return m.recordedValidity("recorded_from <= ? AND (recorded_to IS NULL OR recorded_to > ?)", *knownAt, *knownAt)
+}
+
+// At selects the version of each entity that tt asks for, with its validity.
+func (m *SCDManager[T]) At(tt TimeTravel) *gorm.DB {
+	switch {
+	case tt.KnownAt != nil:
+		return m.AsKnownAt(*tt.KnownAt, *tt.AsOf)
+	case tt.AsOf != nil:
+		return m.AsOf(*tt.AsOf)
+	}
+	return m.Current()
+}
+
+// recordedValidity selects the versions on record under the given condition
+// with valid_from and valid_to columns. A version is valid until the next one
+// on record takes effect, so the periods always follow what was known.
+func (m *SCDManager[T]) recordedValidity(recorded string, args ...any) *gorm.DB {
+	var dummy T
+	sub := m.db.Table(dummy.TableName()).
+		Where(recorded, args...).
+		Select("*, effective_from AS valid_from, LEAD(effective_from) OVER (PARTITION BY id ORDER BY effective_from) AS valid_to")
+	return m.db.Table("(?) AS v", sub)
+}
+
+// FindAsKnownByUID returns the version of the entity the given version
+// belongs to which was effective at effectiveAt as recorded at knownAt.
+func (m *SCDManager[T]) FindAsKnownByUID(uid string, knownAt, effectiveAt time.Time) (T, error) {
+	v, err := m.FindByUID(uid)
+	if err != nil {
+		return v, err
+	}
+	var entity T
+	err = m.AsKnownAt(knownAt, effectiveAt).Where("v.id = ?", v.GetID()).Take(&entity).Error
+	return entity, err
+}
+
+// stamp opens the periods of a new entity recorded at now. The effective
+// period starts now unless the entity is back-dated; it may not start in the
+// future, only later versions can be scheduled.
+func (m *SCDManager[T]) stamp(row *T, now time.Time) error {
+	s, err := m.schema()
+	if err != nil {
+		return err
+	}
+	rv := reflect.ValueOf(row).Elem()
+	from := effectiveFrom(s, rv)
+	if from.IsZero() {
+		from = now
+	} else if from.After(now) {
+		return fmt.Errorf("%w: %s is in the future", ErrInvalidPeriod, from.Format(time.RFC3339))
+	}
+	return setColumns(s, rv, map[string]any{
+		colEffectiveFrom: from,
+		colRecordedFrom:  now,
+	})
+}
+
+// appendVersion stores next as the version following head, the version
+// effective now. A change effective from now on, or scheduled for later,
+// ends the effective period of head; a back-dated one, or one taking effect
+// before a version already scheduled, restates the history from its
+// effective time on.
+func (m *SCDManager[T]) appendVersion(head, next T) (T, error) {
+	s, err := m.schema()
+	if err != nil {
+		return next, err
+	}
+	now := time.Now()
+	nv := reflect.ValueOf(&next).Elem()
+	from := effectiveFrom(s, nv)
+	if from.IsZero() {
+		from = now
+	}
+	var last T
+	err = m.db.Where("id = ? AND recorded_to IS NULL", head.GetID()).Order("version DESC").First(&last).Error
+	if err != nil {
+		return next, err
+	}
+	if from.Before(now) || last.GetUID() != head.GetUID() {
+		return m.restate(head, next, from, now)
+	}
+	version, err := m.maxVersion(head.GetID())
+	if err != nil {
+		return next, err
+	}
+	err = setColumns(s, nv, map[string]any{
+		"version":        version + 1,
+		colEffectiveFrom: from,
+		colRecordedFrom:  now,
+	})
+	if err != nil {
+		return next, err
+	}
+	err = m.db.Table(head.TableName()).
+		Where("uid = ?", head.GetUID()).
+		UpdateColumn(colEffectiveTo, from).Error
+	if err != nil {
+		return next, err
+	}
+	return next, m.db.Create(&next).Error
+}
+
+// maxVersion returns the highest version number the entity has used.
+func (m *SCDManager[T]) maxVersion(id string) (int, error) {
+	var model T
+	var version int
+	err := m.db.Table(model.TableName()).
+		Select("COALESCE(MAX(version), 0)").
+		Where("id = ?", id).
+		Scan(&version).Error
+	return version, err
+}
+
+// restate records the changes next makes to head as effective from `from`
+// on. The current versions effective after from stop being recorded at now
+// and are recorded again with the changes applied; the one spanning from is
+// split in two, keeping its values before from. A changed column is carried
+// forward until the first version that changed it again, so later changes
+// survive a correction of the past. Versions effective before from are left
+// alone. The version written to take effect at from is returned.
+func (m *SCDManager[T]) restate(head, next T, from, now time.Time) (T, error) {
+	s, err := m.schema()
+	if err != nil {
+		return next, err
+	}
+	var current []T
+	err = m.db.Where("id = ? AND recorded_to IS NULL AND (effective_to IS NULL OR effective_to > ?)", head.GetID(), from).
+		Order("effective_from").
+		Find(&current).Error
+	if err != nil {
+		return next, err
+	}
+	if len(current) == 0 || effectiveFrom(s, reflect.ValueOf(&current[0]).Elem()).After(from) {
+		return next, fmt.Errorf("%w: %s precedes the entity", ErrInvalidPeriod, from.Format(time.RFC3339))
+	}
+	changed := changedColumns(s, reflect.ValueOf(&head).Elem(), reflect.ValueOf(&next).Elem())
+
+	version, err := m.maxVersion(head.GetID())
+	if err != nil {
+		return next, err
+	}
+	var rows []T
+	written := 0
+	var superseded []string
+	add := func(r T, start time.Time, end *time.Time, values map[string]any) error {
+		row := r.CopyForNewVersion()
+		version++
+		cols := map[string]any{
+			"version":        version,
+			colEffectiveFrom: start,
+			colEffectiveTo:   end,
+			colRecordedFrom:  now,
+		}
+		// The copy records the same version again, reversion included.
+		for _, col := range []string{colRevertedFrom, colRevertReason} {
+			if f := s.LookUpField(col); f != nil {
+				cols[col], _ = f.ValueOf(context.Background(), reflect.ValueOf(&r).Elem())
+			}
+		}
+		for col, v := range values {
+			cols[col] = v
+		}
+		if err := setColumns(s, reflect.ValueOf(&row).Elem(), cols); err != nil {
+			return err
+		}
+		rows = append(rows, row)
+		return nil
+	}
+	base := reflect.ValueOf(&current[0]).Elem()
+	for _, r := range current {
+		rv := reflect.ValueOf(&r).Elem()
+		start, end := effectiveFrom(s, rv), effectiveTo(s, rv)
+		if start.Before(from) {
+			split := from
+			if err := add(r, start, &split, nil); err != nil {
+				return next, err
+			}
+			start = from
+		}
+		if start.Equal(from) {
+			written = len(rows)
+		}
+		for col := range changed {
+			f := s.LookUpField(col)
+			was, _ := f.ValueOf(context.Background(), base)
+			is, _ := f.ValueOf(context.Background(), rv)
+			if !equal(was, is) {
+				delete(changed, col)
+			}
+		}
+		if err := add(r, start, end, changed); err != nil {
+			return next, err
+		}
+		superseded = append(superseded, r.GetUID())
+	}
+
+	err = m.db.Table(head.TableName()).
+		Where("uid IN ?", superseded).
+		UpdateColumn(colRecordedTo, now).Error
+	if err != nil {
+		return next, err
+	}
+	if err := m.db.Create(&rows).Error; err != nil {
+		return next, err
+	}
+	return rows[written], nil
+}
+
+// Scheduled selects the versions on record that take effect after now,
+// soonest first.
+func (m *SCDManager[T]) Scheduled() *gorm.DB {
+	return m.db.Where("recorded_to IS NULL AND effective_from > ?", time.Now()).Order("effective_from")
+}
+
+// CancelScheduled withdraws the scheduled version with the given UID before
+// it takes effect: it stops being recorded, and the version before it stays
+// effective until the one after it, if any. Versions scheduled after it keep
+// their values.
+func (m *SCDManager[T]) CancelScheduled(uid string) (T, error) {
+	var out T
+	err := m.Transaction(func(tx *SCDManager[T]) error {
+		if _, err := tx.lockHead(uid); err != nil {
+			return err
+		}
+		row, err := tx.FindByUID(uid)
+		if err != nil {
+			return err
+		}
+		s, err := tx.schema()
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		rv := reflect.ValueOf(&row).Elem()
+		if recordedTo(s, rv) != nil || !effectiveFrom(s, rv).After(now) {
+			return ErrNotScheduled
+		}
+		err = tx.db.Table(row.TableName()).
+			Where("id = ? AND recorded_to IS NULL AND effective_to = ?", row.GetID(), effectiveFrom(s, rv)).
+			UpdateColumn(colEffectiveTo, effectiveTo(s, rv)).Error
+		if err != nil {
+			return err
+		}
+		err = tx.db.Table(row.TableName()).
+			Where("uid = ?", uid).
+			UpdateColumn(colRecordedTo, now).Error
+		if err != nil {
+			return err
+		}
+		out = row
+		return setColumns(s, reflect.ValueOf(&out).Elem(), map[string]any{colRecordedTo: &now})
+	})
+	return out, err
+}
+
+// BackfillPeriods fills in the periods of rows written before they were
+// tracked, or inserted around the manager: each version is taken to be
+// effective and recorded from its creation, until the next version.
+func (m *SCDManager[T]) BackfillPeriods() error {
+	var model T
+	t := model.TableName()
+	next := m.db.Table(t + " AS n").
+		Select("MIN(n.created_at)").
+		Where("n.id = " + t + ".id AND n.version > " + t + ".version")
+	return m.db.Table(t).
+		Where("recorded_from IS NULL OR recorded_from < ?", time.Unix(0, 0)).
+		UpdateColumns(map[string]any{
+			colEffectiveFrom: gorm.Expr("created_at"),
+			colEffectiveTo:   next,
+			colRecordedFrom:  gorm.Expr("created_at"),
+		}).Error
+}
+
+// changedColumns returns the columns, other than the ones the manager
+// maintains, whose value differs between head and next, with next's value.
+func changedColumns(s *schema.Schema, head, next reflect.Value) map[string]any {
+	out := map[string]any{}
+	for _, f := range s.Fields {
+		if f.DBName == "" || versionColumns[f.DBName] {
+			continue
+		}
+		old, _ := f.ValueOf(context.Background(), head)
+		val, _ := f.ValueOf(context.Background(), next)
+		if !equal(old, val) {
+			out[f.DBName] = val
+		}
+	}
+	return out
+}
+
+func effectiveFrom(s *schema.Schema, rv reflect.Value) time.Time {
+	v, _ := s.LookUpField(colEffectiveFrom).ValueOf(context.Background(), rv)
+	t, _ := v.(time.Time)
+	return t
+}
+
+func effectiveTo(s *schema.Schema, rv reflect.Value) *time.Time {
+	v, _ := s.LookUpField(colEffectiveTo).ValueOf(context.Background(), rv)
+	t, _ := v.(*time.Time)
+	return t
+}
+
+func recordedTo(s *schema.Schema, rv reflect.Value) *time.Time {
+	v, _ := s.LookUpField(colRecordedTo).ValueOf(context.Background(), rv)
+	t, _ := v.(*time.Time)
+	return t
+}
+
+func setColumns(s *schema.Schema, rv reflect.Value, values map[string]any) error {
+	for col, v := range values {
+		f := s.LookUpField(col)
+		if f == nil {
+			return fmt.Errorf("%s has no column %s", s.Table, col)
+		}
+		if err := f.Set(context.Background(), rv, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TimeTravel is the point in time a read asks for: the version effective at
+// AsOf as recorded at KnownAt. Nil fields mean the current state.
+type TimeTravel struct {
+	AsOf    *time.Time
+	KnownAt *time.Time
+}
+
+// ParseTimeTravel reads the as_of and known_at parameters of a read, RFC 3339
+// times or empty. known_at alone asks what was on record then about that same
+// time.
+func ParseTimeTravel(asOf, knownAt string) (TimeTravel, error) {
+	var tt TimeTravel
+	for _, p := range []struct {
+		name, value string
+		dst         **time.Time
+	}{{"as_of", asOf, &tt.AsOf}, {"known_at", knownAt, &tt.KnownAt}} {
+		if p.value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, p.value)
+		if err != nil {
+			return tt, fmt.Errorf("%s must be an RFC 3339 time", p.name)
+		}
+		*p.dst = &t
+	}
+	if tt.AsOf == nil {
+		tt.AsOf = tt.KnownAt
+	}
+	return tt, nil
+}
@@ -0,0 +1,141 @@
+package scd
+
+import (
+	"reflect"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Cascade says what happens to the versions linking to a parent version when
+// the parent gets a new one.
+type Cascade int
+
+const (
+	// ResolveByID leaves the dependents alone: each keeps the parent version
+	// it was written against, and queries for the current parent go through
+	// the logical ID of the version it names (see LinkedTo).
+	ResolveByID Cascade = iota
+	// Repoint writes a new version of every current dependent linked to the
+	// parent's new head version, so links always name the current parent.
+	// It runs when the parent is written and follows the version in effect
+	// then: a version scheduled for later is not linked to when it takes
+	// effect, only at the next write of the parent. Dependents that have to
+	// follow scheduled versions use ResolveByID.
+	Repoint
+)
+
+// Relationship is a link from the versions of a dependent entity to the
+// versions of a parent entity, through a column of the dependent holding a
+// parent version UID.
+type Relationship interface {
+	// Column is the column of the dependent holding the parent UID.
+	Column() string
+	// Cascade is the policy applied when the parent gets a new version.
+	Cascade() Cascade
+	// Table is the table of the dependent.
+	Table() string
+	repoint(db *gorm.DB, parentTable, parentID, headUID string) error
+}
+
+// Parent is implemented by the models other entities link to. Dependents
+// lists the relationships pointing at the model.
+type Parent interface {
+	Dependents() []Relationship
+}
+
+// DependsOn declares that versions of D link to the parent model through
+// column, under the given cascade policy.
+func DependsOn[D SCDModel[D]](column string, cascade Cascade) Relationship {
+	return relationship[D]{column: column, cascade: cascade}
+}
+
+type relationship[D SCDModel[D]] struct {
+	column  string
+	cascade Cascade
+}
+
+func (r relationship[D]) Column() string   { return r.column }
+func (r relationship[D]) Cascade() Cascade { return r.cascade }
+func (r relationship[D]) Table() string {
+	var model D
+	return model.TableName()
+}
+
+// repoint stores a new version of each current dependent that links to an
+// older version of the parent, linked to headUID instead. It runs in the
+// transaction of the parent write and locks each dependent like any other
+// writer. The new versions cascade to their own dependents in turn.
+func (r relationship[D]) repoint(db *gorm.DB, parentTable, parentID, headUID string) error {
+	dm := NewManager[D](db)
+	var stale []D
+	err := dm.GetLatest().
+		Where("main."+r.column+" IN (?) AND main."+r.column+" <> ?",
+			db.Table(parentTable).Select("uid").Where("id = ?", parentID), headUID).
+		Find(&stale).Error
+	if err != nil {
+		return err
+	}
+	if len(stale) == 0 {
+		return nil
+	}
+	s, err := dm.schema()
+	if err != nil {
+		return err
+	}
+	f := s.LookUpField(r.column)
+	link := reflect.ValueOf(uuid.MustParse(headUID))
+	if f.FieldType.Kind() == reflect.Ptr {
+		p := reflect.New(link.Type())
+		p.Elem().Set(link)
+		link = p
+	}
+	for _, d := range stale {
+		head, err := dm.lockHead(d.GetUID())
+		if err != nil {
+			return err
+		}
+		row := head.CopyForNewVersion()
+		if err := setColumns(s, reflect.ValueOf(&row).Elem(), map[string]any{r.column: link.Interface()}); err != nil {
+			return err
+		}
+		if _, err := dm.store(head, row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cascade applies the Repoint relationships of the model to the dependents
+// of the entity with the given ID, after it got a new version. They are
+// linked to the version in effect now, so a scheduled version leaves them be.
+func (m *SCDManager[T]) cascade(id string) error {
+	var model T
+	p, ok := any(model).(Parent)
+	if !ok {
+		return nil
+	}
+	var head T
+	err := m.GetLatest().Where("main.id = ?", id).Take(&head).Error
+	if err != nil {
+		return err
+	}
+	for _, rel := range p.Dependents() {
+		if rel.Cascade() != Repoint {
+			continue
+		}
+		if err := rel.repoint(m.db, model.TableName(), id, head.GetUID()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LinkedTo selects the current versions whose column links to any version of
+// the parent entity the given UID belongs to, resolving the link through the
+// parent's logical ID.
+func (m *SCDManager[T]) LinkedTo(parentTable, column, parentUID string) *gorm.DB {
+	parent := m.db.Table(parentTable).Select("id").Where("uid = ?", parentUID)
+	versions := m.db.Table(parentTable).Select("uid").Where("id IN (?)", parent)
+	return m.GetLatest().Where("main."+column+" IN (?)", versions)
+}
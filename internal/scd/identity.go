@@ -0,0 +1,47 @@
+package scd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrIdentityChanged is returned when a write changes a column that says
+// which business entity an ID stands for.
+var ErrIdentityChanged = errors.New("identity of the entity cannot change")
+
+// Identified is implemented by the models whose ID stands for a business
+// entity named by some of their columns, such as the owner of a record. Every
+// version of an entity has the same values in them; a different value means
+// a different entity, which has to be created as such.
+type Identified interface {
+	Identity() []string
+}
+
+// checkIdentity returns ErrIdentityChanged when row has other values than
+// head in the identity columns of the model.
+func (m *SCDManager[T]) checkIdentity(head, row T) error {
+	id, ok := any(row).(Identified)
+	if !ok {
+		return nil
+	}
+	s, err := m.schema()
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	hv, rv := reflect.ValueOf(&head).Elem(), reflect.ValueOf(&row).Elem()
+	for _, col := range id.Identity() {
+		f := s.LookUpField(col)
+		if f == nil {
+			return fmt.Errorf("%s: unknown identity column %q", s.Table, col)
+		}
+		was, _ := f.ValueOf(ctx, hv)
+		is, _ := f.ValueOf(ctx, rv)
+		if !equal(was, is) {
+			return fmt.Errorf("%s %v: %w", col, is, ErrIdentityChanged)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package scd
+
+import "fmt"
+
+// CreateIndexes creates the indexes the manager's queries rely on, if they
+// do not exist yet:
+//
+//   - <table>_versions on (id, version), for the history of an entity;
+//   - <table>_latest on (id, version, effective_from) of the versions on
+//     record, for the latest version of an entity, which GetLatest and
+//     appendVersion look up. It includes effective_to and recorded_to, which
+//     the validity test of the check for a later version reads, so the check
+//     can be answered from the index alone. It replaces <table>_heads, which
+//     left them out.
+//
+// Indexes on the columns entities are listed by are declared on the models.
+func (m *SCDManager[T]) CreateIndexes() error {
+	t := m.table()
+	stmts := []string{
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s_versions ON %s (id, version)", t, t),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s_latest ON %s (id, version, effective_from) INCLUDE (effective_to, recorded_to) WHERE recorded_to IS NULL", t, t),
+		fmt.Sprintf("DROP INDEX IF EXISTS %s_heads", t),
+	}
+	for _, stmt := range stmts {
+		if err := m.db.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
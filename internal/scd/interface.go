@@ -6,4 +6,7 @@ type SCDModel[T any] interface {
 	GetUID() string
 	GetVersion() int
 	CopyForNewVersion() T
+	// ChangePolicy says how changes to each column are stored. Columns
+	// without a policy are Type2.
+	ChangePolicy() Policy
 }
@@ -1,7 +1,10 @@
 package scd
 
 import (
+  "time"
+
   "gorm.io/gorm"
+  "gorm.io/gorm/clause"
 )
 
 type SCDManager[T SCDModel[T]] struct {
@@ -12,15 +15,78 @@ func NewManager[T SCDModel[T]](db *gorm.DB) *SCDManager[T] {
   return &SCDManager[T]{db: db}
 }
 
-// Get only the latest versions
+// Get only the latest versions: the ones effective now. Versions scheduled
+// for later are left out until they take effect.
+//
+// A version is the latest if no later version of its entity is on record and
+// in effect. Filters added by the caller apply to main before that check, so
+// a list of one company's jobs only looks up the heads of those jobs, in the
+// index made by CreateIndexes. The latest version is still valid, as is every
+// later one, so on a split table only the partitions of valid versions are
+// read.
 func (m *SCDManager[T]) GetLatest() *gorm.DB {
   var dummy T
   t := dummy.TableName()
-  sub := m.db.Table(t+" as s").
-    Select("id, MAX(version) as max_ver").
-    Group("id")
+  now := time.Now()
+  later := m.db.Table(t+" as later").
+    Select("1").
+    Where("later.id = main.id AND later.version > main.version").
+    Where("later.recorded_to IS NULL AND later.effective_from <= ?", now).
+    Where(stillValid("later"), now)
   return m.db.Table(t+" as main").
-    Joins("JOIN (?) as latest ON main.id = latest.id AND main.version = latest.max_ver", sub)
+    Where("main.recorded_to IS NULL AND main.effective_from <= ?", now).
+    Where(stillValid("main"), now).
+    Where("NOT EXISTS (?)", later)
+}
+
+// WithValidity selects every version with valid_from and valid_to columns,
+// its effective period. Versions that were restated stay in the selection
+// with their recorded_to set.
+func (m *SCDManager[T]) WithValidity() *gorm.DB {
+  var dummy T
+  sub := m.db.Table(dummy.TableName()).
+    Select("*, effective_from AS valid_from, effective_to AS valid_to")
+  return m.db.Table("(?) AS v", sub)
+}
+
+// AsOf selects the version of each entity that was effective at the given
+// time, as currently recorded.
+func (m *SCDManager[T]) AsOf(at time.Time) *gorm.DB {
+  return m.recordedValidity("recorded_to IS NULL").
+    Where("v.valid_from <= ? AND (v.valid_to IS NULL OR v.valid_to > ?)", at, at)
+}
+
+// Current selects the version of each entity effective now, with its
+// validity.
+func (m *SCDManager[T]) Current() *gorm.DB {
+  return m.AsOf(time.Now())
+}
+
+// VersionsOf returns every version of the entities with the given IDs,
+// ordered by entity and version.
+func (m *SCDManager[T]) VersionsOf(ids any) ([]T, error) {
+  var list []T
+  err := m.db.Where("id IN ?", ids).Order("id").Order("version").Find(&list).Error
+  return list, err
+}
+
+// FindIn returns the head versions whose column holds one of values or, with
+// a non-nil at, the versions that were valid at that time.
+func (m *SCDManager[T]) FindIn(column string, values any, at *time.Time) ([]T, error) {
+  q, alias := m.GetLatest(), "main"
+  if at != nil {
+    q, alias = m.AsOf(*at), "v"
+  }
+  var list []T
+  err := q.Where(alias+"."+column+" IN ?", values).Order(alias + ".created_at").Find(&list).Error
+  return list, err
+}
+
+// FindUIDs returns the versions with the given uids, whatever their validity.
+func (m *SCDManager[T]) FindUIDs(uids any) ([]T, error) {
+  var list []T
+  err := m.db.Where("uid IN ?", uids).Find(&list).Error
+  return list, err
 }
 
 func (m *SCDManager[T]) FindByUID(uid string) (T, error) {
@@ -29,13 +95,102 @@ func (m *SCDManager[T]) FindByUID(uid string) (T, error) {
   return entity, err
 }
 
-func (m *SCDManager[T]) Insert(newItem T) error {
-  return m.db.Create(&newItem).Error
+// FindLatestByID returns the head version of the entity with the given ID.
+func (m *SCDManager[T]) FindLatestByID(id string) (T, error) {
+  var entity T
+  err := m.GetLatest().Where("main.id = ?", id).First(&entity).Error
+  return entity, err
+}
+
+// FindHeadByUID returns the head version of the entity that the given version
+// belongs to.
+func (m *SCDManager[T]) FindHeadByUID(uid string) (T, error) {
+  v, err := m.FindByUID(uid)
+  if err != nil {
+    return v, err
+  }
+  return m.FindLatestByID(v.GetID())
+}
+
+// HistoryByUID returns every version of the entity that the given version
+// belongs to, oldest first.
+func (m *SCDManager[T]) HistoryByUID(uid string) ([]T, error) {
+  v, err := m.FindByUID(uid)
+  if err != nil {
+    return nil, err
+  }
+  var list []T
+  err = m.db.Where("id = ?", v.GetID()).Order("version").Find(&list).Error
+  return list, err
+}
+
+// FindAsOfByUID returns the version of the entity that the given version
+// belongs to which was valid at the given time.
+func (m *SCDManager[T]) FindAsOfByUID(uid string, at time.Time) (T, error) {
+  v, err := m.FindByUID(uid)
+  if err != nil {
+    return v, err
+  }
+  var entity T
+  err = m.AsOf(at).Where("v.id = ?", v.GetID()).Take(&entity).Error
+  return entity, err
+}
+
+// AppendVersion stores the state that next builds from the head version of
+// the entity uid belongs to, provided the head matches pre. Following the
+// change policy of the entity it becomes a new version or is written in place.
+// The versions of the entity stay locked until it is written, so concurrent
+// writers are applied one after the other and each sees the real head.
+func (m *SCDManager[T]) AppendVersion(uid string, pre Precondition, next func(head T) (T, error)) (T, error) {
+  var out T
+  err := m.Transaction(func(tx *SCDManager[T]) error {
+    head, err := tx.lockHead(uid)
+    if err != nil {
+      return err
+    }
+    if err := pre.Check(head.GetUID()); err != nil {
+      return err
+    }
+    row, err := next(head)
+    if err != nil {
+      return err
+    }
+    out, err = tx.store(head, row)
+    return err
+  })
+  return out, err
+}
+
+// lockHead locks every version of the entity uid belongs to for the rest of
+// the transaction and returns the head version.
+func (m *SCDManager[T]) lockHead(uid string) (T, error) {
+  v, err := m.FindByUID(uid)
+  if err != nil {
+    return v, err
+  }
+  var versions []T
+  err = m.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+    Select("uid").
+    Where("id = ?", v.GetID()).
+    Find(&versions).Error
+  if err != nil {
+    return v, err
+  }
+  return m.FindLatestByID(v.GetID())
 }
 
-func (m *SCDManager[T]) CreateNewVersion(old T) (T, error) {
-  newItem := old.CopyForNewVersion()
-  return newItem, m.db.Create(&newItem).Error
+// Insert writes newItem, recorded from now, and fills in the fields set by
+// the database, such as the timestamps. It fails with ErrUnknownReference
+// when newItem references an entity that is not on record.
+func (m *SCDManager[T]) Insert(newItem *T) error {
+  var none T
+  if err := m.checkReferences(none, *newItem); err != nil {
+    return err
+  }
+  if err := m.stamp(newItem, time.Now()); err != nil {
+    return err
+  }
+  return m.db.Create(newItem).Error
 }
 
 
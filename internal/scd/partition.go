@@ -0,0 +1,224 @@
+package scd
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// A versioned table can be split for entities with a long history, on
+// Postgres. Split turns it into a table partitioned by validTo, the time each
+// version stopped being valid, in two parts:
+//
+//   - <table>_current holds the versions that are still open, which have no
+//     validTo;
+//   - <table>_history holds the others, partitioned in turn by month:
+//     <table>_history_YYYY_MM holds the versions that stopped being valid in
+//     that month, and <table>_history_default those of months that have no
+//     partition yet.
+//
+// The table keeps its name, so every query reads the versions of both as
+// before, links by uid included. A write that ends a version moves it from
+// the current table to its month's partition in the same statement, and the
+// latest versions are looked up in the current table and the months to come
+// only.
+//
+// Unique indexes of a partitioned table have to include the partition key, so
+// a split table has no primary key. Its uids are kept unique in <table>_uids,
+// whose primary key they are, by a trigger on every insert and delete; a
+// version moved to another partition is deleted and inserted again.
+
+// validTo is the partition key of split tables: the end of the effective
+// period of a version or, for a restated one, when it left the record. The
+// prefix is empty or a table alias followed by a dot.
+func validTo(prefix string) string {
+	return fmt.Sprintf("COALESCE(%[1]seffective_to, %[1]srecorded_to)", prefix)
+}
+
+// stillValid selects the versions under alias that are still valid at the
+// time bound to it; on a split table it reads the partitions of the current
+// versions and of the months after that time only.
+func stillValid(alias string) string {
+	key := validTo(alias + ".")
+	return "(" + key + " IS NULL OR " + key + " > ?)"
+}
+
+// Partitioning reports the partitions that Split or AddPartitions created.
+type Partitioning struct {
+	Table string `json:"table"`
+	// Split is set when the table was split by this run.
+	Split      bool     `json:"split"`
+	Partitions []string `json:"partitions"`
+}
+
+// IsSplit reports whether the table has been split by Split.
+func (m *SCDManager[T]) IsSplit() (bool, error) {
+	var n int64
+	err := m.db.Raw(`SELECT count(*) FROM pg_partitioned_table p
+		JOIN pg_class c ON c.oid = p.partrelid
+		WHERE c.relname = ? AND pg_table_is_visible(c.oid)`, m.table()).Scan(&n).Error
+	return n > 0, err
+}
+
+// Split moves the versions of the table into the current table and a
+// history partition for every month, from the first month a version stopped
+// being valid through the month of through. The table is rebuilt in one
+// transaction, with its indexes. A table that is split already only gets the
+// partitions it is missing, as with AddPartitions.
+func (m *SCDManager[T]) Split(through time.Time) (Partitioning, error) {
+	split, err := m.IsSplit()
+	if err != nil || split {
+		if split {
+			return m.AddPartitions(through)
+		}
+		return Partitioning{}, err
+	}
+	t := m.table()
+	old := t + "_unsplit"
+	p := Partitioning{Table: t, Split: true, Partitions: []string{}}
+	err = m.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("ALTER TABLE " + t + " RENAME TO " + old).Error; err != nil {
+			return err
+		}
+		var first sql.NullTime
+		if err := tx.Raw("SELECT MIN(" + validTo("") + ") FROM " + old).Row().Scan(&first); err != nil {
+			return err
+		}
+		stmts := []string{
+			"CREATE TABLE " + t + " (LIKE " + old + " INCLUDING DEFAULTS) PARTITION BY RANGE ((" + validTo("") + "))",
+			"CREATE TABLE " + t + "_current PARTITION OF " + t + " DEFAULT",
+			"CREATE TABLE " + t + "_history PARTITION OF " + t + " FOR VALUES FROM (MINVALUE) TO (MAXVALUE) PARTITION BY RANGE ((" + validTo("") + "))",
+			"CREATE TABLE " + t + "_history_default PARTITION OF " + t + "_history DEFAULT",
+		}
+		start := time.Now()
+		if first.Valid && first.Time.Before(start) {
+			start = first.Time
+		}
+		for lo := monthOf(start); !lo.After(through); lo = lo.AddDate(0, 1, 0) {
+			name := historyPartition(t, lo)
+			stmts = append(stmts, fmt.Sprintf("CREATE TABLE %s PARTITION OF %s_history FOR VALUES FROM (%s) TO (%s)",
+				name, t, bound(lo), bound(lo.AddDate(0, 1, 0))))
+			p.Partitions = append(p.Partitions, name)
+		}
+		stmts = append(stmts,
+			"INSERT INTO "+t+" SELECT * FROM "+old,
+			"CREATE TABLE "+t+"_uids AS SELECT uid FROM "+old,
+			"ALTER TABLE "+t+"_uids ADD PRIMARY KEY (uid)",
+			"DROP TABLE "+old,
+			"CREATE INDEX "+t+"_uid ON "+t+" (uid)",
+			fmt.Sprintf(`CREATE FUNCTION %[1]s_uids() RETURNS trigger LANGUAGE plpgsql AS $$
+BEGIN
+	IF TG_OP = 'INSERT' THEN
+		INSERT INTO %[1]s_uids (uid) VALUES (NEW.uid);
+	ELSE
+		DELETE FROM %[1]s_uids WHERE uid = OLD.uid;
+	END IF;
+	RETURN NULL;
+END $$`, t),
+			fmt.Sprintf("CREATE TRIGGER %[1]s_uids AFTER INSERT OR DELETE ON %[1]s FOR EACH ROW EXECUTE FUNCTION %[1]s_uids()", t),
+		)
+		for _, stmt := range stmts {
+			if err := tx.Exec(stmt).Error; err != nil {
+				return err
+			}
+		}
+		var model T
+		if err := tx.AutoMigrate(&model); err != nil {
+			return err
+		}
+		return m.WithTx(tx).CreateIndexes()
+	})
+	if err != nil {
+		return Partitioning{}, err
+	}
+	return p, nil
+}
+
+// AddPartitions creates the history partitions of a split table that are
+// missing through the month of through, from the current month or, if
+// versions that ended earlier are in the default history partition, from
+// theirs; those versions are moved to their partitions. It does nothing on a
+// table that is not split.
+func (m *SCDManager[T]) AddPartitions(through time.Time) (Partitioning, error) {
+	t := m.table()
+	p := Partitioning{Table: t, Partitions: []string{}}
+	split, err := m.IsSplit()
+	if err != nil || !split {
+		return p, err
+	}
+	var first sql.NullTime
+	err = m.db.Raw("SELECT MIN(" + validTo("") + ") FROM " + t + "_history_default").Row().Scan(&first)
+	if err != nil {
+		return p, err
+	}
+	start := time.Now()
+	if first.Valid && first.Time.Before(start) {
+		start = first.Time
+	}
+	for lo := monthOf(start); !lo.After(through); lo = lo.AddDate(0, 1, 0) {
+		name := historyPartition(t, lo)
+		var exists bool
+		if err := m.db.Raw("SELECT to_regclass(?) IS NOT NULL", name).Row().Scan(&exists); err != nil {
+			return p, err
+		}
+		if exists {
+			continue
+		}
+		hi := lo.AddDate(0, 1, 0)
+		// The default partition may not hold versions of the new one's
+		// range when it is attached. Deleting them there drops their uids
+		// from <table>_uids, and the new partition has no trigger before it
+		// is attached, so they are recorded again afterwards.
+		stmts := []string{
+			"CREATE TABLE " + name + " (LIKE " + t + " INCLUDING DEFAULTS)",
+			fmt.Sprintf("WITH moved AS (DELETE FROM %s_history_default WHERE %s >= %s AND %s < %s RETURNING *) INSERT INTO %s SELECT * FROM moved",
+				t, validTo(""), bound(lo), validTo(""), bound(hi), name),
+			fmt.Sprintf("ALTER TABLE %s_history ATTACH PARTITION %s FOR VALUES FROM (%s) TO (%s)", t, name, bound(lo), bound(hi)),
+			fmt.Sprintf("INSERT INTO %s_uids (uid) SELECT uid FROM %s", t, name),
+		}
+		err := m.db.Transaction(func(tx *gorm.DB) error {
+			for _, stmt := range stmts {
+				if err := tx.Exec(stmt).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return p, err
+		}
+		p.Partitions = append(p.Partitions, name)
+	}
+	return p, nil
+}
+
+// monthOf returns the start of the month of t, in UTC.
+func monthOf(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+func historyPartition(table string, month time.Time) string {
+	return fmt.Sprintf("%s_history_%04d_%02d", table, month.Year(), month.Month())
+}
+
+// bound formats a partition bound; DDL takes no bind parameters.
+func bound(t time.Time) string {
+	return "'" + t.UTC().Format("2006-01-02 15:04:05") + "+00'"
+}
+
+// maxAttempts bounds how often Transaction runs a function that failed on a
+// version moved by a concurrent write.
+const maxAttempts = 3
+
+// movedConcurrently reports whether err is the serialization failure
+// Postgres reports when a row to be locked was moved to another partition by
+// a concurrent update, as ending a version of a split table does.
+func movedConcurrently(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "40001"
+}
@@ -0,0 +1,213 @@
+package scd
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// ChangeType says how a change to a field is stored.
+type ChangeType int
+
+const (
+	// Type2 stores the new value in a new version. It is the default for
+	// every field without a policy.
+	Type2 ChangeType = iota
+	// Type1 overwrites the value in place on every version of the entity
+	// still on record, for corrections that should not show up as history.
+	// Versions a restatement took off the record keep what was known then.
+	Type1
+	// Type3 overwrites the value in place on the head version and keeps the
+	// value it replaced in a previous-value column.
+	Type3
+)
+
+// FieldPolicy is the change type of one field. Previous names the column that
+// keeps the replaced value of a Type3 field.
+type FieldPolicy struct {
+	Type     ChangeType
+	Previous string
+}
+
+// Policy maps the columns of an entity to their change type.
+type Policy map[string]FieldPolicy
+
+// Overwrite is the policy of a Type1 field.
+func Overwrite() FieldPolicy {
+	return FieldPolicy{Type: Type1}
+}
+
+// KeepPrevious is the policy of a Type3 field whose replaced value is kept in
+// the given column.
+func KeepPrevious(column string) FieldPolicy {
+	return FieldPolicy{Type: Type3, Previous: column}
+}
+
+// versionColumns are maintained by the manager and never compared. The
+// reversion columns are set by Revert on the version it writes only, so a
+// later write never changes them.
+var versionColumns = map[string]bool{
+	"id":             true,
+	"uid":            true,
+	"version":        true,
+	"created_at":     true,
+	"updated_at":     true,
+	colEffectiveFrom: true,
+	colEffectiveTo:   true,
+	colRecordedFrom:  true,
+	colRecordedTo:    true,
+	colRevertedFrom:  true,
+	colRevertReason:  true,
+}
+
+// changeSet is what a write changes on the head version, sorted by policy.
+type changeSet struct {
+	// versioned is set when a Type2 field changed, or when nothing changed at
+	// all, and the write is stored as a new version.
+	versioned bool
+	// overwrite holds the Type1 columns, set on every version.
+	overwrite map[string]any
+	// head holds the Type3 columns and their previous-value columns, set on
+	// the head version when no new version is stored.
+	head map[string]any
+}
+
+func (m *SCDManager[T]) schema() (*schema.Schema, error) {
+	var model T
+	stmt := &gorm.Statement{DB: m.db}
+	if err := stmt.Parse(&model); err != nil {
+		return nil, err
+	}
+	return stmt.Schema, nil
+}
+
+// diff compares next with head under the policy of the entity. For a new
+// version it moves the replaced values of changed Type3 fields into their
+// previous-value columns on next.
+func (m *SCDManager[T]) diff(head T, next *T) (changeSet, error) {
+	c := changeSet{overwrite: map[string]any{}, head: map[string]any{}}
+	s, err := m.schema()
+	if err != nil {
+		return c, err
+	}
+	policy := head.ChangePolicy()
+	previous := map[string]bool{}
+	for col, p := range policy {
+		if p.Type != Type3 {
+			continue
+		}
+		if s.LookUpField(p.Previous) == nil {
+			return c, fmt.Errorf("%s.%s: unknown previous-value column %q", s.Table, col, p.Previous)
+		}
+		previous[p.Previous] = true
+	}
+
+	ctx := context.Background()
+	hv, nv := reflect.ValueOf(&head).Elem(), reflect.ValueOf(next).Elem()
+	moved := map[*schema.Field]any{}
+	for _, f := range s.Fields {
+		if f.DBName == "" || versionColumns[f.DBName] || previous[f.DBName] {
+			continue
+		}
+		old, _ := f.ValueOf(ctx, hv)
+		val, _ := f.ValueOf(ctx, nv)
+		if equal(old, val) {
+			continue
+		}
+		switch p := policy[f.DBName]; p.Type {
+		case Type1:
+			c.overwrite[f.DBName] = val
+		case Type3:
+			c.head[f.DBName] = val
+			c.head[p.Previous] = old
+			moved[s.LookUpField(p.Previous)] = old
+		default:
+			c.versioned = true
+		}
+	}
+	if len(c.overwrite) == 0 && len(c.head) == 0 {
+		c.versioned = true
+	}
+	if c.versioned {
+		for f, old := range moved {
+			if err := f.Set(ctx, nv, old); err != nil {
+				return c, err
+			}
+		}
+	}
+	return c, nil
+}
+
+// store writes next, built from head, as the policy demands: as a new
+// version or in place on head. Type1 changes are applied to the older
+// versions as well, and a new version cascades to the dependents.
+func (m *SCDManager[T]) store(head, next T) (T, error) {
+	if err := m.checkIdentity(head, next); err != nil {
+		return next, err
+	}
+	if err := m.checkReferences(head, next); err != nil {
+		return next, err
+	}
+	c, err := m.diff(head, &next)
+	if err != nil {
+		return next, err
+	}
+	if c.versioned {
+		next, err = m.appendVersion(head, next)
+		if err != nil {
+			return next, err
+		}
+		if err := m.overwrite(next, c); err != nil {
+			return next, err
+		}
+		return next, m.cascade(next.GetID())
+	}
+	return m.updateHead(head, c)
+}
+
+// updateHead applies the in-place changes of c to the head version.
+func (m *SCDManager[T]) updateHead(head T, c changeSet) (T, error) {
+	s, err := m.schema()
+	if err != nil {
+		return head, err
+	}
+	ctx := context.Background()
+	hv := reflect.ValueOf(&head).Elem()
+	cols := []string{"updated_at"}
+	for _, values := range []map[string]any{c.overwrite, c.head} {
+		for col, v := range values {
+			if err := s.LookUpField(col).Set(ctx, hv, v); err != nil {
+				return head, err
+			}
+			cols = append(cols, col)
+		}
+	}
+	if err := m.db.Model(&head).Select(cols).Updates(&head).Error; err != nil {
+		return head, err
+	}
+	return head, m.overwrite(head, c)
+}
+
+// overwrite applies the Type1 changes of c to the versions before current
+// that are still on record.
+func (m *SCDManager[T]) overwrite(current T, c changeSet) error {
+	if len(c.overwrite) == 0 {
+		return nil
+	}
+	return m.db.Table(current.TableName()).
+		Where("id = ? AND uid <> ? AND "+colRecordedTo+" IS NULL", current.GetID(), current.GetUID()).
+		UpdateColumns(c.overwrite).Error
+}
+
+func equal(a, b any) bool {
+	if ta, ok := a.(time.Time); ok {
+		if tb, ok := b.(time.Time); ok {
+			return ta.Equal(tb)
+		}
+	}
+	return reflect.DeepEqual(a, b)
+}
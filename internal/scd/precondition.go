@@ -0,0 +1,82 @@
+package scd
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+var ErrPreconditionFailed = errors.New("precondition failed: the entity has a newer version")
+
+// ErrUnknownReference is returned when a write references an entity that
+// does not exist.
+var ErrUnknownReference = errors.New("referenced entity does not exist")
+
+// ETag is the entity tag of a version. Every version has its own UID, so the
+// UID identifies the representation exactly.
+func ETag(uid string) string {
+	return `"` + uid + `"`
+}
+
+// Precondition is the If-Match condition of a write. The zero value matches
+// any head version.
+type Precondition struct {
+	tags []string
+	any  bool
+}
+
+// IfMatch parses an If-Match header value.
+func IfMatch(header string) Precondition {
+	var p Precondition
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		switch {
+		case tag == "":
+		case tag == "*":
+			p.any = true
+		case strings.HasPrefix(tag, "W/"):
+			// Weak tags never match If-Match, which uses strong comparison.
+			p.tags = append(p.tags, "")
+		default:
+			p.tags = append(p.tags, tag)
+		}
+	}
+	return p
+}
+
+// Check reports ErrPreconditionFailed unless the head version with the given
+// UID matches.
+func (p Precondition) Check(headUID string) error {
+	if p.any || len(p.tags) == 0 {
+		return nil
+	}
+	for _, tag := range p.tags {
+		if tag == ETag(headUID) {
+			return nil
+		}
+	}
+	return ErrPreconditionFailed
+}
+
+// StatusCode maps errors of reads and writes of versions to HTTP statuses.
+func StatusCode(err error) int {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrPreconditionFailed):
+		return http.StatusPreconditionFailed
+	case errors.Is(err, ErrInvalidPeriod):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, ErrNotScheduled):
+		return http.StatusConflict
+	case errors.Is(err, ErrNotInHistory):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, ErrUnknownReference):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, ErrIdentityChanged):
+		return http.StatusUnprocessableEntity
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,63 @@
+package scd
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// Reference is a column holding the logical ID of an entity stored in
+// another SCD table.
+type Reference struct {
+	Column string
+	Table  string
+}
+
+// RefersTo declares that column holds the ID of an entity in table.
+func RefersTo(column, table string) Reference {
+	return Reference{Column: column, Table: table}
+}
+
+// Referrer is implemented by the models whose columns reference other
+// entities. A version is only written when every entity it newly references
+// is on record.
+type Referrer interface {
+	References() []Reference
+}
+
+// checkReferences returns ErrUnknownReference when row references an entity
+// that is not on record. Only values that differ from old are checked, so
+// versions keep the references they were written with; creates pass the
+// zero T as old.
+func (m *SCDManager[T]) checkReferences(old, row T) error {
+	r, ok := any(row).(Referrer)
+	if !ok {
+		return nil
+	}
+	s, err := m.schema()
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	ov, rv := reflect.ValueOf(&old).Elem(), reflect.ValueOf(&row).Elem()
+	for _, ref := range r.References() {
+		f := s.LookUpField(ref.Column)
+		if f == nil {
+			return fmt.Errorf("%s: unknown reference column %q", s.Table, ref.Column)
+		}
+		was, _ := f.ValueOf(ctx, ov)
+		val, zero := f.ValueOf(ctx, rv)
+		if zero || equal(was, val) {
+			continue
+		}
+		var count int64
+		err := m.db.Table(ref.Table).Where("id = ? AND recorded_to IS NULL", val).Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return fmt.Errorf("%s %v: %w", ref.Column, val, ErrUnknownReference)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,362 @@
+package scd
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"reflect"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// Retention says how long the versions of an entity are kept as written. The
+// zero value keeps every version.
+type Retention struct {
+	// After is how long a version is kept as written once its effective
+	// period ended. Older versions are collapsed into one snapshot per
+	// calendar month: the last version effective in the month, made
+	// effective from the start of the first.
+	After time.Duration
+}
+
+// KeepAll reports whether the retention keeps every version.
+func (r Retention) KeepAll() bool { return r.After <= 0 }
+
+// LegalHold keeps every version of an entity from being compacted until the
+// hold is released.
+type LegalHold struct {
+	ID          uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	EntityTable string    `gorm:"uniqueIndex:idx_legal_hold_entity" json:"table"`
+	EntityID    uuid.UUID `gorm:"type:uuid;uniqueIndex:idx_legal_hold_entity" json:"entityId"`
+	Reason      string    `json:"reason"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+func (LegalHold) TableName() string { return "scd_legal_holds" }
+
+// ArchivedVersion records a version removed by compaction. Data holds the
+// version as JSON, unless it was archived to the file named by Location.
+type ArchivedVersion struct {
+	UID         uuid.UUID `gorm:"type:uuid;primaryKey" json:"uid"`
+	EntityTable string    `gorm:"index:idx_archived_entity" json:"table"`
+	EntityID    uuid.UUID `gorm:"type:uuid;index:idx_archived_entity" json:"id"`
+	Version     int       `json:"version"`
+	Data        string    `json:"-"`
+	Location    string    `json:"location,omitempty"`
+	ArchivedAt  time.Time `json:"archivedAt"`
+}
+
+func (ArchivedVersion) TableName() string { return "scd_archived_versions" }
+
+// Pin is a column of another table that names versions by uid, such as the
+// invoice lines naming the job and line item versions they billed. With At
+// set, a row pins the version effective at its At column of the entity the
+// named version belongs to, as statements price work at the rate in effect
+// when it was done.
+type Pin struct {
+	Table  string
+	Column string
+	At     string
+}
+
+// CompactOptions configure one compaction. With DryRun set nothing is
+// written. With File set the removed versions are written to it as NDJSON
+// once their removal committed, and the archive table then only records
+// them, with Location as their place.
+type CompactOptions struct {
+	Retention Retention
+	DryRun    bool
+	File      io.Writer
+	Location  string
+	// Pins are the columns of other tables naming versions of the entity,
+	// besides its dependents' links. The versions they name are kept.
+	Pins []Pin
+	// Now is the time the retention is measured from; the zero value is the
+	// current time.
+	Now time.Time
+}
+
+// Compaction reports what a compaction removed, or would remove in a dry run.
+type Compaction struct {
+	Table    string `json:"table"`
+	DryRun   bool   `json:"dryRun"`
+	Entities int    `json:"entities"`
+	Removed  int    `json:"removed"`
+	Held     int    `json:"held"`
+}
+
+// Compact collapses the versions on record that ended before the retention,
+// as Retention describes. Versions that dependents link to, that a pin names
+// or that a revert restored are kept as they are and split the months around
+// them; restated versions, no longer on record, are left alone. Entities
+// under a legal hold are skipped. Each entity is compacted in its own
+// transaction, its removed versions archived before they are deleted.
+func (m *SCDManager[T]) Compact(opts CompactOptions) (Compaction, error) {
+	out := Compaction{Table: m.table(), DryRun: opts.DryRun}
+	if opts.Retention.KeepAll() {
+		return out, nil
+	}
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	cutoff := opts.Now.Add(-opts.Retention.After)
+
+	var ids []string
+	err := m.db.Table(m.table()).
+		Where("recorded_to IS NULL AND effective_to < ?", cutoff).
+		Group("id").
+		Having("COUNT(*) > 1").
+		Order("id").
+		Pluck("id", &ids).Error
+	if err != nil {
+		return out, err
+	}
+
+	for _, id := range ids {
+		var removed int
+		var held bool
+		var archived []ArchivedVersion
+		err := m.Transaction(func(tx *SCDManager[T]) error {
+			var err error
+			removed, held, archived, err = tx.compactEntity(id, cutoff, opts)
+			return err
+		})
+		if err != nil {
+			return out, err
+		}
+		if held {
+			out.Held++
+			continue
+		}
+		if err := m.writeArchive(archived, opts); err != nil {
+			return out, err
+		}
+		if removed > 0 {
+			out.Entities++
+			out.Removed += removed
+		}
+	}
+	return out, nil
+}
+
+// compactEntity collapses the old versions of one entity and returns how
+// many it removed and the archive rows it recorded for them. It reports held
+// instead for an entity under a legal hold: the versions are locked first,
+// which placing a hold waits for, so a hold placed meanwhile is seen here.
+func (m *SCDManager[T]) compactEntity(id string, cutoff time.Time, opts CompactOptions) (int, bool, []ArchivedVersion, error) {
+	s, err := m.schema()
+	if err != nil {
+		return 0, false, nil, err
+	}
+	var versions []T
+	err = m.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND recorded_to IS NULL", id).
+		Order("effective_from, version").
+		Find(&versions).Error
+	if err != nil {
+		return 0, false, nil, err
+	}
+	var holds []string
+	err = m.db.Model(&LegalHold{}).
+		Clauses(clause.Locking{Strength: "SHARE"}).
+		Where("entity_table = ? AND entity_id = ?", m.table(), id).
+		Pluck("id", &holds).Error
+	if err != nil || len(holds) > 0 {
+		return 0, len(holds) > 0, nil, err
+	}
+	pinned, err := m.pinned(s, id, versions, opts.Pins)
+	if err != nil {
+		return 0, false, nil, err
+	}
+
+	// Runs of consecutive old versions in one month, each kept as its last.
+	var runs [][]T
+	var run []T
+	month := ""
+	for _, v := range versions {
+		rv := reflect.ValueOf(&v).Elem()
+		end := effectiveTo(s, rv)
+		key := effectiveFrom(s, rv).UTC().Format("2006-01")
+		if end == nil || !end.Before(cutoff) || pinned[v.GetUID()] || key != month {
+			if len(run) > 1 {
+				runs = append(runs, run)
+			}
+			run, month = nil, ""
+		}
+		if end != nil && end.Before(cutoff) && !pinned[v.GetUID()] {
+			run, month = append(run, v), key
+		}
+	}
+	if len(run) > 1 {
+		runs = append(runs, run)
+	}
+
+	removed := 0
+	var archived []ArchivedVersion
+	for _, run := range runs {
+		drop, keep := run[:len(run)-1], run[len(run)-1]
+		removed += len(drop)
+		if opts.DryRun {
+			continue
+		}
+		rows, err := m.archive(drop, opts)
+		if err != nil {
+			return removed, false, archived, err
+		}
+		archived = append(archived, rows...)
+		uids := make([]string, len(drop))
+		for i, v := range drop {
+			uids[i] = v.GetUID()
+		}
+		var model T
+		if err := m.db.Where("uid IN ?", uids).Delete(&model).Error; err != nil {
+			return removed, false, archived, err
+		}
+		start := effectiveFrom(s, reflect.ValueOf(&run[0]).Elem())
+		err = m.db.Table(m.table()).
+			Where("uid = ?", keep.GetUID()).
+			UpdateColumn(colEffectiveFrom, start).Error
+		if err != nil {
+			return removed, false, archived, err
+		}
+	}
+	return removed, false, archived, nil
+}
+
+// pinned returns the UIDs of the versions that other rows name: the ones
+// dependents link to, the ones pins name and the ones a revert restored.
+// versions are the entity's versions on record, in the order they took
+// effect.
+func (m *SCDManager[T]) pinned(s *schema.Schema, id string, versions []T, pins []Pin) (map[string]bool, error) {
+	uids := make([]string, len(versions))
+	for i, v := range versions {
+		uids[i] = v.GetUID()
+	}
+	var named []string
+	err := m.db.Table(m.table()).Where(colRevertedFrom+" IN ?", uids).Pluck(colRevertedFrom, &named).Error
+	if err != nil {
+		return nil, err
+	}
+	all := append([]Pin{}, pins...)
+	var model T
+	if p, ok := any(model).(Parent); ok {
+		for _, rel := range p.Dependents() {
+			all = append(all, Pin{Table: rel.Table(), Column: rel.Column()})
+		}
+	}
+	for _, pin := range all {
+		if pin.At == "" {
+			var links []string
+			err := m.db.Table(pin.Table).Where(pin.Column+" IN ?", uids).Pluck(pin.Column, &links).Error
+			if err != nil {
+				return nil, err
+			}
+			named = append(named, links...)
+			continue
+		}
+		// Rows may name any version, restated ones included.
+		every := m.db.Table(m.table()).Select("uid").Where("id = ?", id)
+		var times []time.Time
+		err := m.db.Table(pin.Table).Where(pin.Column+" IN (?)", every).Pluck(pin.At, &times).Error
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range times {
+			if v, ok := effectiveAt(s, versions, t); ok {
+				named = append(named, v.GetUID())
+			}
+		}
+	}
+	out := make(map[string]bool, len(named))
+	for _, uid := range named {
+		out[uid] = true
+	}
+	return out, nil
+}
+
+// effectiveAt returns the version of versions, ordered as they took effect,
+// that was effective at t; the first one for times before it.
+func effectiveAt[T any](s *schema.Schema, versions []T, t time.Time) (T, bool) {
+	var found T
+	if len(versions) == 0 {
+		return found, false
+	}
+	found = versions[0]
+	for _, v := range versions[1:] {
+		if effectiveFrom(s, reflect.ValueOf(&v).Elem()).After(t) {
+			break
+		}
+		found = v
+	}
+	return found, true
+}
+
+// archive records the versions in the archive table with their data and,
+// when opts has a file, the location writeArchive moves the data to.
+func (m *SCDManager[T]) archive(versions []T, opts CompactOptions) ([]ArchivedVersion, error) {
+	now := time.Now()
+	rows := make([]ArchivedVersion, len(versions))
+	for i, v := range versions {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		rows[i] = ArchivedVersion{
+			UID:         uuid.MustParse(v.GetUID()),
+			EntityTable: m.table(),
+			EntityID:    uuid.MustParse(v.GetID()),
+			Version:     v.GetVersion(),
+			Data:        string(data),
+			ArchivedAt:  now,
+		}
+		if opts.File != nil {
+			rows[i].Location = opts.Location
+		}
+	}
+	return rows, m.db.Create(&rows).Error
+}
+
+// writeArchive writes the archived versions to the file of opts, after the
+// transaction removing them committed, and then clears their data from the
+// archive table. Should writing fail, the data stays in the table.
+func (m *SCDManager[T]) writeArchive(rows []ArchivedVersion, opts CompactOptions) error {
+	if opts.File == nil || len(rows) == 0 {
+		return nil
+	}
+	uids := make([]uuid.UUID, len(rows))
+	for i, row := range rows {
+		line, err := json.Marshal(struct {
+			ArchivedVersion
+			Data json.RawMessage `json:"data"`
+		}{row, json.RawMessage(row.Data)})
+		if err != nil {
+			return err
+		}
+		if _, err := opts.File.Write(append(line, '\n')); err != nil {
+			return err
+		}
+		uids[i] = row.UID
+	}
+	return m.db.Model(&ArchivedVersion{}).Where("uid IN ?", uids).Update("data", "").Error
+}
+
+// RunEvery calls run every interval until ctx is done, as a background job.
+// Failures are logged and retried at the next interval.
+func RunEvery(ctx context.Context, name string, interval time.Duration, run func() error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := run(); err != nil {
+				log.Printf("%s: %v", name, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package scd
+
+import (
+	"errors"
+	"reflect"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Reversion is embedded in every SCD model next to Bitemporal. On a version
+// written by Revert it names the version whose values were restored and why;
+// it is empty on every other version.
+type Reversion struct {
+	RevertedFrom *uuid.UUID `gorm:"type:uuid" json:"revertedFrom"`
+	RevertReason string     `json:"revertReason"`
+}
+
+const (
+	colRevertedFrom = "reverted_from"
+	colRevertReason = "revert_reason"
+)
+
+// ErrNotInHistory is returned when reverting to a version that does not
+// belong to the entity.
+var ErrNotInHistory = errors.New("not a version of this entity")
+
+// RevertRequest is the body of a revert.
+type RevertRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// Revert stores a new version of the entity uid belongs to with the business
+// fields of its version `to`, provided the head matches pre. The history is
+// not rewritten: the revert is a version of its own, effective from now on,
+// that records the version it restores and the reason. Type1 fields restored
+// by it are overwritten on every version, as for any other write.
+func (m *SCDManager[T]) Revert(uid, to, reason string, pre Precondition) (T, error) {
+	var out T
+	err := m.Transaction(func(tx *SCDManager[T]) error {
+		head, err := tx.lockHead(uid)
+		if err != nil {
+			return err
+		}
+		if err := pre.Check(head.GetUID()); err != nil {
+			return err
+		}
+		if _, err := uuid.Parse(to); err != nil {
+			return ErrNotInHistory
+		}
+		source, err := tx.FindByUID(to)
+		if errors.Is(err, gorm.ErrRecordNotFound) || err == nil && source.GetID() != head.GetID() {
+			return ErrNotInHistory
+		}
+		if err != nil {
+			return err
+		}
+		s, err := tx.schema()
+		if err != nil {
+			return err
+		}
+		from := uuid.MustParse(source.GetUID())
+		row := source.CopyForNewVersion()
+		err = setColumns(s, reflect.ValueOf(&row).Elem(), map[string]any{
+			colRevertedFrom: &from,
+			colRevertReason: reason,
+		})
+		if err != nil {
+			return err
+		}
+		// A revert is always a new version, even when it only restores Type1
+		// or Type3 fields.
+		c, err := tx.diff(head, &row)
+		if err != nil {
+			return err
+		}
+		if err := tx.checkIdentity(head, row); err != nil {
+			return err
+		}
+		if err := tx.checkReferences(head, row); err != nil {
+			return err
+		}
+		if out, err = tx.appendVersion(head, row); err != nil {
+			return err
+		}
+		if err := tx.overwrite(out, c); err != nil {
+			return err
+		}
+		return tx.cascade(out.GetID())
+	})
+	return out, err
+}
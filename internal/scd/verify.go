@@ -0,0 +1,336 @@
+package scd
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Kinds of violations of the invariants of a versioned table.
+const (
+	// ViolationVersionGap is an entity whose versions are not numbered from 1
+	// without gaps.
+	ViolationVersionGap = "version_gap"
+	// ViolationDuplicateVersion is a version number an entity uses more than
+	// once.
+	ViolationDuplicateVersion = "duplicate_version"
+	// ViolationDuplicateUID is a UID held by more than one row, so that links
+	// to it do not name one version. Split tables have no primary key to
+	// prevent it, only the trigger maintaining their uid table.
+	ViolationDuplicateUID = "duplicate_uid"
+	// ViolationMultipleHeads is an entity with more than one version on
+	// record whose effective period has no end.
+	ViolationMultipleHeads = "multiple_heads"
+	// ViolationIdentityChanged is an entity whose versions differ in an
+	// identity column, so that its ID stands for more than one entity.
+	ViolationIdentityChanged = "identity_changed"
+	// ViolationDanglingReference is a reference column holding the ID of an
+	// entity that was never stored.
+	ViolationDanglingReference = "dangling_reference"
+	// ViolationDanglingLink is a dependent column holding the UID of a parent
+	// version that was never stored.
+	ViolationDanglingLink = "dangling_link"
+)
+
+// Violation is an invariant of the versioned tables that does not hold for
+// one entity. UIDs are the versions involved, and Column and Value the
+// offending column of the identity and dangling kinds.
+type Violation struct {
+	Kind   string   `json:"kind"`
+	Table  string   `json:"table"`
+	ID     string   `json:"id"`
+	UIDs   []string `json:"uids,omitempty"`
+	Column string   `json:"column,omitempty"`
+	Value  string   `json:"value,omitempty"`
+	Detail string   `json:"detail"`
+	// Repairable is set when Verify can repair the violation without
+	// choosing between conflicting values; Repaired once it did.
+	Repairable bool `json:"repairable"`
+	Repaired   bool `json:"repaired"`
+}
+
+// Verify checks the invariants of the table of T, and the links its
+// dependents hold to it, over every version ever recorded. With repair set
+// it repairs the repairable violations, multiple heads, in one transaction:
+// every version but the last to take effect ends where the next one starts,
+// as if it had been appended normally. Other violations are only reported.
+func (m *SCDManager[T]) Verify(repair bool) ([]Violation, error) {
+	if !repair {
+		return m.verify(false)
+	}
+	var out []Violation
+	err := m.Transaction(func(tx *SCDManager[T]) error {
+		var err error
+		out, err = tx.verify(true)
+		return err
+	})
+	return out, err
+}
+
+func (m *SCDManager[T]) verify(repair bool) ([]Violation, error) {
+	var out []Violation
+	for _, check := range []func() ([]Violation, error){
+		m.versionGaps,
+		m.duplicateVersions,
+		m.duplicateUIDs,
+		func() ([]Violation, error) { return m.multipleHeads(repair) },
+		m.identityChanges,
+		m.danglingReferences,
+		m.danglingLinks,
+	} {
+		found, err := check()
+		if err != nil {
+			return out, err
+		}
+		out = append(out, found...)
+	}
+	return out, nil
+}
+
+func (m *SCDManager[T]) table() string {
+	var model T
+	return model.TableName()
+}
+
+// versionGaps finds the entities whose distinct version numbers do not run
+// from 1 to their count. Versions removed by compaction are counted from the
+// archive table.
+func (m *SCDManager[T]) versionGaps() ([]Violation, error) {
+	var rows []struct {
+		ID                 string
+		Count, First, Last int
+	}
+	versions := m.db.Raw("SELECT id, version FROM "+m.table()+
+		" UNION ALL SELECT entity_id AS id, version FROM "+ArchivedVersion{}.TableName()+" WHERE entity_table = ?", m.table())
+	err := m.db.Table("(?) AS v", versions).
+		Select("id, COUNT(DISTINCT version) AS count, MIN(version) AS first, MAX(version) AS last").
+		Group("id").
+		Having("MIN(version) <> 1 OR MAX(version) <> COUNT(DISTINCT version)").
+		Order("id").
+		Scan(&rows).Error
+	out := make([]Violation, len(rows))
+	for i, r := range rows {
+		out[i] = Violation{
+			Kind: ViolationVersionGap, Table: m.table(), ID: r.ID,
+			Detail: fmt.Sprintf("%d distinct versions numbered %d to %d", r.Count, r.First, r.Last),
+		}
+	}
+	return out, err
+}
+
+// duplicateVersions finds the version numbers used by more than one version
+// of an entity.
+func (m *SCDManager[T]) duplicateVersions() ([]Violation, error) {
+	t := m.table()
+	dups := m.db.Table(t).Select("id, version").Group("id, version").Having("COUNT(*) > 1")
+	var rows []struct {
+		ID, UID string
+		Version int
+	}
+	err := m.db.Table(t+" AS t").
+		Select("t.id, t.uid, t.version").
+		Joins("JOIN (?) AS d ON d.id = t.id AND d.version = t.version", dups).
+		Order("t.id, t.version, t.recorded_from").
+		Scan(&rows).Error
+	var out []Violation
+	for _, r := range rows {
+		if n := len(out); n > 0 && out[n-1].ID == r.ID && out[n-1].Value == strconv.Itoa(r.Version) {
+			out[n-1].UIDs = append(out[n-1].UIDs, r.UID)
+			continue
+		}
+		out = append(out, Violation{
+			Kind: ViolationDuplicateVersion, Table: t, ID: r.ID, UIDs: []string{r.UID},
+			Column: "version", Value: strconv.Itoa(r.Version),
+		})
+	}
+	for i := range out {
+		out[i].Detail = fmt.Sprintf("version %s is used by %d versions", out[i].Value, len(out[i].UIDs))
+	}
+	return out, err
+}
+
+// duplicateUIDs finds the UIDs held by more than one row. The violation is
+// reported on the entity of the first version holding the UID.
+func (m *SCDManager[T]) duplicateUIDs() ([]Violation, error) {
+	t := m.table()
+	dups := m.db.Table(t).Select("uid").Group("uid").Having("COUNT(*) > 1")
+	var rows []struct {
+		ID, UID string
+		Version int
+	}
+	err := m.db.Table(t+" AS t").
+		Select("t.id, t.uid, t.version").
+		Where("t.uid IN (?)", dups).
+		Order("t.uid, t.recorded_from, t.version").
+		Scan(&rows).Error
+	var out []Violation
+	var held [][]string
+	for _, r := range rows {
+		version := fmt.Sprintf("%s version %d", r.ID, r.Version)
+		if n := len(out); n > 0 && out[n-1].Value == r.UID {
+			held[n-1] = append(held[n-1], version)
+			continue
+		}
+		out = append(out, Violation{
+			Kind: ViolationDuplicateUID, Table: t, ID: r.ID, UIDs: []string{r.UID},
+			Column: "uid", Value: r.UID,
+		})
+		held = append(held, []string{version})
+	}
+	for i := range out {
+		out[i].Detail = fmt.Sprintf("UID is held by %d versions: %s", len(held[i]), strings.Join(held[i], ", "))
+	}
+	return out, err
+}
+
+// multipleHeads finds the entities with more than one open-ended version on
+// record and, with repair set, ends each of them but the last at the start
+// of the version after it.
+func (m *SCDManager[T]) multipleHeads(repair bool) ([]Violation, error) {
+	var ids []string
+	err := m.db.Table(m.table()).
+		Where("recorded_to IS NULL AND effective_to IS NULL").
+		Group("id").
+		Having("COUNT(*) > 1").
+		Order("id").
+		Pluck("id", &ids).Error
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+	s, err := m.schema()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Violation, 0, len(ids))
+	for _, id := range ids {
+		var versions []T
+		err := m.db.Where("id = ? AND recorded_to IS NULL", id).
+			Order("effective_from, version").
+			Find(&versions).Error
+		if err != nil {
+			return out, err
+		}
+		v := Violation{Kind: ViolationMultipleHeads, Table: m.table(), ID: id, Repairable: true}
+		for i := range versions {
+			rv := reflect.ValueOf(&versions[i]).Elem()
+			if effectiveTo(s, rv) != nil {
+				continue
+			}
+			v.UIDs = append(v.UIDs, versions[i].GetUID())
+			if !repair || i == len(versions)-1 {
+				continue
+			}
+			end := effectiveFrom(s, reflect.ValueOf(&versions[i+1]).Elem())
+			err := m.db.Table(m.table()).
+				Where("uid = ?", versions[i].GetUID()).
+				UpdateColumn(colEffectiveTo, end).Error
+			if err != nil {
+				return out, err
+			}
+		}
+		v.Detail = fmt.Sprintf("%d versions on record are effective without end", len(v.UIDs))
+		v.Repaired = repair
+		out = append(out, v)
+	}
+	return out, nil
+}
+
+// identityChanges finds the entities whose versions differ in an identity
+// column.
+func (m *SCDManager[T]) identityChanges() ([]Violation, error) {
+	var model T
+	id, ok := any(model).(Identified)
+	if !ok {
+		return nil, nil
+	}
+	var out []Violation
+	for _, col := range id.Identity() {
+		var rows []struct {
+			ID    string
+			Count int
+		}
+		err := m.db.Table(m.table()).
+			Select("id, COUNT(DISTINCT " + col + ") AS count").
+			Group("id").
+			Having("COUNT(DISTINCT " + col + ") > 1").
+			Order("id").
+			Scan(&rows).Error
+		if err != nil {
+			return out, err
+		}
+		for _, r := range rows {
+			out = append(out, Violation{
+				Kind: ViolationIdentityChanged, Table: m.table(), ID: r.ID, Column: col,
+				Detail: fmt.Sprintf("versions hold %d different values of %s", r.Count, col),
+			})
+		}
+	}
+	return out, nil
+}
+
+// danglingReferences finds the reference columns naming an ID that no
+// version of the referenced table has.
+func (m *SCDManager[T]) danglingReferences() ([]Violation, error) {
+	var model T
+	r, ok := any(model).(Referrer)
+	if !ok {
+		return nil, nil
+	}
+	var out []Violation
+	for _, ref := range r.References() {
+		found, err := m.dangling(ViolationDanglingReference, m.table(), ref.Column, m.db.Table(ref.Table).Select("id"))
+		if err != nil {
+			return out, err
+		}
+		for i := range found {
+			found[i].Detail = fmt.Sprintf("%s has no entity with ID %s", ref.Table, found[i].Value)
+		}
+		out = append(out, found...)
+	}
+	return out, nil
+}
+
+// danglingLinks finds the versions of dependents linking to a UID that no
+// version of T has.
+func (m *SCDManager[T]) danglingLinks() ([]Violation, error) {
+	var model T
+	p, ok := any(model).(Parent)
+	if !ok {
+		return nil, nil
+	}
+	var out []Violation
+	for _, rel := range p.Dependents() {
+		found, err := m.dangling(ViolationDanglingLink, rel.Table(), rel.Column(), m.db.Table(m.table()).Select("uid"))
+		if err != nil {
+			return out, err
+		}
+		for i := range found {
+			found[i].Detail = fmt.Sprintf("no version of %s has UID %s", m.table(), found[i].Value)
+		}
+		out = append(out, found...)
+	}
+	return out, nil
+}
+
+// dangling returns a violation for each entity of table and value of column
+// that is not among known, with the versions holding it.
+func (m *SCDManager[T]) dangling(kind, table, column string, known *gorm.DB) ([]Violation, error) {
+	var rows []struct{ ID, UID, Value string }
+	err := m.db.Table(table).
+		Select("id, uid, "+column+" AS value").
+		Where(column+" IS NOT NULL AND "+column+" NOT IN (?)", known).
+		Order("id, " + column + ", version").
+		Scan(&rows).Error
+	var out []Violation
+	for _, r := range rows {
+		if n := len(out); n > 0 && out[n-1].ID == r.ID && out[n-1].Value == r.Value {
+			out[n-1].UIDs = append(out[n-1].UIDs, r.UID)
+			continue
+		}
+		out = append(out, Violation{Kind: kind, Table: table, ID: r.ID, UIDs: []string{r.UID}, Column: column, Value: r.Value})
+	}
+	return out, err
+}
@@ -0,0 +1,112 @@
+// Package validation runs the binding rules of request types and renders their
+// failures per field, using the JSON names clients send.
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+func init() {
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(jsonName)
+	}
+}
+
+func jsonName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return f.Name
+	}
+	return name
+}
+
+// Error lists the failed fields with a message for each.
+type Error struct {
+	Fields map[string]string
+}
+
+func (e *Error) Error() string {
+	names := make([]string, 0, len(e.Fields))
+	for name := range e.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = name + " " + e.Fields[name]
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Struct checks v against its binding tags, the same way ShouldBindJSON does.
+func Struct[T any](v T) error {
+	return Translate(binding.Validator.ValidateStruct(v))
+}
+
+// Translate turns validator errors into an *Error and returns any other error
+// unchanged.
+func Translate(err error) error {
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		return err
+	}
+	out := &Error{Fields: map[string]string{}}
+	for _, fe := range verrs {
+		out.Fields[fe.Field()] = message(fe)
+	}
+	return out
+}
+
+// Response is the JSON body for a request that failed to bind.
+func Response(err error) gin.H {
+	var verr *Error
+	if errors.As(Translate(err), &verr) {
+		return gin.H{"error": "validation failed", "fields": verr.Fields}
+	}
+	return gin.H{"error": err.Error()}
+}
+
+func message(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "uuid":
+		return "must be a valid UUID"
+	case "email":
+		return "must be a valid email address"
+	case "iso4217":
+		return "must be an ISO 4217 currency code"
+	case "gt":
+		return "must be greater than " + fe.Param()
+	case "gte":
+		return "must be at least " + fe.Param()
+	case "lte":
+		return "must be at most " + fe.Param()
+	case "gtfield":
+		return "must be after " + lowerFirst(fe.Param())
+	case "oneof":
+		return "must be one of " + strings.ReplaceAll(fe.Param(), " ", ", ")
+	case "max":
+		return "must be at most " + fe.Param()
+	}
+	return fmt.Sprintf("failed the %q rule", fe.Tag())
+}
+
+// lowerFirst maps a Go field name used as a rule parameter to its JSON name.
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}